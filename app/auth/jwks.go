@@ -0,0 +1,120 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+// JSONWebKey is a public key of a JSON Web Key Set, only RSA and EC signing keys are supported.
+type JSONWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+	Y   string `json:"y,omitempty"`
+}
+
+type JSONWebKeySet struct {
+	Keys []JSONWebKey `json:"keys"`
+}
+
+var ErrNoSigningKeys = errors.New("key set contains no supported signing keys")
+
+// ParseKeySet returns the signing keys of a JWKS document by key id.
+func ParseKeySet(data []byte) (map[string]crypto.PublicKey, error) {
+	var set JSONWebKeySet
+	if err := json.Unmarshal(data, &set); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+
+		publicKey, err := key.PublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("key %q: %w", key.Kid, err)
+		}
+		if publicKey != nil {
+			keys[key.Kid] = publicKey
+		}
+	}
+
+	if len(keys) == 0 {
+		return nil, ErrNoSigningKeys
+	}
+	return keys, nil
+}
+
+// PublicKey decodes the key, key types other than RSA and EC are skipped with a nil key.
+func (k JSONWebKey) PublicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		curve, err := ellipticCurve(k.Crv)
+		if err != nil {
+			return nil, err
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, nil
+	}
+}
+
+func ellipticCurve(name string) (elliptic.Curve, error) {
+	switch name {
+	case "P-256":
+		return elliptic.P256(), nil
+	case "P-384":
+		return elliptic.P384(), nil
+	case "P-521":
+		return elliptic.P521(), nil
+	default:
+		return nil, fmt.Errorf("unsupported curve %q", name)
+	}
+}
+
+func decodeInt(value string) (*big.Int, error) {
+	if value == "" {
+		return nil, errors.New("missing key parameter")
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,242 @@
+package auth
+
+import (
+	"context"
+	"crypto"
+	"errors"
+	"fmt"
+	"log/slog"
+	"messaging-system/app/model"
+	"messaging-system/config"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	DefaultScopeClaim      = "scope"
+	DefaultTenantClaim     = "tenant"
+	DefaultRefreshInterval = 5 * time.Minute
+
+	jwksTimeout = 10 * time.Second
+)
+
+// SigningMethods are the accepted token algorithms, symmetric algorithms are rejected so a public key
+// can never be used as an HMAC secret.
+var SigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+// JWTAuthenticator validates bearer tokens of an identity provider against its JWKS and maps their
+// claims to API scopes.
+type JWTAuthenticator struct {
+	conf      *config.JWT
+	resty     *resty.Client
+	parser    *jwt.Parser
+	logger    *slog.Logger
+	mu        sync.RWMutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewJWTAuthenticator loads the signing keys once so a misconfigured key source fails at startup.
+func NewJWTAuthenticator(ctx context.Context, conf *config.JWT, logger *slog.Logger) (*JWTAuthenticator, error) {
+	if conf.JWKSURL == "" && conf.JWKSFile == "" {
+		return nil, errors.New("jwt: jwksUrl or jwksFile is required")
+	}
+	if conf.Issuer == "" || conf.Audience == "" {
+		return nil, errors.New("jwt: issuer and audience are required")
+	}
+	for value, scopes := range conf.Scopes {
+		for _, scope := range scopes {
+			if !slices.Contains(model.Scopes, scope) {
+				return nil, fmt.Errorf("jwt: claim value %q maps to unknown scope %q", value, scope)
+			}
+		}
+	}
+
+	a := &JWTAuthenticator{
+		conf:  conf,
+		resty: resty.New().SetTimeout(jwksTimeout),
+		parser: jwt.NewParser(
+			jwt.WithValidMethods(SigningMethods),
+			jwt.WithExpirationRequired(),
+			jwt.WithLeeway(conf.Leeway),
+			jwt.WithIssuer(conf.Issuer),
+			jwt.WithAudience(conf.Audience),
+		),
+		logger: logger,
+	}
+	if err := a.refresh(ctx); err != nil {
+		return nil, fmt.Errorf("jwt: %w", err)
+	}
+
+	return a, nil
+}
+
+// Authenticate resolves the caller of a bearer token, invalid tokens are reported as model.ErrUnauthorized.
+func (a *JWTAuthenticator) Authenticate(ctx context.Context, token string) (*model.Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := a.parser.ParseWithClaims(token, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		return a.key(ctx, kid)
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrUnauthorized, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return nil, fmt.Errorf("%w: token has no subject", model.ErrUnauthorized)
+	}
+
+	name, _ := claims["name"].(string)
+	if name == "" {
+		name = subject
+	}
+
+	tenantID, _ := claims[a.tenantClaim()].(string)
+	if tenantID == "" {
+		tenantID = model.DefaultTenantID
+	}
+
+	return &model.Principal{
+		ID:       subject,
+		Name:     name,
+		TenantID: tenantID,
+		Scopes:   a.scopes(claims),
+	}, nil
+}
+
+// key returns the signing key with the given id. Unknown ids refresh the key set so rotated keys are
+// picked up, at most once per refresh interval.
+func (a *JWTAuthenticator) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	if key, ok := a.lookup(kid); ok {
+		return key, nil
+	}
+
+	if a.conf.JWKSURL == "" || time.Since(a.lastFetch()) < a.refreshInterval() {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	if err := a.refresh(ctx); err != nil {
+		a.logger.Error("failed to refresh JWKS", "url", a.conf.JWKSURL, "error", err)
+		return nil, err
+	}
+
+	if key, ok := a.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookup finds a key by id, tokens without an id are accepted when the set holds a single key.
+func (a *JWTAuthenticator) lookup(kid string) (crypto.PublicKey, bool) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if kid == "" && len(a.keys) == 1 {
+		for _, key := range a.keys {
+			return key, true
+		}
+	}
+
+	key, ok := a.keys[kid]
+	return key, ok
+}
+
+func (a *JWTAuthenticator) lastFetch() time.Time {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.fetchedAt
+}
+
+func (a *JWTAuthenticator) refresh(ctx context.Context) error {
+	data, err := a.fetch(ctx)
+	if err != nil {
+		return err
+	}
+
+	keys, err := ParseKeySet(data)
+	if err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.keys = keys
+	a.fetchedAt = time.Now()
+	return nil
+}
+
+func (a *JWTAuthenticator) fetch(ctx context.Context) ([]byte, error) {
+	if a.conf.JWKSURL == "" {
+		return os.ReadFile(a.conf.JWKSFile)
+	}
+
+	resp, err := a.resty.R().SetContext(ctx).Get(a.conf.JWKSURL)
+	if err != nil {
+		return nil, err
+	}
+	if resp.IsError() {
+		return nil, fmt.Errorf("JWKS request failed with status %d", resp.StatusCode())
+	}
+	return resp.Body(), nil
+}
+
+// scopes maps the values of the scope claim to API scopes, unknown values are ignored.
+func (a *JWTAuthenticator) scopes(claims jwt.MapClaims) []string {
+	var values []string
+	switch claim := claims[a.scopeClaim()].(type) {
+	case string:
+		values = strings.Fields(claim)
+	case []interface{}:
+		for _, value := range claim {
+			if value, ok := value.(string); ok {
+				values = append(values, value)
+			}
+		}
+	}
+
+	granted := []string{}
+	grant := func(scope string) {
+		if !slices.Contains(granted, scope) {
+			granted = append(granted, scope)
+		}
+	}
+	for _, value := range values {
+		if slices.Contains(model.Scopes, value) {
+			grant(value)
+		}
+		// the config loader lower cases map keys
+		for _, scope := range a.conf.Scopes[strings.ToLower(value)] {
+			grant(scope)
+		}
+	}
+
+	return granted
+}
+
+func (a *JWTAuthenticator) scopeClaim() string {
+	if a.conf.ScopeClaim == "" {
+		return DefaultScopeClaim
+	}
+	return a.conf.ScopeClaim
+}
+
+func (a *JWTAuthenticator) tenantClaim() string {
+	if a.conf.TenantClaim == "" {
+		return DefaultTenantClaim
+	}
+	return a.conf.TenantClaim
+}
+
+func (a *JWTAuthenticator) refreshInterval() time.Duration {
+	if a.conf.RefreshInterval <= 0 {
+		return DefaultRefreshInterval
+	}
+	return a.conf.RefreshInterval
+}
@@ -0,0 +1,217 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"log/slog"
+	"math/big"
+	"messaging-system/app/model"
+	"messaging-system/config"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	issuer   = "https://idp.example.com"
+	audience = "messaging-system"
+)
+
+func rsaJWK(kid string, key *rsa.PublicKey) JSONWebKey {
+	return JSONWebKey{
+		Kty: "RSA",
+		Kid: kid,
+		Use: "sig",
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+}
+
+func ecJWK(kid string, key *ecdsa.PublicKey) JSONWebKey {
+	return JSONWebKey{
+		Kty: "EC",
+		Kid: kid,
+		Crv: "P-256",
+		X:   base64.RawURLEncoding.EncodeToString(key.X.FillBytes(make([]byte, 32))),
+		Y:   base64.RawURLEncoding.EncodeToString(key.Y.FillBytes(make([]byte, 32))),
+	}
+}
+
+func writeKeySet(t *testing.T, keys ...JSONWebKey) string {
+	data, err := json.Marshal(JSONWebKeySet{Keys: keys})
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	assert.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func signToken(t *testing.T, method jwt.SigningMethod, kid string, key interface{}, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	signed, err := token.SignedString(key)
+	assert.NoError(t, err)
+	return signed
+}
+
+func validClaims(scope interface{}) jwt.MapClaims {
+	return jwt.MapClaims{
+		"iss":    issuer,
+		"aud":    audience,
+		"sub":    "user-1",
+		"name":   "Jane Operator",
+		"tenant": "retail",
+		"exp":    time.Now().Add(time.Hour).Unix(),
+		"scope":  scope,
+	}
+}
+
+func TestJWTAuthenticator_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NoError(t, err)
+
+	authenticator, err := NewJWTAuthenticator(ctx, &config.JWT{
+		JWKSFile: writeKeySet(t, rsaJWK("rsa-1", &rsaKey.PublicKey), ecJWK("ec-1", &ecKey.PublicKey)),
+		Issuer:   issuer,
+		Audience: audience,
+		Scopes:   map[string][]string{"messaging-operator": {model.ScopeProcessorAdmin, model.ScopeMessagesRead}},
+	}, slog.Default())
+	assert.NoError(t, err)
+
+	t.Run("maps claim values to scopes", func(t *testing.T) {
+		token := signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, validClaims("openid Messaging-Operator"))
+
+		principal, err := authenticator.Authenticate(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, "user-1", principal.ID)
+		assert.Equal(t, "Jane Operator", principal.Name)
+		assert.Equal(t, "retail", principal.TenantID)
+		assert.ElementsMatch(t, []string{model.ScopeProcessorAdmin, model.ScopeMessagesRead}, principal.Scopes)
+	})
+
+	t.Run("grants API scopes listed in the claim", func(t *testing.T) {
+		token := signToken(t, jwt.SigningMethodES256, "ec-1", ecKey,
+			validClaims([]interface{}{model.ScopeMessagesRead, "profile"}))
+
+		principal, err := authenticator.Authenticate(ctx, token)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{model.ScopeMessagesRead}, principal.Scopes)
+	})
+
+	t.Run("rejects invalid tokens", func(t *testing.T) {
+		otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+		assert.NoError(t, err)
+
+		expired := validClaims(model.ScopeMessagesRead)
+		expired["exp"] = time.Now().Add(-time.Hour).Unix()
+		wrongIssuer := validClaims(model.ScopeMessagesRead)
+		wrongIssuer["iss"] = "https://evil.example.com"
+		wrongAudience := validClaims(model.ScopeMessagesRead)
+		wrongAudience["aud"] = "billing"
+		noExpiry := validClaims(model.ScopeMessagesRead)
+		delete(noExpiry, "exp")
+
+		tokens := map[string]string{
+			"expired":        signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, expired),
+			"wrong issuer":   signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, wrongIssuer),
+			"wrong audience": signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, wrongAudience),
+			"no expiry":      signToken(t, jwt.SigningMethodRS256, "rsa-1", rsaKey, noExpiry),
+			"unknown key":    signToken(t, jwt.SigningMethodRS256, "rsa-2", otherKey, validClaims("")),
+			"forged":         signToken(t, jwt.SigningMethodRS256, "rsa-1", otherKey, validClaims("")),
+			"hmac":           signToken(t, jwt.SigningMethodHS256, "rsa-1", []byte("secret"), validClaims("")),
+			"malformed":      "not-a-token",
+		}
+		for name, token := range tokens {
+			principal, err := authenticator.Authenticate(ctx, token)
+			assert.ErrorIs(t, err, model.ErrUnauthorized, name)
+			assert.Nil(t, principal, name)
+		}
+	})
+}
+
+func TestJWTAuthenticator_RefreshesRotatedKeys(t *testing.T) {
+	ctx := context.Background()
+	oldKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+	newKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	var rotated atomic.Bool
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		set := JSONWebKeySet{Keys: []JSONWebKey{rsaJWK("old", &oldKey.PublicKey)}}
+		if rotated.Load() {
+			set.Keys = append(set.Keys, rsaJWK("new", &newKey.PublicKey))
+		}
+		_ = json.NewEncoder(w).Encode(set)
+	}))
+	defer server.Close()
+
+	authenticator, err := NewJWTAuthenticator(ctx, &config.JWT{
+		JWKSURL:         server.URL,
+		Issuer:          issuer,
+		Audience:        audience,
+		RefreshInterval: time.Nanosecond,
+	}, slog.Default())
+	assert.NoError(t, err)
+
+	rotated.Store(true)
+	token := signToken(t, jwt.SigningMethodRS256, "new", newKey, validClaims(model.ScopeMessagesRead))
+
+	principal, err := authenticator.Authenticate(ctx, token)
+	assert.NoError(t, err)
+	assert.Equal(t, "user-1", principal.ID)
+	assert.Equal(t, int32(2), requests.Load())
+}
+
+func TestNewJWTAuthenticator_InvalidConfig(t *testing.T) {
+	ctx := context.Background()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NoError(t, err)
+
+	_, err = NewJWTAuthenticator(ctx, &config.JWT{}, slog.Default())
+	assert.Error(t, err)
+
+	_, err = NewJWTAuthenticator(ctx, &config.JWT{
+		JWKSFile: writeKeySet(t, rsaJWK("rsa-1", &key.PublicKey)),
+		Audience: audience,
+	}, slog.Default())
+	assert.ErrorContains(t, err, "issuer and audience are required")
+
+	_, err = NewJWTAuthenticator(ctx, &config.JWT{
+		JWKSFile: writeKeySet(t, rsaJWK("rsa-1", &key.PublicKey)),
+		Issuer:   issuer,
+	}, slog.Default())
+	assert.ErrorContains(t, err, "issuer and audience are required")
+
+	_, err = NewJWTAuthenticator(ctx, &config.JWT{
+		JWKSFile: writeKeySet(t, rsaJWK("rsa-1", &key.PublicKey)),
+		Issuer:   issuer,
+		Audience: audience,
+		Scopes:   map[string][]string{"admins": {"everything"}},
+	}, slog.Default())
+	assert.ErrorContains(t, err, "unknown scope")
+
+	_, err = NewJWTAuthenticator(ctx, &config.JWT{
+		JWKSFile: writeKeySet(t),
+		Issuer:   issuer,
+		Audience: audience,
+	}, slog.Default())
+	assert.ErrorIs(t, err, ErrNoSigningKeys)
+}
@@ -2,11 +2,55 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"messaging-system/config"
+	"strconv"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
+var ErrCacheMiss = errors.New("cache miss")
+
+const tenantKeyPrefix = "tenant:"
+
+// slidingWindowScript keeps the hits of the last window in a sorted set scored by their time in
+// milliseconds. A hit is only added while fewer than limit hits are in the window, it returns whether
+// the hit was added, the hits in the window and the time of the oldest one.
+var slidingWindowScript = redis.NewScript(`
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+  redis.call('ZADD', KEYS[1], now, ARGV[4])
+  redis.call('PEXPIRE', KEYS[1], window)
+  count = count + 1
+  allowed = 1
+end
+local oldest = now
+local first = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if first[2] then
+  oldest = tonumber(first[2])
+end
+return {allowed, count, oldest}
+`)
+
+// WindowUsage is the state of a sliding window after a hit.
+type WindowUsage struct {
+	Allowed bool
+	Count   int
+	// Oldest is the time of the oldest hit in the window, the window frees up a slot once it leaves it.
+	Oldest time.Time
+}
+
+// TenantKey scopes a key to a tenant, every key holding tenant data is built with it.
+func TenantKey(tenantID, key string) string {
+	return tenantKeyPrefix + tenantID + ":" + key
+}
+
 type Cache struct {
 	client      *redis.Client
 	cacheConfig *config.Redis
@@ -28,3 +72,75 @@ func NewRedis(conf *config.Redis) *Cache {
 func (c *Cache) Set(ctx context.Context, key string, value interface{}) error {
 	return c.client.Set(ctx, key, value, c.cacheConfig.TTL).Err()
 }
+
+// SetWithTTL stores the value with its own time to live instead of the configured one.
+func (c *Cache) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return c.client.Set(ctx, key, value, ttl).Err()
+}
+
+// SetNX stores the value for ttl only if the key does not exist yet and reports whether it was stored.
+func (c *Cache) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	return c.client.SetNX(ctx, key, value, ttl).Result()
+}
+
+func (c *Cache) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := c.client.Get(ctx, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrCacheMiss
+	}
+	return value, err
+}
+
+func (c *Cache) Delete(ctx context.Context, key string) error {
+	return c.client.Del(ctx, key).Err()
+}
+
+// Increment adds one to the counter at key and returns the new value, the counter expires after ttl.
+func (c *Cache) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	pipe := c.client.TxPipeline()
+	count := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return count.Val(), nil
+}
+
+func (c *Cache) Decrement(ctx context.Context, key string) error {
+	return c.client.Decr(ctx, key).Err()
+}
+
+// IncrementBy adds n to the counter at key and returns the new value, the counter expires after ttl.
+func (c *Cache) IncrementBy(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error) {
+	pipe := c.client.TxPipeline()
+	count := pipe.IncrBy(ctx, key, n)
+	pipe.Expire(ctx, key, ttl)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+	return count.Val(), nil
+}
+
+func (c *Cache) DecrementBy(ctx context.Context, key string, n int64) error {
+	return c.client.DecrBy(ctx, key, n).Err()
+}
+
+// SlidingWindow counts a hit at now against the hits of the last window at key, the hit is rejected
+// once limit hits are in the window. Rejected hits are not counted.
+func (c *Cache) SlidingWindow(ctx context.Context, key string, now time.Time, window time.Duration,
+	limit int) (*WindowUsage, error) {
+	nowMillis := now.UnixMilli()
+	// the nanoseconds keep hits of the same millisecond apart
+	member := strconv.FormatInt(now.UnixNano(), 10)
+	result, err := slidingWindowScript.Run(ctx, c.client, []string{key},
+		nowMillis, window.Milliseconds(), limit, member).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	return &WindowUsage{
+		Allowed: result[0] == 1,
+		Count:   int(result[1]),
+		Oldest:  time.UnixMilli(result[2]).UTC(),
+	}, nil
+}
@@ -1,9 +1,12 @@
 package client
 
 import (
+	"context"
 	"log/slog"
 	"messaging-system/app/dto"
+	"messaging-system/app/requestid"
 	"messaging-system/config"
+	"net/url"
 
 	"github.com/go-resty/resty/v2"
 )
@@ -13,9 +16,10 @@ const (
 )
 
 type Client struct {
-	resty  *resty.Client
-	conf   *config.Client
-	logger *slog.Logger
+	resty    *resty.Client
+	conf     *config.Client
+	provider string
+	logger   *slog.Logger
 }
 
 func NewClient(conf *config.Client, logger *slog.Logger) *Client {
@@ -26,30 +30,45 @@ func NewClient(conf *config.Client, logger *slog.Logger) *Client {
 
 	logger.Info("Client initialized", "URL", conf.URL)
 	return &Client{
-		resty:  restyClient,
-		conf:   conf,
-		logger: logger,
+		resty:    restyClient,
+		conf:     conf,
+		provider: providerName(conf.URL),
+		logger:   logger,
 	}
 }
 
-func (c *Client) SendMessage(request *dto.MessageRequest) (*dto.MessageResponse, error) {
+// SendMessage posts the message to the provider webhook, forwarding the request ID carried by ctx so the
+// provider side can be correlated with the request that created the message.
+func (c *Client) SendMessage(ctx context.Context, request *dto.MessageRequest) (*dto.MessageResponse, error) {
 	if validErr := request.Validate(); validErr != nil {
-		c.logger.Error("Invalid message request", "error", validErr)
+		c.logger.ErrorContext(ctx, "Invalid message request", "error", validErr)
 		return nil, validErr
 	}
 
 	response := &dto.MessageResponse{}
-	res, err := c.resty.R().
+	req := c.resty.R().
 		SetHeader("Content-Type", "application/json").
 		SetBody(request).
-		SetResult(response).
-		Post("/fa36ba41-1794-4295-8053-d198499d2fc9")
+		SetResult(response)
+	if id := requestid.FromContext(ctx); id != "" {
+		req.SetHeader(requestid.Header, id)
+	}
 
+	res, err := req.Post("/fa36ba41-1794-4295-8053-d198499d2fc9")
 	if err != nil {
-		c.logger.Error("Error sending message", "error", err)
+		c.logger.ErrorContext(ctx, "Error sending message", "error", err)
 		return nil, err
 	}
 
-	c.logger.Info("Message successfully sent", "StatusCode", res.StatusCode(), "Response", response)
+	c.logger.InfoContext(ctx, "Message successfully sent", "StatusCode", res.StatusCode(), "Response", response)
+	response.Provider = c.provider
 	return response, nil
 }
+
+// providerName identifies a provider by the host of its URL in reports.
+func providerName(rawURL string) string {
+	if parsed, err := url.Parse(rawURL); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return rawURL
+}
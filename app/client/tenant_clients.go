@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"log/slog"
+	"messaging-system/app/dto"
+	"messaging-system/config"
+	"strings"
+)
+
+// TenantClients sends every message with the provider credentials of its tenant, tenants without
+// credentials of their own share the default client.
+type TenantClients struct {
+	shared  *Client
+	tenants map[string]*Client
+}
+
+func NewTenantClients(conf *config.Client, tenants config.Tenants, logger *slog.Logger) *TenantClients {
+	clients := make(map[string]*Client, len(tenants))
+	for tenantID, tenant := range tenants {
+		if tenant == nil || tenant.Client == nil {
+			continue
+		}
+
+		tenantConf := *conf
+		if tenant.Client.URL != "" {
+			tenantConf.URL = tenant.Client.URL
+		}
+		if tenant.Client.ApiKey != "" {
+			tenantConf.ApiKey = tenant.Client.ApiKey
+		}
+		clients[strings.ToLower(tenantID)] = NewClient(&tenantConf, logger.With("tenantId", tenantID))
+	}
+
+	return &TenantClients{
+		shared:  NewClient(conf, logger),
+		tenants: clients,
+	}
+}
+
+func (t *TenantClients) SendMessage(ctx context.Context, tenantID string,
+	request *dto.MessageRequest) (*dto.MessageResponse, error) {
+	if client, ok := t.tenants[strings.ToLower(tenantID)]; ok {
+		return client.SendMessage(ctx, request)
+	}
+	return t.shared.SendMessage(ctx, request)
+}
@@ -1,13 +1,213 @@
 package dto
 
+import "time"
+
+const (
+	PriorityLow    = -1
+	PriorityNormal = 0
+	PriorityHigh   = 1
+)
+
+const (
+	ReportGroupStatus   = "status"
+	ReportGroupProvider = "provider"
+	ReportGroupCampaign = "campaign"
+
+	ReportIntervalHour = "hour"
+	ReportIntervalDay  = "day"
+)
+
 type MessageRequest struct {
-	To      string `json:"to"`
-	Content string `json:"content"`
+	To        string     `json:"to"`
+	Content   string     `json:"content"`
+	Priority  int        `json:"priority,omitempty"`
+	Category  string     `json:"category,omitempty"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// CallbackURL receives a signed event whenever the status of the message changes, its host must be one
+	// of the callback hosts of the tenant.
+	CallbackURL string `json:"callbackUrl,omitempty"`
+
+	// TemplateID renders the content from a template instead of sending Content as is.
+	TemplateID string            `json:"templateId,omitempty"`
+	Params     map[string]string `json:"params,omitempty"`
+	Locale     string            `json:"locale,omitempty"`
+}
+
+type TemplateRequest struct {
+	Name          string            `json:"name"`
+	DefaultLocale string            `json:"defaultLocale"`
+	Locales       map[string]string `json:"locales"`
+}
+
+type SuppressionRequest struct {
+	PhoneNumber string `json:"phoneNumber"`
+	Reason      string `json:"reason,omitempty"`
+}
+
+type SuppressionImportRequest struct {
+	PhoneNumbers []string `json:"phoneNumbers"`
+	Reason       string   `json:"reason,omitempty"`
+}
+
+type SuppressionImportResponse struct {
+	Imported int                  `json:"imported"`
+	Invalid  []InvalidPhoneNumber `json:"invalid"`
+}
+
+// CampaignRequest sends a template to every phone number of the audience.
+type CampaignRequest struct {
+	Name       string            `json:"name"`
+	Audience   []string          `json:"audience"`
+	TemplateID string            `json:"templateId"`
+	Params     map[string]string `json:"params,omitempty"`
+	Locale     string            `json:"locale,omitempty"`
+
+	// ScheduledAt holds the messages back until the given time, they are sent right away when empty.
+	ScheduledAt *time.Time `json:"scheduledAt,omitempty"`
+	// Throttle is the maximum number of messages sent per minute, unlimited when zero.
+	Throttle int `json:"throttle,omitempty"`
+}
+
+type InvalidPhoneNumber struct {
+	PhoneNumber string `json:"phoneNumber"`
+	Error       string `json:"error"`
+}
+
+// Formats of a contact upload.
+const (
+	ContactFormatCSV    = "csv"
+	ContactFormatNDJSON = "ndjson"
+)
+
+type ContactListRequest struct {
+	Name string `json:"name"`
+}
+
+// ContactImportResponse reports an upload of contacts, at most MaxReportedRows invalid rows are listed.
+type ContactImportResponse struct {
+	Rows         int          `json:"rows"`
+	Imported     int          `json:"imported"`
+	Duplicates   int          `json:"duplicates"`
+	InvalidCount int          `json:"invalidCount"`
+	Invalid      []InvalidRow `json:"invalid"`
+}
+
+type InvalidRow struct {
+	Row         int    `json:"row"`
+	PhoneNumber string `json:"phoneNumber,omitempty"`
+	Error       string `json:"error"`
+}
+
+// ListSendRequest sends the same content to every member of a contact list.
+type ListSendRequest struct {
+	Content     string `json:"content"`
+	Priority    int    `json:"priority,omitempty"`
+	Category    string `json:"category,omitempty"`
+	CallbackURL string `json:"callbackUrl,omitempty"`
+}
+
+// ListSendResponse reports the messages created for a list, at most MaxReportedRows rejected members are
+// listed. Skipped members were not sent to because the daily quota of the tenant ran out.
+type ListSendResponse struct {
+	Queued        int                  `json:"queued"`
+	Skipped       int                  `json:"skipped"`
+	RejectedCount int                  `json:"rejectedCount"`
+	Rejected      []InvalidPhoneNumber `json:"rejected"`
+}
+
+// ReportRequest selects the messages created within [From, To) and the dimensions they are counted by.
+type ReportRequest struct {
+	From    time.Time
+	To      time.Time
+	GroupBy []string
+	// Interval buckets the counts by the hour or day the messages were created, time series only.
+	Interval string
+}
+
+// ProcessorState is the state of the message processor recorded in the audit log.
+type ProcessorState struct {
+	Running bool `json:"running"`
+}
+
+// AuditQuery filters the audit log of a tenant to entries created within [From, To), zero times leave the
+// range open and empty filters match every entry.
+type AuditQuery struct {
+	Actions []string
+	ActorID string
+	From    time.Time
+	To      time.Time
+	Limit   int
+}
+
+// InboundRequest is a mobile originated message forwarded by the SMS provider.
+type InboundRequest struct {
+	MessageID string `json:"messageId,omitempty"`
+	From      string `json:"from"`
+	Text      string `json:"text"`
+}
+
+type InboundResponse struct {
+	Message          string `json:"message"`
+	InboundMessageID string `json:"inboundMessageId"`
+	Keyword          string `json:"keyword,omitempty"`
+}
+
+const (
+	ReceiptDelivered = "delivered"
+	ReceiptFailed    = "failed"
+)
+
+// ReceiptRequest is a delivery receipt of the SMS provider, MessageID is the id the provider accepted the
+// message with.
+type ReceiptRequest struct {
+	MessageID string `json:"messageId"`
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+}
+
+type ReceiptResponse struct {
+	Message   string `json:"message"`
+	MessageID string `json:"messageId,omitempty"`
+}
+
+const (
+	DirectionInbound  = "inbound"
+	DirectionOutbound = "outbound"
+)
+
+// ConversationEntry is an inbound or outbound message of a conversation with one phone number.
+type ConversationEntry struct {
+	ID        string    `json:"id"`
+	Direction string    `json:"direction"`
+	Content   string    `json:"content"`
+	Status    string    `json:"status,omitempty"`
+	Keyword   string    `json:"keyword,omitempty"`
+	ReplyTo   string    `json:"replyTo,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+type APIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// TenantID creates the key for another tenant, only the bootstrap key may set it.
+	TenantID string `json:"tenantId,omitempty"`
+}
+
+// APIKeyResponse carries the plaintext key, it is only returned once when the key is created.
+type APIKeyResponse struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	TenantID string   `json:"tenantId"`
+	Key      string   `json:"key,omitempty"`
+	Scopes   []string `json:"scopes"`
 }
 
 type MessageResponse struct {
 	Message   string `json:"message"`
 	MessageID string `json:"messageId"`
+	// Provider is the host of the provider that accepted the message, it is set by the client.
+	Provider string `json:"-"`
 }
 
 type SuccessResponse struct {
@@ -2,27 +2,207 @@ package dto
 
 import (
 	"errors"
-	"regexp"
+	"fmt"
+	"messaging-system/app/phone"
+	"messaging-system/app/sms"
+	"messaging-system/app/template"
+	"net/url"
+	"slices"
+	"time"
 )
 
-const MaxMessageLength = 100
+// DefaultMaxSegments is used when no maximum number of SMS segments per message is configured.
+const DefaultMaxSegments = 3
 
-// for TR phone numbers
-var phoneRegex = regexp.MustCompile(`^\+905\d{9}$`)
+// MaxImportSize is the maximum number of phone numbers of a single bulk import.
+const MaxImportSize = 10000
 
-func (m *MessageRequest) Validate() error {
-	if len(m.To) == 0 {
+// MaxReportedRows is the maximum number of invalid rows listed in the report of an upload.
+const MaxReportedRows = 1000
+
+// ValidatePhoneNumber checks that a normalized phone number is set and in E.164 format.
+func ValidatePhoneNumber(phoneNumber string) error {
+	if len(phoneNumber) == 0 {
 		return errors.New("phone number is required")
 	}
-	if !phoneRegex.MatchString(m.To) {
-		return errors.New("invalid phone number format, expected +905xxxxxxxxx")
+	if !phone.IsE164(phoneNumber) {
+		return errors.New("invalid phone number format, expected E.164 (+<country code><number>)")
+	}
+
+	return nil
+}
+
+func (m *MessageRequest) Validate() error {
+	if err := ValidatePhoneNumber(m.To); err != nil {
+		return err
 	}
 
 	if len(m.Content) == 0 {
 		return errors.New("content cannot be empty")
 	}
-	if len(m.Content) > MaxMessageLength {
-		return errors.New("content exceeds maximum length of 500 characters")
+
+	if m.Priority < PriorityLow || m.Priority > PriorityHigh {
+		return errors.New("invalid priority, expected -1 (low), 0 (normal) or 1 (high)")
+	}
+
+	if m.ExpiresAt != nil && !m.ExpiresAt.After(time.Now()) {
+		return errors.New("expiresAt must be in the future")
+	}
+
+	if m.CallbackURL != "" {
+		callback, err := url.Parse(m.CallbackURL)
+		if err != nil || (callback.Scheme != "http" && callback.Scheme != "https") || callback.Host == "" {
+			return errors.New("invalid callbackUrl, expected an absolute http or https URL")
+		}
+	}
+
+	return nil
+}
+
+// ValidateLength checks that the content fits into maxSegments SMS segments.
+func (m *MessageRequest) ValidateLength(maxSegments int) error {
+	if maxSegments <= 0 {
+		maxSegments = DefaultMaxSegments
+	}
+
+	info := sms.Analyze(m.Content)
+	if info.Segments > maxSegments {
+		return fmt.Errorf("content needs %d %s segments, maximum is %d", info.Segments, info.Encoding, maxSegments)
+	}
+
+	return nil
+}
+
+func (t *TemplateRequest) Validate() error {
+	if len(t.Name) == 0 {
+		return errors.New("template name is required")
+	}
+
+	if len(t.Locales) == 0 {
+		return errors.New("at least one locale is required")
+	}
+	if _, ok := t.Locales[t.DefaultLocale]; !ok {
+		return errors.New("default locale must be one of the locales")
+	}
+
+	for locale, body := range t.Locales {
+		if len(body) == 0 {
+			return fmt.Errorf("template body of locale %s cannot be empty", locale)
+		}
+		if _, err := template.Placeholders(body); err != nil {
+			return fmt.Errorf("locale %s: %w", locale, err)
+		}
+	}
+
+	return nil
+}
+
+func (s *SuppressionImportRequest) Validate() error {
+	if len(s.PhoneNumbers) == 0 {
+		return errors.New("at least one phone number is required")
+	}
+	if len(s.PhoneNumbers) > MaxImportSize {
+		return fmt.Errorf("at most %d phone numbers can be imported at once", MaxImportSize)
+	}
+
+	return nil
+}
+
+func (c *CampaignRequest) Validate() error {
+	if len(c.Name) == 0 {
+		return errors.New("campaign name is required")
+	}
+	if len(c.TemplateID) == 0 {
+		return errors.New("templateId is required")
+	}
+
+	if len(c.Audience) == 0 {
+		return errors.New("at least one phone number is required")
+	}
+	if len(c.Audience) > MaxImportSize {
+		return fmt.Errorf("a campaign can have at most %d phone numbers", MaxImportSize)
+	}
+
+	if c.Throttle < 0 {
+		return errors.New("throttle cannot be negative")
+	}
+
+	return nil
+}
+
+func (l *ContactListRequest) Validate() error {
+	if len(l.Name) == 0 {
+		return errors.New("list name is required")
+	}
+
+	return nil
+}
+
+func (l *ListSendRequest) Validate() error {
+	if len(l.Content) == 0 {
+		return errors.New("content cannot be empty")
+	}
+
+	if l.Priority < PriorityLow || l.Priority > PriorityHigh {
+		return errors.New("invalid priority, expected -1 (low), 0 (normal) or 1 (high)")
+	}
+
+	return nil
+}
+
+func (r *ReportRequest) Validate() error {
+	if !r.From.Before(r.To) {
+		return errors.New("from must be before to")
+	}
+
+	for i, dimension := range r.GroupBy {
+		if !slices.Contains([]string{ReportGroupStatus, ReportGroupProvider, ReportGroupCampaign}, dimension) {
+			return fmt.Errorf("invalid groupBy %q, expected status, provider or campaign", dimension)
+		}
+		if slices.Contains(r.GroupBy[:i], dimension) {
+			return fmt.Errorf("groupBy %q is given twice", dimension)
+		}
+	}
+
+	if r.Interval != "" && r.Interval != ReportIntervalHour && r.Interval != ReportIntervalDay {
+		return errors.New("invalid interval, expected hour or day")
+	}
+
+	return nil
+}
+
+func (i *InboundRequest) Validate() error {
+	if len(i.From) == 0 {
+		return errors.New("sender phone number is required")
+	}
+
+	return nil
+}
+
+func (r *ReceiptRequest) Validate() error {
+	if len(r.MessageID) == 0 {
+		return errors.New("messageId is required")
+	}
+
+	if r.Status != ReceiptDelivered && r.Status != ReceiptFailed {
+		return fmt.Errorf("invalid status, expected %s or %s", ReceiptDelivered, ReceiptFailed)
+	}
+
+	return nil
+}
+
+func (a *APIKeyRequest) Validate(allowedScopes []string) error {
+	if len(a.Name) == 0 {
+		return errors.New("key name is required")
+	}
+
+	if len(a.Scopes) == 0 {
+		return errors.New("at least one scope is required")
+	}
+	for _, scope := range a.Scopes {
+		if !slices.Contains(allowedScopes, scope) {
+			return fmt.Errorf("unknown scope %q", scope)
+		}
 	}
 
 	return nil
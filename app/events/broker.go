@@ -0,0 +1,116 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	TypeTickStarted   = "tick.started"
+	TypeMessageSent   = "message.sent"
+	TypeSendFailed    = "message.failed"
+	TypeSuppressed    = "message.suppressed"
+	TypeExpired       = "message.expired"
+	TypeBatchFinished = "batch.finished"
+
+	// StatusFailed marks send failures, the message stays unsent and is retried until its attempts run out.
+	StatusFailed = "failed"
+
+	// SubscriberBuffer is the number of events buffered per subscriber, events for
+	// subscribers that fall further behind are dropped.
+	SubscriberBuffer = 64
+)
+
+// Event is a processor activity event.
+type Event struct {
+	Type        string    `json:"type"`
+	TenantID    string    `json:"tenantId,omitempty"`
+	MessageID   string    `json:"messageId,omitempty"`
+	PhoneNumber string    `json:"phoneNumber,omitempty"`
+	Status      string    `json:"status,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Count       int       `json:"count,omitempty"`
+	Time        time.Time `json:"time"`
+}
+
+// Filter selects events by tenant, status and phone number, empty fields match every event. Processor
+// events that belong to no tenant, like tick.started, pass the tenant filter, they carry no message data;
+// the processor publishes batch.finished per tenant with the count of its messages, and a run that failed
+// to fetch its batch only carries a generic error.
+type Filter struct {
+	TenantID    string
+	Status      string
+	PhoneNumber string
+}
+
+func (f Filter) Matches(event Event) bool {
+	if f.TenantID != "" && event.TenantID != "" && f.TenantID != event.TenantID {
+		return false
+	}
+	if f.Status != "" && f.Status != event.Status {
+		return false
+	}
+	if f.PhoneNumber != "" && f.PhoneNumber != event.PhoneNumber {
+		return false
+	}
+	return true
+}
+
+type subscriber struct {
+	filter Filter
+	events chan Event
+}
+
+// Broker fans published events out to subscribers without ever blocking the publisher.
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[*subscriber]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{subscribers: make(map[*subscriber]struct{})}
+}
+
+// Publish sends the event to every matching subscriber.
+func (b *Broker) Publish(event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subscribers {
+		if !sub.filter.Matches(event) {
+			continue
+		}
+
+		select {
+		case sub.events <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel of matching events and a function that cancels the
+// subscription and closes the channel.
+func (b *Broker) Subscribe(filter Filter) (<-chan Event, func()) {
+	sub := &subscriber{
+		filter: filter,
+		events: make(chan Event, SubscriberBuffer),
+	}
+
+	b.mu.Lock()
+	b.subscribers[sub] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return sub.events, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, sub)
+			b.mu.Unlock()
+			close(sub.events)
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter_Matches(t *testing.T) {
+	event := Event{Type: TypeMessageSent, TenantID: "retail", Status: "sent", PhoneNumber: "+905551112233"}
+
+	assert.True(t, Filter{}.Matches(event))
+	assert.True(t, Filter{Status: "sent", PhoneNumber: "+905551112233"}.Matches(event))
+	assert.False(t, Filter{Status: "failed"}.Matches(event))
+	assert.False(t, Filter{PhoneNumber: "+905550000000"}.Matches(event))
+	assert.False(t, Filter{Status: "sent"}.Matches(Event{Type: TypeTickStarted}))
+	assert.True(t, Filter{TenantID: "retail"}.Matches(event))
+	assert.False(t, Filter{TenantID: "logistics"}.Matches(event))
+	assert.True(t, Filter{TenantID: "logistics"}.Matches(Event{Type: TypeTickStarted}))
+}
+
+func TestBroker(t *testing.T) {
+	broker := NewBroker()
+
+	t.Run("delivers matching events", func(t *testing.T) {
+		all, cancelAll := broker.Subscribe(Filter{})
+		defer cancelAll()
+		failed, cancelFailed := broker.Subscribe(Filter{Status: "failed"})
+		defer cancelFailed()
+
+		broker.Publish(Event{Type: TypeMessageSent, Status: "sent"})
+		broker.Publish(Event{Type: TypeSendFailed, Status: "failed"})
+
+		assert.Len(t, all, 2)
+		assert.Len(t, failed, 1)
+		event := <-failed
+		assert.Equal(t, TypeSendFailed, event.Type)
+		assert.False(t, event.Time.IsZero())
+	})
+
+	t.Run("slow subscriber does not block publisher", func(t *testing.T) {
+		events, cancel := broker.Subscribe(Filter{})
+		defer cancel()
+
+		for i := 0; i < SubscriberBuffer+10; i++ {
+			broker.Publish(Event{Type: TypeTickStarted})
+		}
+		assert.Len(t, events, SubscriberBuffer)
+	})
+
+	t.Run("cancel closes the channel once", func(t *testing.T) {
+		events, cancel := broker.Subscribe(Filter{})
+		cancel()
+		cancel()
+
+		_, ok := <-events
+		assert.False(t, ok)
+		broker.Publish(Event{Type: TypeTickStarted})
+	})
+}
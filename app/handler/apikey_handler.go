@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/model"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IAPIKeyService interface {
+	CreateAPIKey(ctx context.Context, principal *model.Principal, request *dto.APIKeyRequest) (*dto.APIKeyResponse, error)
+	GetAPIKeys(ctx context.Context, tenantID string) ([]model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, tenantID string, keyID primitive.ObjectID) error
+}
+
+type APIKeyHandler struct {
+	service IAPIKeyService
+	audit   IAuditRecorder
+}
+
+func NewAPIKeyHandler(service IAPIKeyService, audit IAuditRecorder) *APIKeyHandler {
+	return &APIKeyHandler{
+		service: service,
+		audit:   audit,
+	}
+}
+
+func (h *APIKeyHandler) RegisterRoutes(server *fiber.App) {
+	keys := server.Group("/api-keys", middleware.RequireScope(model.ScopeProcessorAdmin))
+	keys.Post("/", h.CreateAPIKey)
+	keys.Get("/", h.GetAPIKeys)
+	keys.Delete("/:id", h.RevokeAPIKey)
+}
+
+// CreateAPIKey godoc
+// @Summary Create API key
+// @Description Creates an API key with the given scopes, the key is only returned in this response
+// @Tags api-keys
+// @Accept json
+// @Produce json
+// @Param request body dto.APIKeyRequest true "Key name and scopes (messages:write, messages:read, processor:admin)"
+// @Success 201 {object} dto.APIKeyResponse "Created key"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request body or scope"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error or action not recorded in the audit log"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	ctx := c.Context()
+	request := &dto.APIKeyRequest{}
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid request body",
+		})
+	}
+
+	key, err := h.service.CreateAPIKey(ctx, middleware.PrincipalFrom(c), request)
+	if errors.Is(err, model.ErrInvalidRequest) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	// the audit log keeps the key without its secret
+	event := auditEvent(c, model.AuditActionAPIKeyCreate, key.ID)
+	after := *key
+	after.Key = ""
+	event.After = &after
+	if err := h.audit.Record(ctx, event); err != nil {
+		return auditError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(key)
+}
+
+// GetAPIKeys godoc
+// @Summary List API keys
+// @Description Retrieves all API keys including revoked ones, keys are identified by their prefix
+// @Tags api-keys
+// @Produce json
+// @Success 200 {array} model.APIKey "List of API keys"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api-keys [get]
+func (h *APIKeyHandler) GetAPIKeys(c *fiber.Ctx) error {
+	ctx := c.Context()
+	keys, err := h.service.GetAPIKeys(ctx, middleware.TenantFrom(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(keys)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke API key
+// @Description Revokes an API key, requests using it are rejected from now on
+// @Tags api-keys
+// @Produce json
+// @Param id path string true "API key ID"
+// @Success 200 {object} dto.SuccessResponse "API key revoked"
+// @Failure 400 {object} dto.ErrorResponse "Invalid API key ID"
+// @Failure 404 {object} dto.ErrorResponse "API key not found or already revoked"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error or action not recorded in the audit log"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	ctx := c.Context()
+	keyID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid API key id",
+		})
+	}
+
+	err = h.service.RevokeAPIKey(ctx, middleware.TenantFrom(c), keyID)
+	if errors.Is(err, model.ErrNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error: "API key not found",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	if err := h.audit.Record(ctx, auditEvent(c, model.AuditActionAPIKeyRevoke, keyID.Hex())); err != nil {
+		return auditError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.SuccessResponse{
+		Message: "API key revoked",
+	})
+}
@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAPIKeyHandler(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockIAPIKeyService(mockController)
+	mockAudit := mocks.NewMockIAuditRecorder(mockController)
+
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewAPIKeyHandler(mockService, mockAudit).RegisterRoutes(app)
+
+	t.Run("create key", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			CreateAPIKey(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&dto.APIKeyResponse{ID: "key-id", Name: "billing", Key: "msk_abc"}, nil)
+		mockAudit.
+			EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, event *model.AuditEvent) error {
+				assert.Equal(t, model.AuditActionAPIKeyCreate, event.Action)
+				assert.Equal(t, "key-id", event.Target)
+				assert.Equal(t, &dto.APIKeyResponse{ID: "key-id", Name: "billing"}, event.After)
+				return nil
+			})
+
+		req := httptest.NewRequest(http.MethodPost, "/api-keys",
+			strings.NewReader(`{"name":"billing","scopes":["messages:read"]}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+		body, _ := io.ReadAll(resp.Body)
+		assert.Contains(t, string(body), "msk_abc")
+	})
+
+	t.Run("create key not recorded", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			CreateAPIKey(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&dto.APIKeyResponse{ID: "key-id", Name: "billing", Key: "msk_abc"}, nil)
+		mockAudit.
+			EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("mongo down"))
+
+		req := httptest.NewRequest(http.MethodPost, "/api-keys",
+			strings.NewReader(`{"name":"billing","scopes":["messages:read"]}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+
+	t.Run("create key with invalid scope", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			CreateAPIKey(gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, fmt.Errorf("%w: unknown scope", model.ErrInvalidRequest))
+
+		req := httptest.NewRequest(http.MethodPost, "/api-keys",
+			strings.NewReader(`{"name":"billing","scopes":["messages:delete"]}`))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("list keys", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			GetAPIKeys(gomock.Any(), model.DefaultTenantID).
+			Return([]model.APIKey{{Name: "billing"}}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api-keys", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("revoke key", func(t *testing.T) {
+		keyID := primitive.NewObjectID()
+		mockService.
+			EXPECT().
+			RevokeAPIKey(gomock.Any(), model.DefaultTenantID, keyID).
+			Return(nil)
+		mockAudit.
+			EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, event *model.AuditEvent) error {
+				assert.Equal(t, model.AuditActionAPIKeyRevoke, event.Action)
+				assert.Equal(t, keyID.Hex(), event.Target)
+				assert.Nil(t, event.Before)
+				return nil
+			})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/api-keys/"+keyID.Hex(), nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("revoke unknown key", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			RevokeAPIKey(gomock.Any(), model.DefaultTenantID, gomock.Any()).
+			Return(model.ErrNotFound)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/api-keys/"+primitive.NewObjectID().Hex(), nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("revoke with invalid id", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/api-keys/abc", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+}
+
+func TestAPIKeyHandler_RequiresAdminScope(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		middleware.SetPrincipal(c, &model.Principal{Scopes: []string{model.ScopeMessagesWrite}})
+		return c.Next()
+	})
+	NewAPIKeyHandler(mocks.NewMockIAPIKeyService(mockController), mocks.NewMockIAuditRecorder(mockController)).
+		RegisterRoutes(app)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/api-keys", nil))
+
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
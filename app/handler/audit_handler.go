@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/model"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// AuditTargetProcessor is the target of processor start and stop entries. The processor is shared by
+// every tenant, so its entries are recorded in the default tenant and readable by all of them.
+const AuditTargetProcessor = "processor"
+
+type IAuditReader interface {
+	GetAuditEntries(ctx context.Context, tenantID string, query *dto.AuditQuery) ([]model.AuditEntry, error)
+}
+
+type AuditHandler struct {
+	reader IAuditReader
+}
+
+func NewAuditHandler(reader IAuditReader) *AuditHandler {
+	return &AuditHandler{reader: reader}
+}
+
+func (h *AuditHandler) RegisterRoutes(server *fiber.App) {
+	server.Get("/audit", middleware.RequireScope(model.ScopeProcessorAdmin), h.GetAuditEntries)
+	server.Get("/processor/audit", middleware.RequireScope(model.ScopeProcessorAdmin), h.GetProcessorAuditEntries)
+}
+
+// GetAuditEntries godoc
+// @Summary Get audit log
+// @Description Retrieves the administrative actions of the caller's tenant newest first, with the state of their
+// @Description target before and after the action
+// @Tags audit
+// @Produce json
+// @Param action query string false "Comma separated actions, e.g. apikey.create,template.delete"
+// @Param actorId query string false "ID of the API key or user that performed the action"
+// @Param from query string false "Start of the range, RFC 3339 or YYYY-MM-DD"
+// @Param to query string false "End of the range, exclusive, RFC 3339 or YYYY-MM-DD"
+// @Param limit query int false "Number of entries to retrieve" default(50)
+// @Success 200 {array} model.AuditEntry "Audit entries"
+// @Failure 400 {object} dto.ErrorResponse "Invalid range or limit parameter"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /audit [get]
+func (h *AuditHandler) GetAuditEntries(c *fiber.Ctx) error {
+	query, err := auditQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	if actions := c.Query("action"); actions != "" {
+		for _, action := range strings.Split(actions, ",") {
+			query.Actions = append(query.Actions, strings.ToLower(strings.TrimSpace(action)))
+		}
+	}
+
+	return h.sendAuditEntries(c, middleware.TenantFrom(c), query)
+}
+
+// GetProcessorAuditEntries godoc
+// @Summary Get processor audit log
+// @Description Retrieves who started or stopped the message processor, newest first. The processor is shared by every
+// @Description tenant, so every tenant sees the same entries.
+// @Tags processor
+// @Produce json
+// @Param actorId query string false "ID of the API key or user that performed the action"
+// @Param from query string false "Start of the range, RFC 3339 or YYYY-MM-DD"
+// @Param to query string false "End of the range, exclusive, RFC 3339 or YYYY-MM-DD"
+// @Param limit query int false "Number of entries to retrieve" default(50)
+// @Success 200 {array} model.AuditEntry "Audit entries"
+// @Failure 400 {object} dto.ErrorResponse "Invalid range or limit parameter"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /processor/audit [get]
+func (h *AuditHandler) GetProcessorAuditEntries(c *fiber.Ctx) error {
+	query, err := auditQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	query.Actions = []string{model.AuditActionProcessorStart, model.AuditActionProcessorStop}
+
+	return h.sendAuditEntries(c, model.DefaultTenantID, query)
+}
+
+func (h *AuditHandler) sendAuditEntries(c *fiber.Ctx, tenantID string, query *dto.AuditQuery) error {
+	entries, err := h.reader.GetAuditEntries(c.Context(), tenantID, query)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(entries)
+}
+
+// auditQuery reads the actor, range and limit shared by the audit log endpoints from the query.
+func auditQuery(c *fiber.Ctx) (*dto.AuditQuery, error) {
+	query := &dto.AuditQuery{ActorID: c.Query("actorId")}
+
+	var err error
+	if query.From, err = queryTime(c.Query("from")); err != nil {
+		return nil, errors.New("invalid from parameter")
+	}
+	if query.To, err = queryTime(c.Query("to")); err != nil {
+		return nil, errors.New("invalid to parameter")
+	}
+	if query.Limit, err = strconv.Atoi(c.Query("limit", "50")); err != nil || query.Limit <= 0 {
+		return nil, errors.New("invalid limit parameter")
+	}
+
+	return query, nil
+}
+
+// auditEvent starts the audit log event of an action performed by the caller on target.
+func auditEvent(c *fiber.Ctx, action, target string) *model.AuditEvent {
+	return &model.AuditEvent{
+		Action:    action,
+		Principal: middleware.PrincipalFrom(c),
+		SourceIP:  c.IP(),
+		RequestID: middleware.RequestIDFrom(c),
+		Target:    target,
+	}
+}
+
+// auditError answers an action that was performed but could not be recorded in the audit log.
+func auditError(c *fiber.Ctx, err error) error {
+	return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+		Error: "action performed but not recorded in the audit log: " + err.Error(),
+	})
+}
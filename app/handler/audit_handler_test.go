@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"fmt"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditHandler_GetAuditEntries(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockReader := mocks.NewMockIAuditReader(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewAuditHandler(mockReader).RegisterRoutes(app)
+
+	t.Run("invalid limit query param", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/audit?limit=abc", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid from query param", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/audit?from=yesterday", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("audit log returns error", func(t *testing.T) {
+		mockReader.
+			EXPECT().
+			GetAuditEntries(gomock.Any(), model.DefaultTenantID, &dto.AuditQuery{Limit: 50}).
+			Return(nil, fmt.Errorf("mongo down"))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/audit", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+
+	t.Run("successful retrieval of filtered entries", func(t *testing.T) {
+		mockReader.
+			EXPECT().
+			GetAuditEntries(gomock.Any(), model.DefaultTenantID, &dto.AuditQuery{
+				Actions: []string{model.AuditActionTemplateDelete, model.AuditActionAPIKeyRevoke},
+				ActorID: "key-id",
+				From:    time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC),
+				To:      time.Date(2024, 5, 2, 0, 0, 0, 0, time.UTC),
+				Limit:   limit,
+			}).
+			Return([]model.AuditEntry{{Action: model.AuditActionTemplateDelete, ActorID: "key-id"}}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, fmt.Sprintf(
+			"/audit?action=template.delete,%%20apikey.revoke&actorId=key-id&from=2024-05-01&to=2024-05-02&limit=%d",
+			limit), nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestAuditHandler_GetProcessorAuditEntries(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockReader := mocks.NewMockIAuditReader(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewAuditHandler(mockReader).RegisterRoutes(app)
+
+	t.Run("only processor actions", func(t *testing.T) {
+		mockReader.
+			EXPECT().
+			GetAuditEntries(gomock.Any(), model.DefaultTenantID, &dto.AuditQuery{
+				Actions: []string{model.AuditActionProcessorStart, model.AuditActionProcessorStop},
+				Limit:   limit,
+			}).
+			Return([]model.AuditEntry{{Action: model.AuditActionProcessorStop, ActorID: "key-id"}}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet,
+			fmt.Sprintf("/processor/audit?action=apikey.create&limit=%d", limit), nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("shared by every tenant", func(t *testing.T) {
+		tenantApp := fiber.New()
+		tenantApp.Use(func(c *fiber.Ctx) error {
+			middleware.SetPrincipal(c, &model.Principal{TenantID: "retail", Scopes: model.Scopes})
+			return c.Next()
+		})
+		NewAuditHandler(mockReader).RegisterRoutes(tenantApp)
+
+		mockReader.
+			EXPECT().
+			GetAuditEntries(gomock.Any(), model.DefaultTenantID, gomock.Any()).
+			Return([]model.AuditEntry{{Action: model.AuditActionProcessorStart, ActorID: "key-id"}}, nil)
+
+		resp, err := tenantApp.Test(httptest.NewRequest(http.MethodGet, "/processor/audit", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestAuditHandler_RequiresAdminScope(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		middleware.SetPrincipal(c, &model.Principal{Scopes: []string{model.ScopeMessagesRead}})
+		return c.Next()
+	})
+	NewAuditHandler(mocks.NewMockIAuditReader(mockController)).RegisterRoutes(app)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/audit", nil))
+
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/model"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ICampaignService interface {
+	CreateCampaign(ctx context.Context, tenantID string, request *dto.CampaignRequest) (*model.Campaign, error)
+	GetCampaign(ctx context.Context, tenantID string, campaignID primitive.ObjectID) (*model.Campaign, error)
+	GetCampaigns(ctx context.Context, tenantID string, limit int) ([]model.Campaign, error)
+	PauseCampaign(ctx context.Context, tenantID string, campaignID primitive.ObjectID) (*model.Campaign, error)
+	ResumeCampaign(ctx context.Context, tenantID string, campaignID primitive.ObjectID) (*model.Campaign, error)
+	CancelCampaign(ctx context.Context, tenantID string, campaignID primitive.ObjectID) (*model.Campaign, error)
+}
+
+type CampaignHandler struct {
+	service ICampaignService
+}
+
+func NewCampaignHandler(service ICampaignService) *CampaignHandler {
+	return &CampaignHandler{service: service}
+}
+
+func (h *CampaignHandler) RegisterRoutes(server *fiber.App) {
+	campaigns := server.Group("/campaigns")
+	campaigns.Post("/", middleware.RequireScope(model.ScopeMessagesWrite), h.CreateCampaign)
+	campaigns.Get("/", middleware.RequireScope(model.ScopeMessagesRead), h.GetCampaigns)
+	campaigns.Get("/:id", middleware.RequireScope(model.ScopeMessagesRead), h.GetCampaign)
+	campaigns.Post("/:id/pause", middleware.RequireScope(model.ScopeMessagesWrite), h.PauseCampaign)
+	campaigns.Post("/:id/resume", middleware.RequireScope(model.ScopeMessagesWrite), h.ResumeCampaign)
+	campaigns.Post("/:id/cancel", middleware.RequireScope(model.ScopeMessagesWrite), h.CancelCampaign)
+}
+
+// CreateCampaign godoc
+// @Summary Create campaign
+// @Description Sends a template to every phone number of the audience as low priority messages, optionally
+// @Description scheduled for later and throttled to a number of messages per minute. Recipients no message
+// @Description could be created for are reported in rejected.
+// @Tags campaigns
+// @Accept json
+// @Produce json
+// @Param request body dto.CampaignRequest true "Campaign to create"
+// @Success 201 {object} model.Campaign "Created campaign"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request body, template or audience"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /campaigns [post]
+func (h *CampaignHandler) CreateCampaign(c *fiber.Ctx) error {
+	ctx := c.Context()
+	request := &dto.CampaignRequest{}
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid request body",
+		})
+	}
+
+	campaign, err := h.service.CreateCampaign(ctx, middleware.TenantFrom(c), request)
+	if err != nil {
+		return campaignError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(campaign)
+}
+
+// GetCampaigns godoc
+// @Summary List campaigns
+// @Description Retrieves the newest campaigns with the counts of their messages per status
+// @Tags campaigns
+// @Produce json
+// @Param limit query int false "Number of campaigns to retrieve" default(20)
+// @Success 200 {array} model.Campaign "List of campaigns"
+// @Failure 400 {object} dto.ErrorResponse "Invalid limit parameter"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /campaigns [get]
+func (h *CampaignHandler) GetCampaigns(c *fiber.Ctx) error {
+	ctx := c.Context()
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil || limit <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid limit parameter",
+		})
+	}
+
+	campaigns, err := h.service.GetCampaigns(ctx, middleware.TenantFrom(c), limit)
+	if err != nil {
+		return campaignError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(campaigns)
+}
+
+// GetCampaign godoc
+// @Summary Get campaign
+// @Description Retrieves a campaign with its audience and the counts of its messages per status
+// @Tags campaigns
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} model.Campaign "Campaign"
+// @Failure 400 {object} dto.ErrorResponse "Invalid campaign ID"
+// @Failure 404 {object} dto.ErrorResponse "Campaign not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /campaigns/{id} [get]
+func (h *CampaignHandler) GetCampaign(c *fiber.Ctx) error {
+	return h.withCampaign(c, h.service.GetCampaign)
+}
+
+// PauseCampaign godoc
+// @Summary Pause campaign
+// @Description Holds back the unsent messages of a running campaign until it is resumed
+// @Tags campaigns
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} model.Campaign "Paused campaign"
+// @Failure 400 {object} dto.ErrorResponse "Invalid campaign ID"
+// @Failure 404 {object} dto.ErrorResponse "Campaign not found"
+// @Failure 409 {object} dto.ErrorResponse "Campaign is not running"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /campaigns/{id}/pause [post]
+func (h *CampaignHandler) PauseCampaign(c *fiber.Ctx) error {
+	return h.withCampaign(c, h.service.PauseCampaign)
+}
+
+// ResumeCampaign godoc
+// @Summary Resume campaign
+// @Description Lets the processor send the unsent messages of a paused campaign again
+// @Tags campaigns
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} model.Campaign "Resumed campaign"
+// @Failure 400 {object} dto.ErrorResponse "Invalid campaign ID"
+// @Failure 404 {object} dto.ErrorResponse "Campaign not found"
+// @Failure 409 {object} dto.ErrorResponse "Campaign is not paused"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /campaigns/{id}/resume [post]
+func (h *CampaignHandler) ResumeCampaign(c *fiber.Ctx) error {
+	return h.withCampaign(c, h.service.ResumeCampaign)
+}
+
+// CancelCampaign godoc
+// @Summary Cancel campaign
+// @Description Stops a campaign for good, its unsent messages move to the cancelled status
+// @Tags campaigns
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} model.Campaign "Cancelled campaign"
+// @Failure 400 {object} dto.ErrorResponse "Invalid campaign ID"
+// @Failure 404 {object} dto.ErrorResponse "Campaign not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /campaigns/{id}/cancel [post]
+func (h *CampaignHandler) CancelCampaign(c *fiber.Ctx) error {
+	return h.withCampaign(c, h.service.CancelCampaign)
+}
+
+// withCampaign responds with the campaign action returns for the campaign of the path.
+func (h *CampaignHandler) withCampaign(c *fiber.Ctx, action func(ctx context.Context, tenantID string,
+	campaignID primitive.ObjectID) (*model.Campaign, error)) error {
+	campaignID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid campaign id",
+		})
+	}
+
+	campaign, err := action(c.Context(), middleware.TenantFrom(c), campaignID)
+	if err != nil {
+		return campaignError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(campaign)
+}
+
+func campaignError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidRequest):
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	case errors.Is(err, model.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error: "campaign not found",
+		})
+	case errors.Is(err, model.ErrInvalidState):
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+			Error: "campaign cannot be changed in its current status",
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+}
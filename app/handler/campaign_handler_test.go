@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"encoding/json"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCampaignHandler_CreateCampaign(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockICampaignService(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewCampaignHandler(mockService).RegisterRoutes(app)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/campaigns", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return req
+	}
+	body := `{"name":"spring sale","audience":["+905551112233"],"templateId":"t1","throttle":60}`
+
+	t.Run("invalid request body", func(t *testing.T) {
+		resp, err := app.Test(newRequest(`{"name":`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid campaign", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			CreateCampaign(gomock.Any(), model.DefaultTenantID, gomock.Any()).
+			Return(nil, model.ErrInvalidRequest)
+
+		resp, err := app.Test(newRequest(body))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("successfully create campaign", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			CreateCampaign(gomock.Any(), model.DefaultTenantID, &dto.CampaignRequest{
+				Name:       "spring sale",
+				Audience:   []string{"+905551112233"},
+				TemplateID: "t1",
+				Throttle:   60,
+			}).
+			Return(&model.Campaign{ID: primitive.NewObjectID(), Status: model.CampaignStatusRunning}, nil)
+
+		resp, err := app.Test(newRequest(body))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	})
+}
+
+func TestCampaignHandler_GetCampaigns(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockICampaignService(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewCampaignHandler(mockService).RegisterRoutes(app)
+
+	t.Run("invalid limit", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/campaigns?limit=0", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("list campaigns with counts", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			GetCampaigns(gomock.Any(), model.DefaultTenantID, 5).
+			Return([]model.Campaign{{ID: primitive.NewObjectID(), Counts: &model.CampaignCounts{Total: 2, Sent: 2}}}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/campaigns?limit=5", nil))
+		assert.Nil(t, err)
+
+		var campaigns []model.Campaign
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&campaigns))
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, 2, campaigns[0].Counts.Sent)
+	})
+}
+
+func TestCampaignHandler_Actions(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockICampaignService(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewCampaignHandler(mockService).RegisterRoutes(app)
+	campaignID := primitive.NewObjectID()
+	campaign := &model.Campaign{ID: campaignID}
+
+	t.Run("invalid campaign id", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/campaigns/abc/pause", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("campaign not found", func(t *testing.T) {
+		mockService.EXPECT().GetCampaign(gomock.Any(), model.DefaultTenantID, campaignID).Return(nil, model.ErrNotFound)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/campaigns/"+campaignID.Hex(), nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("pause campaign", func(t *testing.T) {
+		mockService.EXPECT().PauseCampaign(gomock.Any(), model.DefaultTenantID, campaignID).Return(campaign, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/campaigns/"+campaignID.Hex()+"/pause", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("resume campaign in the wrong status", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			ResumeCampaign(gomock.Any(), model.DefaultTenantID, campaignID).
+			Return(nil, model.ErrInvalidState)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/campaigns/"+campaignID.Hex()+"/resume", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("cancel campaign", func(t *testing.T) {
+		mockService.EXPECT().CancelCampaign(gomock.Any(), model.DefaultTenantID, campaignID).Return(campaign, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/campaigns/"+campaignID.Hex()+"/cancel", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
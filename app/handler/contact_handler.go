@@ -0,0 +1,247 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/model"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IContactService interface {
+	CreateList(ctx context.Context, tenantID string, request *dto.ContactListRequest) (*model.ContactList, error)
+	GetList(ctx context.Context, tenantID string, listID primitive.ObjectID) (*model.ContactList, error)
+	GetLists(ctx context.Context, tenantID string, limit int) ([]model.ContactList, error)
+	ImportContacts(ctx context.Context, tenantID string, listID primitive.ObjectID, format string,
+		body io.Reader) (*dto.ContactImportResponse, error)
+	SendToList(ctx context.Context, tenantID string, listID primitive.ObjectID,
+		request *dto.ListSendRequest) (*dto.ListSendResponse, error)
+}
+
+type ContactHandler struct {
+	service IContactService
+}
+
+func NewContactHandler(service IContactService) *ContactHandler {
+	return &ContactHandler{service: service}
+}
+
+func (h *ContactHandler) RegisterRoutes(server *fiber.App) {
+	lists := server.Group("/lists")
+	lists.Post("/", middleware.RequireScope(model.ScopeMessagesWrite), h.CreateList)
+	lists.Get("/", middleware.RequireScope(model.ScopeMessagesRead), h.GetLists)
+	lists.Get("/:id", middleware.RequireScope(model.ScopeMessagesRead), h.GetList)
+	lists.Post("/:id/contacts", middleware.RequireScope(model.ScopeMessagesWrite), h.ImportContacts)
+	lists.Post("/:id/send", middleware.RequireScope(model.ScopeMessagesWrite), h.SendToList)
+}
+
+// CreateList godoc
+// @Summary Create contact list
+// @Description Creates an empty contact list, members are added by uploading contacts
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param request body dto.ContactListRequest true "List to create"
+// @Success 201 {object} model.ContactList "Created list"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request body"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /lists [post]
+func (h *ContactHandler) CreateList(c *fiber.Ctx) error {
+	ctx := c.Context()
+	request := &dto.ContactListRequest{}
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid request body",
+		})
+	}
+
+	list, err := h.service.CreateList(ctx, middleware.TenantFrom(c), request)
+	if err != nil {
+		return contactError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(list)
+}
+
+// GetLists godoc
+// @Summary List contact lists
+// @Description Retrieves the newest contact lists with their number of contacts
+// @Tags lists
+// @Produce json
+// @Param limit query int false "Number of lists to retrieve" default(20)
+// @Success 200 {array} model.ContactList "List of contact lists"
+// @Failure 400 {object} dto.ErrorResponse "Invalid limit parameter"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /lists [get]
+func (h *ContactHandler) GetLists(c *fiber.Ctx) error {
+	ctx := c.Context()
+	limit, err := strconv.Atoi(c.Query("limit", "20"))
+	if err != nil || limit <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid limit parameter",
+		})
+	}
+
+	lists, err := h.service.GetLists(ctx, middleware.TenantFrom(c), limit)
+	if err != nil {
+		return contactError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(lists)
+}
+
+// GetList godoc
+// @Summary Get contact list
+// @Description Retrieves a contact list with its number of contacts
+// @Tags lists
+// @Produce json
+// @Param id path string true "List ID"
+// @Success 200 {object} model.ContactList "Contact list"
+// @Failure 400 {object} dto.ErrorResponse "Invalid list ID"
+// @Failure 404 {object} dto.ErrorResponse "List not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /lists/{id} [get]
+func (h *ContactHandler) GetList(c *fiber.Ctx) error {
+	listID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid list id",
+		})
+	}
+
+	list, err := h.service.GetList(c.Context(), middleware.TenantFrom(c), listID)
+	if err != nil {
+		return contactError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(list)
+}
+
+// ImportContacts godoc
+// @Summary Upload contacts
+// @Description Streams a CSV file with a phone column or NDJSON objects with a phone field into the list.
+// @Description The format is taken from the format parameter or the Content-Type (text/csv,
+// @Description application/x-ndjson). Numbers are normalized to E.164, duplicates are skipped and invalid
+// @Description rows are reported.
+// @Tags lists
+// @Accept plain
+// @Produce json
+// @Param id path string true "List ID"
+// @Param format query string false "Upload format, csv or ndjson"
+// @Param file body string true "CSV or NDJSON contacts"
+// @Success 200 {object} dto.ContactImportResponse "Import report"
+// @Failure 400 {object} dto.ErrorResponse "Invalid list ID, format or file"
+// @Failure 404 {object} dto.ErrorResponse "List not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /lists/{id}/contacts [post]
+func (h *ContactHandler) ImportContacts(c *fiber.Ctx) error {
+	listID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid list id",
+		})
+	}
+
+	response, err := h.service.ImportContacts(c.Context(), middleware.TenantFrom(c), listID, uploadFormat(c),
+		requestBody(c))
+	if err != nil {
+		return contactError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// SendToList godoc
+// @Summary Send to contact list
+// @Description Creates an unsent message with the given content for every member of the list
+// @Tags lists
+// @Accept json
+// @Produce json
+// @Param id path string true "List ID"
+// @Param request body dto.ListSendRequest true "Message to send"
+// @Success 200 {object} dto.ListSendResponse "Send report"
+// @Failure 400 {object} dto.ErrorResponse "Invalid list ID or request body"
+// @Failure 404 {object} dto.ErrorResponse "List not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /lists/{id}/send [post]
+func (h *ContactHandler) SendToList(c *fiber.Ctx) error {
+	listID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid list id",
+		})
+	}
+
+	request := &dto.ListSendRequest{}
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid request body",
+		})
+	}
+
+	response, err := h.service.SendToList(c.Context(), middleware.TenantFrom(c), listID, request)
+	if err != nil {
+		return contactError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// uploadFormat takes the format of an upload from the format parameter, then from the Content-Type.
+func uploadFormat(c *fiber.Ctx) string {
+	if format := c.Query("format"); format != "" {
+		return strings.ToLower(format)
+	}
+
+	contentType := strings.ToLower(c.Get(fiber.HeaderContentType))
+	switch {
+	case strings.HasPrefix(contentType, "text/csv"):
+		return dto.ContactFormatCSV
+	case strings.Contains(contentType, "ndjson"), strings.Contains(contentType, "jsonl"):
+		return dto.ContactFormatNDJSON
+	default:
+		return contentType
+	}
+}
+
+// requestBody reads the body as it arrives when the server streams request bodies.
+func requestBody(c *fiber.Ctx) io.Reader {
+	if stream := c.Context().RequestBodyStream(); stream != nil {
+		return stream
+	}
+	return bytes.NewReader(c.Body())
+}
+
+func contactError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidRequest):
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	case errors.Is(err, model.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error: "list not found",
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"io"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestContactHandler_CreateList(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockIContactService(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewContactHandler(mockService).RegisterRoutes(app)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/lists", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return req
+	}
+
+	t.Run("invalid request body", func(t *testing.T) {
+		resp, err := app.Test(newRequest(`{"name":`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("successfully create list", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			CreateList(gomock.Any(), model.DefaultTenantID, &dto.ContactListRequest{Name: "newsletter"}).
+			Return(&model.ContactList{ID: primitive.NewObjectID(), Name: "newsletter"}, nil)
+
+		resp, err := app.Test(newRequest(`{"name":"newsletter"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	})
+}
+
+func TestContactHandler_ImportContacts(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockIContactService(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewContactHandler(mockService).RegisterRoutes(app)
+
+	listID := primitive.NewObjectID()
+	newRequest := func(path, contentType, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, contentType)
+		return req
+	}
+
+	t.Run("invalid list id", func(t *testing.T) {
+		resp, err := app.Test(newRequest("/lists/invalid/contacts", "text/csv", "phone\n"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("list not found", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			ImportContacts(gomock.Any(), model.DefaultTenantID, listID, dto.ContactFormatCSV, gomock.Any()).
+			Return(nil, model.ErrNotFound)
+
+		resp, err := app.Test(newRequest("/lists/"+listID.Hex()+"/contacts", "text/csv", "phone\n"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("format is taken from the content type", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			ImportContacts(gomock.Any(), model.DefaultTenantID, listID, dto.ContactFormatNDJSON, gomock.Any()).
+			DoAndReturn(func(_, _, _, _ interface{}, body io.Reader) (*dto.ContactImportResponse, error) {
+				data, err := io.ReadAll(body)
+				assert.Nil(t, err)
+				assert.Equal(t, `{"phoneNumber":"+905551112233"}`, string(data))
+				return &dto.ContactImportResponse{Rows: 1, Imported: 1}, nil
+			})
+
+		resp, err := app.Test(newRequest("/lists/"+listID.Hex()+"/contacts", "application/x-ndjson",
+			`{"phoneNumber":"+905551112233"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("format parameter wins over the content type", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			ImportContacts(gomock.Any(), model.DefaultTenantID, listID, dto.ContactFormatCSV, gomock.Any()).
+			Return(&dto.ContactImportResponse{}, nil)
+
+		resp, err := app.Test(newRequest("/lists/"+listID.Hex()+"/contacts?format=CSV", "text/plain", "phone\n"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestContactHandler_SendToList(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockIContactService(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewContactHandler(mockService).RegisterRoutes(app)
+
+	listID := primitive.NewObjectID()
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/lists/"+listID.Hex()+"/send", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return req
+	}
+
+	t.Run("invalid request", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			SendToList(gomock.Any(), model.DefaultTenantID, listID, &dto.ListSendRequest{}).
+			Return(nil, model.ErrInvalidRequest)
+
+		resp, err := app.Test(newRequest(`{}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("successfully send to list", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			SendToList(gomock.Any(), model.DefaultTenantID, listID, &dto.ListSendRequest{Content: "Sale!"}).
+			Return(&dto.ListSendResponse{Queued: 2}, nil)
+
+		resp, err := app.Test(newRequest(`{"content":"Sale!"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"messaging-system/app/model"
+	"strconv"
+	"time"
+)
+
+const (
+	ExportFormatCSV    = "csv"
+	ExportFormatNDJSON = "ndjson"
+)
+
+// exportColumns are the CSV columns of exported messages.
+var exportColumns = []string{
+	"id", "phoneNumber", "country", "content", "encoding", "segments", "priority", "category", "templateId",
+	"campaignId", "provider", "webhookMessageId", "createdAt", "sentAt",
+}
+
+// messageWriter writes exported messages in the format of an export. An export failing midway ends with
+// an error line, the status code has been sent by then.
+type messageWriter interface {
+	write(message *model.Message) error
+	writeError(err error) error
+	flush() error
+}
+
+func newMessageWriter(format string, w io.Writer) messageWriter {
+	if format == ExportFormatCSV {
+		writer := csv.NewWriter(w)
+		_ = writer.Write(exportColumns)
+		return &csvMessageWriter{writer: writer}
+	}
+	return &ndjsonMessageWriter{encoder: json.NewEncoder(w)}
+}
+
+type csvMessageWriter struct {
+	writer *csv.Writer
+}
+
+func (c *csvMessageWriter) write(message *model.Message) error {
+	campaignID := ""
+	if message.CampaignID != nil {
+		campaignID = message.CampaignID.Hex()
+	}
+
+	// content and the values callers or providers chose are escaped, the rest is written by the service
+	return c.writer.Write([]string{
+		message.ID.Hex(),
+		csvCell(message.PhoneNumber),
+		csvCell(message.Country),
+		csvCell(message.Content),
+		string(message.Encoding),
+		strconv.Itoa(message.Segments),
+		strconv.Itoa(message.Priority),
+		csvCell(message.Category),
+		csvCell(message.TemplateID),
+		campaignID,
+		csvCell(message.Provider),
+		csvCell(message.WebhookMessageID),
+		message.CreatedAt.UTC().Format(time.RFC3339),
+		message.SentAt.UTC().Format(time.RFC3339),
+	})
+}
+
+func (c *csvMessageWriter) writeError(err error) error {
+	return c.writer.Write([]string{"error", csvCell(err.Error())})
+}
+
+func (c *csvMessageWriter) flush() error {
+	c.writer.Flush()
+	return c.writer.Error()
+}
+
+type ndjsonMessageWriter struct {
+	encoder *json.Encoder
+}
+
+func (n *ndjsonMessageWriter) write(message *model.Message) error {
+	return n.encoder.Encode(message)
+}
+
+func (n *ndjsonMessageWriter) writeError(err error) error {
+	return n.encoder.Encode(map[string]string{"error": err.Error()})
+}
+
+// flush has nothing to do, the encoder writes every message through.
+func (n *ndjsonMessageWriter) flush() error {
+	return nil
+}
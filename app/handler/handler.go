@@ -1,10 +1,20 @@
 package handler
 
 import (
+	"bufio"
+	"compress/gzip"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"messaging-system/app/dto"
+	"messaging-system/app/events"
+	"messaging-system/app/middleware"
 	"messaging-system/app/model"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -12,31 +22,118 @@ import (
 const (
 	ActionStart = "start"
 	ActionStop  = "stop"
+
+	// EventHeartbeatInterval keeps idle event streams open through proxies.
+	EventHeartbeatInterval = 15 * time.Second
+
+	// ExportFlushSize is the number of exported messages sent to the client at once.
+	ExportFlushSize = 500
 )
 
 type IMessageProcessor interface {
 	Start(ctx context.Context)
 	Stop(ctx context.Context)
-	GetSentMessages(ctx context.Context, limit int) ([]model.Message, error)
+	IsRunning() bool
+	GetSentMessages(ctx context.Context, tenantID string, limit int) ([]model.Message, error)
+	ExportSentMessages(ctx context.Context, tenantID string, from, to time.Time,
+		handle func(ctx context.Context, message *model.Message) error) error
+	Subscribe(filter events.Filter) (<-chan events.Event, func())
+}
+
+type IMessageCreator interface {
+	CreateMessage(ctx context.Context, tenantID string, request *dto.MessageRequest) (*model.Message, error)
+}
+
+type IAuditRecorder interface {
+	Record(ctx context.Context, event *model.AuditEvent) error
 }
 
 type Handler struct {
+	// ctx is the context the processor runs in once started, it outlives the request starting it
+	ctx       context.Context
 	processor IMessageProcessor
+	creator   IMessageCreator
+	audit     IAuditRecorder
 }
 
-func NewMessageHandler(processor IMessageProcessor) *Handler {
-	return &Handler{processor: processor}
+// NewMessageHandler starts the processor in ctx rather than in the context of the start request, the
+// request context is recycled after the response and carries the ID of that request.
+func NewMessageHandler(ctx context.Context, processor IMessageProcessor, creator IMessageCreator,
+	audit IAuditRecorder) *Handler {
+	return &Handler{
+		ctx:       ctx,
+		processor: processor,
+		creator:   creator,
+		audit:     audit,
+	}
 }
 
 func (h *Handler) RegisterRoutes(server *fiber.App) {
+	messages := server.Group("/messages")
+	messages.Post("/", middleware.RequireScope(model.ScopeMessagesWrite), h.CreateMessage)
+
 	processor := server.Group("/processor")
-	processor.Get("/sent-messages", h.GetSentMessages)
-	processor.Post("/:action", h.StartStopJob)
+	processor.Get("/sent-messages", middleware.RequireScope(model.ScopeMessagesRead), h.GetSentMessages)
+	processor.Get("/sent-messages/export", middleware.RequireScope(model.ScopeMessagesRead), h.ExportSentMessages)
+	processor.Get("/events", middleware.RequireScope(model.ScopeMessagesRead), h.StreamEvents)
+	processor.Post("/:action", middleware.RequireScope(model.ScopeProcessorAdmin), middleware.RequirePlatform(),
+		h.StartStopJob)
+}
+
+// CreateMessage godoc
+// @Summary Create message
+// @Description Stores a new unsent message of the caller's tenant to be picked up by the message processor.
+// @Description The content can be rendered from a template by passing templateId, params and locale instead of content.
+// @Description Requests carrying an Idempotency-Key header are only processed once; retries return the original response.
+// @Tags messages
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Unique key to safely retry the request"
+// @Param request body dto.MessageRequest true "Message to send"
+// @Success 201 {object} dto.MessageResponse "Message created"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request body or template params"
+// @Failure 409 {object} dto.ErrorResponse "Request with the same Idempotency-Key is in progress"
+// @Failure 422 {object} dto.ErrorResponse "Idempotency-Key reused with a different request body"
+// @Failure 429 {object} dto.ErrorResponse "Daily message quota of the tenant or rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /messages [post]
+func (h *Handler) CreateMessage(c *fiber.Ctx) error {
+	ctx := c.Context()
+	request := &dto.MessageRequest{}
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid request body",
+		})
+	}
+
+	message, err := h.creator.CreateMessage(ctx, middleware.TenantFrom(c), request)
+	if errors.Is(err, model.ErrInvalidRequest) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	if errors.Is(err, model.ErrQuotaExceeded) {
+		return c.Status(fiber.StatusTooManyRequests).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dto.MessageResponse{
+		Message:   "message created",
+		MessageID: message.ID.Hex(),
+	})
 }
 
 // GetSentMessages godoc
 // @Summary Get sent messages
-// @Description Retrieves a list of sent messages with an optional limit
+// @Description Retrieves a list of sent messages of the caller's tenant with an optional limit
 // @Tags processor
 // @Accept json
 // @Produce json
@@ -45,6 +142,8 @@ func (h *Handler) RegisterRoutes(server *fiber.App) {
 // @Failure 400 {object} dto.ErrorResponse "Invalid limit parameter"
 // @Failure 404 {object} dto.ErrorResponse "No unsent messages found"
 // @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /processor/sent-messages [get]
 func (h *Handler) GetSentMessages(c *fiber.Ctx) error {
 	ctx := c.Context()
@@ -56,7 +155,7 @@ func (h *Handler) GetSentMessages(c *fiber.Ctx) error {
 		})
 	}
 
-	messages, err := h.processor.GetSentMessages(ctx, limitInt)
+	messages, err := h.processor.GetSentMessages(ctx, middleware.TenantFrom(c), limitInt)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
 			Error: err.Error(),
@@ -72,33 +171,210 @@ func (h *Handler) GetSentMessages(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusOK).JSON(messages)
 }
 
+// ExportSentMessages godoc
+// @Summary Export sent messages
+// @Description Streams every sent message of the caller's tenant within a time range as CSV or NDJSON, in the order they were sent.
+// @Description The export is gzip compressed when the request accepts gzip. An export failing midway ends with an error line.
+// @Tags processor
+// @Produce text/csv,application/x-ndjson
+// @Param from query string false "Start of the range by sent time, RFC 3339 or YYYY-MM-DD"
+// @Param to query string false "End of the range by sent time, exclusive, RFC 3339 or YYYY-MM-DD"
+// @Param format query string false "Export format" Enums(csv, ndjson) default(ndjson)
+// @Param Accept-Encoding header string false "gzip to compress the export"
+// @Success 200 {string} string "Sent messages, one per line"
+// @Failure 400 {object} dto.ErrorResponse "Invalid range or format"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /processor/sent-messages/export [get]
+func (h *Handler) ExportSentMessages(c *fiber.Ctx) error {
+	from, err := queryTime(c.Query("from"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid from parameter",
+		})
+	}
+	to, err := queryTime(c.Query("to"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid to parameter",
+		})
+	}
+
+	format := strings.ToLower(c.Query("format", ExportFormatNDJSON))
+	contentType, ok := map[string]string{
+		ExportFormatCSV:    "text/csv; charset=utf-8",
+		ExportFormatNDJSON: "application/x-ndjson",
+	}[format]
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid format, use 'csv' or 'ndjson'",
+		})
+	}
+	// the attachment sets a content type from the file extension, it is replaced right after
+	c.Attachment("sent-messages." + format)
+	c.Set(fiber.HeaderContentType, contentType)
+
+	compress := strings.Contains(c.Get(fiber.HeaderAcceptEncoding), "gzip")
+	if compress {
+		c.Set(fiber.HeaderContentEncoding, "gzip")
+	}
+	c.Vary(fiber.HeaderAcceptEncoding)
+
+	// the fiber context is released once the handler returns, the stream only keeps the request context
+	ctx := c.Context()
+	tenantID := middleware.TenantFrom(c)
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		var out io.Writer = w
+		var zipped *gzip.Writer
+		if compress {
+			zipped = gzip.NewWriter(w)
+			out = zipped
+		}
+		writer := newMessageWriter(format, out)
+		flush := func() error {
+			if err := writer.flush(); err != nil {
+				return err
+			}
+			if zipped != nil {
+				if err := zipped.Flush(); err != nil {
+					return err
+				}
+			}
+			// a failing flush means the client went away
+			return w.Flush()
+		}
+
+		written := 0
+		err := h.processor.ExportSentMessages(ctx, tenantID, from, to,
+			func(_ context.Context, message *model.Message) error {
+				if err := writer.write(message); err != nil {
+					return err
+				}
+				written++
+				if written%ExportFlushSize == 0 {
+					return flush()
+				}
+				return nil
+			})
+		if err != nil {
+			_ = writer.writeError(err)
+		}
+
+		_ = writer.flush()
+		if zipped != nil {
+			_ = zipped.Close()
+		}
+		_ = w.Flush()
+	})
+
+	return nil
+}
+
+// StreamEvents godoc
+// @Summary Stream processor events
+// @Description Streams processor activity as Server-Sent Events: tick.started, message.sent, message.failed,
+// @Description message.suppressed, message.expired and batch.finished. Filters only pass message events carrying
+// @Description the given status or phone number, the leading + of the phone number must be URL encoded as %2B.
+// @Description Message events of other tenants are never streamed, batch.finished counts the messages of the tenant only.
+// @Tags processor
+// @Produce text/event-stream
+// @Param status query string false "Only stream events with this status" Enums(sent, failed, suppressed, expired)
+// @Param phone query string false "Only stream events of this E.164 phone number"
+// @Success 200 {object} events.Event "Stream of processor events"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /processor/events [get]
+func (h *Handler) StreamEvents(c *fiber.Ctx) error {
+	filter := events.Filter{
+		TenantID:    middleware.TenantFrom(c),
+		Status:      c.Query("status"),
+		PhoneNumber: c.Query("phone"),
+	}
+	stream, cancel := h.processor.Subscribe(filter)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer cancel()
+		heartbeat := time.NewTicker(EventHeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case event, ok := <-stream:
+				if !ok {
+					return
+				}
+
+				data, err := json.Marshal(event)
+				if err != nil {
+					continue
+				}
+				fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+			}
+
+			// a failing flush means the client went away
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	})
+
+	return nil
+}
+
 // StartStopJob godoc
 // @Summary Start or stop message processor
-// @Description Starts or stops the message processor job based on the action parameter
+// @Description Starts or stops the message processor job based on the action parameter, every call is recorded in the audit log.
+// @Description The processor sends the messages of every tenant, so only callers of the default tenant may start or stop it.
 // @Tags processor
 // @Accept json
 // @Produce json
 // @Param action path string true "Action to perform" Enums(start, stop)
 // @Success 200 {object} dto.SuccessResponse "Message processor started or stopped successfully"
 // @Failure 400 {object} dto.ErrorResponse "Invalid action parameter"
+// @Failure 403 {object} dto.ErrorResponse "Caller is not of the default tenant"
+// @Failure 500 {object} dto.ErrorResponse "Action could not be recorded in the audit log"
+// @Security ApiKeyAuth
+// @Security BearerAuth
 // @Router /processor/{action} [post]
 func (h *Handler) StartStopJob(c *fiber.Ctx) error {
 	ctx := c.Context()
 	action := c.Params("action")
-	switch action {
-	case ActionStart:
-		h.processor.Start(ctx)
-		return c.Status(fiber.StatusOK).JSON(dto.SuccessResponse{
-			Message: "message processor started",
-		})
-	case ActionStop:
-		h.processor.Stop(ctx)
-		return c.Status(fiber.StatusOK).JSON(dto.SuccessResponse{
-			Message: "message processor stopped",
-		})
-	default:
+	if action != ActionStart && action != ActionStop {
 		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
 			Error: "invalid action, use 'start' or 'stop'",
 		})
 	}
+
+	// the action is only performed once it is recorded, so it can always be traced back to its caller
+	auditAction := model.AuditActionProcessorStart
+	if action == ActionStop {
+		auditAction = model.AuditActionProcessorStop
+	}
+	event := auditEvent(c, auditAction, AuditTargetProcessor)
+	event.Before = &dto.ProcessorState{Running: h.processor.IsRunning()}
+	event.After = &dto.ProcessorState{Running: action == ActionStart}
+	if err := h.audit.Record(ctx, event); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	if action == ActionStart {
+		h.processor.Start(h.ctx)
+		return c.Status(fiber.StatusOK).JSON(dto.SuccessResponse{
+			Message: "message processor started",
+		})
+	}
+
+	h.processor.Stop(ctx)
+	return c.Status(fiber.StatusOK).JSON(dto.SuccessResponse{
+		Message: "message processor stopped",
+	})
 }
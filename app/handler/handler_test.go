@@ -1,15 +1,31 @@
 package handler
 
 import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"messaging-system/app/client"
+	"messaging-system/app/dto"
+	"messaging-system/app/events"
+	"messaging-system/app/middleware"
 	"messaging-system/app/mocks"
 	"messaging-system/app/model"
+	"messaging-system/app/requestid"
+	"messaging-system/config"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const limit = 10
@@ -19,7 +35,8 @@ func TestHandler_GetSentMessages(t *testing.T) {
 	defer mockController.Finish()
 
 	mockProcessor := mocks.NewMockIMessageProcessor(mockController)
-	mockHandler := NewMessageHandler(mockProcessor)
+	mockHandler := NewMessageHandler(context.Background(), mockProcessor, mocks.NewMockIMessageCreator(mockController),
+		mocks.NewMockIAuditRecorder(mockController))
 
 	app := fiber.New()
 	app.Get("/processor/sent-messages", mockHandler.GetSentMessages)
@@ -35,7 +52,7 @@ func TestHandler_GetSentMessages(t *testing.T) {
 	t.Run("processor returns error", func(t *testing.T) {
 		mockProcessor.
 			EXPECT().
-			GetSentMessages(gomock.Any(), limit).
+			GetSentMessages(gomock.Any(), model.DefaultTenantID, limit).
 			Return(nil, assert.AnError)
 
 		req := httptest.NewRequest(http.MethodGet, "/processor/sent-messages?limit=10", nil)
@@ -48,7 +65,7 @@ func TestHandler_GetSentMessages(t *testing.T) {
 	t.Run("no sent messages found", func(t *testing.T) {
 		mockProcessor.
 			EXPECT().
-			GetSentMessages(gomock.Any(), limit).
+			GetSentMessages(gomock.Any(), model.DefaultTenantID, limit).
 			Return([]model.Message{}, nil)
 
 		req := httptest.NewRequest(http.MethodGet, "/processor/sent-messages?limit=10", nil)
@@ -61,7 +78,7 @@ func TestHandler_GetSentMessages(t *testing.T) {
 	t.Run("successful retrieval of sent messages", func(t *testing.T) {
 		mockProcessor.
 			EXPECT().
-			GetSentMessages(gomock.Any(), limit).
+			GetSentMessages(gomock.Any(), model.DefaultTenantID, limit).
 			Return([]model.Message{
 				{Content: "Hello"},
 				{Content: "World"},
@@ -75,29 +92,232 @@ func TestHandler_GetSentMessages(t *testing.T) {
 	})
 }
 
+func TestHandler_ExportSentMessages(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockProcessor := mocks.NewMockIMessageProcessor(mockController)
+	mockHandler := NewMessageHandler(context.Background(), mockProcessor, mocks.NewMockIMessageCreator(mockController),
+		mocks.NewMockIAuditRecorder(mockController))
+
+	app := fiber.New()
+	app.Get("/processor/sent-messages/export", mockHandler.ExportSentMessages)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC)
+	sentAt := from.Add(time.Hour)
+	message := model.Message{
+		ID:          primitive.NewObjectID(),
+		PhoneNumber: "+905551112233",
+		Content:     "Hello, world",
+		Status:      model.StatusSent,
+		Segments:    1,
+		Provider:    "sms.example.com",
+		CreatedAt:   from,
+		SentAt:      sentAt,
+	}
+	formula := message
+	formula.Content = `=HYPERLINK("https://evil.example.com","Hello")`
+	export := func(messages ...model.Message) interface{} {
+		return func(ctx context.Context, _ string, _, _ time.Time,
+			handle func(context.Context, *model.Message) error) error {
+			for i := range messages {
+				if err := handle(ctx, &messages[i]); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	t.Run("invalid format", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/processor/sent-messages/export?format=xml", nil)
+		resp, err := app.Test(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+		assert.Empty(t, resp.Header.Get(fiber.HeaderContentDisposition))
+	})
+
+	t.Run("invalid to parameter", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/processor/sent-messages/export?to=tomorrow", nil)
+		resp, err := app.Test(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("export as ndjson", func(t *testing.T) {
+		mockProcessor.
+			EXPECT().
+			ExportSentMessages(gomock.Any(), model.DefaultTenantID, from, to, gomock.Any()).
+			DoAndReturn(export(message, message))
+
+		req := httptest.NewRequest(http.MethodGet,
+			"/processor/sent-messages/export?from=2026-10-01&to=2026-10-02T00:00:00Z", nil)
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/x-ndjson", resp.Header.Get(fiber.HeaderContentType))
+
+		body, err := io.ReadAll(resp.Body)
+		assert.Nil(t, err)
+		lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+		assert.Len(t, lines, 2)
+		exported := model.Message{}
+		assert.Nil(t, json.Unmarshal([]byte(lines[0]), &exported))
+		assert.Equal(t, message.ID, exported.ID)
+	})
+
+	t.Run("export as gzipped csv", func(t *testing.T) {
+		mockProcessor.
+			EXPECT().
+			ExportSentMessages(gomock.Any(), model.DefaultTenantID, time.Time{}, time.Time{}, gomock.Any()).
+			DoAndReturn(export(message, formula))
+
+		req := httptest.NewRequest(http.MethodGet, "/processor/sent-messages/export?format=csv", nil)
+		req.Header.Set(fiber.HeaderAcceptEncoding, "gzip, deflate")
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "gzip", resp.Header.Get(fiber.HeaderContentEncoding))
+		assert.Contains(t, resp.Header.Get(fiber.HeaderContentDisposition), "sent-messages.csv")
+
+		reader, err := gzip.NewReader(resp.Body)
+		assert.Nil(t, err)
+		records, err := csv.NewReader(reader).ReadAll()
+		assert.Nil(t, err)
+		assert.Len(t, records, 3)
+		assert.Equal(t, "phoneNumber", records[0][1])
+		assert.Equal(t, "+905551112233", records[1][1])
+		assert.Equal(t, "Hello, world", records[1][3])
+		assert.Equal(t, `'=HYPERLINK("https://evil.example.com","Hello")`, records[2][3])
+		assert.Equal(t, "sms.example.com", records[1][10])
+		assert.Equal(t, "2026-10-01T01:00:00Z", records[1][13])
+	})
+
+	t.Run("failure midway ends with an error line", func(t *testing.T) {
+		mockProcessor.
+			EXPECT().
+			ExportSentMessages(gomock.Any(), model.DefaultTenantID, gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ string, _, _ time.Time,
+				handle func(context.Context, *model.Message) error) error {
+				assert.Nil(t, handle(ctx, &message))
+				return assert.AnError
+			})
+
+		req := httptest.NewRequest(http.MethodGet, "/processor/sent-messages/export", nil)
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		assert.Nil(t, err)
+		lines := strings.Split(strings.TrimSpace(string(body)), "\n")
+		assert.Len(t, lines, 2)
+		assert.JSONEq(t, fmt.Sprintf(`{"error":%q}`, assert.AnError.Error()), lines[1])
+	})
+}
+
 func TestHandler_StartStopJob(t *testing.T) {
 	mockController := gomock.NewController(t)
 	defer mockController.Finish()
 
 	mockProcessor := mocks.NewMockIMessageProcessor(mockController)
-	mockHandler := NewMessageHandler(mockProcessor)
+	mockAudit := mocks.NewMockIAuditRecorder(mockController)
+	mockHandler := NewMessageHandler(context.Background(), mockProcessor, mocks.NewMockIMessageCreator(mockController),
+		mockAudit)
+	principal := &model.Principal{ID: "key-id", Name: "ops", Scopes: []string{model.ScopeProcessorAdmin}}
 
 	app := fiber.New()
+	app.Use(middleware.RequestID(), func(c *fiber.Ctx) error {
+		middleware.SetPrincipal(c, principal)
+		return c.Next()
+	})
 	app.Post("/processor/:action", mockHandler.StartStopJob)
 
 	t.Run("successfully start action", func(t *testing.T) {
+		mockProcessor.
+			EXPECT().
+			IsRunning().
+			Return(false)
+		mockAudit.
+			EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, event *model.AuditEvent) error {
+				assert.Equal(t, model.AuditActionProcessorStart, event.Action)
+				assert.Equal(t, principal, event.Principal)
+				assert.Equal(t, AuditTargetProcessor, event.Target)
+				assert.Equal(t, "request-id", event.RequestID)
+				assert.Equal(t, &dto.ProcessorState{Running: false}, event.Before)
+				assert.Equal(t, &dto.ProcessorState{Running: true}, event.After)
+				return nil
+			})
 		mockProcessor.
 			EXPECT().
 			Start(gomock.Any())
 
 		req := httptest.NewRequest(http.MethodPost, "/processor/start", nil)
+		req.Header.Set(fiber.HeaderXRequestID, "request-id")
 		resp, err := app.Test(req)
 
 		assert.Nil(t, err)
 		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
 	})
 
+	t.Run("processor runs without the start request's ID", func(t *testing.T) {
+		var forwardedIDs [][]string
+		provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			forwardedIDs = append(forwardedIDs, r.Header.Values(requestid.Header))
+			w.Header().Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			_, _ = w.Write([]byte(`{"messageId":"provider-id"}`))
+		}))
+		defer provider.Close()
+
+		smsClient := client.NewClient(&config.Client{URL: provider.URL}, slog.Default())
+		send := func(ctx context.Context) {
+			_, err := smsClient.SendMessage(ctx, &dto.MessageRequest{To: "+905551112233", Content: "Hello"})
+			assert.NoError(t, err)
+		}
+
+		var processorCtx context.Context
+		mockProcessor.EXPECT().IsRunning().Return(false)
+		mockAudit.EXPECT().Record(gomock.Any(), gomock.Any()).Return(nil)
+		mockProcessor.
+			EXPECT().
+			Start(gomock.Any()).
+			Do(func(ctx context.Context) {
+				// the processor loop sends right away while the start request is still being answered
+				processorCtx = ctx
+				send(ctx)
+			})
+
+		req := httptest.NewRequest(http.MethodPost, "/processor/start", nil)
+		req.Header.Set(fiber.HeaderXRequestID, "start-request-id")
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		// and keeps sending with the same context once the request is long answered
+		send(processorCtx)
+		assert.NoError(t, processorCtx.Err())
+		assert.Equal(t, [][]string{nil, nil}, forwardedIDs)
+	})
+
 	t.Run("successfully stop action", func(t *testing.T) {
+		mockProcessor.
+			EXPECT().
+			IsRunning().
+			Return(true)
+		mockAudit.
+			EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, event *model.AuditEvent) error {
+				assert.Equal(t, model.AuditActionProcessorStop, event.Action)
+				assert.Equal(t, &dto.ProcessorState{Running: true}, event.Before)
+				assert.Equal(t, &dto.ProcessorState{Running: false}, event.After)
+				return nil
+			})
 		mockProcessor.
 			EXPECT().
 			Stop(gomock.Any())
@@ -116,4 +336,128 @@ func TestHandler_StartStopJob(t *testing.T) {
 		assert.Nil(t, err)
 		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
 	})
+
+	t.Run("audit log fails - processor untouched", func(t *testing.T) {
+		mockProcessor.
+			EXPECT().
+			IsRunning().
+			Return(false)
+		mockAudit.
+			EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			Return(fmt.Errorf("mongo down"))
+
+		req := httptest.NewRequest(http.MethodPost, "/processor/start", nil)
+		resp, err := app.Test(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+}
+
+func TestHandler_StreamEvents(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockProcessor := mocks.NewMockIMessageProcessor(mockController)
+	mockHandler := NewMessageHandler(context.Background(), mockProcessor, mocks.NewMockIMessageCreator(mockController),
+		mocks.NewMockIAuditRecorder(mockController))
+
+	app := fiber.New()
+	app.Get("/processor/events", mockHandler.StreamEvents)
+
+	t.Run("streams filtered events", func(t *testing.T) {
+		stream := make(chan events.Event, 1)
+		stream <- events.Event{Type: events.TypeMessageSent, Status: "sent", PhoneNumber: "+905551112233"}
+		close(stream)
+
+		mockProcessor.
+			EXPECT().
+			Subscribe(events.Filter{TenantID: model.DefaultTenantID, Status: "sent", PhoneNumber: "+905551112233"}).
+			Return(stream, func() {})
+
+		req := httptest.NewRequest(http.MethodGet, "/processor/events?status=sent&phone=%2B905551112233", nil)
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "text/event-stream", resp.Header.Get(fiber.HeaderContentType))
+
+		body, err := io.ReadAll(resp.Body)
+		assert.Nil(t, err)
+		assert.Contains(t, string(body), "event: message.sent\ndata: {")
+		assert.Contains(t, string(body), `"phoneNumber":"+905551112233"`)
+	})
+}
+
+func TestHandler_CreateMessage(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockCreator := mocks.NewMockIMessageCreator(mockController)
+	mockHandler := NewMessageHandler(context.Background(), mocks.NewMockIMessageProcessor(mockController), mockCreator,
+		mocks.NewMockIAuditRecorder(mockController))
+
+	app := fiber.New()
+	app.Post("/messages", mockHandler.CreateMessage)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return req
+	}
+
+	t.Run("invalid request body", func(t *testing.T) {
+		resp, err := app.Test(newRequest(`{"to":`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid message request", func(t *testing.T) {
+		mockCreator.
+			EXPECT().
+			CreateMessage(gomock.Any(), model.DefaultTenantID, &dto.MessageRequest{To: "123", Content: "Hello"}).
+			Return(nil, fmt.Errorf("%w: invalid phone number format", model.ErrInvalidRequest))
+
+		resp, err := app.Test(newRequest(`{"to":"123","content":"Hello"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("daily quota exceeded", func(t *testing.T) {
+		mockCreator.
+			EXPECT().
+			CreateMessage(gomock.Any(), model.DefaultTenantID, &dto.MessageRequest{To: "+905551112233", Content: "Hello"}).
+			Return(nil, model.ErrQuotaExceeded)
+
+		resp, err := app.Test(newRequest(`{"to":"+905551112233","content":"Hello"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+	})
+
+	t.Run("creator returns error", func(t *testing.T) {
+		mockCreator.
+			EXPECT().
+			CreateMessage(gomock.Any(), model.DefaultTenantID, &dto.MessageRequest{To: "+905551112233", Content: "Hello"}).
+			Return(nil, assert.AnError)
+
+		resp, err := app.Test(newRequest(`{"to":"+905551112233","content":"Hello"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+
+	t.Run("successfully create message", func(t *testing.T) {
+		mockCreator.
+			EXPECT().
+			CreateMessage(gomock.Any(), model.DefaultTenantID, &dto.MessageRequest{To: "+905551112233", Content: "Hello"}).
+			Return(&model.Message{ID: primitive.NewObjectID()}, nil)
+
+		resp, err := app.Test(newRequest(`{"to":"+905551112233","content":"Hello"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	})
 }
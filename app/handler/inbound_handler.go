@@ -0,0 +1,118 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/model"
+	"net/url"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type IInboundService interface {
+	ReceiveMessage(ctx context.Context, tenantID string, request *dto.InboundRequest) (*model.InboundMessage, error)
+	GetConversation(ctx context.Context, tenantID, phoneNumber string, limit int) ([]dto.ConversationEntry, error)
+}
+
+type InboundHandler struct {
+	service IInboundService
+}
+
+func NewInboundHandler(service IInboundService) *InboundHandler {
+	return &InboundHandler{service: service}
+}
+
+func (h *InboundHandler) RegisterRoutes(server *fiber.App) {
+	server.Post("/inbound", middleware.RequireScope(model.ScopeMessagesWrite), h.ReceiveMessage)
+	server.Get("/conversations/:phone", middleware.RequireScope(model.ScopeMessagesRead), h.GetConversation)
+}
+
+// ReceiveMessage godoc
+// @Summary Receive inbound message
+// @Description Receives a mobile originated message from the SMS provider and links it to the last message sent to the sender.
+// @Description Replies STOP, START and HELP unsubscribe, resubscribe and request the configured help text.
+// @Tags inbound
+// @Accept json
+// @Produce json
+// @Param request body dto.InboundRequest true "Inbound message"
+// @Success 200 {object} dto.InboundResponse "Inbound message received"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request body or sender"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /inbound [post]
+func (h *InboundHandler) ReceiveMessage(c *fiber.Ctx) error {
+	ctx := c.Context()
+	request := &dto.InboundRequest{}
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid request body",
+		})
+	}
+
+	message, err := h.service.ReceiveMessage(ctx, middleware.TenantFrom(c), request)
+	if errors.Is(err, model.ErrInvalidRequest) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.InboundResponse{
+		Message:          "inbound message received",
+		InboundMessageID: message.ID.Hex(),
+		Keyword:          message.Keyword,
+	})
+}
+
+// GetConversation godoc
+// @Summary Get conversation
+// @Description Retrieves the latest inbound and outbound messages of a phone number in time order, the leading + must be URL encoded as %2B
+// @Tags inbound
+// @Produce json
+// @Param phone path string true "Phone number"
+// @Param limit query int false "Number of messages to retrieve" default(100)
+// @Success 200 {array} dto.ConversationEntry "Conversation"
+// @Failure 400 {object} dto.ErrorResponse "Invalid phone number or limit parameter"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /conversations/{phone} [get]
+func (h *InboundHandler) GetConversation(c *fiber.Ctx) error {
+	ctx := c.Context()
+	phoneNumber, err := url.PathUnescape(c.Params("phone"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid phone number",
+		})
+	}
+
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
+	if err != nil || limit <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid limit parameter",
+		})
+	}
+
+	conversation, err := h.service.GetConversation(ctx, middleware.TenantFrom(c), phoneNumber, limit)
+	if errors.Is(err, model.ErrInvalidRequest) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(conversation)
+}
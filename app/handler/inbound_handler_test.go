@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestInboundHandler_ReceiveMessage(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockIInboundService(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewInboundHandler(mockService).RegisterRoutes(app)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/inbound", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return req
+	}
+
+	t.Run("invalid request body", func(t *testing.T) {
+		resp, err := app.Test(newRequest(`{"from":`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid sender", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			ReceiveMessage(gomock.Any(), model.DefaultTenantID, gomock.Any()).
+			Return(nil, model.ErrInvalidRequest)
+
+		resp, err := app.Test(newRequest(`{"text":"STOP"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("stop keyword", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			ReceiveMessage(gomock.Any(), model.DefaultTenantID, &dto.InboundRequest{From: "+905551112233", Text: "STOP"}).
+			Return(&model.InboundMessage{ID: primitive.NewObjectID(), Keyword: model.KeywordStop}, nil)
+
+		resp, err := app.Test(newRequest(`{"from":"+905551112233","text":"STOP"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			ReceiveMessage(gomock.Any(), model.DefaultTenantID, gomock.Any()).
+			Return(nil, assert.AnError)
+
+		resp, err := app.Test(newRequest(`{"from":"+905551112233","text":"STOP"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+}
+
+func TestInboundHandler_GetConversation(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockIInboundService(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewInboundHandler(mockService).RegisterRoutes(app)
+
+	t.Run("invalid limit", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/conversations/%2B905551112233?limit=0", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("successfully get conversation", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			GetConversation(gomock.Any(), model.DefaultTenantID, "+905551112233", 20).
+			Return([]dto.ConversationEntry{{Direction: dto.DirectionInbound, Content: "STOP"}}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/conversations/%2B905551112233?limit=20", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"context"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/model"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type INotificationLog interface {
+	GetNotifications(ctx context.Context, tenantID string, messageID primitive.ObjectID) ([]model.Notification, error)
+}
+
+type NotificationHandler struct {
+	log INotificationLog
+}
+
+func NewNotificationHandler(log INotificationLog) *NotificationHandler {
+	return &NotificationHandler{log: log}
+}
+
+func (h *NotificationHandler) RegisterRoutes(server *fiber.App) {
+	server.Get("/messages/:id/notifications", middleware.RequireScope(model.ScopeMessagesRead), h.GetNotifications)
+}
+
+// GetNotifications godoc
+// @Summary Get message notifications
+// @Description Retrieves the delivery log of the status events posted to the callback URL of a message
+// @Tags messages
+// @Produce json
+// @Param id path string true "Message ID"
+// @Success 200 {array} model.Notification "Delivery log"
+// @Failure 400 {object} dto.ErrorResponse "Invalid message ID"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /messages/{id}/notifications [get]
+func (h *NotificationHandler) GetNotifications(c *fiber.Ctx) error {
+	ctx := c.Context()
+	messageID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid message id",
+		})
+	}
+
+	notifications, err := h.log.GetNotifications(ctx, middleware.TenantFrom(c), messageID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(notifications)
+}
@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"messaging-system/app/middleware"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNotificationHandler_GetNotifications(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockLog := mocks.NewMockINotificationLog(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewNotificationHandler(mockLog).RegisterRoutes(app)
+
+	t.Run("invalid message id", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/messages/abc/notifications", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("successfully get notifications", func(t *testing.T) {
+		messageID := primitive.NewObjectID()
+		mockLog.
+			EXPECT().
+			GetNotifications(gomock.Any(), model.DefaultTenantID, messageID).
+			Return([]model.Notification{{MessageID: messageID}}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/messages/"+messageID.Hex()+"/notifications", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("error getting notifications", func(t *testing.T) {
+		mockLog.
+			EXPECT().
+			GetNotifications(gomock.Any(), model.DefaultTenantID, gomock.Any()).
+			Return(nil, assert.AnError)
+
+		path := "/messages/" + primitive.NewObjectID().Hex() + "/notifications"
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, path, nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/model"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type IReceiptService interface {
+	ReceiveReceipt(ctx context.Context, tenantID string, request *dto.ReceiptRequest) (*model.Message, error)
+}
+
+type ReceiptHandler struct {
+	service IReceiptService
+}
+
+func NewReceiptHandler(service IReceiptService) *ReceiptHandler {
+	return &ReceiptHandler{service: service}
+}
+
+func (h *ReceiptHandler) RegisterRoutes(server *fiber.App) {
+	server.Post("/receipts", middleware.RequireScope(model.ScopeMessagesWrite), h.ReceiveReceipt)
+}
+
+// ReceiveReceipt godoc
+// @Summary Receive delivery receipt
+// @Description Receives a delivery receipt of the SMS provider for a sent message, identified by the id the provider accepted it with.
+// @Description Delivered messages report message.delivered to their callback, undelivered ones move to failed and report message.failed.
+// @Tags inbound
+// @Accept json
+// @Produce json
+// @Param request body dto.ReceiptRequest true "Delivery receipt"
+// @Success 200 {object} dto.ReceiptResponse "Receipt received or already received before"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request body"
+// @Failure 404 {object} dto.ErrorResponse "No sent message with this provider message id"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /receipts [post]
+func (h *ReceiptHandler) ReceiveReceipt(c *fiber.Ctx) error {
+	ctx := c.Context()
+	request := &dto.ReceiptRequest{}
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid request body",
+		})
+	}
+
+	message, err := h.service.ReceiveReceipt(ctx, middleware.TenantFrom(c), request)
+	switch {
+	case errors.Is(err, model.ErrInvalidRequest):
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	case errors.Is(err, model.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error: "message not found",
+		})
+	case errors.Is(err, model.ErrAlreadyExists):
+		// providers retry receipts they saw no success for
+		return c.Status(fiber.StatusOK).JSON(dto.ReceiptResponse{
+			Message: "receipt already received",
+		})
+	case err != nil:
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.ReceiptResponse{
+		Message:   "receipt received",
+		MessageID: message.ID.Hex(),
+	})
+}
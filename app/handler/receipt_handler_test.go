@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReceiptHandler_ReceiveReceipt(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockIReceiptService(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewReceiptHandler(mockService).RegisterRoutes(app)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/receipts", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return req
+	}
+
+	t.Run("invalid request body", func(t *testing.T) {
+		resp, err := app.Test(newRequest(`{"messageId":`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid status", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			ReceiveReceipt(gomock.Any(), model.DefaultTenantID, gomock.Any()).
+			Return(nil, model.ErrInvalidRequest)
+
+		resp, err := app.Test(newRequest(`{"messageId":"webhook-1","status":"read"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("delivered", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			ReceiveReceipt(gomock.Any(), model.DefaultTenantID,
+				&dto.ReceiptRequest{MessageID: "webhook-1", Status: dto.ReceiptDelivered}).
+			Return(&model.Message{ID: primitive.NewObjectID()}, nil)
+
+		resp, err := app.Test(newRequest(`{"messageId":"webhook-1","status":"delivered"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("repeated receipt", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			ReceiveReceipt(gomock.Any(), model.DefaultTenantID, gomock.Any()).
+			Return(nil, model.ErrAlreadyExists)
+
+		resp, err := app.Test(newRequest(`{"messageId":"webhook-1","status":"delivered"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("unknown message", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			ReceiveReceipt(gomock.Any(), model.DefaultTenantID, gomock.Any()).
+			Return(nil, model.ErrNotFound)
+
+		resp, err := app.Test(newRequest(`{"messageId":"webhook-9","status":"delivered"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("service error", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			ReceiveReceipt(gomock.Any(), model.DefaultTenantID, gomock.Any()).
+			Return(nil, assert.AnError)
+
+		resp, err := app.Test(newRequest(`{"messageId":"webhook-1","status":"delivered"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+}
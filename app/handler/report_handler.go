@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/model"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const reportFormatCSV = "csv"
+
+type IReportService interface {
+	Summary(ctx context.Context, tenantID string, request *dto.ReportRequest) (*model.Report, error)
+	Timeseries(ctx context.Context, tenantID string, request *dto.ReportRequest) (*model.Report, error)
+}
+
+type ReportHandler struct {
+	service IReportService
+}
+
+func NewReportHandler(service IReportService) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+func (h *ReportHandler) RegisterRoutes(server *fiber.App) {
+	reports := server.Group("/reports")
+	reports.Get("/summary", middleware.RequireScope(model.ScopeMessagesRead), h.GetSummary)
+	reports.Get("/timeseries", middleware.RequireScope(model.ScopeMessagesRead), h.GetTimeseries)
+}
+
+// GetSummary godoc
+// @Summary Get message summary
+// @Description Counts the messages created within a time range by status, provider and campaign. Failed counts unsent messages whose last attempt failed.
+// @Tags reports
+// @Produce json,text/csv
+// @Param from query string false "Start of the range, RFC 3339 or YYYY-MM-DD" default(7 days before to)
+// @Param to query string false "End of the range, exclusive, RFC 3339 or YYYY-MM-DD" default(end of the current hour)
+// @Param groupBy query string false "Comma separated dimensions: status, provider, campaign" default(status)
+// @Param format query string false "csv to download the rows as CSV"
+// @Success 200 {object} model.Report "Report"
+// @Failure 400 {object} dto.ErrorResponse "Invalid range or dimension"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /reports/summary [get]
+func (h *ReportHandler) GetSummary(c *fiber.Ctx) error {
+	request, err := reportRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	report, err := h.service.Summary(c.Context(), middleware.TenantFrom(c), request)
+	if err != nil {
+		return reportError(c, err)
+	}
+
+	return sendReport(c, "summary", report)
+}
+
+// GetTimeseries godoc
+// @Summary Get message time series
+// @Description Counts the messages created within a time range per hour or day, by status, provider and campaign.
+// @Tags reports
+// @Produce json,text/csv
+// @Param from query string false "Start of the range, RFC 3339 or YYYY-MM-DD" default(7 days before to)
+// @Param to query string false "End of the range, exclusive, RFC 3339 or YYYY-MM-DD" default(end of the current hour)
+// @Param interval query string false "Bucket size: hour or day" default(day)
+// @Param groupBy query string false "Comma separated dimensions: status, provider, campaign" default(status)
+// @Param format query string false "csv to download the rows as CSV"
+// @Success 200 {object} model.Report "Report"
+// @Failure 400 {object} dto.ErrorResponse "Invalid range, interval or dimension"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /reports/timeseries [get]
+func (h *ReportHandler) GetTimeseries(c *fiber.Ctx) error {
+	request, err := reportRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	request.Interval = strings.ToLower(c.Query("interval"))
+
+	report, err := h.service.Timeseries(c.Context(), middleware.TenantFrom(c), request)
+	if err != nil {
+		return reportError(c, err)
+	}
+
+	return sendReport(c, "timeseries", report)
+}
+
+// reportRequest reads the range and dimensions of a report from the query, the service fills in the defaults.
+func reportRequest(c *fiber.Ctx) (*dto.ReportRequest, error) {
+	request := &dto.ReportRequest{}
+
+	var err error
+	if request.From, err = queryTime(c.Query("from")); err != nil {
+		return nil, errors.New("invalid from parameter")
+	}
+	if request.To, err = queryTime(c.Query("to")); err != nil {
+		return nil, errors.New("invalid to parameter")
+	}
+
+	if groupBy := c.Query("groupBy"); groupBy != "" {
+		for _, dimension := range strings.Split(groupBy, ",") {
+			request.GroupBy = append(request.GroupBy, strings.ToLower(strings.TrimSpace(dimension)))
+		}
+	}
+
+	return request, nil
+}
+
+// queryTime reads a time in RFC 3339 or a date, which stands for its start in UTC.
+func queryTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if date, err := time.Parse(time.DateOnly, value); err == nil {
+		return date, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// sendReport answers with the report as JSON, or with its rows as a CSV download when asked for.
+func sendReport(c *fiber.Ctx, name string, report *model.Report) error {
+	asCSV := strings.EqualFold(c.Query("format"), reportFormatCSV) ||
+		strings.Contains(c.Get(fiber.HeaderAccept), "text/csv")
+	if !asCSV {
+		return c.Status(fiber.StatusOK).JSON(report)
+	}
+
+	c.Attachment("report-" + name + ".csv")
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	return writeReportCSV(c.Status(fiber.StatusOK), report)
+}
+
+// writeReportCSV writes a header and a line per row, with a time column for time series and a column per
+// dimension the report is grouped by.
+func writeReportCSV(w io.Writer, report *model.Report) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{}
+	if report.Interval != "" {
+		header = append(header, "time")
+	}
+	header = append(header, report.GroupBy...)
+	if err := writer.Write(append(header, "count", "segments")); err != nil {
+		return err
+	}
+
+	for _, row := range report.Rows {
+		record := []string{}
+		if report.Interval != "" && row.Time != nil {
+			record = append(record, row.Time.Format(time.RFC3339))
+		}
+		for _, dimension := range report.GroupBy {
+			switch dimension {
+			case dto.ReportGroupStatus:
+				record = append(record, csvCell(row.Status))
+			case dto.ReportGroupProvider:
+				record = append(record, csvCell(row.Provider))
+			case dto.ReportGroupCampaign:
+				record = append(record, csvCell(row.CampaignID))
+			}
+		}
+		record = append(record, strconv.Itoa(row.Count), strconv.Itoa(row.Segments))
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// csvCell prefixes values spreadsheets would evaluate as a formula with a single quote, so opening a CSV
+// download cannot run content an API caller chose. Numbers such as E.164 phone numbers are kept as they are.
+func csvCell(value string) string {
+	if value == "" || !strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return value
+	}
+	if _, err := strconv.ParseFloat(value, 64); err == nil {
+		return value
+	}
+	return "'" + value
+}
+
+func reportError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidRequest):
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+}
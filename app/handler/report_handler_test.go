@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"io"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportHandler_GetSummary(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockIReportService(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewReportHandler(mockService).RegisterRoutes(app)
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 10, 8, 12, 0, 0, 0, time.UTC)
+
+	t.Run("invalid from parameter", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/reports/summary?from=yesterday", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			Summary(gomock.Any(), model.DefaultTenantID, gomock.Any()).
+			Return(nil, model.ErrInvalidRequest)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/reports/summary?groupBy=country", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("successfully get summary", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			Summary(gomock.Any(), model.DefaultTenantID, &dto.ReportRequest{
+				From:    from,
+				To:      to,
+				GroupBy: []string{dto.ReportGroupStatus, dto.ReportGroupCampaign},
+			}).
+			Return(&model.Report{From: from, To: to, Total: 3}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet,
+			"/reports/summary?from=2026-10-01&to=2026-10-08T12:00:00Z&groupBy=status,%20Campaign", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, fiber.MIMEApplicationJSON, resp.Header.Get(fiber.HeaderContentType))
+	})
+
+	t.Run("export summary as csv", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			Summary(gomock.Any(), model.DefaultTenantID, gomock.Any()).
+			Return(&model.Report{
+				GroupBy: []string{dto.ReportGroupStatus, dto.ReportGroupProvider},
+				Rows: []model.ReportRow{
+					{Status: model.StatusSent, Provider: "sms.example.com", Count: 40, Segments: 52},
+					{Status: model.ReportStatusRetrying, Count: 2, Segments: 2},
+				},
+			}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/reports/summary?format=csv", nil))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Contains(t, resp.Header.Get(fiber.HeaderContentDisposition), "report-summary.csv")
+
+		body, err := io.ReadAll(resp.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, "status,provider,count,segments\nsent,sms.example.com,40,52\nretrying,,2,2\n", string(body))
+	})
+}
+
+func TestCSVCell(t *testing.T) {
+	for value, expected := range map[string]string{
+		"sent":                     "sent",
+		"":                         "",
+		"+905551112233":            "+905551112233",
+		"-1":                       "-1",
+		"=HYPERLINK(\"evil.com\")": "'=HYPERLINK(\"evil.com\")",
+		"+cmd|' /C calc'!A0":       "'+cmd|' /C calc'!A0",
+		"-2+3":                     "'-2+3",
+		"@SUM(A1:A2)":              "'@SUM(A1:A2)",
+		"\t=1+1":                   "'\t=1+1",
+	} {
+		assert.Equal(t, expected, csvCell(value), value)
+	}
+}
+
+func TestReportHandler_GetTimeseries(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockIReportService(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewReportHandler(mockService).RegisterRoutes(app)
+
+	t.Run("export time series as csv", func(t *testing.T) {
+		bucket := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+		mockService.
+			EXPECT().
+			Timeseries(gomock.Any(), model.DefaultTenantID, &dto.ReportRequest{Interval: dto.ReportIntervalHour}).
+			Return(&model.Report{
+				GroupBy:  []string{dto.ReportGroupStatus},
+				Interval: dto.ReportIntervalHour,
+				Rows:     []model.ReportRow{{Time: &bucket, Status: model.StatusSent, Count: 5, Segments: 5}},
+			}, nil)
+
+		req := httptest.NewRequest(http.MethodGet, "/reports/timeseries?interval=hour", nil)
+		req.Header.Set(fiber.HeaderAccept, "text/csv")
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		body, err := io.ReadAll(resp.Body)
+		assert.Nil(t, err)
+		assert.Equal(t, "time,status,count,segments\n2026-10-01T09:00:00Z,sent,5,5\n", string(body))
+	})
+
+	t.Run("error getting time series", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			Timeseries(gomock.Any(), model.DefaultTenantID, gomock.Any()).
+			Return(nil, assert.AnError)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/reports/timeseries", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/model"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type IRetentionReporter interface {
+	DryRun(ctx context.Context, tenantID string, now time.Time) (*model.RetentionReport, error)
+}
+
+type RetentionHandler struct {
+	reporter IRetentionReporter
+}
+
+func NewRetentionHandler(reporter IRetentionReporter) *RetentionHandler {
+	return &RetentionHandler{reporter: reporter}
+}
+
+func (h *RetentionHandler) RegisterRoutes(server *fiber.App) {
+	server.Get("/retention/dry-run", middleware.RequireScope(model.ScopeProcessorAdmin), h.GetDryRun)
+}
+
+// GetDryRun godoc
+// @Summary Get retention dry run
+// @Description Counts the messages of the caller's tenant the retention archiver would move out of the messages
+// @Description collection now, per status. Nothing is archived.
+// @Tags retention
+// @Produce json
+// @Success 200 {object} model.RetentionReport "Messages past their retention"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /retention/dry-run [get]
+func (h *RetentionHandler) GetDryRun(c *fiber.Ctx) error {
+	report, err := h.reporter.DryRun(c.Context(), middleware.TenantFrom(c), time.Now().UTC())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(report)
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"encoding/json"
+	"messaging-system/app/middleware"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetentionHandler_GetDryRun(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockReporter := mocks.NewMockIRetentionReporter(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewRetentionHandler(mockReporter).RegisterRoutes(app)
+
+	t.Run("error counting messages", func(t *testing.T) {
+		mockReporter.EXPECT().DryRun(gomock.Any(), model.DefaultTenantID, gomock.Any()).Return(nil, assert.AnError)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/retention/dry-run", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+
+	t.Run("successfully get dry run", func(t *testing.T) {
+		mockReporter.
+			EXPECT().
+			DryRun(gomock.Any(), model.DefaultTenantID, gomock.Any()).
+			Return(&model.RetentionReport{
+				Archive:  "collection",
+				Total:    3,
+				Statuses: []model.RetentionStatus{{Status: model.StatusSent, RetainFor: "2160h0m0s", Messages: 3}},
+			}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/retention/dry-run", nil))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		report := &model.RetentionReport{}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(report))
+		assert.Equal(t, int64(3), report.Total)
+	})
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/model"
+	"messaging-system/app/pii"
+	"net/url"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ISubjectService interface {
+	Export(ctx context.Context, tenantID, phoneNumber string) (*model.SubjectExport, error)
+	Erase(ctx context.Context, tenantID, phoneNumber, mode string) (*model.SubjectErasure, error)
+}
+
+type SubjectHandler struct {
+	service ISubjectService
+	audit   IAuditRecorder
+}
+
+func NewSubjectHandler(service ISubjectService, audit IAuditRecorder) *SubjectHandler {
+	return &SubjectHandler{
+		service: service,
+		audit:   audit,
+	}
+}
+
+func (h *SubjectHandler) RegisterRoutes(server *fiber.App) {
+	subjects := server.Group("/subjects")
+	subjects.Get("/:phone/export", middleware.RequireScope(model.ScopeProcessorAdmin), h.ExportSubject)
+	subjects.Delete("/:phone", middleware.RequireScope(model.ScopeProcessorAdmin), h.EraseSubject)
+}
+
+// ExportSubject godoc
+// @Summary Export data subject
+// @Description Returns everything stored about a phone number: messages, archived messages of the archive
+// @Description collection and archive files, inbound messages, contact list memberships, outbox rows, the campaigns
+// @Description whose audience holds it and its suppression. The leading + must be URL encoded as %2B
+// @Tags subjects
+// @Produce json
+// @Param phone path string true "Phone number"
+// @Success 200 {object} model.SubjectExport "Stored data of the phone number"
+// @Failure 400 {object} dto.ErrorResponse "Invalid phone number"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /subjects/{phone}/export [get]
+func (h *SubjectHandler) ExportSubject(c *fiber.Ctx) error {
+	phoneNumber, err := url.PathUnescape(c.Params("phone"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid phone number",
+		})
+	}
+
+	export, err := h.service.Export(c.Context(), middleware.TenantFrom(c), phoneNumber)
+	if err != nil {
+		return subjectError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(export)
+}
+
+// EraseSubject godoc
+// @Summary Erase data subject
+// @Description Erases the messages, archived messages of the archive collection and archive files, inbound
+// @Description messages, contacts and outbox rows of a phone number, removes it from the audience of campaigns and
+// @Description clears its cache entries. With mode=anonymize messages are kept for reports without phone number
+// @Description and content. The suppression of the number is kept. Nothing is erased when a store cannot be, such
+// @Description as archive files that cannot be rewritten. The leading + must be URL encoded as %2B
+// @Tags subjects
+// @Produce json
+// @Param phone path string true "Phone number"
+// @Param mode query string false "erase (default) or anonymize"
+// @Success 200 {object} model.SubjectErasure "Erased documents"
+// @Failure 400 {object} dto.ErrorResponse "Invalid phone number or mode"
+// @Failure 409 {object} dto.ErrorResponse "A store cannot be erased"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error or action not recorded in the audit log"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /subjects/{phone} [delete]
+func (h *SubjectHandler) EraseSubject(c *fiber.Ctx) error {
+	phoneNumber, err := url.PathUnescape(c.Params("phone"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid phone number",
+		})
+	}
+
+	ctx := c.Context()
+	erasure, err := h.service.Erase(ctx, middleware.TenantFrom(c), phoneNumber, c.Query("mode"))
+	if err != nil {
+		return subjectError(c, err)
+	}
+
+	// the erased number must not survive in the audit log
+	recorded := *erasure
+	recorded.PhoneNumber = pii.MaskPhoneNumber(erasure.PhoneNumber)
+	event := auditEvent(c, model.AuditActionSubjectErase, recorded.PhoneNumber)
+	event.After = &recorded
+	if err := h.audit.Record(ctx, event); err != nil {
+		return auditError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(erasure)
+}
+
+func subjectError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, model.ErrInvalidRequest) {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	if errors.Is(err, model.ErrNotErasable) {
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+		Error: err.Error(),
+	})
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"messaging-system/app/middleware"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubjectHandler_ExportSubject(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockISubjectService(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewSubjectHandler(mockService, mocks.NewMockIAuditRecorder(mockController)).RegisterRoutes(app)
+
+	t.Run("invalid phone number", func(t *testing.T) {
+		mockService.EXPECT().Export(gomock.Any(), model.DefaultTenantID, "123").Return(nil, model.ErrInvalidRequest)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/subjects/123/export", nil))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("successfully export subject", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			Export(gomock.Any(), model.DefaultTenantID, "+905551112233").
+			Return(&model.SubjectExport{PhoneNumber: "+905551112233", Messages: []model.Message{{Content: "hi"}}}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/subjects/%2B905551112233/export", nil))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		export := &model.SubjectExport{}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(export))
+		assert.Equal(t, "hi", export.Messages[0].Content)
+	})
+}
+
+func TestSubjectHandler_EraseSubject(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockISubjectService(mockController)
+	mockAudit := mocks.NewMockIAuditRecorder(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewSubjectHandler(mockService, mockAudit).RegisterRoutes(app)
+
+	t.Run("error erasing subject", func(t *testing.T) {
+		mockService.EXPECT().Erase(gomock.Any(), model.DefaultTenantID, "+905551112233", "").Return(nil, assert.AnError)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/subjects/%2B905551112233", nil))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+
+	t.Run("store cannot be erased", func(t *testing.T) {
+		mockService.EXPECT().Erase(gomock.Any(), model.DefaultTenantID, "+905551112233", "").
+			Return(nil, fmt.Errorf("%w: archive files cannot be rewritten", model.ErrNotErasable))
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/subjects/%2B905551112233", nil))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("successfully anonymize subject", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			Erase(gomock.Any(), model.DefaultTenantID, "+905551112233", model.ErasureModeAnonymize).
+			Return(&model.SubjectErasure{PhoneNumber: "+905551112233", Mode: model.ErasureModeAnonymize, Messages: 2}, nil)
+		mockAudit.
+			EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, event *model.AuditEvent) error {
+				assert.Equal(t, model.AuditActionSubjectErase, event.Action)
+				assert.Equal(t, "+90********33", event.Target)
+				assert.Equal(t, &model.SubjectErasure{PhoneNumber: "+90********33", Mode: model.ErasureModeAnonymize,
+					Messages: 2}, event.After)
+				return nil
+			})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/subjects/%2B905551112233?mode=anonymize", nil))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+
+		erasure := &model.SubjectErasure{}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(erasure))
+		assert.Equal(t, int64(2), erasure.Messages)
+		assert.Equal(t, "+905551112233", erasure.PhoneNumber)
+	})
+
+	t.Run("erasure not recorded", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			Erase(gomock.Any(), model.DefaultTenantID, "+905551112233", "").
+			Return(&model.SubjectErasure{PhoneNumber: "+905551112233", Mode: model.ErasureModeErase}, nil)
+		mockAudit.EXPECT().Record(gomock.Any(), gomock.Any()).Return(assert.AnError)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/subjects/%2B905551112233", nil))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/model"
+	"messaging-system/app/pii"
+	"net/url"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type ISuppressionService interface {
+	Suppress(ctx context.Context, tenantID string, request *dto.SuppressionRequest,
+		source string) (*model.Suppression, error)
+	Import(ctx context.Context, tenantID string,
+		request *dto.SuppressionImportRequest) (*dto.SuppressionImportResponse, error)
+	GetSuppression(ctx context.Context, tenantID, phoneNumber string) (*model.Suppression, error)
+	GetSuppressions(ctx context.Context, tenantID string, limit int) ([]model.Suppression, error)
+	Unsuppress(ctx context.Context, tenantID, phoneNumber string) error
+}
+
+type SuppressionHandler struct {
+	service ISuppressionService
+	audit   IAuditRecorder
+}
+
+func NewSuppressionHandler(service ISuppressionService, audit IAuditRecorder) *SuppressionHandler {
+	return &SuppressionHandler{
+		service: service,
+		audit:   audit,
+	}
+}
+
+func (h *SuppressionHandler) RegisterRoutes(server *fiber.App) {
+	suppressions := server.Group("/suppressions")
+	suppressions.Post("/", middleware.RequireScope(model.ScopeMessagesWrite), h.CreateSuppression)
+	suppressions.Post("/import", middleware.RequireScope(model.ScopeMessagesWrite), h.ImportSuppressions)
+	suppressions.Get("/", middleware.RequireScope(model.ScopeMessagesRead), h.GetSuppressions)
+	suppressions.Get("/:phone", middleware.RequireScope(model.ScopeMessagesRead), h.GetSuppression)
+	suppressions.Delete("/:phone", middleware.RequireScope(model.ScopeMessagesWrite), h.DeleteSuppression)
+}
+
+// CreateSuppression godoc
+// @Summary Suppress phone number
+// @Description Adds a phone number to the suppression list, no message is sent to it until it is removed
+// @Tags suppressions
+// @Accept json
+// @Produce json
+// @Param request body dto.SuppressionRequest true "Phone number to suppress"
+// @Success 201 {object} model.Suppression "Suppressed phone number"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request body or phone number"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /suppressions [post]
+func (h *SuppressionHandler) CreateSuppression(c *fiber.Ctx) error {
+	ctx := c.Context()
+	request := &dto.SuppressionRequest{}
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid request body",
+		})
+	}
+
+	suppression, err := h.service.Suppress(ctx, middleware.TenantFrom(c), request, model.SuppressionSourceManual)
+	if err != nil {
+		return suppressionError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(suppression)
+}
+
+// ImportSuppressions godoc
+// @Summary Import suppressed phone numbers
+// @Description Adds many phone numbers to the suppression list at once, invalid numbers are skipped and reported
+// @Tags suppressions
+// @Accept json
+// @Produce json
+// @Param request body dto.SuppressionImportRequest true "Phone numbers to suppress"
+// @Success 200 {object} dto.SuppressionImportResponse "Import report"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request body"
+// @Failure 429 {object} dto.ErrorResponse "Rate limit exceeded"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /suppressions/import [post]
+func (h *SuppressionHandler) ImportSuppressions(c *fiber.Ctx) error {
+	ctx := c.Context()
+	request := &dto.SuppressionImportRequest{}
+	if err := c.BodyParser(request); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid request body",
+		})
+	}
+
+	response, err := h.service.Import(ctx, middleware.TenantFrom(c), request)
+	if err != nil {
+		return suppressionError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(response)
+}
+
+// GetSuppressions godoc
+// @Summary List suppressed phone numbers
+// @Description Retrieves the most recently suppressed phone numbers with an optional limit
+// @Tags suppressions
+// @Produce json
+// @Param limit query int false "Number of suppressions to retrieve" default(100)
+// @Success 200 {array} model.Suppression "List of suppressions"
+// @Failure 400 {object} dto.ErrorResponse "Invalid limit parameter"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /suppressions [get]
+func (h *SuppressionHandler) GetSuppressions(c *fiber.Ctx) error {
+	ctx := c.Context()
+	limit, err := strconv.Atoi(c.Query("limit", "100"))
+	if err != nil || limit <= 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid limit parameter",
+		})
+	}
+
+	suppressions, err := h.service.GetSuppressions(ctx, middleware.TenantFrom(c), limit)
+	if err != nil {
+		return suppressionError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(suppressions)
+}
+
+// GetSuppression godoc
+// @Summary Get suppressed phone number
+// @Description Retrieves the suppression of a phone number, the leading + must be URL encoded as %2B
+// @Tags suppressions
+// @Produce json
+// @Param phone path string true "Phone number"
+// @Success 200 {object} model.Suppression "Suppression"
+// @Failure 400 {object} dto.ErrorResponse "Invalid phone number"
+// @Failure 404 {object} dto.ErrorResponse "Phone number is not suppressed"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /suppressions/{phone} [get]
+func (h *SuppressionHandler) GetSuppression(c *fiber.Ctx) error {
+	ctx := c.Context()
+	phoneNumber, err := url.PathUnescape(c.Params("phone"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid phone number",
+		})
+	}
+
+	suppression, err := h.service.GetSuppression(ctx, middleware.TenantFrom(c), phoneNumber)
+	if err != nil {
+		return suppressionError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(suppression)
+}
+
+// DeleteSuppression godoc
+// @Summary Remove suppressed phone number
+// @Description Removes a phone number from the suppression list, the leading + must be URL encoded as %2B
+// @Tags suppressions
+// @Produce json
+// @Param phone path string true "Phone number"
+// @Success 200 {object} dto.SuccessResponse "Suppression removed"
+// @Failure 400 {object} dto.ErrorResponse "Invalid phone number"
+// @Failure 404 {object} dto.ErrorResponse "Phone number is not suppressed"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error or action not recorded in the audit log"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /suppressions/{phone} [delete]
+func (h *SuppressionHandler) DeleteSuppression(c *fiber.Ctx) error {
+	ctx := c.Context()
+	phoneNumber, err := url.PathUnescape(c.Params("phone"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid phone number",
+		})
+	}
+
+	tenantID := middleware.TenantFrom(c)
+	before, err := h.service.GetSuppression(ctx, tenantID, phoneNumber)
+	if err != nil {
+		return suppressionError(c, err)
+	}
+
+	if err := h.service.Unsuppress(ctx, tenantID, phoneNumber); err != nil {
+		return suppressionError(c, err)
+	}
+
+	// the audit log outlives erasures of the number, so it only keeps the number masked
+	masked := *before
+	masked.PhoneNumber = pii.MaskPhoneNumber(before.PhoneNumber)
+	event := auditEvent(c, model.AuditActionSuppressionDelete, masked.PhoneNumber)
+	event.Before = &masked
+	if err := h.audit.Record(ctx, event); err != nil {
+		return auditError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.SuccessResponse{
+		Message: "suppression removed",
+	})
+}
+
+func suppressionError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidRequest):
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	case errors.Is(err, model.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error: "phone number is not suppressed",
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+}
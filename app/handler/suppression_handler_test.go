@@ -0,0 +1,139 @@
+package handler
+
+import (
+	"context"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuppressionHandler_CreateSuppression(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockISuppressionService(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewSuppressionHandler(mockService, mocks.NewMockIAuditRecorder(mockController)).RegisterRoutes(app)
+
+	newRequest := func(path, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return req
+	}
+
+	t.Run("invalid phone number", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			Suppress(gomock.Any(), model.DefaultTenantID, gomock.Any(), model.SuppressionSourceManual).
+			Return(nil, model.ErrInvalidRequest)
+
+		resp, err := app.Test(newRequest("/suppressions", `{"phoneNumber":"123"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("successfully suppress phone number", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			Suppress(gomock.Any(), model.DefaultTenantID, &dto.SuppressionRequest{PhoneNumber: "+905551112233"}, model.SuppressionSourceManual).
+			Return(&model.Suppression{PhoneNumber: "+905551112233"}, nil)
+
+		resp, err := app.Test(newRequest("/suppressions", `{"phoneNumber":"+905551112233"}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	})
+
+	t.Run("import phone numbers", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			Import(gomock.Any(), model.DefaultTenantID, gomock.Any()).
+			Return(&dto.SuppressionImportResponse{Imported: 1}, nil)
+
+		resp, err := app.Test(newRequest("/suppressions/import", `{"phoneNumbers":["+905551112233"]}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestSuppressionHandler_GetDeleteSuppression(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockISuppressionService(mockController)
+	mockAudit := mocks.NewMockIAuditRecorder(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewSuppressionHandler(mockService, mockAudit).RegisterRoutes(app)
+
+	t.Run("invalid limit", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/suppressions?limit=abc", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("url encoded phone number", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			GetSuppression(gomock.Any(), model.DefaultTenantID, "+905551112233").
+			Return(&model.Suppression{PhoneNumber: "+905551112233"}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/suppressions/%2B905551112233", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("phone number is not suppressed", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			GetSuppression(gomock.Any(), model.DefaultTenantID, "+905551112233").
+			Return(nil, model.ErrNotFound)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/suppressions/%2B905551112233", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("successfully delete suppression", func(t *testing.T) {
+		suppression := &model.Suppression{PhoneNumber: "+905551112233", Source: model.SuppressionSourceManual}
+		mockService.
+			EXPECT().
+			GetSuppression(gomock.Any(), model.DefaultTenantID, "+905551112233").
+			Return(suppression, nil)
+		mockService.
+			EXPECT().
+			Unsuppress(gomock.Any(), model.DefaultTenantID, "+905551112233").
+			Return(nil)
+		mockAudit.
+			EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, event *model.AuditEvent) error {
+				assert.Equal(t, model.AuditActionSuppressionDelete, event.Action)
+				assert.Equal(t, "+90********33", event.Target)
+				assert.Equal(t, &model.Suppression{PhoneNumber: "+90********33", Source: model.SuppressionSourceManual},
+					event.Before)
+				return nil
+			})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/suppressions/%2B905551112233", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "+905551112233", suppression.PhoneNumber)
+	})
+}
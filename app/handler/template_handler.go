@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/model"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ITemplateService interface {
+	CreateTemplate(ctx context.Context, tenantID string, request *dto.TemplateRequest) (*model.Template, error)
+	GetTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID) (*model.Template, error)
+	GetTemplates(ctx context.Context, tenantID string) ([]model.Template, error)
+	UpdateTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID,
+		request *dto.TemplateRequest) (*model.Template, error)
+	DeleteTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID) error
+}
+
+type TemplateHandler struct {
+	service ITemplateService
+	audit   IAuditRecorder
+}
+
+func NewTemplateHandler(service ITemplateService, audit IAuditRecorder) *TemplateHandler {
+	return &TemplateHandler{
+		service: service,
+		audit:   audit,
+	}
+}
+
+func (h *TemplateHandler) RegisterRoutes(server *fiber.App) {
+	templates := server.Group("/templates")
+	templates.Post("/", middleware.RequireScope(model.ScopeMessagesWrite), h.CreateTemplate)
+	templates.Get("/", middleware.RequireScope(model.ScopeMessagesRead), h.GetTemplates)
+	templates.Get("/:id", middleware.RequireScope(model.ScopeMessagesRead), h.GetTemplate)
+	templates.Put("/:id", middleware.RequireScope(model.ScopeMessagesWrite), h.UpdateTemplate)
+	templates.Delete("/:id", middleware.RequireScope(model.ScopeMessagesWrite), h.DeleteTemplate)
+}
+
+// CreateTemplate godoc
+// @Summary Create template
+// @Description Creates a message template with one body per locale, bodies can contain {{placeholders}}
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param request body dto.TemplateRequest true "Template to create"
+// @Success 201 {object} model.Template "Created template"
+// @Failure 400 {object} dto.ErrorResponse "Invalid request body"
+// @Failure 409 {object} dto.ErrorResponse "Template name already exists"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error or action not recorded in the audit log"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /templates [post]
+func (h *TemplateHandler) CreateTemplate(c *fiber.Ctx) error {
+	ctx := c.Context()
+	request, err := parseTemplateRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	template, err := h.service.CreateTemplate(ctx, middleware.TenantFrom(c), request)
+	if err != nil {
+		return templateError(c, err)
+	}
+
+	event := auditEvent(c, model.AuditActionTemplateCreate, template.ID.Hex())
+	event.After = template
+	if err := h.audit.Record(ctx, event); err != nil {
+		return auditError(c, err)
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(template)
+}
+
+// GetTemplates godoc
+// @Summary List templates
+// @Description Retrieves all message templates ordered by name
+// @Tags templates
+// @Produce json
+// @Success 200 {array} model.Template "List of templates"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /templates [get]
+func (h *TemplateHandler) GetTemplates(c *fiber.Ctx) error {
+	ctx := c.Context()
+	templates, err := h.service.GetTemplates(ctx, middleware.TenantFrom(c))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(templates)
+}
+
+// GetTemplate godoc
+// @Summary Get template
+// @Description Retrieves a message template by ID
+// @Tags templates
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} model.Template "Template"
+// @Failure 400 {object} dto.ErrorResponse "Invalid template ID"
+// @Failure 404 {object} dto.ErrorResponse "Template not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /templates/{id} [get]
+func (h *TemplateHandler) GetTemplate(c *fiber.Ctx) error {
+	ctx := c.Context()
+	templateID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid template id",
+		})
+	}
+
+	template, err := h.service.GetTemplate(ctx, middleware.TenantFrom(c), templateID)
+	if err != nil {
+		return templateError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(template)
+}
+
+// UpdateTemplate godoc
+// @Summary Update template
+// @Description Replaces the name and locale bodies of a message template
+// @Tags templates
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Param request body dto.TemplateRequest true "Template content"
+// @Success 200 {object} model.Template "Updated template"
+// @Failure 400 {object} dto.ErrorResponse "Invalid template ID or request body"
+// @Failure 404 {object} dto.ErrorResponse "Template not found"
+// @Failure 409 {object} dto.ErrorResponse "Template name already exists"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error or action not recorded in the audit log"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /templates/{id} [put]
+func (h *TemplateHandler) UpdateTemplate(c *fiber.Ctx) error {
+	ctx := c.Context()
+	templateID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid template id",
+		})
+	}
+
+	request, err := parseTemplateRequest(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	tenantID := middleware.TenantFrom(c)
+	before, err := h.service.GetTemplate(ctx, tenantID, templateID)
+	if err != nil {
+		return templateError(c, err)
+	}
+
+	template, err := h.service.UpdateTemplate(ctx, tenantID, templateID, request)
+	if err != nil {
+		return templateError(c, err)
+	}
+
+	event := auditEvent(c, model.AuditActionTemplateUpdate, templateID.Hex())
+	event.Before = before
+	event.After = template
+	if err := h.audit.Record(ctx, event); err != nil {
+		return auditError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(template)
+}
+
+// DeleteTemplate godoc
+// @Summary Delete template
+// @Description Deletes a message template by ID
+// @Tags templates
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} dto.SuccessResponse "Template deleted"
+// @Failure 400 {object} dto.ErrorResponse "Invalid template ID"
+// @Failure 404 {object} dto.ErrorResponse "Template not found"
+// @Failure 500 {object} dto.ErrorResponse "Internal server error or action not recorded in the audit log"
+// @Security ApiKeyAuth
+// @Security BearerAuth
+// @Router /templates/{id} [delete]
+func (h *TemplateHandler) DeleteTemplate(c *fiber.Ctx) error {
+	ctx := c.Context()
+	templateID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(dto.ErrorResponse{
+			Error: "invalid template id",
+		})
+	}
+
+	tenantID := middleware.TenantFrom(c)
+	before, err := h.service.GetTemplate(ctx, tenantID, templateID)
+	if err != nil {
+		return templateError(c, err)
+	}
+
+	if err := h.service.DeleteTemplate(ctx, tenantID, templateID); err != nil {
+		return templateError(c, err)
+	}
+
+	event := auditEvent(c, model.AuditActionTemplateDelete, templateID.Hex())
+	event.Before = before
+	if err := h.audit.Record(ctx, event); err != nil {
+		return auditError(c, err)
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dto.SuccessResponse{
+		Message: "template deleted",
+	})
+}
+
+func parseTemplateRequest(c *fiber.Ctx) (*dto.TemplateRequest, error) {
+	request := &dto.TemplateRequest{}
+	if err := c.BodyParser(request); err != nil {
+		return nil, errors.New("invalid request body")
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	return request, nil
+}
+
+func templateError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, model.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(dto.ErrorResponse{
+			Error: "template not found",
+		})
+	case errors.Is(err, model.ErrAlreadyExists):
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+			Error: "template name already exists",
+		})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+}
@@ -0,0 +1,242 @@
+package handler
+
+import (
+	"context"
+	"messaging-system/app/dto"
+	"messaging-system/app/middleware"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const templateBody = `{"name":"otp","defaultLocale":"en","locales":{"en":"Your code is {{code}}","tr":"Kodunuz {{code}}"}}`
+
+func TestTemplateHandler_CreateTemplate(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockITemplateService(mockController)
+	mockAudit := mocks.NewMockIAuditRecorder(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewTemplateHandler(mockService, mockAudit).RegisterRoutes(app)
+
+	newRequest := func(body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/templates", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		return req
+	}
+
+	t.Run("invalid template", func(t *testing.T) {
+		resp, err := app.Test(newRequest(`{"name":"otp","defaultLocale":"de","locales":{"en":"Hi"}}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("malformed placeholder", func(t *testing.T) {
+		resp, err := app.Test(newRequest(`{"name":"otp","defaultLocale":"en","locales":{"en":"Hi {{name}"}}`))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			CreateTemplate(gomock.Any(), model.DefaultTenantID, gomock.Any()).
+			Return(nil, model.ErrAlreadyExists)
+
+		resp, err := app.Test(newRequest(templateBody))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("successfully create template", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			CreateTemplate(gomock.Any(), model.DefaultTenantID, &dto.TemplateRequest{
+				Name:          "otp",
+				DefaultLocale: "en",
+				Locales:       map[string]string{"en": "Your code is {{code}}", "tr": "Kodunuz {{code}}"},
+			}).
+			Return(&model.Template{ID: primitive.NewObjectID(), Name: "otp"}, nil)
+		mockAudit.
+			EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, event *model.AuditEvent) error {
+				assert.Equal(t, model.AuditActionTemplateCreate, event.Action)
+				assert.Nil(t, event.Before)
+				assert.Equal(t, "otp", event.After.(*model.Template).Name)
+				return nil
+			})
+
+		resp, err := app.Test(newRequest(templateBody))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	})
+}
+
+func TestTemplateHandler_GetTemplate(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockITemplateService(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewTemplateHandler(mockService, mocks.NewMockIAuditRecorder(mockController)).RegisterRoutes(app)
+	templateID := primitive.NewObjectID()
+
+	t.Run("invalid template id", func(t *testing.T) {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/templates/abc", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusBadRequest, resp.StatusCode)
+	})
+
+	t.Run("template not found", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			GetTemplate(gomock.Any(), model.DefaultTenantID, templateID).
+			Return(nil, model.ErrNotFound)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/templates/"+templateID.Hex(), nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("successfully get template", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			GetTemplate(gomock.Any(), model.DefaultTenantID, templateID).
+			Return(&model.Template{ID: templateID}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/templates/"+templateID.Hex(), nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("list templates", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			GetTemplates(gomock.Any(), model.DefaultTenantID).
+			Return([]model.Template{{ID: templateID}}, nil)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/templates", nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestTemplateHandler_UpdateDeleteTemplate(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockService := mocks.NewMockITemplateService(mockController)
+	mockAudit := mocks.NewMockIAuditRecorder(mockController)
+	app := fiber.New()
+	app.Use(middleware.AllowAll())
+	NewTemplateHandler(mockService, mockAudit).RegisterRoutes(app)
+	templateID := primitive.NewObjectID()
+
+	t.Run("successfully update template", func(t *testing.T) {
+		before := &model.Template{ID: templateID, Name: "welcome"}
+		after := &model.Template{ID: templateID, Name: "otp"}
+		mockService.
+			EXPECT().
+			GetTemplate(gomock.Any(), model.DefaultTenantID, templateID).
+			Return(before, nil)
+		mockService.
+			EXPECT().
+			UpdateTemplate(gomock.Any(), model.DefaultTenantID, templateID, gomock.Any()).
+			Return(after, nil)
+		mockAudit.
+			EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, event *model.AuditEvent) error {
+				assert.Equal(t, model.AuditActionTemplateUpdate, event.Action)
+				assert.Equal(t, templateID.Hex(), event.Target)
+				assert.Equal(t, before, event.Before)
+				assert.Equal(t, after, event.After)
+				return nil
+			})
+
+		req := httptest.NewRequest(http.MethodPut, "/templates/"+templateID.Hex(), strings.NewReader(templateBody))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		resp, err := app.Test(req)
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("delete missing template", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			GetTemplate(gomock.Any(), model.DefaultTenantID, templateID).
+			Return(nil, model.ErrNotFound)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/templates/"+templateID.Hex(), nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("successfully delete template", func(t *testing.T) {
+		before := &model.Template{ID: templateID, Name: "otp"}
+		mockService.
+			EXPECT().
+			GetTemplate(gomock.Any(), model.DefaultTenantID, templateID).
+			Return(before, nil)
+		mockService.
+			EXPECT().
+			DeleteTemplate(gomock.Any(), model.DefaultTenantID, templateID).
+			Return(nil)
+		mockAudit.
+			EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, event *model.AuditEvent) error {
+				assert.Equal(t, model.AuditActionTemplateDelete, event.Action)
+				assert.Equal(t, before, event.Before)
+				assert.Nil(t, event.After)
+				return nil
+			})
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/templates/"+templateID.Hex(), nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("template deleted but not recorded", func(t *testing.T) {
+		mockService.
+			EXPECT().
+			GetTemplate(gomock.Any(), model.DefaultTenantID, templateID).
+			Return(&model.Template{ID: templateID}, nil)
+		mockService.
+			EXPECT().
+			DeleteTemplate(gomock.Any(), model.DefaultTenantID, templateID).
+			Return(nil)
+		mockAudit.
+			EXPECT().
+			Record(gomock.Any(), gomock.Any()).
+			Return(assert.AnError)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodDelete, "/templates/"+templateID.Hex(), nil))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	HeaderAPIKey    = "X-API-Key"
+	localsPrincipal = "principal"
+)
+
+type IAPIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, key string) (*model.Principal, error)
+}
+
+type ITokenAuthenticator interface {
+	Authenticate(ctx context.Context, token string) (*model.Principal, error)
+}
+
+// Authenticate accepts a bearer token in the Authorization header or an API key in the X-API-Key
+// header and stores the caller for RequireScope. Bearer tokens are rejected when tokens is nil.
+func Authenticate(apiKeys IAPIKeyAuthenticator, tokens ITokenAuthenticator) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var (
+			principal *model.Principal
+			err       error
+		)
+
+		token, isBearer := bearerToken(c.Get(fiber.HeaderAuthorization))
+		key := c.Get(HeaderAPIKey)
+		switch {
+		case isBearer && tokens != nil:
+			principal, err = tokens.Authenticate(c.Context(), token)
+		case isBearer:
+			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
+				Error: "bearer tokens are not accepted",
+			})
+		case key != "":
+			principal, err = apiKeys.Authenticate(c.Context(), key)
+		default:
+			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
+				Error: "missing API key or bearer token",
+			})
+		}
+
+		if errors.Is(err, model.ErrUnauthorized) {
+			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
+				Error: "invalid credentials",
+			})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+
+		SetPrincipal(c, principal)
+		return c.Next()
+	}
+}
+
+func bearerToken(header string) (string, bool) {
+	const prefix = "Bearer "
+	if len(header) <= len(prefix) || !strings.EqualFold(header[:len(prefix)], prefix) {
+		return "", false
+	}
+	return strings.TrimSpace(header[len(prefix):]), true
+}
+
+// AllowAll grants every scope to every request, it is used when authentication is disabled.
+func AllowAll() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		SetPrincipal(c, &model.Principal{
+			ID:       model.AnonymousPrincipalID,
+			Name:     model.AnonymousPrincipalID,
+			TenantID: model.DefaultTenantID,
+			Scopes:   model.Scopes,
+		})
+		return c.Next()
+	}
+}
+
+// RequireScope rejects requests whose caller was not granted scope.
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal := PrincipalFrom(c)
+		if principal == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(dto.ErrorResponse{
+				Error: "authentication required",
+			})
+		}
+
+		if !principal.HasScope(scope) {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error: "missing scope " + scope,
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// RequirePlatform rejects callers outside the default tenant from actions that affect every tenant,
+// such as starting and stopping the shared message processor.
+func RequirePlatform() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if TenantFrom(c) != model.DefaultTenantID {
+			return c.Status(fiber.StatusForbidden).JSON(dto.ErrorResponse{
+				Error: "only the " + model.DefaultTenantID + " tenant may perform this action",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+func SetPrincipal(c *fiber.Ctx, principal *model.Principal) {
+	c.Locals(localsPrincipal, principal)
+}
+
+// PrincipalFrom returns the authenticated caller of the request or nil.
+func PrincipalFrom(c *fiber.Ctx) *model.Principal {
+	principal, _ := c.Locals(localsPrincipal).(*model.Principal)
+	return principal
+}
+
+// TenantFrom returns the tenant of the caller, callers without a tenant belong to the default tenant.
+func TenantFrom(c *fiber.Ctx) string {
+	if principal := PrincipalFrom(c); principal != nil && principal.TenantID != "" {
+		return principal.TenantID
+	}
+	return model.DefaultTenantID
+}
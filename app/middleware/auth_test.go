@@ -0,0 +1,189 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthenticate_APIKey(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockAuthenticator := mocks.NewMockIAPIKeyAuthenticator(mockController)
+
+	app := fiber.New()
+	app.Use(Authenticate(mockAuthenticator, nil))
+	app.Get("/messages", RequireScope(model.ScopeMessagesRead), func(c *fiber.Ctx) error {
+		return c.SendString(PrincipalFrom(c).Name)
+	})
+
+	newRequest := func(key string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/messages", nil)
+		if key != "" {
+			req.Header.Set(HeaderAPIKey, key)
+		}
+		return req
+	}
+
+	t.Run("missing key", func(t *testing.T) {
+		resp, err := app.Test(newRequest(""))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("invalid key", func(t *testing.T) {
+		mockAuthenticator.
+			EXPECT().
+			Authenticate(gomock.Any(), "invalid").
+			Return(nil, model.ErrUnauthorized)
+
+		resp, err := app.Test(newRequest("invalid"))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("authenticator fails", func(t *testing.T) {
+		mockAuthenticator.
+			EXPECT().
+			Authenticate(gomock.Any(), "valid").
+			Return(nil, fmt.Errorf("mongo down"))
+
+		resp, err := app.Test(newRequest("valid"))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+
+	t.Run("scope missing", func(t *testing.T) {
+		mockAuthenticator.
+			EXPECT().
+			Authenticate(gomock.Any(), "writer").
+			Return(&model.Principal{Name: "writer", Scopes: []string{model.ScopeMessagesWrite}}, nil)
+
+		resp, err := app.Test(newRequest("writer"))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+	})
+
+	t.Run("scope granted", func(t *testing.T) {
+		mockAuthenticator.
+			EXPECT().
+			Authenticate(gomock.Any(), "reader").
+			Return(&model.Principal{Name: "reader", Scopes: []string{model.ScopeMessagesRead}}, nil)
+
+		resp, err := app.Test(newRequest("reader"))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestAuthenticate_BearerToken(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockKeys := mocks.NewMockIAPIKeyAuthenticator(mockController)
+	mockTokens := mocks.NewMockITokenAuthenticator(mockController)
+
+	newApp := func(tokens ITokenAuthenticator) *fiber.App {
+		app := fiber.New()
+		app.Use(Authenticate(mockKeys, tokens))
+		app.Post("/processor/:action", RequireScope(model.ScopeProcessorAdmin), func(c *fiber.Ctx) error {
+			return c.SendString(PrincipalFrom(c).ID)
+		})
+		return app
+	}
+
+	newRequest := func(token string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/processor/start", nil)
+		req.Header.Set(fiber.HeaderAuthorization, "Bearer "+token)
+		return req
+	}
+
+	t.Run("valid token with scope", func(t *testing.T) {
+		mockTokens.
+			EXPECT().
+			Authenticate(gomock.Any(), "token").
+			Return(&model.Principal{ID: "user-1", Scopes: []string{model.ScopeProcessorAdmin}}, nil)
+
+		resp, err := newApp(mockTokens).Test(newRequest("token"))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+
+	t.Run("invalid token", func(t *testing.T) {
+		mockTokens.
+			EXPECT().
+			Authenticate(gomock.Any(), "expired").
+			Return(nil, fmt.Errorf("%w: token is expired", model.ErrUnauthorized))
+
+		resp, err := newApp(mockTokens).Test(newRequest("expired"))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+
+	t.Run("bearer tokens disabled", func(t *testing.T) {
+		resp, err := newApp(nil).Test(newRequest("token"))
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+	})
+}
+
+func TestRequireScope_WithoutPrincipal(t *testing.T) {
+	app := fiber.New()
+	app.Get("/messages", RequireScope(model.ScopeMessagesRead), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/messages", nil))
+	assert.Nil(t, err)
+	assert.Equal(t, fiber.StatusUnauthorized, resp.StatusCode)
+}
+
+func TestRequirePlatform(t *testing.T) {
+	app := fiber.New()
+	app.Post("/processor/start", func(c *fiber.Ctx) error {
+		SetPrincipal(c, &model.Principal{ID: "key-id", TenantID: c.Query("tenant")})
+		return c.Next()
+	}, RequirePlatform(), func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	for query, status := range map[string]int{
+		"":                fiber.StatusOK,
+		"?tenant=default": fiber.StatusOK,
+		"?tenant=retail":  fiber.StatusForbidden,
+	} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodPost, "/processor/start"+query, nil))
+		assert.Nil(t, err)
+		assert.Equal(t, status, resp.StatusCode, query)
+	}
+}
+
+func TestTenantFrom(t *testing.T) {
+	app := fiber.New()
+	app.Get("/tenant", func(c *fiber.Ctx) error {
+		if c.Query("tenant") != "" {
+			SetPrincipal(c, &model.Principal{ID: "key-id", TenantID: c.Query("tenant")})
+		}
+		return c.SendString(TenantFrom(c))
+	})
+
+	for query, tenantID := range map[string]string{
+		"":               model.DefaultTenantID,
+		"?tenant=retail": "retail",
+	} {
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/tenant"+query, nil))
+		assert.Nil(t, err)
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, tenantID, string(body))
+	}
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"messaging-system/app/cache"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	HeaderIdempotencyKey      = "Idempotency-Key"
+	HeaderIdempotentReplayed  = "Idempotent-Replayed"
+	idempotencyCacheKeyPrefix = "idempotency:"
+
+	// IdempotencyLockTTL bounds how long a request in progress holds its key. It outlasts any request
+	// by a wide margin, a crashed instance only blocks retries until it expires.
+	IdempotencyLockTTL = time.Minute
+)
+
+type IIdempotencyStore interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value interface{}) error
+	SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Idempotency makes POST requests carrying an Idempotency-Key header safe to retry.
+// The first response for a key is stored and replayed for every retry with the same body,
+// while reusing the key with a different body is rejected. Keys are scoped to the tenant of the caller,
+// so it has to run after the authentication middleware.
+func Idempotency(store IIdempotencyStore) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(HeaderIdempotencyKey)
+		if c.Method() != fiber.MethodPost || key == "" {
+			return c.Next()
+		}
+
+		ctx := c.Context()
+		cacheKey := cache.TenantKey(TenantFrom(c), idempotencyCacheKeyPrefix+c.Path()+":"+key)
+		requestHash := hashBody(c.Body())
+
+		pending, err := json.Marshal(&model.IdempotencyRecord{RequestHash: requestHash})
+		if err != nil {
+			return err
+		}
+
+		acquired, err := store.SetNX(ctx, cacheKey, pending, IdempotencyLockTTL)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+				Error: err.Error(),
+			})
+		}
+
+		if !acquired {
+			return replay(c, store, cacheKey, requestHash)
+		}
+
+		if err := c.Next(); err != nil {
+			_ = store.Delete(ctx, cacheKey)
+			return err
+		}
+
+		// server errors are not stored so that the client can retry with the same key
+		statusCode := c.Response().StatusCode()
+		if statusCode >= fiber.StatusInternalServerError {
+			return store.Delete(ctx, cacheKey)
+		}
+
+		completed, err := json.Marshal(&model.IdempotencyRecord{
+			RequestHash: requestHash,
+			Completed:   true,
+			StatusCode:  statusCode,
+			Body:        c.Response().Body(),
+		})
+		if err != nil {
+			return err
+		}
+
+		// the completed response replaces the lock and is kept for the full cache TTL
+		return store.Set(ctx, cacheKey, completed)
+	}
+}
+
+func replay(c *fiber.Ctx, store IIdempotencyStore, cacheKey, requestHash string) error {
+	value, err := store.Get(c.Context(), cacheKey)
+	if errors.Is(err, cache.ErrCacheMiss) {
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+			Error: "request with this idempotency key is in progress, retry later",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	record := &model.IdempotencyRecord{}
+	if err := json.Unmarshal(value, record); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(dto.ErrorResponse{
+			Error: err.Error(),
+		})
+	}
+
+	if record.RequestHash != requestHash {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(dto.ErrorResponse{
+			Error: "idempotency key was already used with a different request body",
+		})
+	}
+
+	if !record.Completed {
+		return c.Status(fiber.StatusConflict).JSON(dto.ErrorResponse{
+			Error: "request with this idempotency key is in progress, retry later",
+		})
+	}
+
+	c.Set(HeaderIdempotentReplayed, "true")
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Status(record.StatusCode).Send(record.Body)
+}
+
+func hashBody(body []byte) string {
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
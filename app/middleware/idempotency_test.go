@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"messaging-system/app/cache"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	idempotencyKey = "key-1"
+	requestBody    = `{"to":"+905551112233","content":"Hello"}`
+	cacheKey       = "tenant:default:idempotency:/messages:key-1"
+)
+
+func TestIdempotency(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockStore := mocks.NewMockIIdempotencyStore(mockController)
+	calls := 0
+
+	app := fiber.New()
+	app.Use(Idempotency(mockStore))
+	app.Post("/messages", func(c *fiber.Ctx) error {
+		calls++
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"messageId": "abc"})
+	})
+
+	newRequest := func(key, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/messages", strings.NewReader(body))
+		req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+		if key != "" {
+			req.Header.Set(HeaderIdempotencyKey, key)
+		}
+		return req
+	}
+
+	storedRecord := func(body string, completed bool) []byte {
+		value, _ := json.Marshal(&model.IdempotencyRecord{
+			RequestHash: hashBody([]byte(body)),
+			Completed:   completed,
+			StatusCode:  fiber.StatusCreated,
+			Body:        []byte(`{"messageId":"abc"}`),
+		})
+		return value
+	}
+
+	t.Run("request without key is passed through", func(t *testing.T) {
+		calls = 0
+		resp, err := app.Test(newRequest("", requestBody))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("first request stores the response", func(t *testing.T) {
+		calls = 0
+		mockStore.EXPECT().SetNX(gomock.Any(), cacheKey, gomock.Any(), IdempotencyLockTTL).Return(true, nil)
+		mockStore.EXPECT().Set(gomock.Any(), cacheKey, storedRecord(requestBody, true)).Return(nil)
+
+		resp, err := app.Test(newRequest(idempotencyKey, requestBody))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+		assert.Equal(t, 1, calls)
+	})
+
+	t.Run("repeated request replays the stored response", func(t *testing.T) {
+		calls = 0
+		mockStore.EXPECT().SetNX(gomock.Any(), cacheKey, gomock.Any(), IdempotencyLockTTL).Return(false, nil)
+		mockStore.EXPECT().Get(gomock.Any(), cacheKey).Return(storedRecord(requestBody, true), nil)
+
+		resp, err := app.Test(newRequest(idempotencyKey, requestBody))
+		assert.Nil(t, err)
+
+		body, _ := io.ReadAll(resp.Body)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+		assert.Equal(t, "true", resp.Header.Get(HeaderIdempotentReplayed))
+		assert.JSONEq(t, `{"messageId":"abc"}`, string(body))
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("same key with different body is rejected", func(t *testing.T) {
+		calls = 0
+		mockStore.EXPECT().SetNX(gomock.Any(), cacheKey, gomock.Any(), IdempotencyLockTTL).Return(false, nil)
+		mockStore.EXPECT().Get(gomock.Any(), cacheKey).Return(storedRecord(`{"to":"other"}`, true), nil)
+
+		resp, err := app.Test(newRequest(idempotencyKey, requestBody))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusUnprocessableEntity, resp.StatusCode)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("request in progress returns conflict", func(t *testing.T) {
+		calls = 0
+		mockStore.EXPECT().SetNX(gomock.Any(), cacheKey, gomock.Any(), IdempotencyLockTTL).Return(false, nil)
+		mockStore.EXPECT().Get(gomock.Any(), cacheKey).Return(storedRecord(requestBody, false), nil)
+
+		resp, err := app.Test(newRequest(idempotencyKey, requestBody))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+		assert.Equal(t, 0, calls)
+	})
+
+	t.Run("expired record returns conflict", func(t *testing.T) {
+		mockStore.EXPECT().SetNX(gomock.Any(), cacheKey, gomock.Any(), IdempotencyLockTTL).Return(false, nil)
+		mockStore.EXPECT().Get(gomock.Any(), cacheKey).Return(nil, cache.ErrCacheMiss)
+
+		resp, err := app.Test(newRequest(idempotencyKey, requestBody))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusConflict, resp.StatusCode)
+	})
+
+	t.Run("keys are scoped to the tenant", func(t *testing.T) {
+		tenantApp := fiber.New()
+		tenantApp.Use(func(c *fiber.Ctx) error {
+			SetPrincipal(c, &model.Principal{ID: "key-id", TenantID: "retail"})
+			return c.Next()
+		}, Idempotency(mockStore))
+		tenantApp.Post("/messages", func(c *fiber.Ctx) error {
+			return c.SendStatus(fiber.StatusCreated)
+		})
+
+		tenantKey := "tenant:retail:idempotency:/messages:key-1"
+		mockStore.EXPECT().SetNX(gomock.Any(), tenantKey, gomock.Any(), IdempotencyLockTTL).Return(true, nil)
+		mockStore.EXPECT().Set(gomock.Any(), tenantKey, gomock.Any()).Return(nil)
+
+		resp, err := tenantApp.Test(newRequest(idempotencyKey, requestBody))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusCreated, resp.StatusCode)
+	})
+
+	t.Run("store error", func(t *testing.T) {
+		mockStore.EXPECT().SetNX(gomock.Any(), cacheKey, gomock.Any(), IdempotencyLockTTL).Return(false, assert.AnError)
+
+		resp, err := app.Test(newRequest(idempotencyKey, requestBody))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusInternalServerError, resp.StatusCode)
+	})
+}
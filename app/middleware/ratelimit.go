@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"messaging-system/app/cache"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"messaging-system/config"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+	rateLimitCacheKeyPrefix  = "ratelimit:"
+)
+
+type IRateLimitStore interface {
+	SlidingWindow(ctx context.Context, key string, now time.Time, window time.Duration,
+		limit int) (*cache.WindowUsage, error)
+}
+
+// RateLimit limits the requests of each caller to the configured routes within a sliding window.
+// Callers are told their budget in the RateLimit-* headers and rejected with 429 and Retry-After once
+// it is used up. Callers are told apart by their API key or token subject and by their IP when
+// authentication is disabled, so it has to run after the authentication middleware. Requests are let
+// through when the store fails, an outage of Redis must not take ingestion down.
+func RateLimit(store IRateLimitStore, conf *config.RateLimit, logger *slog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		route := matchRoute(conf.Routes, c.Method(), c.Path())
+		if route == nil || route.Limit <= 0 || route.Window <= 0 {
+			return c.Next()
+		}
+
+		now := time.Now()
+		key := cache.TenantKey(TenantFrom(c),
+			rateLimitCacheKeyPrefix+strings.ToUpper(route.Method)+route.Path+":"+callerID(c))
+		usage, err := store.SlidingWindow(c.Context(), key, now, route.Window, route.Limit)
+		if err != nil {
+			logger.ErrorContext(c.Context(), "rate limit check failed, letting the request through", "key", key,
+				"error", err)
+			return c.Next()
+		}
+
+		reset := resetSeconds(usage.Oldest.Add(route.Window).Sub(now))
+		c.Set(HeaderRateLimitLimit, strconv.Itoa(route.Limit))
+		c.Set(HeaderRateLimitRemaining, strconv.Itoa(max(route.Limit-usage.Count, 0)))
+		c.Set(HeaderRateLimitReset, strconv.Itoa(reset))
+		c.Set(HeaderRateLimitPolicy, fmt.Sprintf("%d;w=%d", route.Limit, int(route.Window.Seconds())))
+
+		if !usage.Allowed {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(reset))
+			return c.Status(fiber.StatusTooManyRequests).JSON(dto.ErrorResponse{
+				Error: "rate limit exceeded, retry in " + strconv.Itoa(reset) + "s",
+			})
+		}
+
+		return c.Next()
+	}
+}
+
+// matchRoute returns the first route matching the request or nil.
+func matchRoute(routes []config.RouteLimit, method, path string) *config.RouteLimit {
+	if len(path) > 1 {
+		path = strings.TrimSuffix(path, "/")
+	}
+
+	for i := range routes {
+		route := &routes[i]
+		if route.Method != "" && !strings.EqualFold(route.Method, method) {
+			continue
+		}
+
+		if prefix, ok := strings.CutSuffix(route.Path, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return route
+			}
+			continue
+		}
+		if route.Path == path {
+			return route
+		}
+	}
+
+	return nil
+}
+
+// callerID identifies the caller by its principal, anonymous callers by their IP.
+func callerID(c *fiber.Ctx) string {
+	principal := PrincipalFrom(c)
+	if principal == nil || principal.ID == model.AnonymousPrincipalID {
+		return "ip:" + c.IP()
+	}
+	return "id:" + principal.ID
+}
+
+// resetSeconds rounds the time until the window frees up a slot up to whole seconds.
+func resetSeconds(d time.Duration) int {
+	return max(int(math.Ceil(d.Seconds())), 1)
+}
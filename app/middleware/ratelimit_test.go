@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"messaging-system/app/cache"
+	"messaging-system/app/dto"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"messaging-system/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimit(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	mockStore := mocks.NewMockIRateLimitStore(mockController)
+	conf := &config.RateLimit{
+		Enabled: true,
+		Routes: []config.RouteLimit{
+			{Method: "POST", Path: "/messages", Limit: 2, Window: time.Minute},
+			{Method: "POST", Path: "/suppressions*", Limit: 5, Window: 10 * time.Second},
+		},
+	}
+
+	app := fiber.New()
+	app.Use(func(c *fiber.Ctx) error {
+		if key := c.Get(HeaderAPIKey); key != "" {
+			SetPrincipal(c, &model.Principal{ID: key, TenantID: "retail"})
+		} else {
+			SetPrincipal(c, &model.Principal{ID: model.AnonymousPrincipalID, TenantID: model.DefaultTenantID})
+		}
+		return c.Next()
+	}, RateLimit(mockStore, conf, slog.Default()))
+	app.All("/*", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusOK)
+	})
+
+	newRequest := func(method, path, key string) *http.Request {
+		req := httptest.NewRequest(method, path, nil)
+		if key != "" {
+			req.Header.Set(HeaderAPIKey, key)
+		}
+		return req
+	}
+
+	// usage reports a window whose oldest hit happened age before the request
+	usage := func(allowed bool, count int, age time.Duration) interface{} {
+		return func(_ context.Context, _ string, now time.Time, _ time.Duration, _ int) (*cache.WindowUsage, error) {
+			return &cache.WindowUsage{Allowed: allowed, Count: count, Oldest: now.Add(-age)}, nil
+		}
+	}
+
+	t.Run("requests within the limit", func(t *testing.T) {
+		mockStore.
+			EXPECT().
+			SlidingWindow(gomock.Any(), "tenant:retail:ratelimit:POST/messages:id:key-1",
+				gomock.Any(), time.Minute, 2).
+			DoAndReturn(usage(true, 1, 20*time.Second))
+
+		resp, err := app.Test(newRequest(http.MethodPost, "/messages/", "key-1"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "2", resp.Header.Get(HeaderRateLimitLimit))
+		assert.Equal(t, "1", resp.Header.Get(HeaderRateLimitRemaining))
+		assert.Equal(t, "40", resp.Header.Get(HeaderRateLimitReset))
+		assert.Equal(t, "2;w=60", resp.Header.Get(HeaderRateLimitPolicy))
+		assert.Empty(t, resp.Header.Get(fiber.HeaderRetryAfter))
+	})
+
+	t.Run("limit exceeded", func(t *testing.T) {
+		mockStore.
+			EXPECT().
+			SlidingWindow(gomock.Any(), gomock.Any(), gomock.Any(), time.Minute, 2).
+			DoAndReturn(usage(false, 2, 59500*time.Millisecond))
+
+		resp, err := app.Test(newRequest(http.MethodPost, "/messages", "key-1"))
+		assert.Nil(t, err)
+
+		response := dto.ErrorResponse{}
+		assert.Nil(t, json.NewDecoder(resp.Body).Decode(&response))
+		assert.Equal(t, fiber.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "0", resp.Header.Get(HeaderRateLimitRemaining))
+		assert.Equal(t, "1", resp.Header.Get(fiber.HeaderRetryAfter))
+		assert.NotEmpty(t, response.Error)
+	})
+
+	t.Run("anonymous callers are limited by IP", func(t *testing.T) {
+		mockStore.
+			EXPECT().
+			SlidingWindow(gomock.Any(), "tenant:default:ratelimit:POST/suppressions*:ip:0.0.0.0",
+				gomock.Any(), 10*time.Second, 5).
+			DoAndReturn(usage(true, 1, 0))
+
+		resp, err := app.Test(newRequest(http.MethodPost, "/suppressions/import", ""))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+		assert.Equal(t, "4", resp.Header.Get(HeaderRateLimitRemaining))
+	})
+
+	t.Run("routes without a limit are passed through", func(t *testing.T) {
+		for _, req := range []*http.Request{
+			newRequest(http.MethodGet, "/messages", "key-1"),
+			newRequest(http.MethodPost, "/processor/start", "key-1"),
+		} {
+			resp, err := app.Test(req)
+
+			assert.Nil(t, err)
+			assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+			assert.Empty(t, resp.Header.Get(HeaderRateLimitLimit))
+		}
+	})
+
+	t.Run("store error lets the request through", func(t *testing.T) {
+		mockStore.
+			EXPECT().
+			SlidingWindow(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(nil, assert.AnError)
+
+		resp, err := app.Test(newRequest(http.MethodPost, "/messages", "key-1"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	})
+}
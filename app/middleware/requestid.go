@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"log/slog"
+	"messaging-system/app/requestid"
+	"net/url"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// RequestID accepts the X-Request-ID header of the caller or assigns a new ID when it is missing or
+// invalid, and returns it in the response. The ID is stored in the request context, so it has to run
+// first for every log line of the request to carry it.
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id := c.Get(requestid.Header)
+		if !requestid.Valid(id) {
+			id = requestid.New()
+			c.Request().Header.Set(requestid.Header, id)
+		}
+
+		c.Locals(requestid.ContextKey{}, id)
+		c.SetUserContext(requestid.NewContext(c.UserContext(), id))
+		c.Set(requestid.Header, id)
+		return c.Next()
+	}
+}
+
+// RequestIDFrom returns the ID of the request or an empty string without the RequestID middleware.
+func RequestIDFrom(c *fiber.Ctx) string {
+	id, _ := c.Locals(requestid.ContextKey{}).(string)
+	return id
+}
+
+// AccessLog logs one line per request once it is answered. Errors returned by handlers are answered by
+// the error handler of the app first, so the logged status is the one the caller receives.
+func AccessLog(logger *slog.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		if err := c.Next(); err != nil {
+			if handleErr := c.App().ErrorHandler(c, err); handleErr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		// unescaped so phone numbers in paths are masked like in every other line
+		path, err := url.PathUnescape(c.Path())
+		if err != nil {
+			path = c.Path()
+		}
+
+		status := c.Response().StatusCode()
+		level := slog.LevelInfo
+		if status >= fiber.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		logger.LogAttrs(c.UserContext(), level, "request",
+			slog.String("method", c.Method()),
+			slog.String("path", path),
+			slog.Int("status", status),
+			slog.Duration("duration", time.Since(start)),
+			slog.String("ip", c.IP()),
+			slog.String("tenantId", TenantFrom(c)),
+			slog.String("actorId", actorID(c)),
+		)
+		return nil
+	}
+}
+
+// actorID identifies the caller in the access log, requests rejected before authentication have none.
+func actorID(c *fiber.Ctx) string {
+	if principal := PrincipalFrom(c); principal != nil {
+		return principal.ID
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"messaging-system/app/model"
+	"messaging-system/app/requestid"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	app := fiber.New()
+	app.Use(RequestID())
+	app.Get("/", func(c *fiber.Ctx) error {
+		// handlers pass the fasthttp context on, it has to carry the ID like the user context
+		assert.Equal(t, RequestIDFrom(c), requestid.FromContext(c.Context()))
+		assert.Equal(t, RequestIDFrom(c), requestid.FromContext(c.UserContext()))
+		assert.Equal(t, RequestIDFrom(c), c.Get(requestid.Header))
+		return c.SendString(RequestIDFrom(c))
+	})
+
+	newRequest := func(id string) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if id != "" {
+			req.Header.Set(requestid.Header, id)
+		}
+		return req
+	}
+
+	t.Run("accepts the id of the caller", func(t *testing.T) {
+		resp, err := app.Test(newRequest("caller-id"))
+
+		assert.Nil(t, err)
+		assert.Equal(t, "caller-id", resp.Header.Get(requestid.Header))
+	})
+
+	t.Run("assigns an id when missing", func(t *testing.T) {
+		resp, err := app.Test(newRequest(""))
+
+		assert.Nil(t, err)
+		assert.True(t, requestid.Valid(resp.Header.Get(requestid.Header)))
+	})
+
+	t.Run("replaces an invalid id", func(t *testing.T) {
+		resp, err := app.Test(newRequest("id with spaces"))
+
+		assert.Nil(t, err)
+		assert.NotEqual(t, "id with spaces", resp.Header.Get(requestid.Header))
+		assert.True(t, requestid.Valid(resp.Header.Get(requestid.Header)))
+	})
+}
+
+func TestAccessLog(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := slog.New(requestid.NewLogHandler(slog.NewJSONHandler(buffer, nil)))
+
+	app := fiber.New()
+	app.Use(RequestID(), AccessLog(logger), func(c *fiber.Ctx) error {
+		SetPrincipal(c, &model.Principal{ID: "key-id", TenantID: "retail"})
+		return c.Next()
+	})
+	app.Get("/suppressions/:phone", func(c *fiber.Ctx) error {
+		return c.SendStatus(fiber.StatusNoContent)
+	})
+	app.Get("/fail", func(c *fiber.Ctx) error {
+		return fiber.ErrServiceUnavailable
+	})
+
+	send := func(path string) (*http.Response, map[string]interface{}) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(requestid.Header, "request-id")
+		resp, err := app.Test(req)
+		assert.Nil(t, err)
+
+		line := map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal(buffer.Bytes(), &line))
+		buffer.Reset()
+		return resp, line
+	}
+
+	t.Run("one line per request", func(t *testing.T) {
+		resp, line := send("/suppressions/%2B905551112233")
+
+		assert.Equal(t, fiber.StatusNoContent, resp.StatusCode)
+		assert.Equal(t, "request", line["msg"])
+		assert.Equal(t, "INFO", line["level"])
+		assert.Equal(t, http.MethodGet, line["method"])
+		assert.Equal(t, "/suppressions/+905551112233", line["path"])
+		assert.Equal(t, float64(fiber.StatusNoContent), line["status"])
+		assert.Equal(t, "request-id", line[requestid.LogKey])
+		assert.Equal(t, "retail", line["tenantId"])
+		assert.Equal(t, "key-id", line["actorId"])
+		assert.Contains(t, line, "duration")
+	})
+
+	t.Run("logs the status of returned errors", func(t *testing.T) {
+		resp, line := send("/fail")
+
+		assert.Equal(t, fiber.StatusServiceUnavailable, resp.StatusCode)
+		assert.Equal(t, float64(fiber.StatusServiceUnavailable), line["status"])
+		assert.Equal(t, "ERROR", line["level"])
+	})
+
+	t.Run("unknown routes", func(t *testing.T) {
+		resp, line := send("/unknown")
+
+		assert.Equal(t, fiber.StatusNotFound, resp.StatusCode)
+		assert.Equal(t, float64(fiber.StatusNotFound), line["status"])
+	})
+}
@@ -0,0 +1,89 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/middleware/auth.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIAPIKeyAuthenticator is a mock of IAPIKeyAuthenticator interface.
+type MockIAPIKeyAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAPIKeyAuthenticatorMockRecorder
+}
+
+// MockIAPIKeyAuthenticatorMockRecorder is the mock recorder for MockIAPIKeyAuthenticator.
+type MockIAPIKeyAuthenticatorMockRecorder struct {
+	mock *MockIAPIKeyAuthenticator
+}
+
+// NewMockIAPIKeyAuthenticator creates a new mock instance.
+func NewMockIAPIKeyAuthenticator(ctrl *gomock.Controller) *MockIAPIKeyAuthenticator {
+	mock := &MockIAPIKeyAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockIAPIKeyAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAPIKeyAuthenticator) EXPECT() *MockIAPIKeyAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockIAPIKeyAuthenticator) Authenticate(ctx context.Context, key string) (*model.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, key)
+	ret0, _ := ret[0].(*model.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockIAPIKeyAuthenticatorMockRecorder) Authenticate(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockIAPIKeyAuthenticator)(nil).Authenticate), ctx, key)
+}
+
+// MockITokenAuthenticator is a mock of ITokenAuthenticator interface.
+type MockITokenAuthenticator struct {
+	ctrl     *gomock.Controller
+	recorder *MockITokenAuthenticatorMockRecorder
+}
+
+// MockITokenAuthenticatorMockRecorder is the mock recorder for MockITokenAuthenticator.
+type MockITokenAuthenticatorMockRecorder struct {
+	mock *MockITokenAuthenticator
+}
+
+// NewMockITokenAuthenticator creates a new mock instance.
+func NewMockITokenAuthenticator(ctrl *gomock.Controller) *MockITokenAuthenticator {
+	mock := &MockITokenAuthenticator{ctrl: ctrl}
+	mock.recorder = &MockITokenAuthenticatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITokenAuthenticator) EXPECT() *MockITokenAuthenticatorMockRecorder {
+	return m.recorder
+}
+
+// Authenticate mocks base method.
+func (m *MockITokenAuthenticator) Authenticate(ctx context.Context, token string) (*model.Principal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Authenticate", ctx, token)
+	ret0, _ := ret[0].(*model.Principal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Authenticate indicates an expected call of Authenticate.
+func (mr *MockITokenAuthenticatorMockRecorder) Authenticate(ctx, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Authenticate", reflect.TypeOf((*MockITokenAuthenticator)(nil).Authenticate), ctx, token)
+}
@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/service/apikey_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "messaging-system/app/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockIAPIKeyRepository is a mock of IAPIKeyRepository interface.
+type MockIAPIKeyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAPIKeyRepositoryMockRecorder
+}
+
+// MockIAPIKeyRepositoryMockRecorder is the mock recorder for MockIAPIKeyRepository.
+type MockIAPIKeyRepositoryMockRecorder struct {
+	mock *MockIAPIKeyRepository
+}
+
+// NewMockIAPIKeyRepository creates a new mock instance.
+func NewMockIAPIKeyRepository(ctrl *gomock.Controller) *MockIAPIKeyRepository {
+	mock := &MockIAPIKeyRepository{ctrl: ctrl}
+	mock.recorder = &MockIAPIKeyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAPIKeyRepository) EXPECT() *MockIAPIKeyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockIAPIKeyRepository) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockIAPIKeyRepositoryMockRecorder) CreateAPIKey(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockIAPIKeyRepository)(nil).CreateAPIKey), ctx, key)
+}
+
+// GetAPIKeyByHash mocks base method.
+func (m *MockIAPIKeyRepository) GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeyByHash", ctx, hash)
+	ret0, _ := ret[0].(*model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeyByHash indicates an expected call of GetAPIKeyByHash.
+func (mr *MockIAPIKeyRepositoryMockRecorder) GetAPIKeyByHash(ctx, hash interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeyByHash", reflect.TypeOf((*MockIAPIKeyRepository)(nil).GetAPIKeyByHash), ctx, hash)
+}
+
+// GetAPIKeys mocks base method.
+func (m *MockIAPIKeyRepository) GetAPIKeys(ctx context.Context, tenantID string) ([]model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeys", ctx, tenantID)
+	ret0, _ := ret[0].([]model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeys indicates an expected call of GetAPIKeys.
+func (mr *MockIAPIKeyRepositoryMockRecorder) GetAPIKeys(ctx, tenantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeys", reflect.TypeOf((*MockIAPIKeyRepository)(nil).GetAPIKeys), ctx, tenantID)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockIAPIKeyRepository) RevokeAPIKey(ctx context.Context, tenantID string, keyID primitive.ObjectID, revokedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, tenantID, keyID, revokedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockIAPIKeyRepositoryMockRecorder) RevokeAPIKey(ctx, tenantID, keyID, revokedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockIAPIKeyRepository)(nil).RevokeAPIKey), ctx, tenantID, keyID, revokedAt)
+}
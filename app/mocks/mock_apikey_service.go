@@ -0,0 +1,82 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/handler/apikey_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "messaging-system/app/dto"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockIAPIKeyService is a mock of IAPIKeyService interface.
+type MockIAPIKeyService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAPIKeyServiceMockRecorder
+}
+
+// MockIAPIKeyServiceMockRecorder is the mock recorder for MockIAPIKeyService.
+type MockIAPIKeyServiceMockRecorder struct {
+	mock *MockIAPIKeyService
+}
+
+// NewMockIAPIKeyService creates a new mock instance.
+func NewMockIAPIKeyService(ctrl *gomock.Controller) *MockIAPIKeyService {
+	mock := &MockIAPIKeyService{ctrl: ctrl}
+	mock.recorder = &MockIAPIKeyServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAPIKeyService) EXPECT() *MockIAPIKeyServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateAPIKey mocks base method.
+func (m *MockIAPIKeyService) CreateAPIKey(ctx context.Context, principal *model.Principal, request *dto.APIKeyRequest) (*dto.APIKeyResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAPIKey", ctx, principal, request)
+	ret0, _ := ret[0].(*dto.APIKeyResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateAPIKey indicates an expected call of CreateAPIKey.
+func (mr *MockIAPIKeyServiceMockRecorder) CreateAPIKey(ctx, principal, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAPIKey", reflect.TypeOf((*MockIAPIKeyService)(nil).CreateAPIKey), ctx, principal, request)
+}
+
+// GetAPIKeys mocks base method.
+func (m *MockIAPIKeyService) GetAPIKeys(ctx context.Context, tenantID string) ([]model.APIKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAPIKeys", ctx, tenantID)
+	ret0, _ := ret[0].([]model.APIKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAPIKeys indicates an expected call of GetAPIKeys.
+func (mr *MockIAPIKeyServiceMockRecorder) GetAPIKeys(ctx, tenantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAPIKeys", reflect.TypeOf((*MockIAPIKeyService)(nil).GetAPIKeys), ctx, tenantID)
+}
+
+// RevokeAPIKey mocks base method.
+func (m *MockIAPIKeyService) RevokeAPIKey(ctx context.Context, tenantID string, keyID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeAPIKey", ctx, tenantID, keyID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeAPIKey indicates an expected call of RevokeAPIKey.
+func (mr *MockIAPIKeyServiceMockRecorder) RevokeAPIKey(ctx, tenantID, keyID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeAPIKey", reflect.TypeOf((*MockIAPIKeyService)(nil).RevokeAPIKey), ctx, tenantID, keyID)
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/handler/audit_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "messaging-system/app/dto"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIAuditReader is a mock of IAuditReader interface.
+type MockIAuditReader struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAuditReaderMockRecorder
+}
+
+// MockIAuditReaderMockRecorder is the mock recorder for MockIAuditReader.
+type MockIAuditReaderMockRecorder struct {
+	mock *MockIAuditReader
+}
+
+// NewMockIAuditReader creates a new mock instance.
+func NewMockIAuditReader(ctrl *gomock.Controller) *MockIAuditReader {
+	mock := &MockIAuditReader{ctrl: ctrl}
+	mock.recorder = &MockIAuditReaderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAuditReader) EXPECT() *MockIAuditReaderMockRecorder {
+	return m.recorder
+}
+
+// GetAuditEntries mocks base method.
+func (m *MockIAuditReader) GetAuditEntries(ctx context.Context, tenantID string, query *dto.AuditQuery) ([]model.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditEntries", ctx, tenantID, query)
+	ret0, _ := ret[0].([]model.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditEntries indicates an expected call of GetAuditEntries.
+func (mr *MockIAuditReaderMockRecorder) GetAuditEntries(ctx, tenantID, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditEntries", reflect.TypeOf((*MockIAuditReader)(nil).GetAuditEntries), ctx, tenantID, query)
+}
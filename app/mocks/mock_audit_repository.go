@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/service/audit_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "messaging-system/app/dto"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIAuditRepository is a mock of IAuditRepository interface.
+type MockIAuditRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAuditRepositoryMockRecorder
+}
+
+// MockIAuditRepositoryMockRecorder is the mock recorder for MockIAuditRepository.
+type MockIAuditRepositoryMockRecorder struct {
+	mock *MockIAuditRepository
+}
+
+// NewMockIAuditRepository creates a new mock instance.
+func NewMockIAuditRepository(ctrl *gomock.Controller) *MockIAuditRepository {
+	mock := &MockIAuditRepository{ctrl: ctrl}
+	mock.recorder = &MockIAuditRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAuditRepository) EXPECT() *MockIAuditRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateAuditEntry mocks base method.
+func (m *MockIAuditRepository) CreateAuditEntry(ctx context.Context, entry *model.AuditEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateAuditEntry", ctx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateAuditEntry indicates an expected call of CreateAuditEntry.
+func (mr *MockIAuditRepositoryMockRecorder) CreateAuditEntry(ctx, entry interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateAuditEntry", reflect.TypeOf((*MockIAuditRepository)(nil).CreateAuditEntry), ctx, entry)
+}
+
+// GetAuditEntries mocks base method.
+func (m *MockIAuditRepository) GetAuditEntries(ctx context.Context, tenantID string, query *dto.AuditQuery) ([]model.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditEntries", ctx, tenantID, query)
+	ret0, _ := ret[0].([]model.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditEntries indicates an expected call of GetAuditEntries.
+func (mr *MockIAuditRepositoryMockRecorder) GetAuditEntries(ctx, tenantID, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditEntries", reflect.TypeOf((*MockIAuditRepository)(nil).GetAuditEntries), ctx, tenantID, query)
+}
@@ -0,0 +1,225 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/service/campaign_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "messaging-system/app/dto"
+	model "messaging-system/app/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockICampaignRepository is a mock of ICampaignRepository interface.
+type MockICampaignRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockICampaignRepositoryMockRecorder
+}
+
+// MockICampaignRepositoryMockRecorder is the mock recorder for MockICampaignRepository.
+type MockICampaignRepositoryMockRecorder struct {
+	mock *MockICampaignRepository
+}
+
+// NewMockICampaignRepository creates a new mock instance.
+func NewMockICampaignRepository(ctrl *gomock.Controller) *MockICampaignRepository {
+	mock := &MockICampaignRepository{ctrl: ctrl}
+	mock.recorder = &MockICampaignRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockICampaignRepository) EXPECT() *MockICampaignRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CancelCampaignMessages mocks base method.
+func (m *MockICampaignRepository) CancelCampaignMessages(ctx context.Context, tenantID string, campaignID primitive.ObjectID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelCampaignMessages", ctx, tenantID, campaignID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelCampaignMessages indicates an expected call of CancelCampaignMessages.
+func (mr *MockICampaignRepositoryMockRecorder) CancelCampaignMessages(ctx, tenantID, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelCampaignMessages", reflect.TypeOf((*MockICampaignRepository)(nil).CancelCampaignMessages), ctx, tenantID, campaignID)
+}
+
+// CountCampaignSends mocks base method.
+func (m *MockICampaignRepository) CountCampaignSends(ctx context.Context, campaignIDs []primitive.ObjectID, since time.Time) (map[primitive.ObjectID]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCampaignSends", ctx, campaignIDs, since)
+	ret0, _ := ret[0].(map[primitive.ObjectID]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCampaignSends indicates an expected call of CountCampaignSends.
+func (mr *MockICampaignRepositoryMockRecorder) CountCampaignSends(ctx, campaignIDs, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCampaignSends", reflect.TypeOf((*MockICampaignRepository)(nil).CountCampaignSends), ctx, campaignIDs, since)
+}
+
+// CreateCampaign mocks base method.
+func (m *MockICampaignRepository) CreateCampaign(ctx context.Context, campaign *model.Campaign) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCampaign", ctx, campaign)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateCampaign indicates an expected call of CreateCampaign.
+func (mr *MockICampaignRepositoryMockRecorder) CreateCampaign(ctx, campaign interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCampaign", reflect.TypeOf((*MockICampaignRepository)(nil).CreateCampaign), ctx, campaign)
+}
+
+// GetCampaign mocks base method.
+func (m *MockICampaignRepository) GetCampaign(ctx context.Context, tenantID string, campaignID primitive.ObjectID) (*model.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaign", ctx, tenantID, campaignID)
+	ret0, _ := ret[0].(*model.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaign indicates an expected call of GetCampaign.
+func (mr *MockICampaignRepositoryMockRecorder) GetCampaign(ctx, tenantID, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaign", reflect.TypeOf((*MockICampaignRepository)(nil).GetCampaign), ctx, tenantID, campaignID)
+}
+
+// GetCampaignCounts mocks base method.
+func (m *MockICampaignRepository) GetCampaignCounts(ctx context.Context, tenantID string, campaignIDs []primitive.ObjectID) (map[primitive.ObjectID]*model.CampaignCounts, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaignCounts", ctx, tenantID, campaignIDs)
+	ret0, _ := ret[0].(map[primitive.ObjectID]*model.CampaignCounts)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaignCounts indicates an expected call of GetCampaignCounts.
+func (mr *MockICampaignRepositoryMockRecorder) GetCampaignCounts(ctx, tenantID, campaignIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignCounts", reflect.TypeOf((*MockICampaignRepository)(nil).GetCampaignCounts), ctx, tenantID, campaignIDs)
+}
+
+// GetCampaigns mocks base method.
+func (m *MockICampaignRepository) GetCampaigns(ctx context.Context, tenantID string, limit int) ([]model.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaigns", ctx, tenantID, limit)
+	ret0, _ := ret[0].([]model.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaigns indicates an expected call of GetCampaigns.
+func (mr *MockICampaignRepositoryMockRecorder) GetCampaigns(ctx, tenantID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaigns", reflect.TypeOf((*MockICampaignRepository)(nil).GetCampaigns), ctx, tenantID, limit)
+}
+
+// GetGatedCampaigns mocks base method.
+func (m *MockICampaignRepository) GetGatedCampaigns(ctx context.Context, now time.Time) ([]model.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetGatedCampaigns", ctx, now)
+	ret0, _ := ret[0].([]model.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetGatedCampaigns indicates an expected call of GetGatedCampaigns.
+func (mr *MockICampaignRepositoryMockRecorder) GetGatedCampaigns(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetGatedCampaigns", reflect.TypeOf((*MockICampaignRepository)(nil).GetGatedCampaigns), ctx, now)
+}
+
+// GetTemplate mocks base method.
+func (m *MockICampaignRepository) GetTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID) (*model.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplate", ctx, tenantID, templateID)
+	ret0, _ := ret[0].(*model.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplate indicates an expected call of GetTemplate.
+func (mr *MockICampaignRepositoryMockRecorder) GetTemplate(ctx, tenantID, templateID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplate", reflect.TypeOf((*MockICampaignRepository)(nil).GetTemplate), ctx, tenantID, templateID)
+}
+
+// UpdateCampaignRejected mocks base method.
+func (m *MockICampaignRepository) UpdateCampaignRejected(ctx context.Context, campaign *model.Campaign) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCampaignRejected", ctx, campaign)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateCampaignRejected indicates an expected call of UpdateCampaignRejected.
+func (mr *MockICampaignRepositoryMockRecorder) UpdateCampaignRejected(ctx, campaign interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCampaignRejected", reflect.TypeOf((*MockICampaignRepository)(nil).UpdateCampaignRejected), ctx, campaign)
+}
+
+// UpdateCampaignStatus mocks base method.
+func (m *MockICampaignRepository) UpdateCampaignStatus(ctx context.Context, tenantID string, campaignID primitive.ObjectID, from []string, status string, now time.Time) (*model.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCampaignStatus", ctx, tenantID, campaignID, from, status, now)
+	ret0, _ := ret[0].(*model.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCampaignStatus indicates an expected call of UpdateCampaignStatus.
+func (mr *MockICampaignRepositoryMockRecorder) UpdateCampaignStatus(ctx, tenantID, campaignID, from, status, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCampaignStatus", reflect.TypeOf((*MockICampaignRepository)(nil).UpdateCampaignStatus), ctx, tenantID, campaignID, from, status, now)
+}
+
+// MockICampaignMessageCreator is a mock of ICampaignMessageCreator interface.
+type MockICampaignMessageCreator struct {
+	ctrl     *gomock.Controller
+	recorder *MockICampaignMessageCreatorMockRecorder
+}
+
+// MockICampaignMessageCreatorMockRecorder is the mock recorder for MockICampaignMessageCreator.
+type MockICampaignMessageCreatorMockRecorder struct {
+	mock *MockICampaignMessageCreator
+}
+
+// NewMockICampaignMessageCreator creates a new mock instance.
+func NewMockICampaignMessageCreator(ctrl *gomock.Controller) *MockICampaignMessageCreator {
+	mock := &MockICampaignMessageCreator{ctrl: ctrl}
+	mock.recorder = &MockICampaignMessageCreatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockICampaignMessageCreator) EXPECT() *MockICampaignMessageCreatorMockRecorder {
+	return m.recorder
+}
+
+// CreateCampaignMessages mocks base method.
+func (m *MockICampaignMessageCreator) CreateCampaignMessages(ctx context.Context, tenantID string, campaignID primitive.ObjectID, request *dto.MessageRequest, phoneNumbers []string) ([]dto.InvalidPhoneNumber, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCampaignMessages", ctx, tenantID, campaignID, request, phoneNumbers)
+	ret0, _ := ret[0].([]dto.InvalidPhoneNumber)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCampaignMessages indicates an expected call of CreateCampaignMessages.
+func (mr *MockICampaignMessageCreatorMockRecorder) CreateCampaignMessages(ctx, tenantID, campaignID, request, phoneNumbers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCampaignMessages", reflect.TypeOf((*MockICampaignMessageCreator)(nil).CreateCampaignMessages), ctx, tenantID, campaignID, request, phoneNumbers)
+}
@@ -0,0 +1,128 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/handler/campaign_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "messaging-system/app/dto"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockICampaignService is a mock of ICampaignService interface.
+type MockICampaignService struct {
+	ctrl     *gomock.Controller
+	recorder *MockICampaignServiceMockRecorder
+}
+
+// MockICampaignServiceMockRecorder is the mock recorder for MockICampaignService.
+type MockICampaignServiceMockRecorder struct {
+	mock *MockICampaignService
+}
+
+// NewMockICampaignService creates a new mock instance.
+func NewMockICampaignService(ctrl *gomock.Controller) *MockICampaignService {
+	mock := &MockICampaignService{ctrl: ctrl}
+	mock.recorder = &MockICampaignServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockICampaignService) EXPECT() *MockICampaignServiceMockRecorder {
+	return m.recorder
+}
+
+// CancelCampaign mocks base method.
+func (m *MockICampaignService) CancelCampaign(ctx context.Context, tenantID string, campaignID primitive.ObjectID) (*model.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelCampaign", ctx, tenantID, campaignID)
+	ret0, _ := ret[0].(*model.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelCampaign indicates an expected call of CancelCampaign.
+func (mr *MockICampaignServiceMockRecorder) CancelCampaign(ctx, tenantID, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelCampaign", reflect.TypeOf((*MockICampaignService)(nil).CancelCampaign), ctx, tenantID, campaignID)
+}
+
+// CreateCampaign mocks base method.
+func (m *MockICampaignService) CreateCampaign(ctx context.Context, tenantID string, request *dto.CampaignRequest) (*model.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCampaign", ctx, tenantID, request)
+	ret0, _ := ret[0].(*model.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCampaign indicates an expected call of CreateCampaign.
+func (mr *MockICampaignServiceMockRecorder) CreateCampaign(ctx, tenantID, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCampaign", reflect.TypeOf((*MockICampaignService)(nil).CreateCampaign), ctx, tenantID, request)
+}
+
+// GetCampaign mocks base method.
+func (m *MockICampaignService) GetCampaign(ctx context.Context, tenantID string, campaignID primitive.ObjectID) (*model.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaign", ctx, tenantID, campaignID)
+	ret0, _ := ret[0].(*model.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaign indicates an expected call of GetCampaign.
+func (mr *MockICampaignServiceMockRecorder) GetCampaign(ctx, tenantID, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaign", reflect.TypeOf((*MockICampaignService)(nil).GetCampaign), ctx, tenantID, campaignID)
+}
+
+// GetCampaigns mocks base method.
+func (m *MockICampaignService) GetCampaigns(ctx context.Context, tenantID string, limit int) ([]model.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaigns", ctx, tenantID, limit)
+	ret0, _ := ret[0].([]model.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaigns indicates an expected call of GetCampaigns.
+func (mr *MockICampaignServiceMockRecorder) GetCampaigns(ctx, tenantID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaigns", reflect.TypeOf((*MockICampaignService)(nil).GetCampaigns), ctx, tenantID, limit)
+}
+
+// PauseCampaign mocks base method.
+func (m *MockICampaignService) PauseCampaign(ctx context.Context, tenantID string, campaignID primitive.ObjectID) (*model.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseCampaign", ctx, tenantID, campaignID)
+	ret0, _ := ret[0].(*model.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PauseCampaign indicates an expected call of PauseCampaign.
+func (mr *MockICampaignServiceMockRecorder) PauseCampaign(ctx, tenantID, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseCampaign", reflect.TypeOf((*MockICampaignService)(nil).PauseCampaign), ctx, tenantID, campaignID)
+}
+
+// ResumeCampaign mocks base method.
+func (m *MockICampaignService) ResumeCampaign(ctx context.Context, tenantID string, campaignID primitive.ObjectID) (*model.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeCampaign", ctx, tenantID, campaignID)
+	ret0, _ := ret[0].(*model.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResumeCampaign indicates an expected call of ResumeCampaign.
+func (mr *MockICampaignServiceMockRecorder) ResumeCampaign(ctx, tenantID, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeCampaign", reflect.TypeOf((*MockICampaignService)(nil).ResumeCampaign), ctx, tenantID, campaignID)
+}
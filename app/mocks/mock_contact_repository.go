@@ -0,0 +1,164 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/service/contact_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "messaging-system/app/dto"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockIContactRepository is a mock of IContactRepository interface.
+type MockIContactRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIContactRepositoryMockRecorder
+}
+
+// MockIContactRepositoryMockRecorder is the mock recorder for MockIContactRepository.
+type MockIContactRepositoryMockRecorder struct {
+	mock *MockIContactRepository
+}
+
+// NewMockIContactRepository creates a new mock instance.
+func NewMockIContactRepository(ctrl *gomock.Controller) *MockIContactRepository {
+	mock := &MockIContactRepository{ctrl: ctrl}
+	mock.recorder = &MockIContactRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIContactRepository) EXPECT() *MockIContactRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddContacts mocks base method.
+func (m *MockIContactRepository) AddContacts(ctx context.Context, contacts []model.Contact) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddContacts", ctx, contacts)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddContacts indicates an expected call of AddContacts.
+func (mr *MockIContactRepositoryMockRecorder) AddContacts(ctx, contacts interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddContacts", reflect.TypeOf((*MockIContactRepository)(nil).AddContacts), ctx, contacts)
+}
+
+// CountContacts mocks base method.
+func (m *MockIContactRepository) CountContacts(ctx context.Context, tenantID string, listID primitive.ObjectID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountContacts", ctx, tenantID, listID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountContacts indicates an expected call of CountContacts.
+func (mr *MockIContactRepositoryMockRecorder) CountContacts(ctx, tenantID, listID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountContacts", reflect.TypeOf((*MockIContactRepository)(nil).CountContacts), ctx, tenantID, listID)
+}
+
+// CreateContactList mocks base method.
+func (m *MockIContactRepository) CreateContactList(ctx context.Context, list *model.ContactList) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateContactList", ctx, list)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateContactList indicates an expected call of CreateContactList.
+func (mr *MockIContactRepositoryMockRecorder) CreateContactList(ctx, list interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContactList", reflect.TypeOf((*MockIContactRepository)(nil).CreateContactList), ctx, list)
+}
+
+// ForEachContact mocks base method.
+func (m *MockIContactRepository) ForEachContact(ctx context.Context, tenantID string, listID primitive.ObjectID, handle func(context.Context, *model.Contact) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEachContact", ctx, tenantID, listID, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEachContact indicates an expected call of ForEachContact.
+func (mr *MockIContactRepositoryMockRecorder) ForEachContact(ctx, tenantID, listID, handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEachContact", reflect.TypeOf((*MockIContactRepository)(nil).ForEachContact), ctx, tenantID, listID, handle)
+}
+
+// GetContactList mocks base method.
+func (m *MockIContactRepository) GetContactList(ctx context.Context, tenantID string, listID primitive.ObjectID) (*model.ContactList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContactList", ctx, tenantID, listID)
+	ret0, _ := ret[0].(*model.ContactList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContactList indicates an expected call of GetContactList.
+func (mr *MockIContactRepositoryMockRecorder) GetContactList(ctx, tenantID, listID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContactList", reflect.TypeOf((*MockIContactRepository)(nil).GetContactList), ctx, tenantID, listID)
+}
+
+// GetContactLists mocks base method.
+func (m *MockIContactRepository) GetContactLists(ctx context.Context, tenantID string, limit int) ([]model.ContactList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetContactLists", ctx, tenantID, limit)
+	ret0, _ := ret[0].([]model.ContactList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetContactLists indicates an expected call of GetContactLists.
+func (mr *MockIContactRepositoryMockRecorder) GetContactLists(ctx, tenantID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetContactLists", reflect.TypeOf((*MockIContactRepository)(nil).GetContactLists), ctx, tenantID, limit)
+}
+
+// MockIListMessageCreator is a mock of IListMessageCreator interface.
+type MockIListMessageCreator struct {
+	ctrl     *gomock.Controller
+	recorder *MockIListMessageCreatorMockRecorder
+}
+
+// MockIListMessageCreatorMockRecorder is the mock recorder for MockIListMessageCreator.
+type MockIListMessageCreatorMockRecorder struct {
+	mock *MockIListMessageCreator
+}
+
+// NewMockIListMessageCreator creates a new mock instance.
+func NewMockIListMessageCreator(ctrl *gomock.Controller) *MockIListMessageCreator {
+	mock := &MockIListMessageCreator{ctrl: ctrl}
+	mock.recorder = &MockIListMessageCreatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIListMessageCreator) EXPECT() *MockIListMessageCreatorMockRecorder {
+	return m.recorder
+}
+
+// CreateMessage mocks base method.
+func (m *MockIListMessageCreator) CreateMessage(ctx context.Context, tenantID string, request *dto.MessageRequest) (*model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMessage", ctx, tenantID, request)
+	ret0, _ := ret[0].(*model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMessage indicates an expected call of CreateMessage.
+func (mr *MockIListMessageCreatorMockRecorder) CreateMessage(ctx, tenantID, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockIListMessageCreator)(nil).CreateMessage), ctx, tenantID, request)
+}
@@ -0,0 +1,114 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/handler/contact_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	io "io"
+	dto "messaging-system/app/dto"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockIContactService is a mock of IContactService interface.
+type MockIContactService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIContactServiceMockRecorder
+}
+
+// MockIContactServiceMockRecorder is the mock recorder for MockIContactService.
+type MockIContactServiceMockRecorder struct {
+	mock *MockIContactService
+}
+
+// NewMockIContactService creates a new mock instance.
+func NewMockIContactService(ctrl *gomock.Controller) *MockIContactService {
+	mock := &MockIContactService{ctrl: ctrl}
+	mock.recorder = &MockIContactServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIContactService) EXPECT() *MockIContactServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateList mocks base method.
+func (m *MockIContactService) CreateList(ctx context.Context, tenantID string, request *dto.ContactListRequest) (*model.ContactList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateList", ctx, tenantID, request)
+	ret0, _ := ret[0].(*model.ContactList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateList indicates an expected call of CreateList.
+func (mr *MockIContactServiceMockRecorder) CreateList(ctx, tenantID, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateList", reflect.TypeOf((*MockIContactService)(nil).CreateList), ctx, tenantID, request)
+}
+
+// GetList mocks base method.
+func (m *MockIContactService) GetList(ctx context.Context, tenantID string, listID primitive.ObjectID) (*model.ContactList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetList", ctx, tenantID, listID)
+	ret0, _ := ret[0].(*model.ContactList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetList indicates an expected call of GetList.
+func (mr *MockIContactServiceMockRecorder) GetList(ctx, tenantID, listID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetList", reflect.TypeOf((*MockIContactService)(nil).GetList), ctx, tenantID, listID)
+}
+
+// GetLists mocks base method.
+func (m *MockIContactService) GetLists(ctx context.Context, tenantID string, limit int) ([]model.ContactList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLists", ctx, tenantID, limit)
+	ret0, _ := ret[0].([]model.ContactList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLists indicates an expected call of GetLists.
+func (mr *MockIContactServiceMockRecorder) GetLists(ctx, tenantID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLists", reflect.TypeOf((*MockIContactService)(nil).GetLists), ctx, tenantID, limit)
+}
+
+// ImportContacts mocks base method.
+func (m *MockIContactService) ImportContacts(ctx context.Context, tenantID string, listID primitive.ObjectID, format string, body io.Reader) (*dto.ContactImportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportContacts", ctx, tenantID, listID, format, body)
+	ret0, _ := ret[0].(*dto.ContactImportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportContacts indicates an expected call of ImportContacts.
+func (mr *MockIContactServiceMockRecorder) ImportContacts(ctx, tenantID, listID, format, body interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportContacts", reflect.TypeOf((*MockIContactService)(nil).ImportContacts), ctx, tenantID, listID, format, body)
+}
+
+// SendToList mocks base method.
+func (m *MockIContactService) SendToList(ctx context.Context, tenantID string, listID primitive.ObjectID, request *dto.ListSendRequest) (*dto.ListSendResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SendToList", ctx, tenantID, listID, request)
+	ret0, _ := ret[0].(*dto.ListSendResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SendToList indicates an expected call of SendToList.
+func (mr *MockIContactServiceMockRecorder) SendToList(ctx, tenantID, listID, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendToList", reflect.TypeOf((*MockIContactService)(nil).SendToList), ctx, tenantID, listID, request)
+}
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/middleware/idempotency.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIIdempotencyStore is a mock of IIdempotencyStore interface.
+type MockIIdempotencyStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockIIdempotencyStoreMockRecorder
+}
+
+// MockIIdempotencyStoreMockRecorder is the mock recorder for MockIIdempotencyStore.
+type MockIIdempotencyStoreMockRecorder struct {
+	mock *MockIIdempotencyStore
+}
+
+// NewMockIIdempotencyStore creates a new mock instance.
+func NewMockIIdempotencyStore(ctrl *gomock.Controller) *MockIIdempotencyStore {
+	mock := &MockIIdempotencyStore{ctrl: ctrl}
+	mock.recorder = &MockIIdempotencyStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIIdempotencyStore) EXPECT() *MockIIdempotencyStoreMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockIIdempotencyStore) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockIIdempotencyStoreMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockIIdempotencyStore)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockIIdempotencyStore) Get(ctx context.Context, key string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockIIdempotencyStoreMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIIdempotencyStore)(nil).Get), ctx, key)
+}
+
+// Set mocks base method.
+func (m *MockIIdempotencyStore) Set(ctx context.Context, key string, value interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockIIdempotencyStoreMockRecorder) Set(ctx, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockIIdempotencyStore)(nil).Set), ctx, key, value)
+}
+
+// SetNX mocks base method.
+func (m *MockIIdempotencyStore) SetNX(ctx context.Context, key string, value interface{}, ttl time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetNX", ctx, key, value, ttl)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetNX indicates an expected call of SetNX.
+func (mr *MockIIdempotencyStoreMockRecorder) SetNX(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetNX", reflect.TypeOf((*MockIIdempotencyStore)(nil).SetNX), ctx, key, value, ttl)
+}
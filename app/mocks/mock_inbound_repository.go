@@ -0,0 +1,201 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/service/inbound_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "messaging-system/app/dto"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIInboundRepository is a mock of IInboundRepository interface.
+type MockIInboundRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIInboundRepositoryMockRecorder
+}
+
+// MockIInboundRepositoryMockRecorder is the mock recorder for MockIInboundRepository.
+type MockIInboundRepositoryMockRecorder struct {
+	mock *MockIInboundRepository
+}
+
+// NewMockIInboundRepository creates a new mock instance.
+func NewMockIInboundRepository(ctrl *gomock.Controller) *MockIInboundRepository {
+	mock := &MockIInboundRepository{ctrl: ctrl}
+	mock.recorder = &MockIInboundRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIInboundRepository) EXPECT() *MockIInboundRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateInboundMessage mocks base method.
+func (m *MockIInboundRepository) CreateInboundMessage(ctx context.Context, message *model.InboundMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateInboundMessage", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateInboundMessage indicates an expected call of CreateInboundMessage.
+func (mr *MockIInboundRepositoryMockRecorder) CreateInboundMessage(ctx, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateInboundMessage", reflect.TypeOf((*MockIInboundRepository)(nil).CreateInboundMessage), ctx, message)
+}
+
+// GetInboundMessageByProviderID mocks base method.
+func (m *MockIInboundRepository) GetInboundMessageByProviderID(ctx context.Context, tenantID, providerMessageID string) (*model.InboundMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInboundMessageByProviderID", ctx, tenantID, providerMessageID)
+	ret0, _ := ret[0].(*model.InboundMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInboundMessageByProviderID indicates an expected call of GetInboundMessageByProviderID.
+func (mr *MockIInboundRepositoryMockRecorder) GetInboundMessageByProviderID(ctx, tenantID, providerMessageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInboundMessageByProviderID", reflect.TypeOf((*MockIInboundRepository)(nil).GetInboundMessageByProviderID), ctx, tenantID, providerMessageID)
+}
+
+// GetInboundMessages mocks base method.
+func (m *MockIInboundRepository) GetInboundMessages(ctx context.Context, tenantID, phoneNumber string, limit int) ([]model.InboundMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetInboundMessages", ctx, tenantID, phoneNumber, limit)
+	ret0, _ := ret[0].([]model.InboundMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetInboundMessages indicates an expected call of GetInboundMessages.
+func (mr *MockIInboundRepositoryMockRecorder) GetInboundMessages(ctx, tenantID, phoneNumber, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetInboundMessages", reflect.TypeOf((*MockIInboundRepository)(nil).GetInboundMessages), ctx, tenantID, phoneNumber, limit)
+}
+
+// GetLastSentMessage mocks base method.
+func (m *MockIInboundRepository) GetLastSentMessage(ctx context.Context, tenantID, phoneNumber string) (*model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLastSentMessage", ctx, tenantID, phoneNumber)
+	ret0, _ := ret[0].(*model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLastSentMessage indicates an expected call of GetLastSentMessage.
+func (mr *MockIInboundRepositoryMockRecorder) GetLastSentMessage(ctx, tenantID, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLastSentMessage", reflect.TypeOf((*MockIInboundRepository)(nil).GetLastSentMessage), ctx, tenantID, phoneNumber)
+}
+
+// GetMessagesByPhoneNumber mocks base method.
+func (m *MockIInboundRepository) GetMessagesByPhoneNumber(ctx context.Context, tenantID, phoneNumber string, limit int) ([]model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessagesByPhoneNumber", ctx, tenantID, phoneNumber, limit)
+	ret0, _ := ret[0].([]model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessagesByPhoneNumber indicates an expected call of GetMessagesByPhoneNumber.
+func (mr *MockIInboundRepositoryMockRecorder) GetMessagesByPhoneNumber(ctx, tenantID, phoneNumber, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesByPhoneNumber", reflect.TypeOf((*MockIInboundRepository)(nil).GetMessagesByPhoneNumber), ctx, tenantID, phoneNumber, limit)
+}
+
+// MockISuppressor is a mock of ISuppressor interface.
+type MockISuppressor struct {
+	ctrl     *gomock.Controller
+	recorder *MockISuppressorMockRecorder
+}
+
+// MockISuppressorMockRecorder is the mock recorder for MockISuppressor.
+type MockISuppressorMockRecorder struct {
+	mock *MockISuppressor
+}
+
+// NewMockISuppressor creates a new mock instance.
+func NewMockISuppressor(ctrl *gomock.Controller) *MockISuppressor {
+	mock := &MockISuppressor{ctrl: ctrl}
+	mock.recorder = &MockISuppressorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISuppressor) EXPECT() *MockISuppressorMockRecorder {
+	return m.recorder
+}
+
+// Suppress mocks base method.
+func (m *MockISuppressor) Suppress(ctx context.Context, tenantID string, request *dto.SuppressionRequest, source string) (*model.Suppression, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suppress", ctx, tenantID, request, source)
+	ret0, _ := ret[0].(*model.Suppression)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suppress indicates an expected call of Suppress.
+func (mr *MockISuppressorMockRecorder) Suppress(ctx, tenantID, request, source interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suppress", reflect.TypeOf((*MockISuppressor)(nil).Suppress), ctx, tenantID, request, source)
+}
+
+// Unsuppress mocks base method.
+func (m *MockISuppressor) Unsuppress(ctx context.Context, tenantID, phoneNumber string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsuppress", ctx, tenantID, phoneNumber)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsuppress indicates an expected call of Unsuppress.
+func (mr *MockISuppressorMockRecorder) Unsuppress(ctx, tenantID, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsuppress", reflect.TypeOf((*MockISuppressor)(nil).Unsuppress), ctx, tenantID, phoneNumber)
+}
+
+// MockIReplySender is a mock of IReplySender interface.
+type MockIReplySender struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReplySenderMockRecorder
+}
+
+// MockIReplySenderMockRecorder is the mock recorder for MockIReplySender.
+type MockIReplySenderMockRecorder struct {
+	mock *MockIReplySender
+}
+
+// NewMockIReplySender creates a new mock instance.
+func NewMockIReplySender(ctrl *gomock.Controller) *MockIReplySender {
+	mock := &MockIReplySender{ctrl: ctrl}
+	mock.recorder = &MockIReplySenderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReplySender) EXPECT() *MockIReplySenderMockRecorder {
+	return m.recorder
+}
+
+// CreateMessage mocks base method.
+func (m *MockIReplySender) CreateMessage(ctx context.Context, tenantID string, request *dto.MessageRequest) (*model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMessage", ctx, tenantID, request)
+	ret0, _ := ret[0].(*model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMessage indicates an expected call of CreateMessage.
+func (mr *MockIReplySenderMockRecorder) CreateMessage(ctx, tenantID, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockIReplySender)(nil).CreateMessage), ctx, tenantID, request)
+}
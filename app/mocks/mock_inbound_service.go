@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/handler/inbound_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "messaging-system/app/dto"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIInboundService is a mock of IInboundService interface.
+type MockIInboundService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIInboundServiceMockRecorder
+}
+
+// MockIInboundServiceMockRecorder is the mock recorder for MockIInboundService.
+type MockIInboundServiceMockRecorder struct {
+	mock *MockIInboundService
+}
+
+// NewMockIInboundService creates a new mock instance.
+func NewMockIInboundService(ctrl *gomock.Controller) *MockIInboundService {
+	mock := &MockIInboundService{ctrl: ctrl}
+	mock.recorder = &MockIInboundServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIInboundService) EXPECT() *MockIInboundServiceMockRecorder {
+	return m.recorder
+}
+
+// GetConversation mocks base method.
+func (m *MockIInboundService) GetConversation(ctx context.Context, tenantID, phoneNumber string, limit int) ([]dto.ConversationEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetConversation", ctx, tenantID, phoneNumber, limit)
+	ret0, _ := ret[0].([]dto.ConversationEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetConversation indicates an expected call of GetConversation.
+func (mr *MockIInboundServiceMockRecorder) GetConversation(ctx, tenantID, phoneNumber, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetConversation", reflect.TypeOf((*MockIInboundService)(nil).GetConversation), ctx, tenantID, phoneNumber, limit)
+}
+
+// ReceiveMessage mocks base method.
+func (m *MockIInboundService) ReceiveMessage(ctx context.Context, tenantID string, request *dto.InboundRequest) (*model.InboundMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReceiveMessage", ctx, tenantID, request)
+	ret0, _ := ret[0].(*model.InboundMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReceiveMessage indicates an expected call of ReceiveMessage.
+func (mr *MockIInboundServiceMockRecorder) ReceiveMessage(ctx, tenantID, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiveMessage", reflect.TypeOf((*MockIInboundService)(nil).ReceiveMessage), ctx, tenantID, request)
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/handler/notification_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockINotificationLog is a mock of INotificationLog interface.
+type MockINotificationLog struct {
+	ctrl     *gomock.Controller
+	recorder *MockINotificationLogMockRecorder
+}
+
+// MockINotificationLogMockRecorder is the mock recorder for MockINotificationLog.
+type MockINotificationLogMockRecorder struct {
+	mock *MockINotificationLog
+}
+
+// NewMockINotificationLog creates a new mock instance.
+func NewMockINotificationLog(ctrl *gomock.Controller) *MockINotificationLog {
+	mock := &MockINotificationLog{ctrl: ctrl}
+	mock.recorder = &MockINotificationLogMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockINotificationLog) EXPECT() *MockINotificationLogMockRecorder {
+	return m.recorder
+}
+
+// GetNotifications mocks base method.
+func (m *MockINotificationLog) GetNotifications(ctx context.Context, tenantID string, messageID primitive.ObjectID) ([]model.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotifications", ctx, tenantID, messageID)
+	ret0, _ := ret[0].([]model.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotifications indicates an expected call of GetNotifications.
+func (mr *MockINotificationLogMockRecorder) GetNotifications(ctx, tenantID, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotifications", reflect.TypeOf((*MockINotificationLog)(nil).GetNotifications), ctx, tenantID, messageID)
+}
@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/notifier/notifier.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "messaging-system/app/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockINotificationRepository is a mock of INotificationRepository interface.
+type MockINotificationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockINotificationRepositoryMockRecorder
+}
+
+// MockINotificationRepositoryMockRecorder is the mock recorder for MockINotificationRepository.
+type MockINotificationRepositoryMockRecorder struct {
+	mock *MockINotificationRepository
+}
+
+// NewMockINotificationRepository creates a new mock instance.
+func NewMockINotificationRepository(ctrl *gomock.Controller) *MockINotificationRepository {
+	mock := &MockINotificationRepository{ctrl: ctrl}
+	mock.recorder = &MockINotificationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockINotificationRepository) EXPECT() *MockINotificationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimNotification mocks base method.
+func (m *MockINotificationRepository) ClaimNotification(ctx context.Context, now time.Time, lease time.Duration) (*model.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimNotification", ctx, now, lease)
+	ret0, _ := ret[0].(*model.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimNotification indicates an expected call of ClaimNotification.
+func (mr *MockINotificationRepositoryMockRecorder) ClaimNotification(ctx, now, lease interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimNotification", reflect.TypeOf((*MockINotificationRepository)(nil).ClaimNotification), ctx, now, lease)
+}
+
+// CreateNotification mocks base method.
+func (m *MockINotificationRepository) CreateNotification(ctx context.Context, notification *model.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateNotification", ctx, notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateNotification indicates an expected call of CreateNotification.
+func (mr *MockINotificationRepositoryMockRecorder) CreateNotification(ctx, notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateNotification", reflect.TypeOf((*MockINotificationRepository)(nil).CreateNotification), ctx, notification)
+}
+
+// GetNotifications mocks base method.
+func (m *MockINotificationRepository) GetNotifications(ctx context.Context, tenantID string, messageID primitive.ObjectID) ([]model.Notification, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetNotifications", ctx, tenantID, messageID)
+	ret0, _ := ret[0].([]model.Notification)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetNotifications indicates an expected call of GetNotifications.
+func (mr *MockINotificationRepositoryMockRecorder) GetNotifications(ctx, tenantID, messageID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetNotifications", reflect.TypeOf((*MockINotificationRepository)(nil).GetNotifications), ctx, tenantID, messageID)
+}
+
+// UpdateNotification mocks base method.
+func (m *MockINotificationRepository) UpdateNotification(ctx context.Context, notification *model.Notification) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateNotification", ctx, notification)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateNotification indicates an expected call of UpdateNotification.
+func (mr *MockINotificationRepositoryMockRecorder) UpdateNotification(ctx, notification interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateNotification", reflect.TypeOf((*MockINotificationRepository)(nil).UpdateNotification), ctx, notification)
+}
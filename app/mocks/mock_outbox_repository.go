@@ -0,0 +1,176 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/relay/outbox_relay.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "messaging-system/app/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	bson "go.mongodb.org/mongo-driver/bson"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockIOutboxRepository is a mock of IOutboxRepository interface.
+type MockIOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOutboxRepositoryMockRecorder
+}
+
+// MockIOutboxRepositoryMockRecorder is the mock recorder for MockIOutboxRepository.
+type MockIOutboxRepositoryMockRecorder struct {
+	mock *MockIOutboxRepository
+}
+
+// NewMockIOutboxRepository creates a new mock instance.
+func NewMockIOutboxRepository(ctrl *gomock.Controller) *MockIOutboxRepository {
+	mock := &MockIOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockIOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOutboxRepository) EXPECT() *MockIOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetPendingOutboxMessages mocks base method.
+func (m *MockIOutboxRepository) GetPendingOutboxMessages(ctx context.Context, limit int) ([]model.OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPendingOutboxMessages", ctx, limit)
+	ret0, _ := ret[0].([]model.OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPendingOutboxMessages indicates an expected call of GetPendingOutboxMessages.
+func (mr *MockIOutboxRepositoryMockRecorder) GetPendingOutboxMessages(ctx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPendingOutboxMessages", reflect.TypeOf((*MockIOutboxRepository)(nil).GetPendingOutboxMessages), ctx, limit)
+}
+
+// GetResumeToken mocks base method.
+func (m *MockIOutboxRepository) GetResumeToken(ctx context.Context, streamName string) (bson.Raw, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetResumeToken", ctx, streamName)
+	ret0, _ := ret[0].(bson.Raw)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetResumeToken indicates an expected call of GetResumeToken.
+func (mr *MockIOutboxRepositoryMockRecorder) GetResumeToken(ctx, streamName interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetResumeToken", reflect.TypeOf((*MockIOutboxRepository)(nil).GetResumeToken), ctx, streamName)
+}
+
+// RejectOutboxMessage mocks base method.
+func (m *MockIOutboxRepository) RejectOutboxMessage(ctx context.Context, outboxID primitive.ObjectID, normalizedPhoneNumber, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RejectOutboxMessage", ctx, outboxID, normalizedPhoneNumber, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RejectOutboxMessage indicates an expected call of RejectOutboxMessage.
+func (mr *MockIOutboxRepositoryMockRecorder) RejectOutboxMessage(ctx, outboxID, normalizedPhoneNumber, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RejectOutboxMessage", reflect.TypeOf((*MockIOutboxRepository)(nil).RejectOutboxMessage), ctx, outboxID, normalizedPhoneNumber, reason)
+}
+
+// RelayOutboxMessage mocks base method.
+func (m *MockIOutboxRepository) RelayOutboxMessage(ctx context.Context, outboxID primitive.ObjectID, message *model.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RelayOutboxMessage", ctx, outboxID, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RelayOutboxMessage indicates an expected call of RelayOutboxMessage.
+func (mr *MockIOutboxRepositoryMockRecorder) RelayOutboxMessage(ctx, outboxID, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RelayOutboxMessage", reflect.TypeOf((*MockIOutboxRepository)(nil).RelayOutboxMessage), ctx, outboxID, message)
+}
+
+// SaveResumeToken mocks base method.
+func (m *MockIOutboxRepository) SaveResumeToken(ctx context.Context, streamName string, token bson.Raw) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveResumeToken", ctx, streamName, token)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveResumeToken indicates an expected call of SaveResumeToken.
+func (mr *MockIOutboxRepositoryMockRecorder) SaveResumeToken(ctx, streamName, token interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveResumeToken", reflect.TypeOf((*MockIOutboxRepository)(nil).SaveResumeToken), ctx, streamName, token)
+}
+
+// WatchOutbox mocks base method.
+func (m *MockIOutboxRepository) WatchOutbox(ctx context.Context, resumeToken bson.Raw, startAt time.Time, handle func(context.Context, *model.OutboxMessage, bson.Raw) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchOutbox", ctx, resumeToken, startAt, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchOutbox indicates an expected call of WatchOutbox.
+func (mr *MockIOutboxRepositoryMockRecorder) WatchOutbox(ctx, resumeToken, startAt, handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchOutbox", reflect.TypeOf((*MockIOutboxRepository)(nil).WatchOutbox), ctx, resumeToken, startAt, handle)
+}
+
+// MockIOutboxQuota is a mock of IOutboxQuota interface.
+type MockIOutboxQuota struct {
+	ctrl     *gomock.Controller
+	recorder *MockIOutboxQuotaMockRecorder
+}
+
+// MockIOutboxQuotaMockRecorder is the mock recorder for MockIOutboxQuota.
+type MockIOutboxQuotaMockRecorder struct {
+	mock *MockIOutboxQuota
+}
+
+// NewMockIOutboxQuota creates a new mock instance.
+func NewMockIOutboxQuota(ctrl *gomock.Controller) *MockIOutboxQuota {
+	mock := &MockIOutboxQuota{ctrl: ctrl}
+	mock.recorder = &MockIOutboxQuotaMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIOutboxQuota) EXPECT() *MockIOutboxQuotaMockRecorder {
+	return m.recorder
+}
+
+// Release mocks base method.
+func (m *MockIOutboxQuota) Release(ctx context.Context, tenantID string, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, tenantID, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIOutboxQuotaMockRecorder) Release(ctx, tenantID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIOutboxQuota)(nil).Release), ctx, tenantID, now)
+}
+
+// Reserve mocks base method.
+func (m *MockIOutboxQuota) Reserve(ctx context.Context, tenantID string, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, tenantID, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockIOutboxQuotaMockRecorder) Reserve(ctx, tenantID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockIOutboxQuota)(nil).Reserve), ctx, tenantID, now)
+}
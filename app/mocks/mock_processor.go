@@ -6,8 +6,11 @@ package mocks
 
 import (
 	context "context"
+	dto "messaging-system/app/dto"
+	events "messaging-system/app/events"
 	model "messaging-system/app/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,19 +38,47 @@ func (m *MockIMessageProcessor) EXPECT() *MockIMessageProcessorMockRecorder {
 	return m.recorder
 }
 
+// ExportSentMessages mocks base method.
+func (m *MockIMessageProcessor) ExportSentMessages(ctx context.Context, tenantID string, from, to time.Time, handle func(context.Context, *model.Message) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportSentMessages", ctx, tenantID, from, to, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportSentMessages indicates an expected call of ExportSentMessages.
+func (mr *MockIMessageProcessorMockRecorder) ExportSentMessages(ctx, tenantID, from, to, handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportSentMessages", reflect.TypeOf((*MockIMessageProcessor)(nil).ExportSentMessages), ctx, tenantID, from, to, handle)
+}
+
 // GetSentMessages mocks base method.
-func (m *MockIMessageProcessor) GetSentMessages(ctx context.Context, limit int) ([]model.Message, error) {
+func (m *MockIMessageProcessor) GetSentMessages(ctx context.Context, tenantID string, limit int) ([]model.Message, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSentMessages", ctx, limit)
+	ret := m.ctrl.Call(m, "GetSentMessages", ctx, tenantID, limit)
 	ret0, _ := ret[0].([]model.Message)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetSentMessages indicates an expected call of GetSentMessages.
-func (mr *MockIMessageProcessorMockRecorder) GetSentMessages(ctx, limit interface{}) *gomock.Call {
+func (mr *MockIMessageProcessorMockRecorder) GetSentMessages(ctx, tenantID, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSentMessages", reflect.TypeOf((*MockIMessageProcessor)(nil).GetSentMessages), ctx, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSentMessages", reflect.TypeOf((*MockIMessageProcessor)(nil).GetSentMessages), ctx, tenantID, limit)
+}
+
+// IsRunning mocks base method.
+func (m *MockIMessageProcessor) IsRunning() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRunning")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsRunning indicates an expected call of IsRunning.
+func (mr *MockIMessageProcessorMockRecorder) IsRunning() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRunning", reflect.TypeOf((*MockIMessageProcessor)(nil).IsRunning))
 }
 
 // Start mocks base method.
@@ -73,3 +104,93 @@ func (mr *MockIMessageProcessorMockRecorder) Stop(ctx interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockIMessageProcessor)(nil).Stop), ctx)
 }
+
+// Subscribe mocks base method.
+func (m *MockIMessageProcessor) Subscribe(filter events.Filter) (<-chan events.Event, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", filter)
+	ret0, _ := ret[0].(<-chan events.Event)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockIMessageProcessorMockRecorder) Subscribe(filter interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockIMessageProcessor)(nil).Subscribe), filter)
+}
+
+// MockIMessageCreator is a mock of IMessageCreator interface.
+type MockIMessageCreator struct {
+	ctrl     *gomock.Controller
+	recorder *MockIMessageCreatorMockRecorder
+}
+
+// MockIMessageCreatorMockRecorder is the mock recorder for MockIMessageCreator.
+type MockIMessageCreatorMockRecorder struct {
+	mock *MockIMessageCreator
+}
+
+// NewMockIMessageCreator creates a new mock instance.
+func NewMockIMessageCreator(ctrl *gomock.Controller) *MockIMessageCreator {
+	mock := &MockIMessageCreator{ctrl: ctrl}
+	mock.recorder = &MockIMessageCreatorMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIMessageCreator) EXPECT() *MockIMessageCreatorMockRecorder {
+	return m.recorder
+}
+
+// CreateMessage mocks base method.
+func (m *MockIMessageCreator) CreateMessage(ctx context.Context, tenantID string, request *dto.MessageRequest) (*model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMessage", ctx, tenantID, request)
+	ret0, _ := ret[0].(*model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMessage indicates an expected call of CreateMessage.
+func (mr *MockIMessageCreatorMockRecorder) CreateMessage(ctx, tenantID, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockIMessageCreator)(nil).CreateMessage), ctx, tenantID, request)
+}
+
+// MockIAuditRecorder is a mock of IAuditRecorder interface.
+type MockIAuditRecorder struct {
+	ctrl     *gomock.Controller
+	recorder *MockIAuditRecorderMockRecorder
+}
+
+// MockIAuditRecorderMockRecorder is the mock recorder for MockIAuditRecorder.
+type MockIAuditRecorderMockRecorder struct {
+	mock *MockIAuditRecorder
+}
+
+// NewMockIAuditRecorder creates a new mock instance.
+func NewMockIAuditRecorder(ctrl *gomock.Controller) *MockIAuditRecorder {
+	mock := &MockIAuditRecorder{ctrl: ctrl}
+	mock.recorder = &MockIAuditRecorderMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIAuditRecorder) EXPECT() *MockIAuditRecorderMockRecorder {
+	return m.recorder
+}
+
+// Record mocks base method.
+func (m *MockIAuditRecorder) Record(ctx context.Context, event *model.AuditEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Record", ctx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Record indicates an expected call of Record.
+func (mr *MockIAuditRecorderMockRecorder) Record(ctx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Record", reflect.TypeOf((*MockIAuditRecorder)(nil).Record), ctx, event)
+}
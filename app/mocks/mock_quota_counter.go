@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/service/quota_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIQuotaCounter is a mock of IQuotaCounter interface.
+type MockIQuotaCounter struct {
+	ctrl     *gomock.Controller
+	recorder *MockIQuotaCounterMockRecorder
+}
+
+// MockIQuotaCounterMockRecorder is the mock recorder for MockIQuotaCounter.
+type MockIQuotaCounterMockRecorder struct {
+	mock *MockIQuotaCounter
+}
+
+// NewMockIQuotaCounter creates a new mock instance.
+func NewMockIQuotaCounter(ctrl *gomock.Controller) *MockIQuotaCounter {
+	mock := &MockIQuotaCounter{ctrl: ctrl}
+	mock.recorder = &MockIQuotaCounterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIQuotaCounter) EXPECT() *MockIQuotaCounterMockRecorder {
+	return m.recorder
+}
+
+// Decrement mocks base method.
+func (m *MockIQuotaCounter) Decrement(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Decrement", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Decrement indicates an expected call of Decrement.
+func (mr *MockIQuotaCounterMockRecorder) Decrement(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Decrement", reflect.TypeOf((*MockIQuotaCounter)(nil).Decrement), ctx, key)
+}
+
+// DecrementBy mocks base method.
+func (m *MockIQuotaCounter) DecrementBy(ctx context.Context, key string, n int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecrementBy", ctx, key, n)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DecrementBy indicates an expected call of DecrementBy.
+func (mr *MockIQuotaCounterMockRecorder) DecrementBy(ctx, key, n interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementBy", reflect.TypeOf((*MockIQuotaCounter)(nil).DecrementBy), ctx, key, n)
+}
+
+// Increment mocks base method.
+func (m *MockIQuotaCounter) Increment(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Increment", ctx, key, ttl)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Increment indicates an expected call of Increment.
+func (mr *MockIQuotaCounterMockRecorder) Increment(ctx, key, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Increment", reflect.TypeOf((*MockIQuotaCounter)(nil).Increment), ctx, key, ttl)
+}
+
+// IncrementBy mocks base method.
+func (m *MockIQuotaCounter) IncrementBy(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementBy", ctx, key, n, ttl)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IncrementBy indicates an expected call of IncrementBy.
+func (mr *MockIQuotaCounterMockRecorder) IncrementBy(ctx, key, n, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementBy", reflect.TypeOf((*MockIQuotaCounter)(nil).IncrementBy), ctx, key, n, ttl)
+}
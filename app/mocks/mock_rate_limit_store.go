@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/middleware/ratelimit.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	cache "messaging-system/app/cache"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIRateLimitStore is a mock of IRateLimitStore interface.
+type MockIRateLimitStore struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRateLimitStoreMockRecorder
+}
+
+// MockIRateLimitStoreMockRecorder is the mock recorder for MockIRateLimitStore.
+type MockIRateLimitStoreMockRecorder struct {
+	mock *MockIRateLimitStore
+}
+
+// NewMockIRateLimitStore creates a new mock instance.
+func NewMockIRateLimitStore(ctrl *gomock.Controller) *MockIRateLimitStore {
+	mock := &MockIRateLimitStore{ctrl: ctrl}
+	mock.recorder = &MockIRateLimitStoreMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRateLimitStore) EXPECT() *MockIRateLimitStoreMockRecorder {
+	return m.recorder
+}
+
+// SlidingWindow mocks base method.
+func (m *MockIRateLimitStore) SlidingWindow(ctx context.Context, key string, now time.Time, window time.Duration, limit int) (*cache.WindowUsage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SlidingWindow", ctx, key, now, window, limit)
+	ret0, _ := ret[0].(*cache.WindowUsage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SlidingWindow indicates an expected call of SlidingWindow.
+func (mr *MockIRateLimitStoreMockRecorder) SlidingWindow(ctx, key, now, window, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SlidingWindow", reflect.TypeOf((*MockIRateLimitStore)(nil).SlidingWindow), ctx, key, now, window, limit)
+}
@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/service/receipt_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "messaging-system/app/dto"
+	model "messaging-system/app/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIReceiptRepository is a mock of IReceiptRepository interface.
+type MockIReceiptRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReceiptRepositoryMockRecorder
+}
+
+// MockIReceiptRepositoryMockRecorder is the mock recorder for MockIReceiptRepository.
+type MockIReceiptRepositoryMockRecorder struct {
+	mock *MockIReceiptRepository
+}
+
+// NewMockIReceiptRepository creates a new mock instance.
+func NewMockIReceiptRepository(ctrl *gomock.Controller) *MockIReceiptRepository {
+	mock := &MockIReceiptRepository{ctrl: ctrl}
+	mock.recorder = &MockIReceiptRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReceiptRepository) EXPECT() *MockIReceiptRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ApplyReceipt mocks base method.
+func (m *MockIReceiptRepository) ApplyReceipt(ctx context.Context, tenantID string, receipt *dto.ReceiptRequest, now time.Time) (*model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ApplyReceipt", ctx, tenantID, receipt, now)
+	ret0, _ := ret[0].(*model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ApplyReceipt indicates an expected call of ApplyReceipt.
+func (mr *MockIReceiptRepositoryMockRecorder) ApplyReceipt(ctx, tenantID, receipt, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ApplyReceipt", reflect.TypeOf((*MockIReceiptRepository)(nil).ApplyReceipt), ctx, tenantID, receipt, now)
+}
+
+// MockIStatusNotifier is a mock of IStatusNotifier interface.
+type MockIStatusNotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockIStatusNotifierMockRecorder
+}
+
+// MockIStatusNotifierMockRecorder is the mock recorder for MockIStatusNotifier.
+type MockIStatusNotifierMockRecorder struct {
+	mock *MockIStatusNotifier
+}
+
+// NewMockIStatusNotifier creates a new mock instance.
+func NewMockIStatusNotifier(ctrl *gomock.Controller) *MockIStatusNotifier {
+	mock := &MockIStatusNotifier{ctrl: ctrl}
+	mock.recorder = &MockIStatusNotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIStatusNotifier) EXPECT() *MockIStatusNotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockIStatusNotifier) Notify(ctx context.Context, message *model.Message, eventType, reason string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Notify", ctx, message, eventType, reason)
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockIStatusNotifierMockRecorder) Notify(ctx, message, eventType, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockIStatusNotifier)(nil).Notify), ctx, message, eventType, reason)
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/handler/receipt_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "messaging-system/app/dto"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIReceiptService is a mock of IReceiptService interface.
+type MockIReceiptService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReceiptServiceMockRecorder
+}
+
+// MockIReceiptServiceMockRecorder is the mock recorder for MockIReceiptService.
+type MockIReceiptServiceMockRecorder struct {
+	mock *MockIReceiptService
+}
+
+// NewMockIReceiptService creates a new mock instance.
+func NewMockIReceiptService(ctrl *gomock.Controller) *MockIReceiptService {
+	mock := &MockIReceiptService{ctrl: ctrl}
+	mock.recorder = &MockIReceiptServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReceiptService) EXPECT() *MockIReceiptServiceMockRecorder {
+	return m.recorder
+}
+
+// ReceiveReceipt mocks base method.
+func (m *MockIReceiptService) ReceiveReceipt(ctx context.Context, tenantID string, request *dto.ReceiptRequest) (*model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReceiveReceipt", ctx, tenantID, request)
+	ret0, _ := ret[0].(*model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReceiveReceipt indicates an expected call of ReceiveReceipt.
+func (mr *MockIReceiptServiceMockRecorder) ReceiveReceipt(ctx, tenantID, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiveReceipt", reflect.TypeOf((*MockIReceiptService)(nil).ReceiveReceipt), ctx, tenantID, request)
+}
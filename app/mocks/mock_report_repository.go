@@ -0,0 +1,105 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/service/report_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "messaging-system/app/dto"
+	model "messaging-system/app/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIReportRepository is a mock of IReportRepository interface.
+type MockIReportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReportRepositoryMockRecorder
+}
+
+// MockIReportRepositoryMockRecorder is the mock recorder for MockIReportRepository.
+type MockIReportRepositoryMockRecorder struct {
+	mock *MockIReportRepository
+}
+
+// NewMockIReportRepository creates a new mock instance.
+func NewMockIReportRepository(ctrl *gomock.Controller) *MockIReportRepository {
+	mock := &MockIReportRepository{ctrl: ctrl}
+	mock.recorder = &MockIReportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReportRepository) EXPECT() *MockIReportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetMessageReport mocks base method.
+func (m *MockIReportRepository) GetMessageReport(ctx context.Context, tenantID string, request *dto.ReportRequest) ([]model.ReportRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessageReport", ctx, tenantID, request)
+	ret0, _ := ret[0].([]model.ReportRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessageReport indicates an expected call of GetMessageReport.
+func (mr *MockIReportRepositoryMockRecorder) GetMessageReport(ctx, tenantID, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessageReport", reflect.TypeOf((*MockIReportRepository)(nil).GetMessageReport), ctx, tenantID, request)
+}
+
+// MockIReportCache is a mock of IReportCache interface.
+type MockIReportCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReportCacheMockRecorder
+}
+
+// MockIReportCacheMockRecorder is the mock recorder for MockIReportCache.
+type MockIReportCacheMockRecorder struct {
+	mock *MockIReportCache
+}
+
+// NewMockIReportCache creates a new mock instance.
+func NewMockIReportCache(ctrl *gomock.Controller) *MockIReportCache {
+	mock := &MockIReportCache{ctrl: ctrl}
+	mock.recorder = &MockIReportCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReportCache) EXPECT() *MockIReportCacheMockRecorder {
+	return m.recorder
+}
+
+// Get mocks base method.
+func (m *MockIReportCache) Get(ctx context.Context, key string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockIReportCacheMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockIReportCache)(nil).Get), ctx, key)
+}
+
+// SetWithTTL mocks base method.
+func (m *MockIReportCache) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetWithTTL", ctx, key, value, ttl)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetWithTTL indicates an expected call of SetWithTTL.
+func (mr *MockIReportCacheMockRecorder) SetWithTTL(ctx, key, value, ttl interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetWithTTL", reflect.TypeOf((*MockIReportCache)(nil).SetWithTTL), ctx, key, value, ttl)
+}
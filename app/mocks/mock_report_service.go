@@ -0,0 +1,67 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/handler/report_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "messaging-system/app/dto"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIReportService is a mock of IReportService interface.
+type MockIReportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockIReportServiceMockRecorder
+}
+
+// MockIReportServiceMockRecorder is the mock recorder for MockIReportService.
+type MockIReportServiceMockRecorder struct {
+	mock *MockIReportService
+}
+
+// NewMockIReportService creates a new mock instance.
+func NewMockIReportService(ctrl *gomock.Controller) *MockIReportService {
+	mock := &MockIReportService{ctrl: ctrl}
+	mock.recorder = &MockIReportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIReportService) EXPECT() *MockIReportServiceMockRecorder {
+	return m.recorder
+}
+
+// Summary mocks base method.
+func (m *MockIReportService) Summary(ctx context.Context, tenantID string, request *dto.ReportRequest) (*model.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Summary", ctx, tenantID, request)
+	ret0, _ := ret[0].(*model.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Summary indicates an expected call of Summary.
+func (mr *MockIReportServiceMockRecorder) Summary(ctx, tenantID, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Summary", reflect.TypeOf((*MockIReportService)(nil).Summary), ctx, tenantID, request)
+}
+
+// Timeseries mocks base method.
+func (m *MockIReportService) Timeseries(ctx context.Context, tenantID string, request *dto.ReportRequest) (*model.Report, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Timeseries", ctx, tenantID, request)
+	ret0, _ := ret[0].(*model.Report)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Timeseries indicates an expected call of Timeseries.
+func (mr *MockIReportServiceMockRecorder) Timeseries(ctx, tenantID, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Timeseries", reflect.TypeOf((*MockIReportService)(nil).Timeseries), ctx, tenantID, request)
+}
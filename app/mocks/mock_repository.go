@@ -8,6 +8,7 @@ import (
 	context "context"
 	model "messaging-system/app/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	primitive "go.mongodb.org/mongo-driver/bson/primitive"
@@ -36,31 +37,240 @@ func (m *MockIRepository) EXPECT() *MockIRepositoryMockRecorder {
 	return m.recorder
 }
 
+// CreateMessage mocks base method.
+func (m *MockIRepository) CreateMessage(ctx context.Context, message *model.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMessage", ctx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMessage indicates an expected call of CreateMessage.
+func (mr *MockIRepositoryMockRecorder) CreateMessage(ctx, message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockIRepository)(nil).CreateMessage), ctx, message)
+}
+
+// CreateMessages mocks base method.
+func (m *MockIRepository) CreateMessages(ctx context.Context, messages []*model.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMessages", ctx, messages)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateMessages indicates an expected call of CreateMessages.
+func (mr *MockIRepositoryMockRecorder) CreateMessages(ctx, messages interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessages", reflect.TypeOf((*MockIRepository)(nil).CreateMessages), ctx, messages)
+}
+
+// ExpireMessages mocks base method.
+func (m *MockIRepository) ExpireMessages(ctx context.Context, now time.Time) ([]model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireMessages", ctx, now)
+	ret0, _ := ret[0].([]model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireMessages indicates an expected call of ExpireMessages.
+func (mr *MockIRepositoryMockRecorder) ExpireMessages(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireMessages", reflect.TypeOf((*MockIRepository)(nil).ExpireMessages), ctx, now)
+}
+
+// ForEachSentMessage mocks base method.
+func (m *MockIRepository) ForEachSentMessage(ctx context.Context, tenantID string, from, to time.Time, handle func(context.Context, *model.Message) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEachSentMessage", ctx, tenantID, from, to, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEachSentMessage indicates an expected call of ForEachSentMessage.
+func (mr *MockIRepositoryMockRecorder) ForEachSentMessage(ctx, tenantID, from, to, handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEachSentMessage", reflect.TypeOf((*MockIRepository)(nil).ForEachSentMessage), ctx, tenantID, from, to, handle)
+}
+
 // GetMessages mocks base method.
-func (m *MockIRepository) GetMessages(ctx context.Context, status string, limit int) ([]model.Message, error) {
+func (m *MockIRepository) GetMessages(ctx context.Context, tenantID, status string, limit int) ([]model.Message, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMessages", ctx, status, limit)
+	ret := m.ctrl.Call(m, "GetMessages", ctx, tenantID, status, limit)
 	ret0, _ := ret[0].([]model.Message)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMessages indicates an expected call of GetMessages.
-func (mr *MockIRepositoryMockRecorder) GetMessages(ctx, status, limit interface{}) *gomock.Call {
+func (mr *MockIRepositoryMockRecorder) GetMessages(ctx, tenantID, status, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockIRepository)(nil).GetMessages), ctx, status, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockIRepository)(nil).GetMessages), ctx, tenantID, status, limit)
+}
+
+// GetMessagesByPriority mocks base method.
+func (m *MockIRepository) GetMessagesByPriority(ctx context.Context, status string, priorities []int, excludedCampaigns []primitive.ObjectID, limit int) ([]model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessagesByPriority", ctx, status, priorities, excludedCampaigns, limit)
+	ret0, _ := ret[0].([]model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessagesByPriority indicates an expected call of GetMessagesByPriority.
+func (mr *MockIRepositoryMockRecorder) GetMessagesByPriority(ctx, status, priorities, excludedCampaigns, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesByPriority", reflect.TypeOf((*MockIRepository)(nil).GetMessagesByPriority), ctx, status, priorities, excludedCampaigns, limit)
+}
+
+// GetTemplate mocks base method.
+func (m *MockIRepository) GetTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID) (*model.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplate", ctx, tenantID, templateID)
+	ret0, _ := ret[0].(*model.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplate indicates an expected call of GetTemplate.
+func (mr *MockIRepositoryMockRecorder) GetTemplate(ctx, tenantID, templateID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplate", reflect.TypeOf((*MockIRepository)(nil).GetTemplate), ctx, tenantID, templateID)
 }
 
 // MarkMessageAsSent mocks base method.
-func (m *MockIRepository) MarkMessageAsSent(ctx context.Context, messageID primitive.ObjectID, webhookMessageID string) error {
+func (m *MockIRepository) MarkMessageAsSent(ctx context.Context, messageID primitive.ObjectID, webhookMessageID, provider string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkMessageAsSent", ctx, messageID, webhookMessageID)
+	ret := m.ctrl.Call(m, "MarkMessageAsSent", ctx, messageID, webhookMessageID, provider)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // MarkMessageAsSent indicates an expected call of MarkMessageAsSent.
-func (mr *MockIRepositoryMockRecorder) MarkMessageAsSent(ctx, messageID, webhookMessageID interface{}) *gomock.Call {
+func (mr *MockIRepositoryMockRecorder) MarkMessageAsSent(ctx, messageID, webhookMessageID, provider interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkMessageAsSent", reflect.TypeOf((*MockIRepository)(nil).MarkMessageAsSent), ctx, messageID, webhookMessageID, provider)
+}
+
+// RecordSendFailure mocks base method.
+func (m *MockIRepository) RecordSendFailure(ctx context.Context, messageID primitive.ObjectID, reason string, final bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSendFailure", ctx, messageID, reason, final)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordSendFailure indicates an expected call of RecordSendFailure.
+func (mr *MockIRepositoryMockRecorder) RecordSendFailure(ctx, messageID, reason, final interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSendFailure", reflect.TypeOf((*MockIRepository)(nil).RecordSendFailure), ctx, messageID, reason, final)
+}
+
+// UpdateMessageStatus mocks base method.
+func (m *MockIRepository) UpdateMessageStatus(ctx context.Context, messageID primitive.ObjectID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessageStatus", ctx, messageID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMessageStatus indicates an expected call of UpdateMessageStatus.
+func (mr *MockIRepositoryMockRecorder) UpdateMessageStatus(ctx, messageID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessageStatus", reflect.TypeOf((*MockIRepository)(nil).UpdateMessageStatus), ctx, messageID, status)
+}
+
+// WatchUnsentMessages mocks base method.
+func (m *MockIRepository) WatchUnsentMessages(ctx context.Context, handle func(context.Context, *model.Message) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchUnsentMessages", ctx, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchUnsentMessages indicates an expected call of WatchUnsentMessages.
+func (mr *MockIRepositoryMockRecorder) WatchUnsentMessages(ctx, handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchUnsentMessages", reflect.TypeOf((*MockIRepository)(nil).WatchUnsentMessages), ctx, handle)
+}
+
+// MockIQuota is a mock of IQuota interface.
+type MockIQuota struct {
+	ctrl     *gomock.Controller
+	recorder *MockIQuotaMockRecorder
+}
+
+// MockIQuotaMockRecorder is the mock recorder for MockIQuota.
+type MockIQuotaMockRecorder struct {
+	mock *MockIQuota
+}
+
+// NewMockIQuota creates a new mock instance.
+func NewMockIQuota(ctrl *gomock.Controller) *MockIQuota {
+	mock := &MockIQuota{ctrl: ctrl}
+	mock.recorder = &MockIQuotaMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIQuota) EXPECT() *MockIQuotaMockRecorder {
+	return m.recorder
+}
+
+// Release mocks base method.
+func (m *MockIQuota) Release(ctx context.Context, tenantID string, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, tenantID, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockIQuotaMockRecorder) Release(ctx, tenantID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockIQuota)(nil).Release), ctx, tenantID, now)
+}
+
+// ReleaseMany mocks base method.
+func (m *MockIQuota) ReleaseMany(ctx context.Context, tenantID string, count int, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseMany", ctx, tenantID, count, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseMany indicates an expected call of ReleaseMany.
+func (mr *MockIQuotaMockRecorder) ReleaseMany(ctx, tenantID, count, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseMany", reflect.TypeOf((*MockIQuota)(nil).ReleaseMany), ctx, tenantID, count, now)
+}
+
+// Reserve mocks base method.
+func (m *MockIQuota) Reserve(ctx context.Context, tenantID string, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reserve", ctx, tenantID, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockIQuotaMockRecorder) Reserve(ctx, tenantID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockIQuota)(nil).Reserve), ctx, tenantID, now)
+}
+
+// ReserveMany mocks base method.
+func (m *MockIQuota) ReserveMany(ctx context.Context, tenantID string, count int, now time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReserveMany", ctx, tenantID, count, now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReserveMany indicates an expected call of ReserveMany.
+func (mr *MockIQuotaMockRecorder) ReserveMany(ctx, tenantID, count, now interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkMessageAsSent", reflect.TypeOf((*MockIRepository)(nil).MarkMessageAsSent), ctx, messageID, webhookMessageID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReserveMany", reflect.TypeOf((*MockIQuota)(nil).ReserveMany), ctx, tenantID, count, now)
 }
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/handler/retention_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "messaging-system/app/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockIRetentionReporter is a mock of IRetentionReporter interface.
+type MockIRetentionReporter struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRetentionReporterMockRecorder
+}
+
+// MockIRetentionReporterMockRecorder is the mock recorder for MockIRetentionReporter.
+type MockIRetentionReporterMockRecorder struct {
+	mock *MockIRetentionReporter
+}
+
+// NewMockIRetentionReporter creates a new mock instance.
+func NewMockIRetentionReporter(ctrl *gomock.Controller) *MockIRetentionReporter {
+	mock := &MockIRetentionReporter{ctrl: ctrl}
+	mock.recorder = &MockIRetentionReporterMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRetentionReporter) EXPECT() *MockIRetentionReporterMockRecorder {
+	return m.recorder
+}
+
+// DryRun mocks base method.
+func (m *MockIRetentionReporter) DryRun(ctx context.Context, tenantID string, now time.Time) (*model.RetentionReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DryRun", ctx, tenantID, now)
+	ret0, _ := ret[0].(*model.RetentionReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DryRun indicates an expected call of DryRun.
+func (mr *MockIRetentionReporterMockRecorder) DryRun(ctx, tenantID, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DryRun", reflect.TypeOf((*MockIRetentionReporter)(nil).DryRun), ctx, tenantID, now)
+}
@@ -0,0 +1,121 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/retention/archiver.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "messaging-system/app/model"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockIRetentionRepository is a mock of IRetentionRepository interface.
+type MockIRetentionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIRetentionRepositoryMockRecorder
+}
+
+// MockIRetentionRepositoryMockRecorder is the mock recorder for MockIRetentionRepository.
+type MockIRetentionRepositoryMockRecorder struct {
+	mock *MockIRetentionRepository
+}
+
+// NewMockIRetentionRepository creates a new mock instance.
+func NewMockIRetentionRepository(ctrl *gomock.Controller) *MockIRetentionRepository {
+	mock := &MockIRetentionRepository{ctrl: ctrl}
+	mock.recorder = &MockIRetentionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIRetentionRepository) EXPECT() *MockIRetentionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CountMessagesCreatedBefore mocks base method.
+func (m *MockIRetentionRepository) CountMessagesCreatedBefore(ctx context.Context, tenantID, status string, before time.Time) (int64, *time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountMessagesCreatedBefore", ctx, tenantID, status, before)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(*time.Time)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CountMessagesCreatedBefore indicates an expected call of CountMessagesCreatedBefore.
+func (mr *MockIRetentionRepositoryMockRecorder) CountMessagesCreatedBefore(ctx, tenantID, status, before interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountMessagesCreatedBefore", reflect.TypeOf((*MockIRetentionRepository)(nil).CountMessagesCreatedBefore), ctx, tenantID, status, before)
+}
+
+// DeleteMessages mocks base method.
+func (m *MockIRetentionRepository) DeleteMessages(ctx context.Context, messageIDs []primitive.ObjectID) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteMessages", ctx, messageIDs)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteMessages indicates an expected call of DeleteMessages.
+func (mr *MockIRetentionRepositoryMockRecorder) DeleteMessages(ctx, messageIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteMessages", reflect.TypeOf((*MockIRetentionRepository)(nil).DeleteMessages), ctx, messageIDs)
+}
+
+// GetMessagesCreatedBefore mocks base method.
+func (m *MockIRetentionRepository) GetMessagesCreatedBefore(ctx context.Context, status string, before time.Time, limit int) ([]model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessagesCreatedBefore", ctx, status, before, limit)
+	ret0, _ := ret[0].([]model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessagesCreatedBefore indicates an expected call of GetMessagesCreatedBefore.
+func (mr *MockIRetentionRepositoryMockRecorder) GetMessagesCreatedBefore(ctx, status, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesCreatedBefore", reflect.TypeOf((*MockIRetentionRepository)(nil).GetMessagesCreatedBefore), ctx, status, before, limit)
+}
+
+// MockIMessageArchive is a mock of IMessageArchive interface.
+type MockIMessageArchive struct {
+	ctrl     *gomock.Controller
+	recorder *MockIMessageArchiveMockRecorder
+}
+
+// MockIMessageArchiveMockRecorder is the mock recorder for MockIMessageArchive.
+type MockIMessageArchiveMockRecorder struct {
+	mock *MockIMessageArchive
+}
+
+// NewMockIMessageArchive creates a new mock instance.
+func NewMockIMessageArchive(ctrl *gomock.Controller) *MockIMessageArchive {
+	mock := &MockIMessageArchive{ctrl: ctrl}
+	mock.recorder = &MockIMessageArchiveMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIMessageArchive) EXPECT() *MockIMessageArchiveMockRecorder {
+	return m.recorder
+}
+
+// ArchiveMessages mocks base method.
+func (m *MockIMessageArchive) ArchiveMessages(ctx context.Context, messages []model.Message) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveMessages", ctx, messages)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ArchiveMessages indicates an expected call of ArchiveMessages.
+func (mr *MockIMessageArchiveMockRecorder) ArchiveMessages(ctx, messages interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveMessages", reflect.TypeOf((*MockIMessageArchive)(nil).ArchiveMessages), ctx, messages)
+}
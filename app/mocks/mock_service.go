@@ -9,6 +9,7 @@ import (
 	dto "messaging-system/app/dto"
 	model "messaging-system/app/model"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	primitive "go.mongodb.org/mongo-driver/bson/primitive"
@@ -37,33 +38,119 @@ func (m *MockIMessageService) EXPECT() *MockIMessageServiceMockRecorder {
 	return m.recorder
 }
 
+// ExpireMessages mocks base method.
+func (m *MockIMessageService) ExpireMessages(ctx context.Context, now time.Time) ([]model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireMessages", ctx, now)
+	ret0, _ := ret[0].([]model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireMessages indicates an expected call of ExpireMessages.
+func (mr *MockIMessageServiceMockRecorder) ExpireMessages(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireMessages", reflect.TypeOf((*MockIMessageService)(nil).ExpireMessages), ctx, now)
+}
+
+// ForEachSentMessage mocks base method.
+func (m *MockIMessageService) ForEachSentMessage(ctx context.Context, tenantID string, from, to time.Time, handle func(context.Context, *model.Message) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForEachSentMessage", ctx, tenantID, from, to, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForEachSentMessage indicates an expected call of ForEachSentMessage.
+func (mr *MockIMessageServiceMockRecorder) ForEachSentMessage(ctx, tenantID, from, to, handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForEachSentMessage", reflect.TypeOf((*MockIMessageService)(nil).ForEachSentMessage), ctx, tenantID, from, to, handle)
+}
+
 // GetMessages mocks base method.
-func (m *MockIMessageService) GetMessages(ctx context.Context, status string, limit int) ([]model.Message, error) {
+func (m *MockIMessageService) GetMessages(ctx context.Context, tenantID, status string, limit int) ([]model.Message, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetMessages", ctx, status, limit)
+	ret := m.ctrl.Call(m, "GetMessages", ctx, tenantID, status, limit)
 	ret0, _ := ret[0].([]model.Message)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetMessages indicates an expected call of GetMessages.
-func (mr *MockIMessageServiceMockRecorder) GetMessages(ctx, status, limit interface{}) *gomock.Call {
+func (mr *MockIMessageServiceMockRecorder) GetMessages(ctx, tenantID, status, limit interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockIMessageService)(nil).GetMessages), ctx, status, limit)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessages", reflect.TypeOf((*MockIMessageService)(nil).GetMessages), ctx, tenantID, status, limit)
+}
+
+// GetMessagesByPriority mocks base method.
+func (m *MockIMessageService) GetMessagesByPriority(ctx context.Context, status string, priorities []int, excludedCampaigns []primitive.ObjectID, limit int) ([]model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetMessagesByPriority", ctx, status, priorities, excludedCampaigns, limit)
+	ret0, _ := ret[0].([]model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetMessagesByPriority indicates an expected call of GetMessagesByPriority.
+func (mr *MockIMessageServiceMockRecorder) GetMessagesByPriority(ctx, status, priorities, excludedCampaigns, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMessagesByPriority", reflect.TypeOf((*MockIMessageService)(nil).GetMessagesByPriority), ctx, status, priorities, excludedCampaigns, limit)
 }
 
 // MarkMessageAsSent mocks base method.
-func (m *MockIMessageService) MarkMessageAsSent(ctx context.Context, messageID primitive.ObjectID, webhookMessageID string) error {
+func (m *MockIMessageService) MarkMessageAsSent(ctx context.Context, messageID primitive.ObjectID, webhookMessageID, provider string) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkMessageAsSent", ctx, messageID, webhookMessageID)
+	ret := m.ctrl.Call(m, "MarkMessageAsSent", ctx, messageID, webhookMessageID, provider)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // MarkMessageAsSent indicates an expected call of MarkMessageAsSent.
-func (mr *MockIMessageServiceMockRecorder) MarkMessageAsSent(ctx, messageID, webhookMessageID interface{}) *gomock.Call {
+func (mr *MockIMessageServiceMockRecorder) MarkMessageAsSent(ctx, messageID, webhookMessageID, provider interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkMessageAsSent", reflect.TypeOf((*MockIMessageService)(nil).MarkMessageAsSent), ctx, messageID, webhookMessageID, provider)
+}
+
+// RecordSendFailure mocks base method.
+func (m *MockIMessageService) RecordSendFailure(ctx context.Context, messageID primitive.ObjectID, reason string, final bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordSendFailure", ctx, messageID, reason, final)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordSendFailure indicates an expected call of RecordSendFailure.
+func (mr *MockIMessageServiceMockRecorder) RecordSendFailure(ctx, messageID, reason, final interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkMessageAsSent", reflect.TypeOf((*MockIMessageService)(nil).MarkMessageAsSent), ctx, messageID, webhookMessageID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordSendFailure", reflect.TypeOf((*MockIMessageService)(nil).RecordSendFailure), ctx, messageID, reason, final)
+}
+
+// UpdateMessageStatus mocks base method.
+func (m *MockIMessageService) UpdateMessageStatus(ctx context.Context, messageID primitive.ObjectID, status string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateMessageStatus", ctx, messageID, status)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateMessageStatus indicates an expected call of UpdateMessageStatus.
+func (mr *MockIMessageServiceMockRecorder) UpdateMessageStatus(ctx, messageID, status interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateMessageStatus", reflect.TypeOf((*MockIMessageService)(nil).UpdateMessageStatus), ctx, messageID, status)
+}
+
+// WatchUnsentMessages mocks base method.
+func (m *MockIMessageService) WatchUnsentMessages(ctx context.Context, handle func(context.Context, *model.Message) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WatchUnsentMessages", ctx, handle)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WatchUnsentMessages indicates an expected call of WatchUnsentMessages.
+func (mr *MockIMessageServiceMockRecorder) WatchUnsentMessages(ctx, handle interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WatchUnsentMessages", reflect.TypeOf((*MockIMessageService)(nil).WatchUnsentMessages), ctx, handle)
 }
 
 // MockIClient is a mock of IClient interface.
@@ -90,18 +177,18 @@ func (m *MockIClient) EXPECT() *MockIClientMockRecorder {
 }
 
 // SendMessage mocks base method.
-func (m *MockIClient) SendMessage(request *dto.MessageRequest) (*dto.MessageResponse, error) {
+func (m *MockIClient) SendMessage(ctx context.Context, tenantID string, request *dto.MessageRequest) (*dto.MessageResponse, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "SendMessage", request)
+	ret := m.ctrl.Call(m, "SendMessage", ctx, tenantID, request)
 	ret0, _ := ret[0].(*dto.MessageResponse)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // SendMessage indicates an expected call of SendMessage.
-func (mr *MockIClientMockRecorder) SendMessage(request interface{}) *gomock.Call {
+func (mr *MockIClientMockRecorder) SendMessage(ctx, tenantID, request interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockIClient)(nil).SendMessage), request)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SendMessage", reflect.TypeOf((*MockIClient)(nil).SendMessage), ctx, tenantID, request)
 }
 
 // MockICacheService is a mock of ICacheService interface.
@@ -140,3 +227,114 @@ func (mr *MockICacheServiceMockRecorder) Set(ctx, key, value interface{}) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockICacheService)(nil).Set), ctx, key, value)
 }
+
+// MockISuppressionChecker is a mock of ISuppressionChecker interface.
+type MockISuppressionChecker struct {
+	ctrl     *gomock.Controller
+	recorder *MockISuppressionCheckerMockRecorder
+}
+
+// MockISuppressionCheckerMockRecorder is the mock recorder for MockISuppressionChecker.
+type MockISuppressionCheckerMockRecorder struct {
+	mock *MockISuppressionChecker
+}
+
+// NewMockISuppressionChecker creates a new mock instance.
+func NewMockISuppressionChecker(ctrl *gomock.Controller) *MockISuppressionChecker {
+	mock := &MockISuppressionChecker{ctrl: ctrl}
+	mock.recorder = &MockISuppressionCheckerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISuppressionChecker) EXPECT() *MockISuppressionCheckerMockRecorder {
+	return m.recorder
+}
+
+// IsSuppressed mocks base method.
+func (m *MockISuppressionChecker) IsSuppressed(ctx context.Context, tenantID, phoneNumber string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsSuppressed", ctx, tenantID, phoneNumber)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsSuppressed indicates an expected call of IsSuppressed.
+func (mr *MockISuppressionCheckerMockRecorder) IsSuppressed(ctx, tenantID, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsSuppressed", reflect.TypeOf((*MockISuppressionChecker)(nil).IsSuppressed), ctx, tenantID, phoneNumber)
+}
+
+// MockINotifier is a mock of INotifier interface.
+type MockINotifier struct {
+	ctrl     *gomock.Controller
+	recorder *MockINotifierMockRecorder
+}
+
+// MockINotifierMockRecorder is the mock recorder for MockINotifier.
+type MockINotifierMockRecorder struct {
+	mock *MockINotifier
+}
+
+// NewMockINotifier creates a new mock instance.
+func NewMockINotifier(ctrl *gomock.Controller) *MockINotifier {
+	mock := &MockINotifier{ctrl: ctrl}
+	mock.recorder = &MockINotifierMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockINotifier) EXPECT() *MockINotifierMockRecorder {
+	return m.recorder
+}
+
+// Notify mocks base method.
+func (m *MockINotifier) Notify(ctx context.Context, message *model.Message, eventType, reason string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Notify", ctx, message, eventType, reason)
+}
+
+// Notify indicates an expected call of Notify.
+func (mr *MockINotifierMockRecorder) Notify(ctx, message, eventType, reason interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Notify", reflect.TypeOf((*MockINotifier)(nil).Notify), ctx, message, eventType, reason)
+}
+
+// MockICampaignGate is a mock of ICampaignGate interface.
+type MockICampaignGate struct {
+	ctrl     *gomock.Controller
+	recorder *MockICampaignGateMockRecorder
+}
+
+// MockICampaignGateMockRecorder is the mock recorder for MockICampaignGate.
+type MockICampaignGateMockRecorder struct {
+	mock *MockICampaignGate
+}
+
+// NewMockICampaignGate creates a new mock instance.
+func NewMockICampaignGate(ctrl *gomock.Controller) *MockICampaignGate {
+	mock := &MockICampaignGate{ctrl: ctrl}
+	mock.recorder = &MockICampaignGateMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockICampaignGate) EXPECT() *MockICampaignGateMockRecorder {
+	return m.recorder
+}
+
+// HeldCampaigns mocks base method.
+func (m *MockICampaignGate) HeldCampaigns(ctx context.Context, now time.Time) ([]primitive.ObjectID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HeldCampaigns", ctx, now)
+	ret0, _ := ret[0].([]primitive.ObjectID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HeldCampaigns indicates an expected call of HeldCampaigns.
+func (mr *MockICampaignGateMockRecorder) HeldCampaigns(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HeldCampaigns", reflect.TypeOf((*MockICampaignGate)(nil).HeldCampaigns), ctx, now)
+}
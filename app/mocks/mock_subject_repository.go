@@ -0,0 +1,290 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/service/subject_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockISubjectRepository is a mock of ISubjectRepository interface.
+type MockISubjectRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockISubjectRepositoryMockRecorder
+}
+
+// MockISubjectRepositoryMockRecorder is the mock recorder for MockISubjectRepository.
+type MockISubjectRepositoryMockRecorder struct {
+	mock *MockISubjectRepository
+}
+
+// NewMockISubjectRepository creates a new mock instance.
+func NewMockISubjectRepository(ctrl *gomock.Controller) *MockISubjectRepository {
+	mock := &MockISubjectRepository{ctrl: ctrl}
+	mock.recorder = &MockISubjectRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISubjectRepository) EXPECT() *MockISubjectRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AnonymizeSubject mocks base method.
+func (m *MockISubjectRepository) AnonymizeSubject(ctx context.Context, tenantID, phoneNumber string) (*model.SubjectErasure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnonymizeSubject", ctx, tenantID, phoneNumber)
+	ret0, _ := ret[0].(*model.SubjectErasure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnonymizeSubject indicates an expected call of AnonymizeSubject.
+func (mr *MockISubjectRepositoryMockRecorder) AnonymizeSubject(ctx, tenantID, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnonymizeSubject", reflect.TypeOf((*MockISubjectRepository)(nil).AnonymizeSubject), ctx, tenantID, phoneNumber)
+}
+
+// EraseSubject mocks base method.
+func (m *MockISubjectRepository) EraseSubject(ctx context.Context, tenantID, phoneNumber string) (*model.SubjectErasure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EraseSubject", ctx, tenantID, phoneNumber)
+	ret0, _ := ret[0].(*model.SubjectErasure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EraseSubject indicates an expected call of EraseSubject.
+func (mr *MockISubjectRepositoryMockRecorder) EraseSubject(ctx, tenantID, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EraseSubject", reflect.TypeOf((*MockISubjectRepository)(nil).EraseSubject), ctx, tenantID, phoneNumber)
+}
+
+// GetSubjectCampaigns mocks base method.
+func (m *MockISubjectRepository) GetSubjectCampaigns(ctx context.Context, tenantID, phoneNumber string) ([]model.SubjectCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubjectCampaigns", ctx, tenantID, phoneNumber)
+	ret0, _ := ret[0].([]model.SubjectCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubjectCampaigns indicates an expected call of GetSubjectCampaigns.
+func (mr *MockISubjectRepositoryMockRecorder) GetSubjectCampaigns(ctx, tenantID, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubjectCampaigns", reflect.TypeOf((*MockISubjectRepository)(nil).GetSubjectCampaigns), ctx, tenantID, phoneNumber)
+}
+
+// GetSubjectContacts mocks base method.
+func (m *MockISubjectRepository) GetSubjectContacts(ctx context.Context, tenantID, phoneNumber string) ([]model.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubjectContacts", ctx, tenantID, phoneNumber)
+	ret0, _ := ret[0].([]model.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubjectContacts indicates an expected call of GetSubjectContacts.
+func (mr *MockISubjectRepositoryMockRecorder) GetSubjectContacts(ctx, tenantID, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubjectContacts", reflect.TypeOf((*MockISubjectRepository)(nil).GetSubjectContacts), ctx, tenantID, phoneNumber)
+}
+
+// GetSubjectInboundMessages mocks base method.
+func (m *MockISubjectRepository) GetSubjectInboundMessages(ctx context.Context, tenantID, phoneNumber string) ([]model.InboundMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubjectInboundMessages", ctx, tenantID, phoneNumber)
+	ret0, _ := ret[0].([]model.InboundMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubjectInboundMessages indicates an expected call of GetSubjectInboundMessages.
+func (mr *MockISubjectRepositoryMockRecorder) GetSubjectInboundMessages(ctx, tenantID, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubjectInboundMessages", reflect.TypeOf((*MockISubjectRepository)(nil).GetSubjectInboundMessages), ctx, tenantID, phoneNumber)
+}
+
+// GetSubjectMessages mocks base method.
+func (m *MockISubjectRepository) GetSubjectMessages(ctx context.Context, tenantID, phoneNumber string, archived bool) ([]model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubjectMessages", ctx, tenantID, phoneNumber, archived)
+	ret0, _ := ret[0].([]model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubjectMessages indicates an expected call of GetSubjectMessages.
+func (mr *MockISubjectRepositoryMockRecorder) GetSubjectMessages(ctx, tenantID, phoneNumber, archived interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubjectMessages", reflect.TypeOf((*MockISubjectRepository)(nil).GetSubjectMessages), ctx, tenantID, phoneNumber, archived)
+}
+
+// GetSubjectOutboxMessages mocks base method.
+func (m *MockISubjectRepository) GetSubjectOutboxMessages(ctx context.Context, tenantID, phoneNumber string) ([]model.OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubjectOutboxMessages", ctx, tenantID, phoneNumber)
+	ret0, _ := ret[0].([]model.OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubjectOutboxMessages indicates an expected call of GetSubjectOutboxMessages.
+func (mr *MockISubjectRepositoryMockRecorder) GetSubjectOutboxMessages(ctx, tenantID, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubjectOutboxMessages", reflect.TypeOf((*MockISubjectRepository)(nil).GetSubjectOutboxMessages), ctx, tenantID, phoneNumber)
+}
+
+// GetSuppression mocks base method.
+func (m *MockISubjectRepository) GetSuppression(ctx context.Context, tenantID, phoneNumber string) (*model.Suppression, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSuppression", ctx, tenantID, phoneNumber)
+	ret0, _ := ret[0].(*model.Suppression)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSuppression indicates an expected call of GetSuppression.
+func (mr *MockISubjectRepositoryMockRecorder) GetSuppression(ctx, tenantID, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSuppression", reflect.TypeOf((*MockISubjectRepository)(nil).GetSuppression), ctx, tenantID, phoneNumber)
+}
+
+// GetUnnormalizedOutboxMessages mocks base method.
+func (m *MockISubjectRepository) GetUnnormalizedOutboxMessages(ctx context.Context, tenantID string) ([]model.OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnnormalizedOutboxMessages", ctx, tenantID)
+	ret0, _ := ret[0].([]model.OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnnormalizedOutboxMessages indicates an expected call of GetUnnormalizedOutboxMessages.
+func (mr *MockISubjectRepositoryMockRecorder) GetUnnormalizedOutboxMessages(ctx, tenantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnnormalizedOutboxMessages", reflect.TypeOf((*MockISubjectRepository)(nil).GetUnnormalizedOutboxMessages), ctx, tenantID)
+}
+
+// SetOutboxNormalizedPhoneNumbers mocks base method.
+func (m *MockISubjectRepository) SetOutboxNormalizedPhoneNumbers(ctx context.Context, phoneNumbers map[primitive.ObjectID]string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetOutboxNormalizedPhoneNumbers", ctx, phoneNumbers)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetOutboxNormalizedPhoneNumbers indicates an expected call of SetOutboxNormalizedPhoneNumbers.
+func (mr *MockISubjectRepositoryMockRecorder) SetOutboxNormalizedPhoneNumbers(ctx, phoneNumbers interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetOutboxNormalizedPhoneNumbers", reflect.TypeOf((*MockISubjectRepository)(nil).SetOutboxNormalizedPhoneNumbers), ctx, phoneNumbers)
+}
+
+// MockISubjectArchive is a mock of ISubjectArchive interface.
+type MockISubjectArchive struct {
+	ctrl     *gomock.Controller
+	recorder *MockISubjectArchiveMockRecorder
+}
+
+// MockISubjectArchiveMockRecorder is the mock recorder for MockISubjectArchive.
+type MockISubjectArchiveMockRecorder struct {
+	mock *MockISubjectArchive
+}
+
+// NewMockISubjectArchive creates a new mock instance.
+func NewMockISubjectArchive(ctrl *gomock.Controller) *MockISubjectArchive {
+	mock := &MockISubjectArchive{ctrl: ctrl}
+	mock.recorder = &MockISubjectArchiveMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISubjectArchive) EXPECT() *MockISubjectArchiveMockRecorder {
+	return m.recorder
+}
+
+// Erasable mocks base method.
+func (m *MockISubjectArchive) Erasable() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Erasable")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Erasable indicates an expected call of Erasable.
+func (mr *MockISubjectArchiveMockRecorder) Erasable() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Erasable", reflect.TypeOf((*MockISubjectArchive)(nil).Erasable))
+}
+
+// EraseSubject mocks base method.
+func (m *MockISubjectArchive) EraseSubject(ctx context.Context, tenantID, phoneNumber string, anonymize bool) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EraseSubject", ctx, tenantID, phoneNumber, anonymize)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// EraseSubject indicates an expected call of EraseSubject.
+func (mr *MockISubjectArchiveMockRecorder) EraseSubject(ctx, tenantID, phoneNumber, anonymize interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EraseSubject", reflect.TypeOf((*MockISubjectArchive)(nil).EraseSubject), ctx, tenantID, phoneNumber, anonymize)
+}
+
+// GetSubjectMessages mocks base method.
+func (m *MockISubjectArchive) GetSubjectMessages(ctx context.Context, tenantID, phoneNumber string) ([]model.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubjectMessages", ctx, tenantID, phoneNumber)
+	ret0, _ := ret[0].([]model.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubjectMessages indicates an expected call of GetSubjectMessages.
+func (mr *MockISubjectArchiveMockRecorder) GetSubjectMessages(ctx, tenantID, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubjectMessages", reflect.TypeOf((*MockISubjectArchive)(nil).GetSubjectMessages), ctx, tenantID, phoneNumber)
+}
+
+// MockISubjectCache is a mock of ISubjectCache interface.
+type MockISubjectCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockISubjectCacheMockRecorder
+}
+
+// MockISubjectCacheMockRecorder is the mock recorder for MockISubjectCache.
+type MockISubjectCacheMockRecorder struct {
+	mock *MockISubjectCache
+}
+
+// NewMockISubjectCache creates a new mock instance.
+func NewMockISubjectCache(ctrl *gomock.Controller) *MockISubjectCache {
+	mock := &MockISubjectCache{ctrl: ctrl}
+	mock.recorder = &MockISubjectCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISubjectCache) EXPECT() *MockISubjectCacheMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockISubjectCache) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockISubjectCacheMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockISubjectCache)(nil).Delete), ctx, key)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/handler/subject_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockISubjectService is a mock of ISubjectService interface.
+type MockISubjectService struct {
+	ctrl     *gomock.Controller
+	recorder *MockISubjectServiceMockRecorder
+}
+
+// MockISubjectServiceMockRecorder is the mock recorder for MockISubjectService.
+type MockISubjectServiceMockRecorder struct {
+	mock *MockISubjectService
+}
+
+// NewMockISubjectService creates a new mock instance.
+func NewMockISubjectService(ctrl *gomock.Controller) *MockISubjectService {
+	mock := &MockISubjectService{ctrl: ctrl}
+	mock.recorder = &MockISubjectServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISubjectService) EXPECT() *MockISubjectServiceMockRecorder {
+	return m.recorder
+}
+
+// Erase mocks base method.
+func (m *MockISubjectService) Erase(ctx context.Context, tenantID, phoneNumber, mode string) (*model.SubjectErasure, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Erase", ctx, tenantID, phoneNumber, mode)
+	ret0, _ := ret[0].(*model.SubjectErasure)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Erase indicates an expected call of Erase.
+func (mr *MockISubjectServiceMockRecorder) Erase(ctx, tenantID, phoneNumber, mode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Erase", reflect.TypeOf((*MockISubjectService)(nil).Erase), ctx, tenantID, phoneNumber, mode)
+}
+
+// Export mocks base method.
+func (m *MockISubjectService) Export(ctx context.Context, tenantID, phoneNumber string) (*model.SubjectExport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Export", ctx, tenantID, phoneNumber)
+	ret0, _ := ret[0].(*model.SubjectExport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Export indicates an expected call of Export.
+func (mr *MockISubjectServiceMockRecorder) Export(ctx, tenantID, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Export", reflect.TypeOf((*MockISubjectService)(nil).Export), ctx, tenantID, phoneNumber)
+}
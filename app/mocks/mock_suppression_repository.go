@@ -0,0 +1,160 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/service/suppression_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockISuppressionRepository is a mock of ISuppressionRepository interface.
+type MockISuppressionRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockISuppressionRepositoryMockRecorder
+}
+
+// MockISuppressionRepositoryMockRecorder is the mock recorder for MockISuppressionRepository.
+type MockISuppressionRepositoryMockRecorder struct {
+	mock *MockISuppressionRepository
+}
+
+// NewMockISuppressionRepository creates a new mock instance.
+func NewMockISuppressionRepository(ctrl *gomock.Controller) *MockISuppressionRepository {
+	mock := &MockISuppressionRepository{ctrl: ctrl}
+	mock.recorder = &MockISuppressionRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISuppressionRepository) EXPECT() *MockISuppressionRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddSuppressions mocks base method.
+func (m *MockISuppressionRepository) AddSuppressions(ctx context.Context, suppressions []model.Suppression) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddSuppressions", ctx, suppressions)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddSuppressions indicates an expected call of AddSuppressions.
+func (mr *MockISuppressionRepositoryMockRecorder) AddSuppressions(ctx, suppressions interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSuppressions", reflect.TypeOf((*MockISuppressionRepository)(nil).AddSuppressions), ctx, suppressions)
+}
+
+// DeleteSuppression mocks base method.
+func (m *MockISuppressionRepository) DeleteSuppression(ctx context.Context, tenantID, phoneNumber string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSuppression", ctx, tenantID, phoneNumber)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSuppression indicates an expected call of DeleteSuppression.
+func (mr *MockISuppressionRepositoryMockRecorder) DeleteSuppression(ctx, tenantID, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSuppression", reflect.TypeOf((*MockISuppressionRepository)(nil).DeleteSuppression), ctx, tenantID, phoneNumber)
+}
+
+// GetSuppression mocks base method.
+func (m *MockISuppressionRepository) GetSuppression(ctx context.Context, tenantID, phoneNumber string) (*model.Suppression, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSuppression", ctx, tenantID, phoneNumber)
+	ret0, _ := ret[0].(*model.Suppression)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSuppression indicates an expected call of GetSuppression.
+func (mr *MockISuppressionRepositoryMockRecorder) GetSuppression(ctx, tenantID, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSuppression", reflect.TypeOf((*MockISuppressionRepository)(nil).GetSuppression), ctx, tenantID, phoneNumber)
+}
+
+// GetSuppressions mocks base method.
+func (m *MockISuppressionRepository) GetSuppressions(ctx context.Context, tenantID string, limit int) ([]model.Suppression, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSuppressions", ctx, tenantID, limit)
+	ret0, _ := ret[0].([]model.Suppression)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSuppressions indicates an expected call of GetSuppressions.
+func (mr *MockISuppressionRepositoryMockRecorder) GetSuppressions(ctx, tenantID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSuppressions", reflect.TypeOf((*MockISuppressionRepository)(nil).GetSuppressions), ctx, tenantID, limit)
+}
+
+// MockISuppressionCache is a mock of ISuppressionCache interface.
+type MockISuppressionCache struct {
+	ctrl     *gomock.Controller
+	recorder *MockISuppressionCacheMockRecorder
+}
+
+// MockISuppressionCacheMockRecorder is the mock recorder for MockISuppressionCache.
+type MockISuppressionCacheMockRecorder struct {
+	mock *MockISuppressionCache
+}
+
+// NewMockISuppressionCache creates a new mock instance.
+func NewMockISuppressionCache(ctrl *gomock.Controller) *MockISuppressionCache {
+	mock := &MockISuppressionCache{ctrl: ctrl}
+	mock.recorder = &MockISuppressionCacheMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISuppressionCache) EXPECT() *MockISuppressionCacheMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockISuppressionCache) Delete(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockISuppressionCacheMockRecorder) Delete(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockISuppressionCache)(nil).Delete), ctx, key)
+}
+
+// Get mocks base method.
+func (m *MockISuppressionCache) Get(ctx context.Context, key string) ([]byte, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", ctx, key)
+	ret0, _ := ret[0].([]byte)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockISuppressionCacheMockRecorder) Get(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockISuppressionCache)(nil).Get), ctx, key)
+}
+
+// Set mocks base method.
+func (m *MockISuppressionCache) Set(ctx context.Context, key string, value interface{}) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Set", ctx, key, value)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Set indicates an expected call of Set.
+func (mr *MockISuppressionCacheMockRecorder) Set(ctx, key, value interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Set", reflect.TypeOf((*MockISuppressionCache)(nil).Set), ctx, key, value)
+}
@@ -0,0 +1,111 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/handler/suppression_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "messaging-system/app/dto"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockISuppressionService is a mock of ISuppressionService interface.
+type MockISuppressionService struct {
+	ctrl     *gomock.Controller
+	recorder *MockISuppressionServiceMockRecorder
+}
+
+// MockISuppressionServiceMockRecorder is the mock recorder for MockISuppressionService.
+type MockISuppressionServiceMockRecorder struct {
+	mock *MockISuppressionService
+}
+
+// NewMockISuppressionService creates a new mock instance.
+func NewMockISuppressionService(ctrl *gomock.Controller) *MockISuppressionService {
+	mock := &MockISuppressionService{ctrl: ctrl}
+	mock.recorder = &MockISuppressionServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockISuppressionService) EXPECT() *MockISuppressionServiceMockRecorder {
+	return m.recorder
+}
+
+// GetSuppression mocks base method.
+func (m *MockISuppressionService) GetSuppression(ctx context.Context, tenantID, phoneNumber string) (*model.Suppression, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSuppression", ctx, tenantID, phoneNumber)
+	ret0, _ := ret[0].(*model.Suppression)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSuppression indicates an expected call of GetSuppression.
+func (mr *MockISuppressionServiceMockRecorder) GetSuppression(ctx, tenantID, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSuppression", reflect.TypeOf((*MockISuppressionService)(nil).GetSuppression), ctx, tenantID, phoneNumber)
+}
+
+// GetSuppressions mocks base method.
+func (m *MockISuppressionService) GetSuppressions(ctx context.Context, tenantID string, limit int) ([]model.Suppression, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSuppressions", ctx, tenantID, limit)
+	ret0, _ := ret[0].([]model.Suppression)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSuppressions indicates an expected call of GetSuppressions.
+func (mr *MockISuppressionServiceMockRecorder) GetSuppressions(ctx, tenantID, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSuppressions", reflect.TypeOf((*MockISuppressionService)(nil).GetSuppressions), ctx, tenantID, limit)
+}
+
+// Import mocks base method.
+func (m *MockISuppressionService) Import(ctx context.Context, tenantID string, request *dto.SuppressionImportRequest) (*dto.SuppressionImportResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Import", ctx, tenantID, request)
+	ret0, _ := ret[0].(*dto.SuppressionImportResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Import indicates an expected call of Import.
+func (mr *MockISuppressionServiceMockRecorder) Import(ctx, tenantID, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Import", reflect.TypeOf((*MockISuppressionService)(nil).Import), ctx, tenantID, request)
+}
+
+// Suppress mocks base method.
+func (m *MockISuppressionService) Suppress(ctx context.Context, tenantID string, request *dto.SuppressionRequest, source string) (*model.Suppression, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Suppress", ctx, tenantID, request, source)
+	ret0, _ := ret[0].(*model.Suppression)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Suppress indicates an expected call of Suppress.
+func (mr *MockISuppressionServiceMockRecorder) Suppress(ctx, tenantID, request, source interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Suppress", reflect.TypeOf((*MockISuppressionService)(nil).Suppress), ctx, tenantID, request, source)
+}
+
+// Unsuppress mocks base method.
+func (m *MockISuppressionService) Unsuppress(ctx context.Context, tenantID, phoneNumber string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Unsuppress", ctx, tenantID, phoneNumber)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Unsuppress indicates an expected call of Unsuppress.
+func (mr *MockISuppressionServiceMockRecorder) Unsuppress(ctx, tenantID, phoneNumber interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Unsuppress", reflect.TypeOf((*MockISuppressionService)(nil).Unsuppress), ctx, tenantID, phoneNumber)
+}
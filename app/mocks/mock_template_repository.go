@@ -0,0 +1,109 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/service/template_service.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockITemplateRepository is a mock of ITemplateRepository interface.
+type MockITemplateRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockITemplateRepositoryMockRecorder
+}
+
+// MockITemplateRepositoryMockRecorder is the mock recorder for MockITemplateRepository.
+type MockITemplateRepositoryMockRecorder struct {
+	mock *MockITemplateRepository
+}
+
+// NewMockITemplateRepository creates a new mock instance.
+func NewMockITemplateRepository(ctrl *gomock.Controller) *MockITemplateRepository {
+	mock := &MockITemplateRepository{ctrl: ctrl}
+	mock.recorder = &MockITemplateRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITemplateRepository) EXPECT() *MockITemplateRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateTemplate mocks base method.
+func (m *MockITemplateRepository) CreateTemplate(ctx context.Context, template *model.Template) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTemplate", ctx, template)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateTemplate indicates an expected call of CreateTemplate.
+func (mr *MockITemplateRepositoryMockRecorder) CreateTemplate(ctx, template interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTemplate", reflect.TypeOf((*MockITemplateRepository)(nil).CreateTemplate), ctx, template)
+}
+
+// DeleteTemplate mocks base method.
+func (m *MockITemplateRepository) DeleteTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplate", ctx, tenantID, templateID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplate indicates an expected call of DeleteTemplate.
+func (mr *MockITemplateRepositoryMockRecorder) DeleteTemplate(ctx, tenantID, templateID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplate", reflect.TypeOf((*MockITemplateRepository)(nil).DeleteTemplate), ctx, tenantID, templateID)
+}
+
+// GetTemplate mocks base method.
+func (m *MockITemplateRepository) GetTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID) (*model.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplate", ctx, tenantID, templateID)
+	ret0, _ := ret[0].(*model.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplate indicates an expected call of GetTemplate.
+func (mr *MockITemplateRepositoryMockRecorder) GetTemplate(ctx, tenantID, templateID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplate", reflect.TypeOf((*MockITemplateRepository)(nil).GetTemplate), ctx, tenantID, templateID)
+}
+
+// GetTemplates mocks base method.
+func (m *MockITemplateRepository) GetTemplates(ctx context.Context, tenantID string) ([]model.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplates", ctx, tenantID)
+	ret0, _ := ret[0].([]model.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplates indicates an expected call of GetTemplates.
+func (mr *MockITemplateRepositoryMockRecorder) GetTemplates(ctx, tenantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplates", reflect.TypeOf((*MockITemplateRepository)(nil).GetTemplates), ctx, tenantID)
+}
+
+// UpdateTemplate mocks base method.
+func (m *MockITemplateRepository) UpdateTemplate(ctx context.Context, template *model.Template) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplate", ctx, template)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTemplate indicates an expected call of UpdateTemplate.
+func (mr *MockITemplateRepositoryMockRecorder) UpdateTemplate(ctx, template interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplate", reflect.TypeOf((*MockITemplateRepository)(nil).UpdateTemplate), ctx, template)
+}
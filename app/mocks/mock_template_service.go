@@ -0,0 +1,112 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: app/handler/template_handler.go
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	dto "messaging-system/app/dto"
+	model "messaging-system/app/model"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	primitive "go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MockITemplateService is a mock of ITemplateService interface.
+type MockITemplateService struct {
+	ctrl     *gomock.Controller
+	recorder *MockITemplateServiceMockRecorder
+}
+
+// MockITemplateServiceMockRecorder is the mock recorder for MockITemplateService.
+type MockITemplateServiceMockRecorder struct {
+	mock *MockITemplateService
+}
+
+// NewMockITemplateService creates a new mock instance.
+func NewMockITemplateService(ctrl *gomock.Controller) *MockITemplateService {
+	mock := &MockITemplateService{ctrl: ctrl}
+	mock.recorder = &MockITemplateServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockITemplateService) EXPECT() *MockITemplateServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateTemplate mocks base method.
+func (m *MockITemplateService) CreateTemplate(ctx context.Context, tenantID string, request *dto.TemplateRequest) (*model.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTemplate", ctx, tenantID, request)
+	ret0, _ := ret[0].(*model.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTemplate indicates an expected call of CreateTemplate.
+func (mr *MockITemplateServiceMockRecorder) CreateTemplate(ctx, tenantID, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTemplate", reflect.TypeOf((*MockITemplateService)(nil).CreateTemplate), ctx, tenantID, request)
+}
+
+// DeleteTemplate mocks base method.
+func (m *MockITemplateService) DeleteTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTemplate", ctx, tenantID, templateID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTemplate indicates an expected call of DeleteTemplate.
+func (mr *MockITemplateServiceMockRecorder) DeleteTemplate(ctx, tenantID, templateID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTemplate", reflect.TypeOf((*MockITemplateService)(nil).DeleteTemplate), ctx, tenantID, templateID)
+}
+
+// GetTemplate mocks base method.
+func (m *MockITemplateService) GetTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID) (*model.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplate", ctx, tenantID, templateID)
+	ret0, _ := ret[0].(*model.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplate indicates an expected call of GetTemplate.
+func (mr *MockITemplateServiceMockRecorder) GetTemplate(ctx, tenantID, templateID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplate", reflect.TypeOf((*MockITemplateService)(nil).GetTemplate), ctx, tenantID, templateID)
+}
+
+// GetTemplates mocks base method.
+func (m *MockITemplateService) GetTemplates(ctx context.Context, tenantID string) ([]model.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplates", ctx, tenantID)
+	ret0, _ := ret[0].([]model.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplates indicates an expected call of GetTemplates.
+func (mr *MockITemplateServiceMockRecorder) GetTemplates(ctx, tenantID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplates", reflect.TypeOf((*MockITemplateService)(nil).GetTemplates), ctx, tenantID)
+}
+
+// UpdateTemplate mocks base method.
+func (m *MockITemplateService) UpdateTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID, request *dto.TemplateRequest) (*model.Template, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTemplate", ctx, tenantID, templateID, request)
+	ret0, _ := ret[0].(*model.Template)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTemplate indicates an expected call of UpdateTemplate.
+func (mr *MockITemplateServiceMockRecorder) UpdateTemplate(ctx, tenantID, templateID, request interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTemplate", reflect.TypeOf((*MockITemplateService)(nil).UpdateTemplate), ctx, tenantID, templateID, request)
+}
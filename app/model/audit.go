@@ -0,0 +1,47 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	AuditActionProcessorStart    = "processor.start"
+	AuditActionProcessorStop     = "processor.stop"
+	AuditActionAPIKeyCreate      = "apikey.create"
+	AuditActionAPIKeyRevoke      = "apikey.revoke"
+	AuditActionTemplateCreate    = "template.create"
+	AuditActionTemplateUpdate    = "template.update"
+	AuditActionTemplateDelete    = "template.delete"
+	AuditActionSuppressionDelete = "suppression.delete"
+	AuditActionSubjectErase      = "subject.erase"
+)
+
+// AuditEntry records who performed an administrative action. Before and After hold the state of the
+// target as the API returns it, entries are never changed once written.
+type AuditEntry struct {
+	ID        primitive.ObjectID     `json:"id" bson:"_id"`
+	TenantID  string                 `json:"tenantId" bson:"tenantId"`
+	Action    string                 `json:"action" bson:"action"`
+	ActorID   string                 `json:"actorId" bson:"actorId"`
+	ActorName string                 `json:"actorName" bson:"actorName"`
+	SourceIP  string                 `json:"sourceIp" bson:"sourceIp"`
+	RequestID string                 `json:"requestId,omitempty" bson:"requestId,omitempty"`
+	Target    string                 `json:"target,omitempty" bson:"target,omitempty"`
+	Before    map[string]interface{} `json:"before,omitempty" bson:"before,omitempty"`
+	After     map[string]interface{} `json:"after,omitempty" bson:"after,omitempty"`
+	CreatedAt time.Time              `json:"createdAt" bson:"createdAt"`
+}
+
+// AuditEvent is an administrative action to record. Before and After are stored as their JSON
+// representation, nil when the target did not exist before or no longer exists after the action.
+type AuditEvent struct {
+	Action    string
+	Principal *Principal
+	SourceIP  string
+	RequestID string
+	Target    string
+	Before    interface{}
+	After     interface{}
+}
@@ -0,0 +1,44 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ScopeMessagesWrite  = "messages:write"
+	ScopeMessagesRead   = "messages:read"
+	ScopeProcessorAdmin = "processor:admin"
+
+	// AnonymousPrincipalID is the caller of requests when authentication is disabled.
+	AnonymousPrincipalID = "anonymous"
+)
+
+// Scopes lists every scope a caller can be granted.
+var Scopes = []string{ScopeMessagesWrite, ScopeMessagesRead, ScopeProcessorAdmin}
+
+// APIKey is a client credential, only the SHA-256 hash of the key is stored.
+type APIKey struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	TenantID  string             `json:"tenantId" bson:"tenantId"`
+	Name      string             `json:"name" bson:"name"`
+	Prefix    string             `json:"prefix" bson:"prefix"`
+	Hash      string             `json:"-" bson:"hash"`
+	Scopes    []string           `json:"scopes" bson:"scopes"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+	RevokedAt *time.Time         `json:"revokedAt,omitempty" bson:"revokedAt,omitempty"`
+}
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	ID       string   `json:"id"`
+	Name     string   `json:"name"`
+	TenantID string   `json:"tenantId"`
+	Scopes   []string `json:"scopes"`
+}
+
+func (p *Principal) HasScope(scope string) bool {
+	return p != nil && slices.Contains(p.Scopes, scope)
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"messaging-system/app/dto"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	CampaignStatusRunning   = "running"
+	CampaignStatusPaused    = "paused"
+	CampaignStatusCancelled = "cancelled"
+)
+
+// Campaign sends one template to an audience. Its messages are held back while it is paused or
+// scheduled for later and are sent at no more than Throttle messages per minute.
+type Campaign struct {
+	ID         primitive.ObjectID `json:"id" bson:"_id"`
+	TenantID   string             `json:"tenantId" bson:"tenantId"`
+	Name       string             `json:"name" bson:"name"`
+	TemplateID string             `json:"templateId" bson:"templateId"`
+	Params     map[string]string  `json:"params,omitempty" bson:"params,omitempty"`
+	Locale     string             `json:"locale,omitempty" bson:"locale,omitempty"`
+	// Audience holds the E.164 phone numbers the campaign was created for.
+	Audience []string `json:"audience,omitempty" bson:"audience"`
+	// Rejected lists the recipients no message could be created for.
+	Rejected    []dto.InvalidPhoneNumber `json:"rejected,omitempty" bson:"rejected,omitempty"`
+	ScheduledAt *time.Time               `json:"scheduledAt,omitempty" bson:"scheduledAt,omitempty"`
+	// Throttle is the maximum number of messages sent per minute, unlimited when zero.
+	Throttle  int             `json:"throttle,omitempty" bson:"throttle,omitempty"`
+	Status    string          `json:"status" bson:"status"`
+	Counts    *CampaignCounts `json:"counts,omitempty" bson:"-"`
+	CreatedAt time.Time       `json:"createdAt" bson:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt" bson:"updatedAt"`
+}
+
+// CampaignCounts is the number of messages of a campaign per status. Retrying messages are unsent
+// messages whose last send attempt failed, failed messages ran out of attempts or were not delivered.
+// Delivered counts the sent messages the provider reported as delivered.
+type CampaignCounts struct {
+	Total      int `json:"total"`
+	Unsent     int `json:"unsent"`
+	Retrying   int `json:"retrying"`
+	Failed     int `json:"failed"`
+	Sent       int `json:"sent"`
+	Delivered  int `json:"delivered"`
+	Expired    int `json:"expired"`
+	Suppressed int `json:"suppressed"`
+	Cancelled  int `json:"cancelled"`
+}
+
+// Add counts messages of a status, retrying tells unsent messages apart whose last send attempt failed and
+// delivered sent messages with a delivery receipt.
+func (c *CampaignCounts) Add(status string, retrying, delivered bool, count int) {
+	c.Total += count
+	switch status {
+	case StatusUnsent:
+		if retrying {
+			c.Retrying += count
+		} else {
+			c.Unsent += count
+		}
+	case StatusFailed:
+		c.Failed += count
+	case StatusSent:
+		c.Sent += count
+		if delivered {
+			c.Delivered += count
+		}
+	case StatusExpired:
+		c.Expired += count
+	case StatusSuppressed:
+		c.Suppressed += count
+	case StatusCancelled:
+		c.Cancelled += count
+	}
+}
+
+// Held reports whether the messages of the campaign must not be sent at now.
+func (c *Campaign) Held(now time.Time) bool {
+	return c.Status == CampaignStatusPaused || (c.ScheduledAt != nil && c.ScheduledAt.After(now))
+}
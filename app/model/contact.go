@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ContactList is a named list of recipients, its members are stored as contacts.
+type ContactList struct {
+	ID       primitive.ObjectID `json:"id" bson:"_id"`
+	TenantID string             `json:"tenantId" bson:"tenantId"`
+	Name     string             `json:"name" bson:"name"`
+	// Size is the number of contacts of the list, it is counted when the list is read.
+	Size      int64     `json:"size" bson:"-"`
+	CreatedAt time.Time `json:"createdAt" bson:"createdAt"`
+}
+
+// Contact is a member of a contact list, a phone number is a member of a list at most once.
+type Contact struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id"`
+	TenantID       string             `json:"tenantId" bson:"tenantId"`
+	ListID         primitive.ObjectID `json:"listId" bson:"listId"`
+	PhoneNumber    string             `json:"phoneNumber" bson:"phoneNumber"`
+	RawPhoneNumber string             `json:"rawPhoneNumber,omitempty" bson:"rawPhoneNumber,omitempty"`
+	Country        string             `json:"country,omitempty" bson:"country,omitempty"`
+	CreatedAt      time.Time          `json:"createdAt" bson:"createdAt"`
+}
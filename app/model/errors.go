@@ -0,0 +1,13 @@
+package model
+
+import "errors"
+
+var (
+	ErrNotFound       = errors.New("not found")
+	ErrAlreadyExists  = errors.New("already exists")
+	ErrInvalidRequest = errors.New("invalid request")
+	ErrUnauthorized   = errors.New("unauthorized")
+	ErrQuotaExceeded  = errors.New("daily quota exceeded")
+	ErrInvalidState   = errors.New("invalid state")
+	ErrNotErasable    = errors.New("not erasable")
+)
@@ -0,0 +1,10 @@
+package model
+
+// IdempotencyRecord is stored in the cache under an Idempotency-Key. A record without
+// Completed set marks a request that is still being handled.
+type IdempotencyRecord struct {
+	RequestHash string `json:"requestHash"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"statusCode,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	KeywordStop  = "STOP"
+	KeywordStart = "START"
+	KeywordHelp  = "HELP"
+)
+
+// InboundMessage is a mobile originated message, ReplyTo links it to the last message sent to the number.
+// PhoneNumberIndex is the blind index the phone number is looked up by while it is stored encrypted.
+type InboundMessage struct {
+	ID                primitive.ObjectID  `json:"id" bson:"_id"`
+	TenantID          string              `json:"tenantId" bson:"tenantId"`
+	ProviderMessageID string              `json:"providerMessageId,omitempty" bson:"providerMessageId,omitempty"`
+	PhoneNumber       string              `json:"phoneNumber" bson:"phoneNumber"`
+	PhoneNumberIndex  string              `json:"-" bson:"phoneNumberIndex,omitempty"`
+	RawPhoneNumber    string              `json:"rawPhoneNumber,omitempty" bson:"rawPhoneNumber,omitempty"`
+	Text              string              `json:"text" bson:"text"`
+	Keyword           string              `json:"keyword,omitempty" bson:"keyword,omitempty"`
+	ReplyTo           *primitive.ObjectID `json:"replyTo,omitempty" bson:"replyTo,omitempty"`
+	ReceivedAt        time.Time           `json:"receivedAt" bson:"receivedAt"`
+}
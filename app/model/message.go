@@ -2,18 +2,51 @@ package model
 
 import (
 	"messaging-system/app/dto"
+	"messaging-system/app/sms"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+const (
+	StatusSent       = "sent"
+	StatusUnsent     = "unsent"
+	StatusExpired    = "expired"
+	StatusSuppressed = "suppressed"
+	StatusCancelled  = "cancelled"
+	// StatusFailed is final, the message ran out of send attempts or the provider reported it undelivered.
+	StatusFailed = "failed"
+)
+
+// Message is an outbound SMS. LastError holds the reason its last send attempt failed until it is sent,
+// Attempts the number of failed send attempts, Provider the provider that accepted it once it is sent and
+// DeliveredAt when the provider reported it delivered. PhoneNumberIndex is the blind index the phone number
+// is looked up by while it is stored encrypted.
 type Message struct {
-	ID               primitive.ObjectID `json:"id" bson:"_id"`
-	WebhookMessageID string             `json:"webhookMessageId" bson:"webhookMessageId,omitempty"`
-	PhoneNumber      string             `json:"phoneNumber" bson:"phoneNumber"`
-	Content          string             `json:"content" bson:"content"`
-	Status           string             `json:"status" bson:"status"`
-	SentAt           time.Time          `bson:"sentAt" json:"sentAt"`
+	ID               primitive.ObjectID  `json:"id" bson:"_id"`
+	TenantID         string              `json:"tenantId" bson:"tenantId"`
+	WebhookMessageID string              `json:"webhookMessageId" bson:"webhookMessageId,omitempty"`
+	PhoneNumber      string              `json:"phoneNumber" bson:"phoneNumber"`
+	PhoneNumberIndex string              `json:"-" bson:"phoneNumberIndex,omitempty"`
+	RawPhoneNumber   string              `json:"rawPhoneNumber,omitempty" bson:"rawPhoneNumber,omitempty"`
+	Country          string              `json:"country,omitempty" bson:"country,omitempty"`
+	Content          string              `json:"content" bson:"content"`
+	Encoding         sms.Encoding        `json:"encoding,omitempty" bson:"encoding,omitempty"`
+	Segments         int                 `json:"segments,omitempty" bson:"segments,omitempty"`
+	Status           string              `json:"status" bson:"status"`
+	Priority         int                 `json:"priority" bson:"priority"`
+	Category         string              `json:"category,omitempty" bson:"category,omitempty"`
+	TemplateID       string              `json:"templateId,omitempty" bson:"templateId,omitempty"`
+	CallbackURL      string              `json:"callbackUrl,omitempty" bson:"callbackUrl,omitempty"`
+	CampaignID       *primitive.ObjectID `json:"campaignId,omitempty" bson:"campaignId,omitempty"`
+	LastError        string              `json:"lastError,omitempty" bson:"lastError,omitempty"`
+	Attempts         int                 `json:"attempts,omitempty" bson:"attempts,omitempty"`
+	Provider         string              `json:"provider,omitempty" bson:"provider,omitempty"`
+	RequestID        string              `json:"requestId,omitempty" bson:"requestId,omitempty"`
+	CreatedAt        time.Time           `bson:"createdAt" json:"createdAt"`
+	ExpiresAt        *time.Time          `bson:"expiresAt,omitempty" json:"expiresAt,omitempty"`
+	SentAt           time.Time           `bson:"sentAt" json:"sentAt"`
+	DeliveredAt      *time.Time          `bson:"deliveredAt,omitempty" json:"deliveredAt,omitempty"`
 }
 
 type CacheMessage struct {
@@ -21,9 +54,29 @@ type CacheMessage struct {
 	SentAt    string `json:"sentAt"`
 }
 
+func NewMessage(tenantID string, request *dto.MessageRequest) *Message {
+	info := sms.Analyze(request.Content)
+	return &Message{
+		ID:          primitive.NewObjectID(),
+		TenantID:    tenantID,
+		PhoneNumber: request.To,
+		Content:     request.Content,
+		Encoding:    info.Encoding,
+		Segments:    info.Segments,
+		Status:      StatusUnsent,
+		Priority:    request.Priority,
+		Category:    request.Category,
+		TemplateID:  request.TemplateID,
+		CallbackURL: request.CallbackURL,
+		CreatedAt:   time.Now().UTC(),
+		ExpiresAt:   request.ExpiresAt,
+	}
+}
+
 func (m *Message) ConvertToRequest() *dto.MessageRequest {
 	return &dto.MessageRequest{
-		To:      m.PhoneNumber,
-		Content: m.Content,
+		To:       m.PhoneNumber,
+		Content:  m.Content,
+		Priority: m.Priority,
 	}
 }
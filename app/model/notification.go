@@ -0,0 +1,46 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	NotificationStatusPending   = "pending"
+	NotificationStatusDelivered = "delivered"
+	NotificationStatusFailed    = "failed"
+
+	EventMessageSent       = "message.sent"
+	EventMessageDelivered  = "message.delivered"
+	EventMessageFailed     = "message.failed"
+	EventMessageSuppressed = "message.suppressed"
+	EventMessageExpired    = "message.expired"
+)
+
+// StatusEvent is the body posted to the callback URL of a message.
+type StatusEvent struct {
+	ID               string    `json:"id" bson:"id"`
+	Type             string    `json:"type" bson:"type"`
+	MessageID        string    `json:"messageId" bson:"messageId"`
+	WebhookMessageID string    `json:"webhookMessageId,omitempty" bson:"webhookMessageId,omitempty"`
+	Status           string    `json:"status" bson:"status"`
+	Error            string    `json:"error,omitempty" bson:"error,omitempty"`
+	OccurredAt       time.Time `json:"occurredAt" bson:"occurredAt"`
+}
+
+// Notification is the delivery log entry of a status event.
+type Notification struct {
+	ID             primitive.ObjectID `json:"id" bson:"_id"`
+	TenantID       string             `json:"tenantId" bson:"tenantId"`
+	MessageID      primitive.ObjectID `json:"messageId" bson:"messageId"`
+	CallbackURL    string             `json:"callbackUrl" bson:"callbackUrl"`
+	Event          StatusEvent        `json:"event" bson:"event"`
+	Status         string             `json:"status" bson:"status"`
+	Attempts       int                `json:"attempts" bson:"attempts"`
+	LastStatusCode int                `json:"lastStatusCode,omitempty" bson:"lastStatusCode,omitempty"`
+	LastError      string             `json:"lastError,omitempty" bson:"lastError,omitempty"`
+	NextAttemptAt  time.Time          `json:"nextAttemptAt" bson:"nextAttemptAt"`
+	CreatedAt      time.Time          `json:"createdAt" bson:"createdAt"`
+	DeliveredAt    *time.Time         `json:"deliveredAt,omitempty" bson:"deliveredAt,omitempty"`
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"messaging-system/app/sms"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	OutboxStatusPending  = "pending"
+	OutboxStatusRelayed  = "relayed"
+	OutboxStatusRejected = "rejected"
+)
+
+// OutboxMessage is a row of the outbox collection. Other services insert it with status
+// "pending" inside their own Mongo transaction and the outbox relay turns it into a Message.
+// The created message reuses the outbox ID, so relaying the same row twice is a no-op.
+// NormalizedPhoneNumber is the E.164 form of the phone number, written when the row is relayed or
+// rejected, data subject requests match rows by it.
+type OutboxMessage struct {
+	ID                    primitive.ObjectID `json:"id" bson:"_id"`
+	TenantID              string             `json:"tenantId,omitempty" bson:"tenantId,omitempty"`
+	PhoneNumber           string             `json:"phoneNumber" bson:"phoneNumber"`
+	NormalizedPhoneNumber string             `json:"normalizedPhoneNumber,omitempty" bson:"normalizedPhoneNumber,omitempty"`
+	Content               string             `json:"content" bson:"content"`
+	Priority              int                `json:"priority" bson:"priority,omitempty"`
+	Status                string             `json:"status" bson:"status"`
+	Error                 string             `json:"error,omitempty" bson:"error,omitempty"`
+	CreatedAt             time.Time          `json:"createdAt" bson:"createdAt"`
+	RelayedAt             time.Time          `json:"relayedAt" bson:"relayedAt,omitempty"`
+}
+
+func (o *OutboxMessage) ToMessage() *Message {
+	createdAt := o.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = o.ID.Timestamp()
+	}
+
+	// rows of services that do not know about tenants belong to the default tenant
+	tenantID := o.TenantID
+	if tenantID == "" {
+		tenantID = DefaultTenantID
+	}
+
+	info := sms.Analyze(o.Content)
+	return &Message{
+		ID:          o.ID,
+		TenantID:    tenantID,
+		PhoneNumber: o.PhoneNumber,
+		Content:     o.Content,
+		Encoding:    info.Encoding,
+		Segments:    info.Segments,
+		Status:      StatusUnsent,
+		Priority:    o.Priority,
+		CreatedAt:   createdAt,
+	}
+}
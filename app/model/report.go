@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+// ReportStatusRetrying counts unsent messages whose last send attempt failed, apart from the other unsent ones.
+const ReportStatusRetrying = "retrying"
+
+// Report counts the messages of a tenant created within [From, To) by the dimensions of GroupBy, and by the
+// hour or day of Interval for time series.
+type Report struct {
+	From        time.Time   `json:"from"`
+	To          time.Time   `json:"to"`
+	GroupBy     []string    `json:"groupBy"`
+	Interval    string      `json:"interval,omitempty"`
+	Total       int         `json:"total"`
+	Segments    int         `json:"segments"`
+	Rows        []ReportRow `json:"rows"`
+	GeneratedAt time.Time   `json:"generatedAt"`
+}
+
+// ReportRow is a single group of a report, only the dimensions the report is grouped by are set. Messages
+// that were never sent have no provider.
+type ReportRow struct {
+	Time       *time.Time `json:"time,omitempty"`
+	Status     string     `json:"status,omitempty"`
+	Provider   string     `json:"provider,omitempty"`
+	CampaignID string     `json:"campaignId,omitempty"`
+	Count      int        `json:"count"`
+	Segments   int        `json:"segments"`
+}
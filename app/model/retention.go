@@ -0,0 +1,20 @@
+package model
+
+import "time"
+
+// RetentionReport lists the messages of a tenant the retention archiver would move at GeneratedAt.
+type RetentionReport struct {
+	GeneratedAt time.Time         `json:"generatedAt"`
+	Archive     string            `json:"archive"`
+	Total       int64             `json:"total"`
+	Statuses    []RetentionStatus `json:"statuses"`
+}
+
+// RetentionStatus counts the messages of a status created before the retention of the status began.
+type RetentionStatus struct {
+	Status        string     `json:"status"`
+	RetainFor     string     `json:"retainFor"`
+	CreatedBefore time.Time  `json:"createdBefore"`
+	Messages      int64      `json:"messages"`
+	Oldest        *time.Time `json:"oldest,omitempty"`
+}
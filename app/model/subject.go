@@ -0,0 +1,57 @@
+package model
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// ErasureModeErase deletes every document holding the phone number.
+	ErasureModeErase = "erase"
+	// ErasureModeAnonymize keeps messages for reports but removes their phone numbers and content.
+	ErasureModeAnonymize = "anonymize"
+
+	// AnonymizedPhoneNumber replaces the phone number of anonymized messages.
+	AnonymizedPhoneNumber = "anonymized"
+)
+
+// SubjectExport is everything stored about a phone number within a tenant.
+type SubjectExport struct {
+	PhoneNumber      string            `json:"phoneNumber"`
+	GeneratedAt      time.Time         `json:"generatedAt"`
+	Messages         []Message         `json:"messages"`
+	ArchivedMessages []Message         `json:"archivedMessages"`
+	InboundMessages  []InboundMessage  `json:"inboundMessages"`
+	Contacts         []Contact         `json:"contacts"`
+	OutboxMessages   []OutboxMessage   `json:"outboxMessages"`
+	Campaigns        []SubjectCampaign `json:"campaigns"`
+	Suppression      *Suppression      `json:"suppression,omitempty"`
+}
+
+// SubjectCampaign is a campaign whose audience holds a phone number. Error is why no message could be
+// created for the number, it is empty when the number was not rejected.
+type SubjectCampaign struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id"`
+	Name      string             `json:"name" bson:"name"`
+	Status    string             `json:"status" bson:"status"`
+	Rejected  bool               `json:"rejected" bson:"-"`
+	Error     string             `json:"error,omitempty" bson:"-"`
+	CreatedAt time.Time          `json:"createdAt" bson:"createdAt"`
+}
+
+// SubjectErasure counts the documents erased or anonymized for a phone number and the cache keys cleared.
+// ArchiveFileMessages counts the messages of archive files, Campaigns the campaigns the number was removed
+// from.
+type SubjectErasure struct {
+	PhoneNumber         string `json:"phoneNumber"`
+	Mode                string `json:"mode"`
+	Messages            int64  `json:"messages"`
+	ArchivedMessages    int64  `json:"archivedMessages"`
+	InboundMessages     int64  `json:"inboundMessages"`
+	Contacts            int64  `json:"contacts"`
+	OutboxMessages      int64  `json:"outboxMessages"`
+	Campaigns           int64  `json:"campaigns"`
+	ArchiveFileMessages int64  `json:"archiveFileMessages"`
+	CacheKeys           int64  `json:"cacheKeys"`
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+const (
+	SuppressionSourceManual  = "manual"
+	SuppressionSourceImport  = "import"
+	SuppressionSourceInbound = "inbound"
+)
+
+// Suppression is an opted out phone number of a tenant, no message of the tenant is sent to it while it exists.
+type Suppression struct {
+	TenantID    string    `json:"tenantId" bson:"tenantId"`
+	PhoneNumber string    `json:"phoneNumber" bson:"phoneNumber"`
+	Reason      string    `json:"reason,omitempty" bson:"reason,omitempty"`
+	Source      string    `json:"source" bson:"source"`
+	CreatedAt   time.Time `json:"createdAt" bson:"createdAt"`
+}
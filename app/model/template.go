@@ -0,0 +1,35 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Template is a reusable message body with {{placeholders}}, stored once per locale.
+type Template struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id"`
+	TenantID      string             `json:"tenantId" bson:"tenantId"`
+	Name          string             `json:"name" bson:"name"`
+	DefaultLocale string             `json:"defaultLocale" bson:"defaultLocale"`
+	Locales       map[string]string  `json:"locales" bson:"locales"`
+	CreatedAt     time.Time          `json:"createdAt" bson:"createdAt"`
+	UpdatedAt     time.Time          `json:"updatedAt" bson:"updatedAt"`
+}
+
+// Body returns the variant for locale, falling back to its language ("tr-TR" to "tr")
+// and then to the default locale.
+func (t *Template) Body(locale string) string {
+	if body, ok := t.Locales[locale]; ok {
+		return body
+	}
+
+	if language, _, found := strings.Cut(locale, "-"); found {
+		if body, ok := t.Locales[language]; ok {
+			return body
+		}
+	}
+
+	return t.Locales[t.DefaultLocale]
+}
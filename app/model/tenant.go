@@ -0,0 +1,4 @@
+package model
+
+// DefaultTenantID owns the data of callers without a tenant and everything stored before tenants existed.
+const DefaultTenantID = "default"
@@ -0,0 +1,55 @@
+package notifier
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+var ErrInternalAddress = errors.New("callback address is not public")
+
+// newTransport returns a transport that refuses to connect to private, loopback and link-local addresses.
+// Addresses are checked after name resolution, so host names resolving into the network are refused too,
+// and no proxy is used since the dialer would check the proxy instead of the receiver.
+func newTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   denyInternal,
+	}
+
+	return &http.Transport{
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
+
+// denyInternal is the Control hook of the dialer, it is called with the resolved address of every connection.
+func denyInternal(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return err
+	}
+
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() || sharedAddressSpace.Contains(ip) {
+		return fmt.Errorf("%w: %s", ErrInternalAddress, ip)
+	}
+	return nil
+}
+
+// sharedAddressSpace is used by carrier-grade NAT and some cloud metadata services.
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")
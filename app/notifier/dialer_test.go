@@ -0,0 +1,29 @@
+package notifier
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDenyInternal(t *testing.T) {
+	for _, address := range []string{
+		"127.0.0.1:80",
+		"[::1]:443",
+		"10.0.0.5:80",
+		"172.16.3.4:80",
+		"192.168.1.1:80",
+		"169.254.169.254:80",
+		"[fe80::1]:80",
+		"[fd00::1]:80",
+		"100.100.100.200:80",
+		"0.0.0.0:80",
+		"[::ffff:127.0.0.1]:80",
+	} {
+		assert.ErrorIs(t, denyInternal("tcp4", address, nil), ErrInternalAddress, address)
+	}
+
+	for _, address := range []string{"93.184.216.34:443", "[2606:2800:220:1:248:1893:25c8:1946]:443"} {
+		assert.NoError(t, denyInternal("tcp", address, nil), address)
+	}
+}
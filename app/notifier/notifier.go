@@ -0,0 +1,297 @@
+package notifier
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"messaging-system/app/model"
+	"messaging-system/config"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	HeaderSignature = "X-Signature"
+	HeaderTimestamp = "X-Signature-Timestamp"
+	HeaderEventID   = "X-Event-ID"
+
+	SignaturePrefix = "sha256="
+
+	DefaultTimeout      = 5 * time.Second
+	DefaultPollInterval = 5 * time.Second
+	DefaultMaxAttempts  = 5
+	DefaultBackoff      = 10 * time.Second
+
+	// placeholderSecret is the secret the sample configurations ship with.
+	placeholderSecret = "change-me"
+)
+
+type INotificationRepository interface {
+	CreateNotification(ctx context.Context, notification *model.Notification) error
+	ClaimNotification(ctx context.Context, now time.Time, lease time.Duration) (*model.Notification, error)
+	UpdateNotification(ctx context.Context, notification *model.Notification) error
+	GetNotifications(ctx context.Context, tenantID string, messageID primitive.ObjectID) ([]model.Notification, error)
+}
+
+// Notifier posts status events to the callback URL of a message. Every event is stored in the
+// delivery log first and delivered by Run, failed deliveries are retried with exponential backoff.
+// Events are only posted to the callback hosts of the tenant and never to internal addresses.
+type Notifier struct {
+	repo    INotificationRepository
+	resty   *resty.Client
+	conf    *config.Notifier
+	tenants config.Tenants
+	logger  *slog.Logger
+	trigger chan struct{}
+}
+
+// NewNotifier fails when notifications are enabled without a secret or with the placeholder secret of the
+// sample configurations, receivers could not tell events of the service from forged ones. Unset durations and
+// attempts fall back to their defaults.
+func NewNotifier(repo INotificationRepository, conf *config.Notifier, tenants config.Tenants,
+	logger *slog.Logger) (*Notifier, error) {
+	notifierConf := config.Notifier{}
+	if conf != nil {
+		notifierConf = *conf
+	}
+	conf = &notifierConf
+	if conf.Timeout <= 0 {
+		conf.Timeout = DefaultTimeout
+	}
+	if conf.PollInterval <= 0 {
+		conf.PollInterval = DefaultPollInterval
+	}
+	if conf.MaxAttempts <= 0 {
+		conf.MaxAttempts = DefaultMaxAttempts
+	}
+	if conf.Backoff <= 0 {
+		conf.Backoff = DefaultBackoff
+	}
+
+	if conf.Enabled {
+		if err := checkSecret("notifier.secret", conf.Secret); err != nil {
+			return nil, err
+		}
+		for tenantID, tenant := range tenants {
+			if tenant == nil || tenant.CallbackSecret == "" {
+				continue
+			}
+			if err := checkSecret("tenants."+tenantID+".callbackSecret", tenant.CallbackSecret); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// redirects are not followed, the target could be a host outside the callback hosts of the tenant
+	client := resty.New().
+		SetTransport(newTransport()).
+		SetTimeout(conf.Timeout).
+		SetRedirectPolicy(resty.RedirectPolicyFunc(func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		}))
+
+	return &Notifier{
+		repo:    repo,
+		resty:   client,
+		conf:    conf,
+		tenants: tenants,
+		logger:  logger,
+		trigger: make(chan struct{}, 1),
+	}, nil
+}
+
+func checkSecret(name, secret string) error {
+	switch secret {
+	case "":
+		return fmt.Errorf("%s is required while notifications are enabled", name)
+	case placeholderSecret:
+		return fmt.Errorf("%s still has the placeholder value %q", name, placeholderSecret)
+	default:
+		return nil
+	}
+}
+
+// Sign returns the X-Signature header value of a body sent at timestamp.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return SignaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Notify records a status event of the message, messages without a callback URL are skipped.
+func (n *Notifier) Notify(ctx context.Context, message *model.Message, eventType, reason string) {
+	if !n.conf.Enabled || message.CallbackURL == "" {
+		return
+	}
+
+	// the callback hosts of the tenant may have changed since the message was created
+	callback, err := url.Parse(message.CallbackURL)
+	if err != nil || !n.tenants.Get(message.TenantID).AllowsCallback(callback.Hostname()) {
+		n.logger.WarnContext(ctx, "skipping notification to a host outside the callback hosts of the tenant",
+			"messageId", message.ID,
+			"tenantId", message.TenantID,
+			"event", eventType,
+		)
+		return
+	}
+
+	now := time.Now().UTC()
+	notification := &model.Notification{
+		ID:          primitive.NewObjectID(),
+		TenantID:    message.TenantID,
+		MessageID:   message.ID,
+		CallbackURL: message.CallbackURL,
+		Status:      model.NotificationStatusPending,
+		Event: model.StatusEvent{
+			Type:             eventType,
+			MessageID:        message.ID.Hex(),
+			WebhookMessageID: message.WebhookMessageID,
+			Status:           message.Status,
+			Error:            reason,
+			OccurredAt:       now,
+		},
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	notification.Event.ID = notification.ID.Hex()
+
+	if err := n.repo.CreateNotification(ctx, notification); err != nil {
+		n.logger.ErrorContext(ctx, "failed to store notification",
+			"messageId", message.ID,
+			"event", eventType,
+			"error", err,
+		)
+		return
+	}
+
+	select {
+	case n.trigger <- struct{}{}:
+	default:
+	}
+}
+
+func (n *Notifier) GetNotifications(ctx context.Context, tenantID string,
+	messageID primitive.ObjectID) ([]model.Notification, error) {
+	return n.repo.GetNotifications(ctx, tenantID, messageID)
+}
+
+// Run delivers due notifications until ctx is cancelled.
+func (n *Notifier) Run(ctx context.Context) {
+	n.logger.Info("Starting notifier")
+	ticker := time.NewTicker(n.conf.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		n.deliverDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			n.logger.Warn("Stopping notifier...")
+			return
+		case <-ticker.C:
+		case <-n.trigger:
+		}
+	}
+}
+
+// deliverDue delivers notifications until none is due anymore.
+func (n *Notifier) deliverDue(ctx context.Context) {
+	// a claimed notification is retried after the lease if this instance dies while delivering it
+	lease := 2 * n.conf.Timeout
+	for ctx.Err() == nil {
+		notification, err := n.repo.ClaimNotification(ctx, time.Now().UTC(), lease)
+		if errors.Is(err, model.ErrNotFound) {
+			return
+		}
+		if err != nil {
+			n.logger.Error("failed to claim notification", "error", err)
+			return
+		}
+
+		n.deliver(ctx, notification)
+	}
+}
+
+func (n *Notifier) deliver(ctx context.Context, notification *model.Notification) {
+	notification.Attempts++
+	statusCode, err := n.post(ctx, notification)
+	notification.LastStatusCode = statusCode
+
+	now := time.Now().UTC()
+	switch {
+	case err == nil:
+		notification.Status = model.NotificationStatusDelivered
+		notification.LastError = ""
+		notification.DeliveredAt = &now
+	case notification.Attempts >= n.conf.MaxAttempts:
+		notification.Status = model.NotificationStatusFailed
+		notification.LastError = err.Error()
+		n.logger.Error("giving up notification",
+			"notificationId", notification.ID,
+			"messageId", notification.MessageID,
+			"attempts", notification.Attempts,
+			"error", err,
+		)
+	default:
+		notification.LastError = err.Error()
+		notification.NextAttemptAt = now.Add(n.backoff(notification.Attempts))
+		n.logger.Warn("notification delivery failed, retrying",
+			"notificationId", notification.ID,
+			"attempts", notification.Attempts,
+			"error", err,
+		)
+	}
+
+	if err := n.repo.UpdateNotification(ctx, notification); err != nil {
+		n.logger.Error("failed to update notification", "notificationId", notification.ID, "error", err)
+	}
+}
+
+func (n *Notifier) post(ctx context.Context, notification *model.Notification) (int, error) {
+	body, err := json.Marshal(notification.Event)
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	res, err := n.resty.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json").
+		SetHeader(HeaderEventID, notification.Event.ID).
+		SetHeader(HeaderTimestamp, timestamp).
+		SetHeader(HeaderSignature, Sign(n.secret(notification.TenantID), timestamp, body)).
+		SetBody(body).
+		Post(notification.CallbackURL)
+	if err != nil {
+		return 0, err
+	}
+
+	if !res.IsSuccess() {
+		return res.StatusCode(), fmt.Errorf("callback responded with status %d", res.StatusCode())
+	}
+	return res.StatusCode(), nil
+}
+
+// secret returns the key the events of a tenant are signed with.
+func (n *Notifier) secret(tenantID string) string {
+	if secret := n.tenants.Get(tenantID).CallbackSecret; secret != "" {
+		return secret
+	}
+	return n.conf.Secret
+}
+
+// backoff returns the delay after the given failed attempt, doubling with every attempt.
+func (n *Notifier) backoff(attempts int) time.Duration {
+	return n.conf.Backoff * time.Duration(1<<(attempts-1))
+}
@@ -0,0 +1,237 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"messaging-system/config"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSign(t *testing.T) {
+	signature := Sign("secret", "1700000000", []byte(`{"id":"1"}`))
+
+	assert.Equal(t, signature, Sign("secret", "1700000000", []byte(`{"id":"1"}`)))
+	assert.NotEqual(t, signature, Sign("other", "1700000000", []byte(`{"id":"1"}`)))
+	assert.NotEqual(t, signature, Sign("secret", "1700000001", []byte(`{"id":"1"}`)))
+	assert.Len(t, signature, len(SignaturePrefix)+64)
+}
+
+func TestNewNotifier(t *testing.T) {
+	enabled := &config.Notifier{Enabled: true, Secret: "secret"}
+
+	t.Run("requires a secret while enabled", func(t *testing.T) {
+		for _, secret := range []string{"", "change-me"} {
+			_, err := NewNotifier(nil, &config.Notifier{Enabled: true, Secret: secret}, nil, slog.Default())
+			assert.Error(t, err)
+		}
+
+		_, err := NewNotifier(nil, &config.Notifier{}, nil, slog.Default())
+		assert.NoError(t, err)
+	})
+
+	t.Run("defaults unset durations and attempts", func(t *testing.T) {
+		notifier, err := NewNotifier(nil, enabled, nil, slog.Default())
+		assert.NoError(t, err)
+
+		assert.Equal(t, DefaultTimeout, notifier.conf.Timeout)
+		assert.Equal(t, DefaultPollInterval, notifier.conf.PollInterval)
+		assert.Equal(t, DefaultMaxAttempts, notifier.conf.MaxAttempts)
+		assert.Equal(t, DefaultBackoff, notifier.conf.Backoff)
+		assert.Equal(t, DefaultBackoff, notifier.backoff(1))
+		assert.Zero(t, enabled.PollInterval, "the passed configuration is left untouched")
+	})
+
+	t.Run("rejects placeholder tenant secrets", func(t *testing.T) {
+		_, err := NewNotifier(nil, enabled, config.Tenants{"retail": {CallbackSecret: "change-me"}}, slog.Default())
+		assert.Error(t, err)
+	})
+
+	t.Run("signs with the secret of the tenant", func(t *testing.T) {
+		notifier, err := NewNotifier(nil, enabled, config.Tenants{"retail": {CallbackSecret: "retail-secret"}},
+			slog.Default())
+		assert.NoError(t, err)
+
+		assert.Equal(t, "retail-secret", notifier.secret("retail"))
+		assert.Equal(t, "secret", notifier.secret(model.DefaultTenantID))
+	})
+}
+
+func TestNotifier_Notify(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockINotificationRepository(mockController)
+	tenants := config.Tenants{"retail": {CallbackHosts: []string{"example.com"}}}
+	notifier, err := NewNotifier(mockRepo, &config.Notifier{Enabled: true, Secret: "secret"}, tenants, slog.Default())
+	assert.NoError(t, err)
+
+	t.Run("stores pending event", func(t *testing.T) {
+		message := &model.Message{ID: primitive.NewObjectID(), TenantID: "retail", Status: model.StatusSent,
+			WebhookMessageID: "webhook123", CallbackURL: "https://example.com/callback"}
+		mockRepo.
+			EXPECT().
+			CreateNotification(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, notification *model.Notification) error {
+				assert.Equal(t, model.NotificationStatusPending, notification.Status)
+				assert.Equal(t, notification.ID.Hex(), notification.Event.ID)
+				assert.Equal(t, model.EventMessageSent, notification.Event.Type)
+				assert.Equal(t, "webhook123", notification.Event.WebhookMessageID)
+				return nil
+			})
+
+		notifier.Notify(ctx, message, model.EventMessageSent, "")
+		assert.Len(t, notifier.trigger, 1)
+	})
+
+	t.Run("message without callback url", func(t *testing.T) {
+		notifier.Notify(ctx, &model.Message{ID: primitive.NewObjectID()}, model.EventMessageSent, "")
+	})
+
+	t.Run("callback host no longer allowed", func(t *testing.T) {
+		notifier.Notify(ctx, &model.Message{ID: primitive.NewObjectID(), TenantID: "retail",
+			CallbackURL: "https://other.example.net/callback"}, model.EventMessageSent, "")
+		notifier.Notify(ctx, &model.Message{ID: primitive.NewObjectID(), TenantID: model.DefaultTenantID,
+			CallbackURL: "https://example.com/callback"}, model.EventMessageSent, "")
+	})
+
+	t.Run("disabled notifier", func(t *testing.T) {
+		disabled, err := NewNotifier(mockRepo, &config.Notifier{}, nil, slog.Default())
+		assert.NoError(t, err)
+		disabled.Notify(ctx, &model.Message{CallbackURL: "https://example.com"}, model.EventMessageSent, "")
+	})
+}
+
+func TestNotifier_deliver(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	conf := &config.Notifier{Enabled: true, Secret: "secret", Timeout: time.Second, MaxAttempts: 3,
+		Backoff: time.Minute}
+	mockRepo := mocks.NewMockINotificationRepository(mockController)
+	notifier, err := NewNotifier(mockRepo, conf, nil, slog.Default())
+	assert.NoError(t, err)
+	// the test server listens on loopback, which the notifier refuses to connect to
+	notifier.resty.SetTransport(http.DefaultTransport)
+
+	statusCode := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, Sign("secret", r.Header.Get(HeaderTimestamp), body), r.Header.Get(HeaderSignature))
+
+		event := model.StatusEvent{}
+		assert.NoError(t, json.Unmarshal(body, &event))
+		assert.Equal(t, event.ID, r.Header.Get(HeaderEventID))
+
+		w.WriteHeader(statusCode)
+	}))
+	defer server.Close()
+
+	newNotification := func(attempts int) *model.Notification {
+		id := primitive.NewObjectID()
+		return &model.Notification{ID: id, CallbackURL: server.URL, Attempts: attempts,
+			Status: model.NotificationStatusPending, Event: model.StatusEvent{ID: id.Hex()}}
+	}
+
+	t.Run("delivered", func(t *testing.T) {
+		notification := newNotification(0)
+		mockRepo.EXPECT().UpdateNotification(gomock.Any(), notification).Return(nil)
+
+		notifier.deliver(ctx, notification)
+		assert.Equal(t, model.NotificationStatusDelivered, notification.Status)
+		assert.Equal(t, http.StatusOK, notification.LastStatusCode)
+		assert.NotNil(t, notification.DeliveredAt)
+	})
+
+	t.Run("retried with backoff", func(t *testing.T) {
+		statusCode = http.StatusServiceUnavailable
+		notification := newNotification(1)
+		mockRepo.EXPECT().UpdateNotification(gomock.Any(), notification).Return(nil)
+
+		notifier.deliver(ctx, notification)
+		assert.Equal(t, model.NotificationStatusPending, notification.Status)
+		assert.Equal(t, 2, notification.Attempts)
+		assert.WithinDuration(t, time.Now().Add(2*time.Minute), notification.NextAttemptAt, 5*time.Second)
+		assert.NotEmpty(t, notification.LastError)
+	})
+
+	t.Run("failed after max attempts", func(t *testing.T) {
+		statusCode = http.StatusInternalServerError
+		notification := newNotification(2)
+		mockRepo.EXPECT().UpdateNotification(gomock.Any(), notification).Return(nil)
+
+		notifier.deliver(ctx, notification)
+		assert.Equal(t, model.NotificationStatusFailed, notification.Status)
+		assert.Equal(t, http.StatusInternalServerError, notification.LastStatusCode)
+	})
+
+	t.Run("does not follow redirects", func(t *testing.T) {
+		redirected := false
+		target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			redirected = true
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer target.Close()
+		redirect := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusTemporaryRedirect))
+		defer redirect.Close()
+
+		notification := newNotification(0)
+		notification.CallbackURL = redirect.URL
+		mockRepo.EXPECT().UpdateNotification(gomock.Any(), notification).Return(nil)
+
+		notifier.deliver(ctx, notification)
+		assert.False(t, redirected)
+		assert.Equal(t, model.NotificationStatusPending, notification.Status)
+		assert.Equal(t, http.StatusTemporaryRedirect, notification.LastStatusCode)
+	})
+
+	t.Run("refuses internal addresses", func(t *testing.T) {
+		guarded, err := NewNotifier(mockRepo, conf, nil, slog.Default())
+		assert.NoError(t, err)
+		notification := newNotification(0)
+		mockRepo.EXPECT().UpdateNotification(gomock.Any(), notification).Return(nil)
+
+		guarded.deliver(ctx, notification)
+		assert.Equal(t, model.NotificationStatusPending, notification.Status)
+		assert.Contains(t, notification.LastError, ErrInternalAddress.Error())
+	})
+}
+
+func TestNotifier_deliverDue(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	mockRepo := mocks.NewMockINotificationRepository(mockController)
+	notifier, err := NewNotifier(mockRepo, &config.Notifier{Enabled: true, Secret: "secret", Timeout: time.Second,
+		MaxAttempts: 1}, nil, slog.Default())
+	assert.NoError(t, err)
+	notifier.resty.SetTransport(http.DefaultTransport)
+
+	gomock.InOrder(
+		mockRepo.EXPECT().
+			ClaimNotification(gomock.Any(), gomock.Any(), 2*time.Second).
+			Return(&model.Notification{ID: primitive.NewObjectID(), CallbackURL: server.URL}, nil),
+		mockRepo.EXPECT().UpdateNotification(gomock.Any(), gomock.Any()).Return(nil),
+		mockRepo.EXPECT().ClaimNotification(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, model.ErrNotFound),
+	)
+
+	notifier.deliverDue(ctx)
+}
@@ -0,0 +1,189 @@
+package phone
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	ErrRequired           = errors.New("phone number is required")
+	ErrInvalid            = errors.New("invalid phone number")
+	ErrCountryNotAllowed  = errors.New("phone number country is not allowed")
+	e164Regex             = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+	separatorReplacer     = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "", ".", "", "/", "")
+	internationalPrefixes = []string{"+", "00"}
+)
+
+// Plan is the mobile numbering plan of a country.
+type Plan struct {
+	Country     string
+	CallingCode string
+	TrunkPrefix string
+	// Mobile matches the national significant number of mobile subscribers.
+	Mobile *regexp.Regexp
+}
+
+var plans = []Plan{
+	{Country: "TR", CallingCode: "90", TrunkPrefix: "0", Mobile: regexp.MustCompile(`^5\d{9}$`)},
+	{Country: "AZ", CallingCode: "994", TrunkPrefix: "0", Mobile: regexp.MustCompile(`^(10|5[015]|60|7[07]|99)\d{7}$`)},
+	{Country: "DE", CallingCode: "49", TrunkPrefix: "0", Mobile: regexp.MustCompile(`^1[5-7]\d{8,9}$`)},
+	{Country: "ES", CallingCode: "34", Mobile: regexp.MustCompile(`^[67]\d{8}$`)},
+	{Country: "FR", CallingCode: "33", TrunkPrefix: "0", Mobile: regexp.MustCompile(`^[67]\d{8}$`)},
+	{Country: "GB", CallingCode: "44", TrunkPrefix: "0", Mobile: regexp.MustCompile(`^7\d{9}$`)},
+	{Country: "IT", CallingCode: "39", Mobile: regexp.MustCompile(`^3\d{8,9}$`)},
+	{Country: "NL", CallingCode: "31", TrunkPrefix: "0", Mobile: regexp.MustCompile(`^6\d{8}$`)},
+	{Country: "US", CallingCode: "1", TrunkPrefix: "1", Mobile: regexp.MustCompile(`^[2-9]\d{2}[2-9]\d{6}$`)},
+}
+
+type Number struct {
+	Country        string
+	NationalNumber string
+	E164           string
+}
+
+// Parser normalizes phone numbers of an allowlist of countries to E.164.
+type Parser struct {
+	defaultCountry *Plan
+	allowed        []Plan
+}
+
+// NewParser creates a parser for the given ISO 3166-1 alpha-2 country codes, all known countries
+// are allowed when countries is empty. Numbers without an international prefix are read in the
+// national format of defaultCountry.
+func NewParser(defaultCountry string, countries []string) (*Parser, error) {
+	parser := &Parser{}
+
+	if len(countries) == 0 {
+		parser.allowed = plans
+	}
+	for _, country := range countries {
+		plan, ok := findPlan(country)
+		if !ok {
+			return nil, fmt.Errorf("unsupported phone country %q", country)
+		}
+		parser.allowed = append(parser.allowed, *plan)
+	}
+
+	if defaultCountry != "" {
+		plan, ok := parser.findAllowed(defaultCountry)
+		if !ok {
+			return nil, fmt.Errorf("default phone country %q is not allowed", defaultCountry)
+		}
+		parser.defaultCountry = plan
+	}
+
+	return parser, nil
+}
+
+// IsE164 reports whether number is formatted as E.164, without checking any numbering plan.
+func IsE164(number string) bool {
+	return e164Regex.MatchString(number)
+}
+
+// Parse accepts international ("+90 555 111 22 33", "0090...") and national ("0555 111 22 33")
+// formats and validates the number against the mobile plan of its country.
+func (p *Parser) Parse(raw string) (*Number, error) {
+	return p.parse(raw, true)
+}
+
+// ParseAnyCountry is Parse without the country allowlist. It reads numbers the service may have stored
+// before their country was removed from the allowlist, so opt-outs and data subject requests of those
+// numbers are still honoured.
+func (p *Parser) ParseAnyCountry(raw string) (*Number, error) {
+	return p.parse(raw, false)
+}
+
+func (p *Parser) parse(raw string, allowlisted bool) (*Number, error) {
+	digits := separatorReplacer.Replace(strings.TrimSpace(raw))
+	if digits == "" {
+		return nil, ErrRequired
+	}
+
+	for _, prefix := range internationalPrefixes {
+		if strings.HasPrefix(digits, prefix) {
+			return p.parseInternational(strings.TrimPrefix(digits, prefix), allowlisted)
+		}
+	}
+
+	if !isDigits(digits) || p.defaultCountry == nil {
+		return nil, ErrInvalid
+	}
+
+	plan := p.defaultCountry
+	if plan.TrunkPrefix != "" && strings.HasPrefix(digits, plan.TrunkPrefix) {
+		if number, ok := plan.number(strings.TrimPrefix(digits, plan.TrunkPrefix)); ok {
+			return number, nil
+		}
+	}
+	if number, ok := plan.number(digits); ok {
+		return number, nil
+	}
+
+	// international format without the leading +, e.g. 905551112233
+	return p.parseInternational(digits, allowlisted)
+}
+
+func (p *Parser) parseInternational(digits string, allowlisted bool) (*Number, error) {
+	if !isDigits(digits) {
+		return nil, ErrInvalid
+	}
+
+	for i := range plans {
+		plan := &plans[i]
+		if !strings.HasPrefix(digits, plan.CallingCode) {
+			continue
+		}
+
+		number, ok := plan.number(strings.TrimPrefix(digits, plan.CallingCode))
+		if !ok {
+			continue
+		}
+		if _, allowed := p.findAllowed(plan.Country); allowlisted && !allowed {
+			return nil, ErrCountryNotAllowed
+		}
+		return number, nil
+	}
+
+	return nil, ErrInvalid
+}
+
+func (p *Parser) findAllowed(country string) (*Plan, bool) {
+	for i := range p.allowed {
+		if p.allowed[i].Country == strings.ToUpper(country) {
+			return &p.allowed[i], true
+		}
+	}
+	return nil, false
+}
+
+func (plan *Plan) number(nationalNumber string) (*Number, bool) {
+	if !plan.Mobile.MatchString(nationalNumber) {
+		return nil, false
+	}
+
+	return &Number{
+		Country:        plan.Country,
+		NationalNumber: nationalNumber,
+		E164:           "+" + plan.CallingCode + nationalNumber,
+	}, true
+}
+
+func findPlan(country string) (*Plan, bool) {
+	for i := range plans {
+		if plans[i].Country == strings.ToUpper(country) {
+			return &plans[i], true
+		}
+	}
+	return nil, false
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return value != ""
+}
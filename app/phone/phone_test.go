@@ -0,0 +1,83 @@
+package phone
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewParser(t *testing.T) {
+	t.Run("unsupported country", func(t *testing.T) {
+		_, err := NewParser("TR", []string{"TR", "XX"})
+		assert.Error(t, err)
+	})
+
+	t.Run("default country outside the allowlist", func(t *testing.T) {
+		_, err := NewParser("DE", []string{"TR"})
+		assert.Error(t, err)
+	})
+}
+
+func TestParser_Parse(t *testing.T) {
+	parser, err := NewParser("TR", []string{"TR", "DE", "GB"})
+	assert.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		raw     string
+		e164    string
+		country string
+		err     error
+	}{
+		{name: "e164", raw: "+905551112233", e164: "+905551112233", country: "TR"},
+		{name: "international with spaces", raw: "+90 555 111 22 33", e164: "+905551112233", country: "TR"},
+		{name: "international with 00 prefix", raw: "0090 (555) 111-22-33", e164: "+905551112233", country: "TR"},
+		{name: "national with trunk prefix", raw: "0555 111 22 33", e164: "+905551112233", country: "TR"},
+		{name: "national without trunk prefix", raw: "5551112233", e164: "+905551112233", country: "TR"},
+		{name: "international without plus", raw: "905551112233", e164: "+905551112233", country: "TR"},
+		{name: "german mobile", raw: "+49 151 23456789", e164: "+4915123456789", country: "DE"},
+		{name: "uk mobile", raw: "+44 7911 123456", e164: "+447911123456", country: "GB"},
+		{name: "empty", raw: " ", err: ErrRequired},
+		{name: "turkish landline", raw: "+902121112233", err: ErrInvalid},
+		{name: "letters", raw: "+90555abc2233", err: ErrInvalid},
+		{name: "too short", raw: "+90555", err: ErrInvalid},
+		{name: "country outside the allowlist", raw: "+31612345678", err: ErrCountryNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			number, err := parser.Parse(tt.raw)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.e164, number.E164)
+			assert.Equal(t, tt.country, number.Country)
+		})
+	}
+}
+
+func TestParser_ParseAnyCountry(t *testing.T) {
+	parser, err := NewParser("TR", []string{"TR"})
+	assert.NoError(t, err)
+
+	number, err := parser.ParseAnyCountry("+31 6 12345678")
+	assert.NoError(t, err)
+	assert.Equal(t, "+31612345678", number.E164)
+	assert.Equal(t, "NL", number.Country)
+
+	number, err = parser.ParseAnyCountry("0555 111 22 33")
+	assert.NoError(t, err)
+	assert.Equal(t, "+905551112233", number.E164)
+
+	_, err = parser.ParseAnyCountry("+902121112233")
+	assert.ErrorIs(t, err, ErrInvalid)
+}
+
+func TestIsE164(t *testing.T) {
+	assert.True(t, IsE164("+905551112233"))
+	assert.False(t, IsE164("05551112233"))
+	assert.False(t, IsE164("+0555"))
+}
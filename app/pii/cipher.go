@@ -0,0 +1,130 @@
+package pii
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"messaging-system/config"
+	"strings"
+)
+
+// encryptedPrefix marks encrypted values, values without it were stored before encryption was enabled.
+const encryptedPrefix = "enc:"
+
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// Cipher encrypts single fields with AES-GCM under the active key and decrypts them with the key they were
+// encrypted with, so keys can be rotated without rewriting stored values. A nil Cipher leaves values as they are.
+type Cipher struct {
+	activeKey string
+	keys      map[string]cipher.AEAD
+	indexKey  []byte
+}
+
+// NewCipher reads the keys of the configuration, it returns a nil Cipher when encryption is disabled.
+func NewCipher(conf *config.PII) (*Cipher, error) {
+	if conf == nil || !conf.Encrypt {
+		return nil, nil
+	}
+
+	c := &Cipher{
+		activeKey: strings.ToLower(conf.ActiveKey),
+		keys:      make(map[string]cipher.AEAD, len(conf.Keys)),
+	}
+	for id, encoded := range conf.Keys {
+		if strings.Contains(id, ":") {
+			return nil, fmt.Errorf("encryption key id %q must not contain a colon", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q is not base64: %w", id, err)
+		}
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q: %w", id, err)
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		c.keys[strings.ToLower(id)] = aead
+	}
+	if _, ok := c.keys[c.activeKey]; !ok {
+		return nil, fmt.Errorf("active encryption key %q is not configured", conf.ActiveKey)
+	}
+
+	indexKey, err := base64.StdEncoding.DecodeString(conf.IndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("index key is not base64: %w", err)
+	}
+	if len(indexKey) < 32 {
+		return nil, errors.New("index key must be at least 32 bytes")
+	}
+	c.indexKey = indexKey
+
+	return c, nil
+}
+
+// Encrypt seals a value under the active key as enc:<key id>:<base64 nonce and ciphertext>, empty values
+// stay empty.
+func (c *Cipher) Encrypt(value string) (string, error) {
+	if c == nil || value == "" {
+		return value, nil
+	}
+
+	aead := c.keys[c.activeKey]
+	nonce := make([]byte, aead.NonceSize(), aead.NonceSize()+len(value)+aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(value), nil)
+	return encryptedPrefix + c.activeKey + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt opens a value sealed by Encrypt with any configured key, values that are not encrypted are
+// returned as they are.
+func (c *Cipher) Decrypt(value string) (string, error) {
+	if c == nil || !strings.HasPrefix(value, encryptedPrefix) {
+		return value, nil
+	}
+
+	id, encoded, found := strings.Cut(strings.TrimPrefix(value, encryptedPrefix), ":")
+	if !found {
+		return "", errors.New("malformed encrypted value")
+	}
+	aead, ok := c.keys[id]
+	if !ok {
+		return "", fmt.Errorf("%w %q", ErrUnknownKey, id)
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", errors.New("malformed encrypted value")
+	}
+	plaintext, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// BlindIndex returns a keyed hash of a value that equal values share, it lets encrypted values be looked
+// up without decrypting them. It is empty for a nil Cipher.
+func (c *Cipher) BlindIndex(value string) string {
+	if c == nil || value == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, c.indexKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,116 @@
+package pii
+
+import (
+	"messaging-system/config"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testKey      = "s16O6thSz/IYuiY6Q1hk9QtLkf/23CKa05t3nwP7zoI="
+	testNextKey  = "3q2+7wAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA="
+	testIndexKey = "FvZhw/147kca6t4ArkYnlqhVn6YxGFpJI/mWeI0ew1o="
+)
+
+func TestNewCipher(t *testing.T) {
+	t.Run("disabled", func(t *testing.T) {
+		c, err := NewCipher(&config.PII{MaskLogs: true})
+		assert.NoError(t, err)
+		assert.Nil(t, c)
+	})
+
+	tests := []struct {
+		name string
+		conf *config.PII
+	}{
+		{name: "active key missing", conf: &config.PII{Encrypt: true, ActiveKey: "2",
+			Keys: map[string]string{"1": testKey}, IndexKey: testIndexKey}},
+		{name: "key not base64", conf: &config.PII{Encrypt: true, ActiveKey: "1",
+			Keys: map[string]string{"1": "not base64"}, IndexKey: testIndexKey}},
+		{name: "key of invalid length", conf: &config.PII{Encrypt: true, ActiveKey: "1",
+			Keys: map[string]string{"1": "c2hvcnQ="}, IndexKey: testIndexKey}},
+		{name: "key id with colon", conf: &config.PII{Encrypt: true, ActiveKey: "a:b",
+			Keys: map[string]string{"a:b": testKey}, IndexKey: testIndexKey}},
+		{name: "index key missing", conf: &config.PII{Encrypt: true, ActiveKey: "1",
+			Keys: map[string]string{"1": testKey}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCipher(tt.conf)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCipher_EncryptDecrypt(t *testing.T) {
+	c, err := NewCipher(&config.PII{Encrypt: true, ActiveKey: "2025", Keys: map[string]string{"2025": testKey},
+		IndexKey: testIndexKey})
+	assert.NoError(t, err)
+
+	encrypted, err := c.Encrypt("+905551112233")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(encrypted, "enc:2025:"))
+	assert.NotContains(t, encrypted, "5551112233")
+
+	again, err := c.Encrypt("+905551112233")
+	assert.NoError(t, err)
+	assert.NotEqual(t, encrypted, again)
+
+	decrypted, err := c.Decrypt(encrypted)
+	assert.NoError(t, err)
+	assert.Equal(t, "+905551112233", decrypted)
+
+	t.Run("plaintext and empty values stay as they are", func(t *testing.T) {
+		plaintext, err := c.Decrypt("+905551112233")
+		assert.NoError(t, err)
+		assert.Equal(t, "+905551112233", plaintext)
+
+		empty, err := c.Encrypt("")
+		assert.NoError(t, err)
+		assert.Empty(t, empty)
+	})
+
+	t.Run("rotated key still decrypts", func(t *testing.T) {
+		rotated, err := NewCipher(&config.PII{Encrypt: true, ActiveKey: "2026",
+			Keys: map[string]string{"2025": testKey, "2026": testNextKey}, IndexKey: testIndexKey})
+		assert.NoError(t, err)
+
+		decrypted, err := rotated.Decrypt(encrypted)
+		assert.NoError(t, err)
+		assert.Equal(t, "+905551112233", decrypted)
+
+		reencrypted, err := rotated.Encrypt(decrypted)
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(reencrypted, "enc:2026:"))
+
+		_, err = c.Decrypt(reencrypted)
+		assert.ErrorIs(t, err, ErrUnknownKey)
+	})
+
+	t.Run("tampered value", func(t *testing.T) {
+		tampered := encrypted[:len(encrypted)-2] + "AA"
+		_, err := c.Decrypt(tampered)
+		assert.Error(t, err)
+	})
+
+	t.Run("nil cipher", func(t *testing.T) {
+		var disabled *Cipher
+		value, err := disabled.Encrypt("+905551112233")
+		assert.NoError(t, err)
+		assert.Equal(t, "+905551112233", value)
+		assert.Empty(t, disabled.BlindIndex("+905551112233"))
+	})
+}
+
+func TestCipher_BlindIndex(t *testing.T) {
+	c, err := NewCipher(&config.PII{Encrypt: true, ActiveKey: "1", Keys: map[string]string{"1": testKey},
+		IndexKey: testIndexKey})
+	assert.NoError(t, err)
+
+	index := c.BlindIndex("+905551112233")
+	assert.Len(t, index, 64)
+	assert.Equal(t, index, c.BlindIndex("+905551112233"))
+	assert.NotEqual(t, index, c.BlindIndex("+905551112234"))
+}
@@ -0,0 +1,211 @@
+package pii
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Redacted replaces message content in logs.
+const Redacted = "[REDACTED]"
+
+const (
+	// maxPhoneDigits is the length of the longest E.164 number.
+	maxPhoneDigits = 15
+	// minInternationalDigits and minNationalDigits are the shortest numbers masked with a leading + and a
+	// leading trunk 0.
+	minInternationalDigits = 7
+	minNationalDigits      = 10
+	phoneSeparators        = " -.()"
+)
+
+var (
+	// phoneRegex matches international numbers starting with + and national numbers starting with a trunk 0,
+	// as a single digit run or in groups separated by spaces, dashes, dots or parentheses. Digit runs without
+	// either prefix, such as timestamps and order ids, are left alone. The first group keeps numbers that are
+	// part of another token, like ORD-0555111223, unmasked; the number is the second group.
+	phoneRegex = regexp.MustCompile(`(^|[^\w\-+.])(` +
+		`\+\d{7,15}|\+\d{1,3}(?:[ \-.]?\(?\d{2,4}\)?){2,6}|` +
+		`0[1-9]\d{8,13}|0\(?[1-9]\d{2,3}\)?(?:[ \-.]?\d{2,4}){2,3})\b`)
+	// contentKeys are attributes holding message content, matched case insensitively.
+	contentKeys = map[string]bool{"content": true, "text": true, "body": true}
+)
+
+// MaskPhoneNumber keeps the calling code and the last two digits of a phone number.
+func MaskPhoneNumber(number string) string {
+	prefix := ""
+	if strings.HasPrefix(number, "+") {
+		prefix, number = "+", number[1:]
+	}
+	if len(number) < 6 {
+		return prefix + strings.Repeat("*", len(number))
+	}
+	return prefix + number[:2] + strings.Repeat("*", len(number)-4) + number[len(number)-2:]
+}
+
+// MaskPhoneNumbers masks every phone number within a text, spaced and dashed numbers are masked without
+// their separators.
+func MaskPhoneNumbers(text string) string {
+	matches := phoneRegex.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+
+	masked := strings.Builder{}
+	last := 0
+	for _, match := range matches {
+		start, end := match[4], match[5]
+		masked.WriteString(text[last:start])
+		masked.WriteString(maskMatch(text[start:end]))
+		last = end
+	}
+	masked.WriteString(text[last:])
+	return masked.String()
+}
+
+// maskMatch masks a matched number. Trailing groups are left out until the number fits into an E.164 number,
+// so a number followed by a year or a count is still masked; numbers too short to be a phone number are kept.
+func maskMatch(match string) string {
+	number, rest := match, ""
+	for countDigits(number) > maxPhoneDigits {
+		cut := strings.LastIndexAny(number, phoneSeparators)
+		if cut <= 0 {
+			return match
+		}
+		number, rest = number[:cut], number[cut:]+rest
+	}
+
+	prefix, minDigits := "", minNationalDigits
+	if strings.HasPrefix(number, "+") {
+		prefix, minDigits = "+", minInternationalDigits
+	}
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, number)
+	if len(digits) < minDigits {
+		return match
+	}
+	return MaskPhoneNumber(prefix+digits) + rest
+}
+
+func countDigits(text string) int {
+	count := 0
+	for _, r := range text {
+		if r >= '0' && r <= '9' {
+			count++
+		}
+	}
+	return count
+}
+
+// MaskingHandler masks phone numbers in the message and attributes of every record and redacts message
+// content before passing the record on.
+type MaskingHandler struct {
+	next slog.Handler
+}
+
+func NewMaskingHandler(next slog.Handler) *MaskingHandler {
+	return &MaskingHandler{next: next}
+}
+
+func (h *MaskingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *MaskingHandler) Handle(ctx context.Context, record slog.Record) error {
+	masked := slog.NewRecord(record.Time, record.Level, MaskPhoneNumbers(record.Message), record.PC)
+	record.Attrs(func(attr slog.Attr) bool {
+		masked.AddAttrs(maskAttr(attr))
+		return true
+	})
+	return h.next.Handle(ctx, masked)
+}
+
+func (h *MaskingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	maskedAttrs := make([]slog.Attr, 0, len(attrs))
+	for _, attr := range attrs {
+		maskedAttrs = append(maskedAttrs, maskAttr(attr))
+	}
+	return &MaskingHandler{next: h.next.WithAttrs(maskedAttrs)}
+}
+
+func (h *MaskingHandler) WithGroup(name string) slog.Handler {
+	return &MaskingHandler{next: h.next.WithGroup(name)}
+}
+
+func maskAttr(attr slog.Attr) slog.Attr {
+	attr.Value = attr.Value.Resolve()
+	if contentKeys[strings.ToLower(attr.Key)] {
+		return slog.String(attr.Key, Redacted)
+	}
+
+	switch attr.Value.Kind() {
+	case slog.KindString:
+		return slog.String(attr.Key, MaskPhoneNumbers(attr.Value.String()))
+	case slog.KindGroup:
+		group := attr.Value.Group()
+		maskedGroup := make([]slog.Attr, 0, len(group))
+		for _, groupAttr := range group {
+			maskedGroup = append(maskedGroup, maskAttr(groupAttr))
+		}
+		return slog.Attr{Key: attr.Key, Value: slog.GroupValue(maskedGroup...)}
+	case slog.KindAny:
+		// structs and maps are logged as groups of their JSON fields, so their content fields are redacted too
+		if group, ok := recordGroup(attr.Value.Any()); ok {
+			return maskAttr(slog.Attr{Key: attr.Key, Value: group})
+		}
+		// errors and other values keep their type unless they print a phone number
+		text := fmt.Sprintf("%+v", attr.Value.Any())
+		if masked := MaskPhoneNumbers(text); masked != text {
+			return slog.String(attr.Key, masked)
+		}
+	}
+	return attr
+}
+
+// recordGroup converts a struct or map that is not an error to a group of its JSON fields, values without
+// exported fields are left to be printed.
+func recordGroup(value interface{}) (slog.Value, bool) {
+	if _, isError := value.(error); isError {
+		return slog.Value{}, false
+	}
+	if kind := reflect.Indirect(reflect.ValueOf(value)).Kind(); kind != reflect.Struct && kind != reflect.Map {
+		return slog.Value{}, false
+	}
+
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return slog.Value{}, false
+	}
+	fields := map[string]interface{}{}
+	if err := json.Unmarshal(encoded, &fields); err != nil || len(fields) == 0 {
+		return slog.Value{}, false
+	}
+	return fieldsGroup(fields), true
+}
+
+func fieldsGroup(fields map[string]interface{}) slog.Value {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, key := range keys {
+		if nested, ok := fields[key].(map[string]interface{}); ok {
+			attrs = append(attrs, slog.Attr{Key: key, Value: fieldsGroup(nested)})
+			continue
+		}
+		attrs = append(attrs, slog.Any(key, fields[key]))
+	}
+	return slog.GroupValue(attrs...)
+}
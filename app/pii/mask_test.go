@@ -0,0 +1,98 @@
+package pii
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaskPhoneNumber(t *testing.T) {
+	assert.Equal(t, "+90********33", MaskPhoneNumber("+905551112233"))
+	assert.Equal(t, "05******33", MaskPhoneNumber("0555111233"))
+	assert.Equal(t, "+*****", MaskPhoneNumber("+12345"))
+}
+
+func TestMaskPhoneNumbers(t *testing.T) {
+	assert.Equal(t, "sent to +90********33 and +44********56",
+		MaskPhoneNumbers("sent to +905551112233 and +447911123456"))
+	// object ids and short numbers are no phone numbers
+	assert.Equal(t, "message 60d5ec9af682fbd12a0f4a1a retried 3 times",
+		MaskPhoneNumbers("message 60d5ec9af682fbd12a0f4a1a retried 3 times"))
+}
+
+func TestMaskPhoneNumbers_Formats(t *testing.T) {
+	for text, expected := range map[string]string{
+		"call +90 555 111 22 33 now":     "call +90********33 now",
+		"call +1 (555) 111-2233 now":     "call +15*******33 now",
+		"call +44-7911-123456":           "call +44********56",
+		"call 0555 111 22 33 now":        "call 05*******33 now",
+		"call 0(555) 111-22-33 now":      "call 05*******33 now",
+		"call 05551112233":               "call 05*******33",
+		"to=+905551112233,+905551112244": "to=+90********33,+90********44",
+		"sent to +90 555 111 22 33 2026": "sent to +90********33 2026",
+		"path /subjects/+905551112233":   "path /subjects/+90********33",
+		"phoneNumber=\"0555-111-22-33\"": "phoneNumber=\"05*******33\"",
+	} {
+		assert.Equal(t, expected, MaskPhoneNumbers(text), text)
+	}
+}
+
+func TestMaskPhoneNumbers_NoPhoneNumbers(t *testing.T) {
+	// timestamps, durations, dates and order ids are left as they are
+	for _, text := range []string{
+		"sent at 1729330000 (1729330000123 ms)",
+		"created 2026-10-19T08:00:00.123Z, 2026-10-19 08:00:00 and 19.10.2026 08:00",
+		"batch 20261019080000 took 0.123456789s",
+		"order 1234567890123 and ORD-0555111223 shipped",
+		"invoice 2026-0001-0002 paid",
+	} {
+		assert.Equal(t, text, MaskPhoneNumbers(text))
+	}
+}
+
+func TestMaskingHandler(t *testing.T) {
+	type response struct {
+		MessageID string
+		To        string
+	}
+
+	buffer := &bytes.Buffer{}
+	logger := slog.New(NewMaskingHandler(slog.NewTextHandler(buffer, nil))).With("phoneNumber", "+905551112233")
+
+	logger.Info("message to +905551112233 sent",
+		"content", "Your code is 123456",
+		"Response", &response{MessageID: "abc", To: "+905551112233"},
+		"error", errors.New("recipient +905551112233 unreachable"),
+		slog.Group("request", "text", "STOP", "status", 200))
+
+	logger.Info("message created",
+		"message", struct {
+			PhoneNumber string `json:"phoneNumber"`
+			Content     string `json:"content"`
+			Segments    int    `json:"segments"`
+		}{PhoneNumber: "+905551112233", Content: "Your PIN is 4711", Segments: 1},
+		"payload", map[string]interface{}{"body": "Hi Jane", "reply": map[string]string{"Text": "YES"}},
+		slog.Group("inbound", slog.Group("sms", "text", "Ship it", "from", "0555 111 22 33")))
+
+	out := buffer.String()
+	assert.NotContains(t, out, "5551112233")
+	assert.NotContains(t, out, "111 22 33")
+	assert.NotContains(t, out, "4711")
+	assert.NotContains(t, out, "Hi Jane")
+	assert.NotContains(t, out, "YES")
+	assert.NotContains(t, out, "Ship it")
+	assert.Contains(t, out, "message.phoneNumber=+90********33")
+	assert.Contains(t, out, "message.content=[REDACTED]")
+	assert.Contains(t, out, "message.segments=1")
+	assert.Contains(t, out, "payload.reply.Text=[REDACTED]")
+	assert.Contains(t, out, "inbound.sms.text=[REDACTED]")
+	assert.NotContains(t, out, "Your code")
+	assert.NotContains(t, out, "STOP")
+	assert.Contains(t, out, `msg="message to +90********33 sent"`)
+	assert.Contains(t, out, "phoneNumber=+90********33")
+	assert.Contains(t, out, "content=[REDACTED]")
+	assert.Contains(t, out, "request.status=200")
+}
@@ -3,130 +3,336 @@ package processor
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"log/slog"
+	"math"
+	"messaging-system/app/cache"
 	"messaging-system/app/dto"
+	"messaging-system/app/events"
 	"messaging-system/app/model"
+	"messaging-system/app/requestid"
+	"messaging-system/config"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 const (
-	StatusSent   = "sent"
-	StatusUnsent = "unsent"
+	StatusSent       = model.StatusSent
+	StatusUnsent     = model.StatusUnsent
+	StatusSuppressed = model.StatusSuppressed
 
-	MessageLimit    = 2
 	MessageInterval = 2
+
+	// DefaultBatchSize is the number of messages sent per run when processor.batchSize is not set.
+	DefaultBatchSize = 2
+	// DefaultHighPriorityShare is the share of each batch reserved for high priority messages when
+	// processor.highPriorityShare is not set.
+	DefaultHighPriorityShare = 0.5
+
+	// fetchFailed is the error batch.finished carries when the batch could not be fetched, the cause
+	// is only logged since the event reaches the subscribers of every tenant.
+	fetchFailed = "failed to fetch messages"
+
+	WatchRetryInterval = 5 * time.Second
+
+	// DefaultMaxAttempts is the number of send attempts of a message when processor.maxAttempts is not set.
+	DefaultMaxAttempts = 5
+)
+
+var (
+	HighPriorityLane     = []int{dto.PriorityHigh}
+	StandardPriorityLane = []int{dto.PriorityNormal, dto.PriorityLow}
 )
 
 type IMessageService interface {
-	GetMessages(ctx context.Context, status string, limit int) ([]model.Message, error)
-	MarkMessageAsSent(ctx context.Context, messageID primitive.ObjectID, webhookMessageID string) error
+	GetMessages(ctx context.Context, tenantID, status string, limit int) ([]model.Message, error)
+	GetMessagesByPriority(ctx context.Context, status string, priorities []int,
+		excludedCampaigns []primitive.ObjectID, limit int) ([]model.Message, error)
+	ExpireMessages(ctx context.Context, now time.Time) ([]model.Message, error)
+	WatchUnsentMessages(ctx context.Context, handle func(ctx context.Context, message *model.Message) error) error
+	ForEachSentMessage(ctx context.Context, tenantID string, from, to time.Time,
+		handle func(ctx context.Context, message *model.Message) error) error
+	MarkMessageAsSent(ctx context.Context, messageID primitive.ObjectID, webhookMessageID,
+		provider string) error
+	UpdateMessageStatus(ctx context.Context, messageID primitive.ObjectID, status string) error
+	RecordSendFailure(ctx context.Context, messageID primitive.ObjectID, reason string, final bool) error
 }
 
 type IClient interface {
-	SendMessage(request *dto.MessageRequest) (*dto.MessageResponse, error)
+	SendMessage(ctx context.Context, tenantID string, request *dto.MessageRequest) (*dto.MessageResponse, error)
 }
 
 type ICacheService interface {
 	Set(ctx context.Context, key string, value interface{}) error
 }
 
+type ISuppressionChecker interface {
+	IsSuppressed(ctx context.Context, tenantID, phoneNumber string) (bool, error)
+}
+
+type INotifier interface {
+	Notify(ctx context.Context, message *model.Message, eventType, reason string)
+}
+
+type ICampaignGate interface {
+	HeldCampaigns(ctx context.Context, now time.Time) ([]primitive.ObjectID, error)
+}
+
 type MessageProcessor struct {
-	service   IMessageService
-	client    IClient
-	cache     ICacheService
-	logger    *slog.Logger
-	ticker    *time.Ticker
-	isRunning bool
+	service      IMessageService
+	client       IClient
+	cache        ICacheService
+	suppressions ISuppressionChecker
+	notifier     INotifier
+	campaigns    ICampaignGate
+	conf         *config.Processor
+	logger       *slog.Logger
+	ticker       *time.Ticker
+	// mu serializes Start and Stop, isRunning is read without it so IsRunning never waits for a batch
+	mu        sync.Mutex
+	isRunning atomic.Bool
 	stopChan  chan bool
+	stopped   chan struct{}
+	trigger   chan struct{}
+	events    *events.Broker
 }
 
-func NewMessageProcessor(service IMessageService, client IClient,
-	cache ICacheService, logger *slog.Logger) *MessageProcessor {
-	return &MessageProcessor{
-		service:  service,
-		client:   client,
-		cache:    cache,
-		logger:   logger,
-		stopChan: make(chan bool),
+func NewMessageProcessor(service IMessageService, client IClient, cache ICacheService,
+	suppressions ISuppressionChecker, notifier INotifier, campaigns ICampaignGate, conf *config.Processor,
+	logger *slog.Logger) (*MessageProcessor, error) {
+	processorConf := config.Processor{}
+	if conf != nil {
+		processorConf = *conf
 	}
+	if processorConf.BatchSize < 0 {
+		return nil, fmt.Errorf("processor batch size must be positive")
+	}
+	if processorConf.BatchSize == 0 {
+		processorConf.BatchSize = DefaultBatchSize
+	}
+	if processorConf.HighPriorityShare < 0 || processorConf.HighPriorityShare > 1 {
+		return nil, fmt.Errorf("processor high priority share must be within (0, 1], got %v",
+			processorConf.HighPriorityShare)
+	}
+	if processorConf.HighPriorityShare == 0 {
+		processorConf.HighPriorityShare = DefaultHighPriorityShare
+	}
+	conf = &processorConf
+
+	return &MessageProcessor{
+		service:      service,
+		client:       client,
+		cache:        cache,
+		suppressions: suppressions,
+		notifier:     notifier,
+		campaigns:    campaigns,
+		conf:         conf,
+		logger:       logger,
+		stopChan:     make(chan bool),
+		trigger:      make(chan struct{}, 1),
+		events:       events.NewBroker(),
+	}, nil
 }
 
 func (p *MessageProcessor) Start(ctx context.Context) {
-	if p.isRunning {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.isRunning.Load() {
 		p.logger.Warn("Message processor already running")
 		return
 	}
 
 	p.logger.Info("Starting message processor")
-	p.isRunning = true
+	p.isRunning.Store(true)
 	p.ticker = time.NewTicker(MessageInterval * time.Minute)
+	p.stopped = make(chan struct{})
+
+	var cancelWatch context.CancelFunc
+	if p.conf.ChangeStream {
+		var watchCtx context.Context
+		watchCtx, cancelWatch = context.WithCancel(ctx)
+		go p.watchMessages(watchCtx)
+	}
 
+	// the loop keeps its own ticker and channel, a later Start replaces the fields
+	ticker, stopped := p.ticker, p.stopped
 	go func() {
+		defer close(stopped)
 		for {
 			select {
-			case <-p.ticker.C:
+			case <-ticker.C:
 				p.processMessages(ctx)
+			case <-p.trigger:
+				// keep draining while inserts arrive faster than one batch
+				if p.processMessages(ctx) == p.conf.BatchSize {
+					p.notify()
+				}
 			case <-p.stopChan:
 				p.logger.Warn("Stopping processor...")
-				p.ticker.Stop()
-				p.isRunning = false
+				ticker.Stop()
+				if cancelWatch != nil {
+					cancelWatch()
+				}
 				return
 			}
 		}
 	}()
 }
 
+// IsRunning reports whether the processor was started and not stopped since.
+func (p *MessageProcessor) IsRunning() bool {
+	return p.isRunning.Load()
+}
+
+// Stop stops the processor and returns once the batch in flight is done and the change stream watch is
+// cancelled, a Start right after it starts a fresh loop.
 func (p *MessageProcessor) Stop(_ context.Context) {
-	if !p.isRunning {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.isRunning.Load() {
 		p.logger.Warn("Message processor not running")
 		return
 	}
 
 	p.stopChan <- true
+	<-p.stopped
+	p.isRunning.Store(false)
 }
 
-func (p *MessageProcessor) GetSentMessages(ctx context.Context, limit int) ([]model.Message, error) {
-	return p.service.GetMessages(ctx, StatusSent, limit)
+func (p *MessageProcessor) GetSentMessages(ctx context.Context, tenantID string, limit int) ([]model.Message, error) {
+	return p.service.GetMessages(ctx, tenantID, StatusSent, limit)
 }
 
-func (p *MessageProcessor) processMessages(ctx context.Context) {
-	messages, err := p.service.GetMessages(ctx, StatusUnsent, MessageLimit)
+// ExportSentMessages calls handle for every message of the tenant sent within [from, to), oldest first.
+func (p *MessageProcessor) ExportSentMessages(ctx context.Context, tenantID string, from, to time.Time,
+	handle func(ctx context.Context, message *model.Message) error) error {
+	return p.service.ForEachSentMessage(ctx, tenantID, from, to, handle)
+}
+
+// Subscribe streams processor activity matching filter until the returned cancel function is called.
+func (p *MessageProcessor) Subscribe(filter events.Filter) (<-chan events.Event, func()) {
+	return p.events.Subscribe(filter)
+}
+
+// watchMessages triggers a processing run for every inserted unsent message and
+// reopens the change stream after failures until ctx is cancelled.
+func (p *MessageProcessor) watchMessages(ctx context.Context) {
+	for {
+		err := p.service.WatchUnsentMessages(ctx, func(_ context.Context, _ *model.Message) error {
+			p.notify()
+			return nil
+		})
+		if ctx.Err() != nil {
+			return
+		}
+
+		p.logger.Error("message change stream failed, retrying", "error", err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(WatchRetryInterval):
+		}
+	}
+}
+
+// notify schedules a processing run, runs that are already scheduled are coalesced.
+func (p *MessageProcessor) notify() {
+	select {
+	case p.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// nextBatch fills a batch of processor.batchSize messages from the high and standard priority lanes. Each
+// lane is guaranteed its share of the batch, at least one slot for the high lane, so neither can starve the
+// other. Slots a lane cannot use go to the other one.
+// Messages of campaigns that are paused, scheduled for later or throttled are left out.
+func (p *MessageProcessor) nextBatch(ctx context.Context) ([]model.Message, error) {
+	held, err := p.campaigns.HeldCampaigns(ctx, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	batchSize := p.conf.BatchSize
+	high, err := p.service.GetMessagesByPriority(ctx, StatusUnsent, HighPriorityLane, held, batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	standard, err := p.service.GetMessagesByPriority(ctx, StatusUnsent, StandardPriorityLane, held, batchSize)
+	if err != nil {
+		return nil, err
+	}
+
+	highSlots := max(1, int(math.Round(float64(batchSize)*p.conf.HighPriorityShare)))
+	highCount := min(len(high), highSlots)
+	standardCount := min(len(standard), batchSize-highCount)
+	highCount = min(len(high), batchSize-standardCount)
+
+	return append(high[:highCount], standard[:standardCount]...), nil
+}
+
+// processMessages sends one batch of unsent messages and returns the size of the batch.
+func (p *MessageProcessor) processMessages(ctx context.Context) int {
+	p.events.Publish(events.Event{Type: events.TypeTickStarted})
+
+	// expired messages must not take up slots of the batch
+	p.expireMessages(ctx)
+
+	messages, err := p.nextBatch(ctx)
 	if err != nil {
 		p.logger.Error("failed to fetch messages", slog.Any("error", err))
-		return
+		p.events.Publish(events.Event{Type: events.TypeBatchFinished, Error: fetchFailed})
+		return 0
 	}
 
 	if len(messages) == 0 {
 		p.logger.Info("no unsent messages found")
-		return
+		p.events.Publish(events.Event{Type: events.TypeBatchFinished})
+		return 0
 	}
 
 	for _, message := range messages {
+		ctx := messageContext(ctx, &message)
+		if !p.deliverable(ctx, &message) {
+			continue
+		}
+
 		request := message.ConvertToRequest()
-		resp, err := p.client.SendMessage(request)
+		resp, err := p.client.SendMessage(ctx, message.TenantID, request)
 		if err != nil {
-			p.logger.Error("failed to send message",
+			p.logger.ErrorContext(ctx, "failed to send message",
 				slog.String("messageId", message.ID.Hex()),
 				slog.Any("error", err),
 			)
+			p.recordFailure(ctx, &message, err.Error())
 			continue
 		}
 
-		p.logger.Info("message sent",
+		p.logger.InfoContext(ctx, "message sent",
 			"messageId", message.ID,
 			"webhookMessageId", resp.MessageID,
 		)
 
-		if markErr := p.service.MarkMessageAsSent(ctx, message.ID, resp.MessageID); markErr != nil {
-			p.logger.Error("failed to mark message as sent",
+		if markErr := p.service.MarkMessageAsSent(ctx, message.ID, resp.MessageID, resp.Provider); markErr != nil {
+			p.logger.ErrorContext(ctx, "failed to mark message as sent",
 				"messageId", message.ID,
 				"error", markErr,
 			)
 			continue
 		}
 
+		message.Status = StatusSent
+		message.WebhookMessageID = resp.MessageID
+		message.Provider = resp.Provider
+		p.notifier.Notify(ctx, &message, model.EventMessageSent, "")
+		p.publish(events.TypeMessageSent, &message, StatusSent, "")
+
 		cacheValue := &model.CacheMessage{
 			MessageID: resp.MessageID,
 			SentAt:    time.Now().UTC().Format(time.RFC3339),
@@ -134,13 +340,128 @@ func (p *MessageProcessor) processMessages(ctx context.Context) {
 
 		jsonValue, err := json.Marshal(cacheValue)
 		if err != nil {
-			p.logger.Error("failed to marshal cache value", "error", err)
+			p.logger.ErrorContext(ctx, "failed to marshal cache value", "error", err)
 			continue
 		}
 
-		if err := p.cache.Set(ctx, message.ID.Hex(), jsonValue); err != nil {
-			p.logger.Error("failed to cache message", "messageId", message.ID, "error", err)
+		if err := p.cache.Set(ctx, cache.TenantKey(message.TenantID, message.ID.Hex()), jsonValue); err != nil {
+			p.logger.ErrorContext(ctx, "failed to cache message", "messageId", message.ID, "error", err)
 			continue
 		}
 	}
+
+	// every tenant only learns the share of the batch its own messages took
+	tenants, counts := tenantCounts(messages)
+	for _, tenantID := range tenants {
+		p.events.Publish(events.Event{Type: events.TypeBatchFinished, TenantID: tenantID, Count: counts[tenantID]})
+	}
+	return len(messages)
+}
+
+// tenantCounts counts the messages of each tenant, tenants are returned in the order of their first message.
+func tenantCounts(messages []model.Message) ([]string, map[string]int) {
+	var tenants []string
+	counts := make(map[string]int)
+	for _, message := range messages {
+		if _, ok := counts[message.TenantID]; !ok {
+			tenants = append(tenants, message.TenantID)
+		}
+		counts[message.TenantID]++
+	}
+	return tenants, counts
+}
+
+// recordFailure counts a failed send attempt, the message is failed and its callback notified once it ran
+// out of attempts, it is retried on the next runs until then.
+func (p *MessageProcessor) recordFailure(ctx context.Context, message *model.Message, reason string) {
+	final := message.Attempts+1 >= p.maxAttempts()
+	if err := p.service.RecordSendFailure(ctx, message.ID, reason, final); err != nil {
+		p.logger.ErrorContext(ctx, "failed to record send failure", "messageId", message.ID, "error", err)
+		return
+	}
+
+	message.Attempts++
+	message.LastError = reason
+	if final {
+		p.logger.WarnContext(ctx, "giving up message", "messageId", message.ID, "attempts", message.Attempts)
+		message.Status = model.StatusFailed
+		p.notifier.Notify(ctx, message, model.EventMessageFailed, reason)
+	}
+	p.publish(events.TypeSendFailed, message, events.StatusFailed, reason)
+}
+
+func (p *MessageProcessor) maxAttempts() int {
+	if p.conf.MaxAttempts <= 0 {
+		return DefaultMaxAttempts
+	}
+	return p.conf.MaxAttempts
+}
+
+// expireMessages moves unsent messages past their expiry to the expired status and reports each of them to
+// its callback and the event stream like any other status change.
+func (p *MessageProcessor) expireMessages(ctx context.Context) {
+	expired, err := p.service.ExpireMessages(ctx, time.Now().UTC())
+	if err != nil {
+		p.logger.Error("failed to expire messages", "error", err)
+		return
+	}
+	if len(expired) > 0 {
+		p.logger.Warn("expired unsent messages", "count", len(expired))
+	}
+
+	for _, message := range expired {
+		ctx := messageContext(ctx, &message)
+		p.notifier.Notify(ctx, &message, model.EventMessageExpired, "message expired before it was sent")
+		p.publish(events.TypeExpired, &message, model.StatusExpired, "")
+	}
+}
+
+// deliverable moves messages to opted out numbers to the suppressed status. Messages whose
+// suppression state cannot be determined stay unsent and are retried on the next run.
+func (p *MessageProcessor) deliverable(ctx context.Context, message *model.Message) bool {
+	suppressed, err := p.suppressions.IsSuppressed(ctx, message.TenantID, message.PhoneNumber)
+	if err != nil {
+		p.logger.ErrorContext(ctx, "failed to check suppression list",
+			"messageId", message.ID,
+			"error", err,
+		)
+		return false
+	}
+	if !suppressed {
+		return true
+	}
+
+	if err := p.service.UpdateMessageStatus(ctx, message.ID, StatusSuppressed); err != nil {
+		p.logger.ErrorContext(ctx, "failed to mark message as suppressed",
+			"messageId", message.ID,
+			"error", err,
+		)
+		return false
+	}
+
+	p.logger.WarnContext(ctx, "message suppressed", "messageId", message.ID)
+	message.Status = StatusSuppressed
+	p.notifier.Notify(ctx, message, model.EventMessageSuppressed, "recipient is on the suppression list")
+	p.publish(events.TypeSuppressed, message, StatusSuppressed, "")
+	return false
+}
+
+func (p *MessageProcessor) publish(eventType string, message *model.Message, status, reason string) {
+	p.events.Publish(events.Event{
+		Type:        eventType,
+		TenantID:    message.TenantID,
+		MessageID:   message.ID.Hex(),
+		PhoneNumber: message.PhoneNumber,
+		Status:      status,
+		Error:       reason,
+	})
+}
+
+// messageContext carries the ID of the request that created the message, so the lines logged while
+// sending it and the webhook call can be correlated with that request.
+func messageContext(ctx context.Context, message *model.Message) context.Context {
+	if message.RequestID == "" {
+		return ctx
+	}
+	return requestid.NewContext(ctx, message.RequestID)
 }
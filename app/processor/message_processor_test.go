@@ -3,9 +3,14 @@ package processor
 import (
 	"context"
 	"log/slog"
+	"messaging-system/app/cache"
 	"messaging-system/app/dto"
+	"messaging-system/app/events"
 	"messaging-system/app/mocks"
 	"messaging-system/app/model"
+	"messaging-system/app/requestid"
+	"messaging-system/config"
+	"sync"
 	"testing"
 	"time"
 
@@ -14,48 +19,171 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+func TestNewMessageProcessor(t *testing.T) {
+	mockService, mockClient, mockCache, mockSuppressions, mockNotifier, mockCampaigns, logger := createMockServices(t)
+
+	processor, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+		mockCampaigns, nil, logger)
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultBatchSize, processor.conf.BatchSize)
+	assert.Equal(t, DefaultHighPriorityShare, processor.conf.HighPriorityShare)
+
+	for _, conf := range []*config.Processor{
+		{HighPriorityShare: -0.1},
+		{HighPriorityShare: 1.5},
+		{BatchSize: -1},
+	} {
+		_, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+			mockCampaigns, conf, logger)
+		assert.Error(t, err)
+	}
+}
+
 func TestMessageProcessor_Start(t *testing.T) {
-	mockService, mockClient, mockCache, logger := createMockServices(t)
-	processor := NewMessageProcessor(mockService, mockClient, mockCache, logger)
+	mockService, mockClient, mockCache, mockSuppressions, mockNotifier, mockCampaigns, logger := createMockServices(t)
+	processor, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+		mockCampaigns, &config.Processor{}, logger)
+	assert.NoError(t, err)
 
 	processor.Start(context.Background())
 
 	processor.ticker.Stop()
 
-	assert.True(t, processor.isRunning)
+	assert.True(t, processor.IsRunning())
 	assert.NotNil(t, processor.ticker)
 }
 
+func TestMessageProcessor_StartWithoutConfig(t *testing.T) {
+	mockService, mockClient, mockCache, mockSuppressions, mockNotifier, mockCampaigns, logger := createMockServices(t)
+	processor, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+		mockCampaigns, nil, logger)
+	assert.NoError(t, err)
+
+	processor.Start(context.Background())
+
+	processor.ticker.Stop()
+
+	assert.True(t, processor.IsRunning())
+}
+
 func TestMessageProcessor_Stop(t *testing.T) {
-	mockService, mockClient, mockCache, logger := createMockServices(t)
-	processor := NewMessageProcessor(mockService, mockClient, mockCache, logger)
+	mockService, mockClient, mockCache, mockSuppressions, mockNotifier, mockCampaigns, logger := createMockServices(t)
+	processor, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+		mockCampaigns, &config.Processor{}, logger)
+	assert.NoError(t, err)
 
 	ctx := context.Background()
 	processor.Start(ctx)
+	processor.Stop(ctx)
+	assert.False(t, processor.IsRunning())
+
+	// a stopped processor can be started again
+	processor.Start(ctx)
+	assert.True(t, processor.IsRunning())
+	processor.Stop(ctx)
+	assert.False(t, processor.IsRunning())
+}
+
+func TestMessageProcessor_ConcurrentStartStop(t *testing.T) {
+	mockService, mockClient, mockCache, mockSuppressions, mockNotifier, mockCampaigns, logger := createMockServices(t)
+	processor, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+		mockCampaigns, &config.Processor{}, logger)
+	assert.NoError(t, err)
+
+	// concurrent requests of the processor endpoint never start two loops or block a Stop forever
+	ctx := context.Background()
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(3)
+		go func() {
+			defer wg.Done()
+			processor.Start(ctx)
+		}()
+		go func() {
+			defer wg.Done()
+			processor.Stop(ctx)
+		}()
+		go func() {
+			defer wg.Done()
+			processor.IsRunning()
+		}()
+	}
+	wg.Wait()
+
+	processor.Stop(ctx)
+	assert.False(t, processor.IsRunning())
+}
+
+func TestMessageProcessor_ChangeStream(t *testing.T) {
+	mockService, mockClient, mockCache, mockSuppressions, mockNotifier, mockCampaigns, logger := createMockServices(t)
+	processor, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+		mockCampaigns, &config.Processor{ChangeStream: true}, logger)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	processed := make(chan struct{})
+	watchStopped := make(chan struct{})
+
+	mockService.
+		EXPECT().
+		ExpireMessages(gomock.Any(), gomock.Any()).
+		Return([]model.Message{}, nil).
+		AnyTimes()
+	mockService.
+		EXPECT().
+		WatchUnsentMessages(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, handle func(context.Context, *model.Message) error) error {
+			_ = handle(ctx, &model.Message{ID: primitive.NewObjectID(), Status: StatusUnsent})
+			<-ctx.Done()
+			close(watchStopped)
+			return ctx.Err()
+		})
+
+	mockService.
+		EXPECT().
+		GetMessagesByPriority(gomock.Any(), StatusUnsent, HighPriorityLane, nil, DefaultBatchSize).
+		DoAndReturn(func(context.Context, string, []int, []primitive.ObjectID, int) ([]model.Message, error) {
+			close(processed)
+			return []model.Message{}, nil
+		})
+	mockService.
+		EXPECT().
+		GetMessagesByPriority(gomock.Any(), StatusUnsent, StandardPriorityLane, nil, DefaultBatchSize).
+		Return([]model.Message{}, nil)
+
+	processor.Start(ctx)
+
+	select {
+	case <-processed:
+	case <-time.After(time.Second):
+		t.Fatal("inserted message did not trigger processing")
+	}
 
-	go func() {
-		time.Sleep(10 * time.Millisecond)
-		processor.Stop(ctx)
-	}()
+	processor.Stop(ctx)
 
-	time.Sleep(20 * time.Millisecond)
-	assert.False(t, processor.isRunning)
+	select {
+	case <-watchStopped:
+	case <-time.After(time.Second):
+		t.Fatal("change stream was not closed on stop")
+	}
 }
 
 func TestMessageProcessor_GetSentMessages(t *testing.T) {
-	mockService, mockClient, mockCache, logger := createMockServices(t)
-	processor := NewMessageProcessor(mockService, mockClient, mockCache, logger)
+	mockService, mockClient, mockCache, mockSuppressions, mockNotifier, mockCampaigns, logger := createMockServices(t)
+	processor, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+		mockCampaigns, &config.Processor{}, logger)
+	assert.NoError(t, err)
 
 	t.Run("success", func(t *testing.T) {
 		ctx := context.Background()
 		mockService.
 			EXPECT().
-			GetMessages(gomock.Any(), StatusSent, 10).
+			GetMessages(gomock.Any(), model.DefaultTenantID, StatusSent, 10).
 			Return([]model.Message{
 				{Content: "Message 1", Status: StatusSent},
 			}, nil)
 
-		messages, err := processor.GetSentMessages(ctx, 10)
+		messages, err := processor.GetSentMessages(ctx, model.DefaultTenantID, 10)
 		assert.NoError(t, err)
 		assert.NotNil(t, messages)
 	})
@@ -64,34 +192,104 @@ func TestMessageProcessor_GetSentMessages(t *testing.T) {
 		ctx := context.Background()
 		mockService.
 			EXPECT().
-			GetMessages(gomock.Any(), StatusSent, 10).
+			GetMessages(gomock.Any(), model.DefaultTenantID, StatusSent, 10).
 			Return(nil, assert.AnError)
 
-		messages, err := processor.GetSentMessages(ctx, 10)
+		messages, err := processor.GetSentMessages(ctx, model.DefaultTenantID, 10)
 		assert.Nil(t, messages)
 		assert.Error(t, err)
 	})
 }
 
+func TestMessageProcessor_ExportSentMessages(t *testing.T) {
+	mockService, mockClient, mockCache, mockSuppressions, mockNotifier, mockCampaigns, logger := createMockServices(t)
+	processor, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+		mockCampaigns, &config.Processor{}, logger)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	from := time.Now().Add(-time.Hour)
+	to := time.Now()
+	mockService.
+		EXPECT().
+		ForEachSentMessage(gomock.Any(), model.DefaultTenantID, from, to, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ string, _, _ time.Time,
+			handle func(context.Context, *model.Message) error) error {
+			return handle(ctx, &model.Message{Content: "Message 1", Status: StatusSent})
+		})
+
+	exported := []string{}
+	err = processor.ExportSentMessages(ctx, model.DefaultTenantID, from, to,
+		func(_ context.Context, message *model.Message) error {
+			exported = append(exported, message.Content)
+			return nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Message 1"}, exported)
+}
+
 func TestMessageProcessor_processMessages(t *testing.T) {
-	mockService, mockClient, mockCache, logger := createMockServices(t)
-	processor := NewMessageProcessor(mockService, mockClient, mockCache, logger)
+	mockService, mockClient, mockCache, mockSuppressions, mockNotifier, mockCampaigns, logger := createMockServices(t)
+	processor, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+		mockCampaigns, &config.Processor{}, logger)
+	assert.NoError(t, err)
 
 	ctx := context.Background()
-	t.Run("fetch messages error", func(t *testing.T) {
+	t.Run("expired messages are moved out before fetching and reported", func(t *testing.T) {
+		expired := model.Message{ID: primitive.NewObjectID(), TenantID: "retail", PhoneNumber: "+905551112233",
+			CallbackURL: "https://hooks.example.com/status", Status: model.StatusExpired}
+		stream, cancel := processor.Subscribe(events.Filter{TenantID: "retail", Status: model.StatusExpired})
+		defer cancel()
+
+		gomock.InOrder(
+			mockService.
+				EXPECT().
+				ExpireMessages(gomock.Any(), gomock.Any()).
+				Return([]model.Message{expired}, nil),
+			mockNotifier.
+				EXPECT().
+				Notify(gomock.Any(), gomock.Any(), model.EventMessageExpired, gomock.Any()).
+				Do(func(_ context.Context, message *model.Message, _, _ string) {
+					assert.Equal(t, expired.ID, message.ID)
+					assert.Equal(t, model.StatusExpired, message.Status)
+				}),
+		)
+		expectBatch(mockService, DefaultBatchSize, []model.Message{}, []model.Message{})
+
+		processor.processMessages(ctx)
+
+		event := <-stream
+		assert.Equal(t, events.TypeExpired, event.Type)
+		assert.Equal(t, expired.ID.Hex(), event.MessageID)
+	})
+
+	t.Run("expire error does not stop processing", func(t *testing.T) {
 		mockService.
 			EXPECT().
-			GetMessages(gomock.Any(), StatusUnsent, MessageLimit).
+			ExpireMessages(gomock.Any(), gomock.Any()).
 			Return(nil, assert.AnError)
+		expectBatch(mockService, DefaultBatchSize, []model.Message{}, []model.Message{})
 
 		processor.processMessages(ctx)
 	})
 
-	t.Run("no messages to process", func(t *testing.T) {
+	mockService.
+		EXPECT().
+		ExpireMessages(gomock.Any(), gomock.Any()).
+		Return([]model.Message{}, nil).
+		AnyTimes()
+
+	t.Run("fetch messages error", func(t *testing.T) {
 		mockService.
 			EXPECT().
-			GetMessages(gomock.Any(), StatusUnsent, MessageLimit).
-			Return([]model.Message{}, nil)
+			GetMessagesByPriority(gomock.Any(), StatusUnsent, HighPriorityLane, nil, DefaultBatchSize).
+			Return(nil, assert.AnError)
+
+		processor.processMessages(ctx)
+	})
+
+	t.Run("no messages to process", func(t *testing.T) {
+		expectBatch(mockService, DefaultBatchSize, []model.Message{}, []model.Message{})
 
 		processor.processMessages(ctx)
 	})
@@ -99,29 +297,86 @@ func TestMessageProcessor_processMessages(t *testing.T) {
 	t.Run("process messages successfully", func(t *testing.T) {
 		message := model.Message{
 			ID:          primitive.NewObjectID(),
+			TenantID:    model.DefaultTenantID,
 			PhoneNumber: "+90555",
 			Content:     "Hello",
 			Status:      StatusUnsent,
+			RequestID:   "request-id",
 		}
 
+		expectBatch(mockService, DefaultBatchSize, []model.Message{}, []model.Message{message})
+		mockSuppressions.
+			EXPECT().
+			IsSuppressed(gomock.Any(), message.TenantID, message.PhoneNumber).
+			Return(false, nil)
+
+		mockClient.
+			EXPECT().
+			SendMessage(gomock.Any(), message.TenantID, gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ string, _ *dto.MessageRequest) (*dto.MessageResponse, error) {
+				// the webhook call carries the request that created the message
+				assert.Equal(t, "request-id", requestid.FromContext(ctx))
+				return &dto.MessageResponse{
+					Message:   "accepted",
+					MessageID: "webhook123",
+					Provider:  "sms.example.com",
+				}, nil
+			})
+
 		mockService.
 			EXPECT().
-			GetMessages(gomock.Any(), StatusUnsent, MessageLimit).
-			Return([]model.Message{message}, nil)
+			MarkMessageAsSent(gomock.Any(), message.ID, "webhook123", "sms.example.com").
+			Return(nil)
+
+		mockNotifier.
+			EXPECT().
+			Notify(gomock.Any(), gomock.Any(), model.EventMessageSent, "").
+			Do(func(_ context.Context, message *model.Message, _, _ string) {
+				assert.Equal(t, StatusSent, message.Status)
+				assert.Equal(t, "webhook123", message.WebhookMessageID)
+				assert.Equal(t, "sms.example.com", message.Provider)
+			})
+
+		mockCache.
+			EXPECT().
+			Set(gomock.Any(), cache.TenantKey(message.TenantID, message.ID.Hex()), gomock.Any()).
+			Return(nil)
+
+		processor.processMessages(ctx)
+	})
+
+	t.Run("process message of another tenant", func(t *testing.T) {
+		message := model.Message{
+			ID:          primitive.NewObjectID(),
+			TenantID:    "retail",
+			PhoneNumber: "+90555",
+			Content:     "Hello",
+			Status:      StatusUnsent,
+		}
+
+		expectBatch(mockService, DefaultBatchSize, []model.Message{}, []model.Message{message})
+		mockSuppressions.
+			EXPECT().
+			IsSuppressed(gomock.Any(), "retail", message.PhoneNumber).
+			Return(false, nil)
 
 		mockClient.
 			EXPECT().
-			SendMessage(gomock.Any()).
-			Return(&dto.MessageResponse{Message: "accepted", MessageID: "webhook123"}, nil)
+			SendMessage(gomock.Any(), "retail", gomock.Any()).
+			Return(&dto.MessageResponse{Message: "accepted", MessageID: "webhook456"}, nil)
 
 		mockService.
 			EXPECT().
-			MarkMessageAsSent(gomock.Any(), message.ID, "webhook123").
+			MarkMessageAsSent(gomock.Any(), message.ID, "webhook456", gomock.Any()).
 			Return(nil)
 
+		mockNotifier.
+			EXPECT().
+			Notify(gomock.Any(), gomock.Any(), model.EventMessageSent, "")
+
 		mockCache.
 			EXPECT().
-			Set(gomock.Any(), message.ID.Hex(), gomock.Any()).
+			Set(gomock.Any(), "tenant:retail:"+message.ID.Hex(), gomock.Any()).
 			Return(nil)
 
 		processor.processMessages(ctx)
@@ -130,91 +385,418 @@ func TestMessageProcessor_processMessages(t *testing.T) {
 	t.Run("send message error", func(t *testing.T) {
 		message := model.Message{
 			ID:          primitive.NewObjectID(),
+			TenantID:    model.DefaultTenantID,
 			PhoneNumber: "+90555",
 			Content:     "Hello",
 			Status:      StatusUnsent,
 		}
 
+		expectBatch(mockService, DefaultBatchSize, []model.Message{}, []model.Message{message})
+		mockSuppressions.
+			EXPECT().
+			IsSuppressed(gomock.Any(), message.TenantID, message.PhoneNumber).
+			Return(false, nil)
+
+		mockClient.
+			EXPECT().
+			SendMessage(gomock.Any(), message.TenantID, gomock.Any()).
+			Return(nil, assert.AnError)
+
 		mockService.
 			EXPECT().
-			GetMessages(gomock.Any(), StatusUnsent, MessageLimit).
-			Return([]model.Message{message}, nil)
+			RecordSendFailure(gomock.Any(), message.ID, assert.AnError.Error(), false).
+			Return(nil)
+
+		processor.processMessages(ctx)
+	})
+
+	t.Run("send message error of the final attempt", func(t *testing.T) {
+		message := model.Message{
+			ID:          primitive.NewObjectID(),
+			TenantID:    model.DefaultTenantID,
+			PhoneNumber: "+90555",
+			Content:     "Hello",
+			Status:      StatusUnsent,
+			Attempts:    DefaultMaxAttempts - 1,
+		}
+
+		expectBatch(mockService, DefaultBatchSize, []model.Message{}, []model.Message{message})
+		mockSuppressions.
+			EXPECT().
+			IsSuppressed(gomock.Any(), message.TenantID, message.PhoneNumber).
+			Return(false, nil)
 
 		mockClient.
 			EXPECT().
-			SendMessage(gomock.Any()).
+			SendMessage(gomock.Any(), message.TenantID, gomock.Any()).
 			Return(nil, assert.AnError)
 
+		mockService.
+			EXPECT().
+			RecordSendFailure(gomock.Any(), message.ID, assert.AnError.Error(), true).
+			Return(nil)
+
+		mockNotifier.
+			EXPECT().
+			Notify(gomock.Any(), gomock.Any(), model.EventMessageFailed, assert.AnError.Error()).
+			Do(func(_ context.Context, failed *model.Message, _, _ string) {
+				assert.Equal(t, model.StatusFailed, failed.Status)
+			})
+
 		processor.processMessages(ctx)
 	})
 
 	t.Run("mark message as sent error", func(t *testing.T) {
 		message := model.Message{
 			ID:          primitive.NewObjectID(),
+			TenantID:    model.DefaultTenantID,
 			PhoneNumber: "+90555",
 			Content:     "Hello",
 			Status:      StatusUnsent,
 		}
 
-		mockService.
+		expectBatch(mockService, DefaultBatchSize, []model.Message{}, []model.Message{message})
+		mockSuppressions.
 			EXPECT().
-			GetMessages(gomock.Any(), StatusUnsent, MessageLimit).
-			Return([]model.Message{message}, nil)
+			IsSuppressed(gomock.Any(), message.TenantID, message.PhoneNumber).
+			Return(false, nil)
 
 		mockClient.
 			EXPECT().
-			SendMessage(gomock.Any()).
+			SendMessage(gomock.Any(), message.TenantID, gomock.Any()).
 			Return(&dto.MessageResponse{Message: "accepted", MessageID: "webhook123"}, nil)
 
 		mockService.
 			EXPECT().
-			MarkMessageAsSent(gomock.Any(), message.ID, "webhook123").
+			MarkMessageAsSent(gomock.Any(), message.ID, "webhook123", gomock.Any()).
 			Return(assert.AnError)
 
 		processor.processMessages(ctx)
 	})
 
-	t.Run("cache set error", func(t *testing.T) {
+	t.Run("suppressed message is not sent", func(t *testing.T) {
 		message := model.Message{
 			ID:          primitive.NewObjectID(),
+			TenantID:    model.DefaultTenantID,
 			PhoneNumber: "+90555",
 			Content:     "Hello",
 			Status:      StatusUnsent,
 		}
 
+		expectBatch(mockService, DefaultBatchSize, []model.Message{}, []model.Message{message})
+		mockSuppressions.
+			EXPECT().
+			IsSuppressed(gomock.Any(), message.TenantID, message.PhoneNumber).
+			Return(true, nil)
+
 		mockService.
 			EXPECT().
-			GetMessages(gomock.Any(), StatusUnsent, MessageLimit).
-			Return([]model.Message{message}, nil)
+			UpdateMessageStatus(gomock.Any(), message.ID, StatusSuppressed).
+			Return(nil)
+
+		mockNotifier.
+			EXPECT().
+			Notify(gomock.Any(), gomock.Any(), model.EventMessageSuppressed, gomock.Any())
+
+		processor.processMessages(ctx)
+	})
+
+	t.Run("suppression check error keeps message unsent", func(t *testing.T) {
+		message := model.Message{
+			ID:          primitive.NewObjectID(),
+			TenantID:    model.DefaultTenantID,
+			PhoneNumber: "+90555",
+			Content:     "Hello",
+			Status:      StatusUnsent,
+		}
+
+		expectBatch(mockService, DefaultBatchSize, []model.Message{}, []model.Message{message})
+		mockSuppressions.
+			EXPECT().
+			IsSuppressed(gomock.Any(), message.TenantID, message.PhoneNumber).
+			Return(false, assert.AnError)
+
+		processor.processMessages(ctx)
+	})
+
+	t.Run("cache set error", func(t *testing.T) {
+		message := model.Message{
+			ID:          primitive.NewObjectID(),
+			TenantID:    model.DefaultTenantID,
+			PhoneNumber: "+90555",
+			Content:     "Hello",
+			Status:      StatusUnsent,
+		}
+
+		expectBatch(mockService, DefaultBatchSize, []model.Message{}, []model.Message{message})
+		mockSuppressions.
+			EXPECT().
+			IsSuppressed(gomock.Any(), message.TenantID, message.PhoneNumber).
+			Return(false, nil)
 
 		mockClient.
 			EXPECT().
-			SendMessage(gomock.Any()).
+			SendMessage(gomock.Any(), message.TenantID, gomock.Any()).
 			Return(&dto.MessageResponse{Message: "accepted", MessageID: "webhook123"}, nil)
 
 		mockService.
 			EXPECT().
-			MarkMessageAsSent(gomock.Any(), message.ID, "webhook123").
+			MarkMessageAsSent(gomock.Any(), message.ID, "webhook123", gomock.Any()).
 			Return(nil)
 
+		mockNotifier.
+			EXPECT().
+			Notify(gomock.Any(), gomock.Any(), model.EventMessageSent, "").
+			Do(func(_ context.Context, message *model.Message, _, _ string) {
+				assert.Equal(t, StatusSent, message.Status)
+				assert.Equal(t, "webhook123", message.WebhookMessageID)
+			})
+
 		mockCache.
 			EXPECT().
-			Set(gomock.Any(), message.ID.Hex(), gomock.Any()).
+			Set(gomock.Any(), cache.TenantKey(message.TenantID, message.ID.Hex()), gomock.Any()).
 			Return(assert.AnError)
 
 		processor.processMessages(ctx)
 	})
 }
 
+func TestMessageProcessor_Subscribe(t *testing.T) {
+	mockService, mockClient, mockCache, mockSuppressions, mockNotifier, mockCampaigns, logger := createMockServices(t)
+	processor, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+		mockCampaigns, &config.Processor{}, logger)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	message := model.Message{ID: primitive.NewObjectID(), TenantID: model.DefaultTenantID, PhoneNumber: "+90555", Content: "Hello"}
+
+	mockService.EXPECT().ExpireMessages(gomock.Any(), gomock.Any()).Return([]model.Message{}, nil)
+	expectBatch(mockService, DefaultBatchSize, []model.Message{}, []model.Message{message})
+	mockSuppressions.EXPECT().IsSuppressed(gomock.Any(), message.TenantID, message.PhoneNumber).Return(false, nil)
+	mockClient.EXPECT().SendMessage(gomock.Any(), message.TenantID, gomock.Any()).Return(nil, assert.AnError)
+	mockService.EXPECT().RecordSendFailure(gomock.Any(), message.ID, gomock.Any(), false).Return(nil)
+
+	all, cancelAll := processor.Subscribe(events.Filter{})
+	defer cancelAll()
+	sent, cancelSent := processor.Subscribe(events.Filter{Status: StatusSent})
+	defer cancelSent()
+
+	processor.processMessages(ctx)
+
+	var types []string
+	for len(all) > 0 {
+		types = append(types, (<-all).Type)
+	}
+	assert.Equal(t, []string{events.TypeTickStarted, events.TypeSendFailed, events.TypeBatchFinished}, types)
+	assert.Empty(t, sent)
+}
+
+func TestMessageProcessor_BatchFinishedPerTenant(t *testing.T) {
+	mockService, mockClient, mockCache, mockSuppressions, mockNotifier, mockCampaigns, logger := createMockServices(t)
+	processor, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+		mockCampaigns, &config.Processor{}, logger)
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	retail := model.Message{ID: primitive.NewObjectID(), TenantID: "retail", PhoneNumber: "+90555", Content: "Hello"}
+	logistics := model.Message{ID: primitive.NewObjectID(), TenantID: "logistics", PhoneNumber: "+90556",
+		Content: "Hello"}
+
+	mockService.EXPECT().ExpireMessages(gomock.Any(), gomock.Any()).Return([]model.Message{}, nil)
+	expectBatch(mockService, DefaultBatchSize, []model.Message{}, []model.Message{retail, logistics})
+	mockSuppressions.EXPECT().IsSuppressed(gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).Times(2)
+	mockService.EXPECT().UpdateMessageStatus(gomock.Any(), gomock.Any(), StatusSuppressed).Return(nil).Times(2)
+	mockNotifier.EXPECT().Notify(gomock.Any(), gomock.Any(), model.EventMessageSuppressed, gomock.Any()).Times(2)
+
+	stream, cancel := processor.Subscribe(events.Filter{TenantID: "retail"})
+	defer cancel()
+
+	processor.processMessages(ctx)
+
+	var received []events.Event
+	for len(stream) > 0 {
+		received = append(received, <-stream)
+	}
+	assert.Len(t, received, 3)
+	assert.Equal(t, events.TypeTickStarted, received[0].Type)
+	assert.Equal(t, "retail", received[1].TenantID)
+	assert.Equal(t, events.Event{Type: events.TypeBatchFinished, TenantID: "retail", Count: 1},
+		events.Event{Type: received[2].Type, TenantID: received[2].TenantID, Count: received[2].Count})
+}
+
+func TestMessageProcessor_BatchFinishedHidesFetchErrors(t *testing.T) {
+	mockService, mockClient, mockCache, mockSuppressions, mockNotifier, mockCampaigns, logger := createMockServices(t)
+	processor, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+		mockCampaigns, &config.Processor{}, logger)
+	assert.NoError(t, err)
+
+	mockService.EXPECT().ExpireMessages(gomock.Any(), gomock.Any()).Return([]model.Message{}, nil)
+	mockService.
+		EXPECT().
+		GetMessagesByPriority(gomock.Any(), StatusUnsent, HighPriorityLane, nil, DefaultBatchSize).
+		Return(nil, assert.AnError)
+
+	stream, cancel := processor.Subscribe(events.Filter{TenantID: "retail"})
+	defer cancel()
+
+	processor.processMessages(context.Background())
+
+	var received []events.Event
+	for len(stream) > 0 {
+		received = append(received, <-stream)
+	}
+	assert.Len(t, received, 2)
+	assert.Equal(t, events.TypeBatchFinished, received[1].Type)
+	assert.Equal(t, fetchFailed, received[1].Error)
+}
+
+func TestMessageProcessor_nextBatch(t *testing.T) {
+	ctx := context.Background()
+	newMessages := func(priority, count int) []model.Message {
+		messages := make([]model.Message, 0, count)
+		for i := 0; i < count; i++ {
+			messages = append(messages, model.Message{ID: primitive.NewObjectID(), Priority: priority})
+		}
+		return messages
+	}
+	countHigh := func(messages []model.Message) int {
+		count := 0
+		for _, message := range messages {
+			if message.Priority == dto.PriorityHigh {
+				count++
+			}
+		}
+		return count
+	}
+
+	tests := []struct {
+		name          string
+		batchSize     int
+		share         float64
+		high          int
+		standard      int
+		expectedHigh  int
+		expectedTotal int
+	}{
+		{name: "both lanes get their share", share: 0.5, high: 2, standard: 2, expectedHigh: 1, expectedTotal: 2},
+		{name: "standard lane fills unused high slots", share: 0.5, high: 0, standard: 2, expectedHigh: 0, expectedTotal: 2},
+		{name: "high lane fills unused standard slots", share: 0.5, high: 2, standard: 0, expectedHigh: 2, expectedTotal: 2},
+		{name: "unset share reserves the default share", share: 0, high: 2, standard: 2, expectedHigh: 1, expectedTotal: 2},
+		{name: "full reservation prefers high lane", share: 1, high: 2, standard: 2, expectedHigh: 2, expectedTotal: 2},
+		{name: "partial batch", share: 0.5, high: 1, standard: 0, expectedHigh: 1, expectedTotal: 1},
+		{name: "share of a configured batch size", batchSize: 10, share: 0.3, high: 10, standard: 10, expectedHigh: 3,
+			expectedTotal: 10},
+		{name: "small share keeps one high slot", batchSize: 10, share: 0.01, high: 5, standard: 10, expectedHigh: 1,
+			expectedTotal: 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockService, mockClient, mockCache, mockSuppressions, mockNotifier, mockCampaigns, logger := createMockServices(t)
+			processor, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+				mockCampaigns, &config.Processor{BatchSize: tt.batchSize, HighPriorityShare: tt.share}, logger)
+			assert.NoError(t, err)
+			batchSize := tt.batchSize
+			if batchSize == 0 {
+				batchSize = DefaultBatchSize
+			}
+			expectBatch(mockService, batchSize, newMessages(dto.PriorityHigh, tt.high),
+				newMessages(dto.PriorityNormal, tt.standard))
+
+			messages, err := processor.nextBatch(ctx)
+			assert.NoError(t, err)
+			assert.Len(t, messages, tt.expectedTotal)
+			assert.Equal(t, tt.expectedHigh, countHigh(messages))
+		})
+	}
+
+	t.Run("messages of held campaigns are left out", func(t *testing.T) {
+		mockService, mockClient, mockCache, mockSuppressions, mockNotifier, _, logger := createMockServices(t)
+		mockCampaigns := mocks.NewMockICampaignGate(gomock.NewController(t))
+		processor, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+			mockCampaigns, &config.Processor{}, logger)
+		assert.NoError(t, err)
+		held := []primitive.ObjectID{primitive.NewObjectID()}
+		mockCampaigns.
+			EXPECT().
+			HeldCampaigns(gomock.Any(), gomock.Any()).
+			Return(held, nil)
+		mockService.
+			EXPECT().
+			GetMessagesByPriority(gomock.Any(), StatusUnsent, HighPriorityLane, held, DefaultBatchSize).
+			Return([]model.Message{}, nil)
+		mockService.
+			EXPECT().
+			GetMessagesByPriority(gomock.Any(), StatusUnsent, StandardPriorityLane, held, DefaultBatchSize).
+			Return([]model.Message{}, nil)
+
+		messages, err := processor.nextBatch(ctx)
+		assert.NoError(t, err)
+		assert.Empty(t, messages)
+	})
+
+	t.Run("held campaigns error", func(t *testing.T) {
+		mockService, mockClient, mockCache, mockSuppressions, mockNotifier, _, logger := createMockServices(t)
+		mockCampaigns := mocks.NewMockICampaignGate(gomock.NewController(t))
+		processor, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+			mockCampaigns, &config.Processor{}, logger)
+		assert.NoError(t, err)
+		mockCampaigns.
+			EXPECT().
+			HeldCampaigns(gomock.Any(), gomock.Any()).
+			Return(nil, assert.AnError)
+
+		messages, err := processor.nextBatch(ctx)
+		assert.Error(t, err)
+		assert.Nil(t, messages)
+	})
+
+	t.Run("standard lane error", func(t *testing.T) {
+		mockService, mockClient, mockCache, mockSuppressions, mockNotifier, mockCampaigns, logger := createMockServices(t)
+		processor, err := NewMessageProcessor(mockService, mockClient, mockCache, mockSuppressions, mockNotifier,
+			mockCampaigns, &config.Processor{}, logger)
+		assert.NoError(t, err)
+		mockService.
+			EXPECT().
+			GetMessagesByPriority(gomock.Any(), StatusUnsent, HighPriorityLane, nil, DefaultBatchSize).
+			Return([]model.Message{}, nil)
+		mockService.
+			EXPECT().
+			GetMessagesByPriority(gomock.Any(), StatusUnsent, StandardPriorityLane, nil, DefaultBatchSize).
+			Return(nil, assert.AnError)
+
+		messages, err := processor.nextBatch(ctx)
+		assert.Error(t, err)
+		assert.Nil(t, messages)
+	})
+}
+
+func expectBatch(mockService *mocks.MockIMessageService, batchSize int, high, standard []model.Message) {
+	mockService.
+		EXPECT().
+		GetMessagesByPriority(gomock.Any(), StatusUnsent, HighPriorityLane, nil, batchSize).
+		Return(high, nil)
+	mockService.
+		EXPECT().
+		GetMessagesByPriority(gomock.Any(), StatusUnsent, StandardPriorityLane, nil, batchSize).
+		Return(standard, nil)
+}
+
 func createMockServices(t *testing.T) (*mocks.MockIMessageService, *mocks.MockIClient,
-	*mocks.MockICacheService, *slog.Logger) {
+	*mocks.MockICacheService, *mocks.MockISuppressionChecker, *mocks.MockINotifier, *mocks.MockICampaignGate,
+	*slog.Logger) {
 	t.Helper()
 	mockController := gomock.NewController(t)
 
 	mockService := mocks.NewMockIMessageService(mockController)
 	mockClient := mocks.NewMockIClient(mockController)
 	mockCache := mocks.NewMockICacheService(mockController)
+	mockSuppressions := mocks.NewMockISuppressionChecker(mockController)
+	mockNotifier := mocks.NewMockINotifier(mockController)
+	// no campaign is held unless a test sets up its own gate
+	mockCampaigns := mocks.NewMockICampaignGate(mockController)
+	mockCampaigns.EXPECT().HeldCampaigns(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 	logger := slog.Default()
 
-	return mockService, mockClient, mockCache, logger
+	return mockService, mockClient, mockCache, mockSuppressions, mockNotifier, mockCampaigns, logger
 }
@@ -0,0 +1,194 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"messaging-system/app/phone"
+	"messaging-system/config"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	StreamName      = "outbox-relay"
+	OutboxBatchSize = 100
+)
+
+type IOutboxRepository interface {
+	GetPendingOutboxMessages(ctx context.Context, limit int) ([]model.OutboxMessage, error)
+	WatchOutbox(ctx context.Context, resumeToken bson.Raw, startAt time.Time,
+		handle func(ctx context.Context, outbox *model.OutboxMessage, token bson.Raw) error) error
+	RelayOutboxMessage(ctx context.Context, outboxID primitive.ObjectID, message *model.Message) error
+	RejectOutboxMessage(ctx context.Context, outboxID primitive.ObjectID, normalizedPhoneNumber, reason string) error
+	GetResumeToken(ctx context.Context, streamName string) (bson.Raw, error)
+	SaveResumeToken(ctx context.Context, streamName string, token bson.Raw) error
+}
+
+type IOutboxQuota interface {
+	Reserve(ctx context.Context, tenantID string, now time.Time) error
+	Release(ctx context.Context, tenantID string, now time.Time) error
+}
+
+// OutboxRelay moves rows written to the outbox collection by other services into the
+// messages collection. It tails the outbox with a change stream and persists the resume
+// token after every relayed row, so a restart continues exactly where it stopped.
+type OutboxRelay struct {
+	repo          IOutboxRepository
+	phones        *phone.Parser
+	quota         IOutboxQuota
+	retryInterval time.Duration
+	maxSegments   int
+	logger        *slog.Logger
+}
+
+func NewOutboxRelay(repo IOutboxRepository, phones *phone.Parser, quota IOutboxQuota, conf *config.Outbox,
+	messageConf *config.Message, logger *slog.Logger) *OutboxRelay {
+	maxSegments := dto.DefaultMaxSegments
+	if messageConf != nil {
+		maxSegments = messageConf.MaxSegments
+	}
+
+	return &OutboxRelay{
+		repo:          repo,
+		phones:        phones,
+		quota:         quota,
+		retryInterval: conf.RetryInterval,
+		maxSegments:   maxSegments,
+		logger:        logger,
+	}
+}
+
+// Run relays outbox rows until ctx is cancelled, restarting the change stream after failures.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	r.logger.Info("Starting outbox relay")
+	for {
+		if err := r.relay(ctx); err != nil && ctx.Err() == nil {
+			r.logger.Error("outbox relay failed, restarting", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Warn("Stopping outbox relay...")
+			return
+		case <-time.After(r.retryInterval):
+		}
+	}
+}
+
+func (r *OutboxRelay) relay(ctx context.Context) error {
+	token, err := r.repo.GetResumeToken(ctx, StreamName)
+	if err != nil {
+		return err
+	}
+
+	// rows inserted while sweeping are picked up by the stream starting at startAt
+	startAt := time.Now()
+	if err := r.sweep(ctx); err != nil {
+		return err
+	}
+
+	return r.repo.WatchOutbox(ctx, token, startAt, r.handleChange)
+}
+
+// sweep relays rows that are still pending, e.g. written while the relay was down
+// for longer than the oplog window.
+func (r *OutboxRelay) sweep(ctx context.Context) error {
+	for {
+		pending, err := r.repo.GetPendingOutboxMessages(ctx, OutboxBatchSize)
+		if err != nil {
+			return err
+		}
+
+		for i := range pending {
+			if err := r.relayMessage(ctx, &pending[i]); err != nil {
+				return err
+			}
+		}
+
+		if len(pending) < OutboxBatchSize {
+			return nil
+		}
+	}
+}
+
+func (r *OutboxRelay) handleChange(ctx context.Context, outbox *model.OutboxMessage, token bson.Raw) error {
+	if err := r.relayMessage(ctx, outbox); err != nil {
+		return err
+	}
+
+	return r.repo.SaveResumeToken(ctx, StreamName, token)
+}
+
+func (r *OutboxRelay) relayMessage(ctx context.Context, outbox *model.OutboxMessage) error {
+	message, err := r.convert(outbox)
+	if err != nil {
+		r.logger.Warn("rejecting invalid outbox message", "outboxId", outbox.ID, "error", err)
+		return r.repo.RejectOutboxMessage(ctx, outbox.ID, r.normalize(outbox.PhoneNumber), err.Error())
+	}
+
+	// outbox rows count against the daily quota of their tenant like messages created through the API
+	now := time.Now().UTC()
+	err = r.quota.Reserve(ctx, message.TenantID, now)
+	if errors.Is(err, model.ErrQuotaExceeded) {
+		r.logger.Warn("rejecting outbox message over quota", "outboxId", outbox.ID, "tenantId", message.TenantID)
+		return r.repo.RejectOutboxMessage(ctx, outbox.ID, message.PhoneNumber, err.Error())
+	}
+	if err != nil {
+		return err
+	}
+
+	// streamed inserts of rows the startup sweep already relayed arrive again, their reservation is released
+	// since no message was created for it
+	err = r.repo.RelayOutboxMessage(ctx, outbox.ID, message)
+	if err != nil {
+		if releaseErr := r.quota.Release(ctx, message.TenantID, now); releaseErr != nil {
+			return errors.Join(err, releaseErr)
+		}
+		if errors.Is(err, model.ErrAlreadyExists) {
+			r.logger.Info("outbox message already relayed", "outboxId", outbox.ID)
+			return nil
+		}
+		return err
+	}
+
+	r.logger.Info("outbox message relayed", "outboxId", outbox.ID)
+	return nil
+}
+
+// normalize returns the E.164 form of the phone number of a rejected row, rows rejected for their content
+// may still hold a valid number. It is empty when the number does not parse.
+func (r *OutboxRelay) normalize(phoneNumber string) string {
+	number, err := r.phones.Parse(phoneNumber)
+	if err != nil {
+		return ""
+	}
+	return number.E164
+}
+
+// convert turns an outbox row into a validated message with a normalized phone number.
+func (r *OutboxRelay) convert(outbox *model.OutboxMessage) (*model.Message, error) {
+	number, err := r.phones.Parse(outbox.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	message := outbox.ToMessage()
+	message.PhoneNumber = number.E164
+	message.RawPhoneNumber = outbox.PhoneNumber
+	message.Country = number.Country
+
+	request := message.ConvertToRequest()
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+	if err := request.ValidateLength(r.maxSegments); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
@@ -0,0 +1,217 @@
+package relay
+
+import (
+	"context"
+	"log/slog"
+	"messaging-system/app/dto"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"messaging-system/app/phone"
+	"messaging-system/app/sms"
+	"messaging-system/config"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestOutboxRelay_relay(t *testing.T) {
+	ctx := context.Background()
+	validOutbox := model.OutboxMessage{
+		ID:          primitive.NewObjectID(),
+		PhoneNumber: "+905551112233",
+		Content:     "Hello",
+		Status:      model.OutboxStatusPending,
+	}
+	rawToken, _ := bson.Marshal(bson.M{"_data": "token-1"})
+	token := bson.Raw(rawToken)
+
+	t.Run("resume token error", func(t *testing.T) {
+		mockRepo, _, outboxRelay := createOutboxRelay(t)
+		mockRepo.EXPECT().GetResumeToken(gomock.Any(), StreamName).Return(nil, assert.AnError)
+
+		assert.Error(t, outboxRelay.relay(ctx))
+	})
+
+	t.Run("sweeps pending rows and relays streamed rows", func(t *testing.T) {
+		mockRepo, mockQuota, outboxRelay := createOutboxRelay(t)
+		streamed := model.OutboxMessage{
+			ID:          primitive.NewObjectID(),
+			PhoneNumber: "+905551112244",
+			Content:     "World",
+			Status:      model.OutboxStatusPending,
+		}
+
+		mockQuota.EXPECT().Reserve(gomock.Any(), model.DefaultTenantID, gomock.Any()).Return(nil).Times(2)
+		gomock.InOrder(
+			mockRepo.EXPECT().GetResumeToken(gomock.Any(), StreamName).Return(nil, nil),
+			mockRepo.EXPECT().GetPendingOutboxMessages(gomock.Any(), OutboxBatchSize).
+				Return([]model.OutboxMessage{validOutbox}, nil),
+			mockRepo.EXPECT().RelayOutboxMessage(gomock.Any(), validOutbox.ID, gomock.Any()).Return(nil),
+			mockRepo.EXPECT().WatchOutbox(gomock.Any(), nil, gomock.Any(), gomock.Any()).
+				DoAndReturn(func(ctx context.Context, _ bson.Raw, _ time.Time,
+					handle func(context.Context, *model.OutboxMessage, bson.Raw) error) error {
+					return handle(ctx, &streamed, token)
+				}),
+			mockRepo.EXPECT().RelayOutboxMessage(gomock.Any(), streamed.ID, gomock.Any()).Return(nil),
+			mockRepo.EXPECT().SaveResumeToken(gomock.Any(), StreamName, token).Return(nil),
+		)
+
+		assert.NoError(t, outboxRelay.relay(ctx))
+	})
+
+	t.Run("resumes the stream from the persisted token", func(t *testing.T) {
+		mockRepo, _, outboxRelay := createOutboxRelay(t)
+
+		mockRepo.EXPECT().GetResumeToken(gomock.Any(), StreamName).Return(token, nil)
+		mockRepo.EXPECT().GetPendingOutboxMessages(gomock.Any(), OutboxBatchSize).
+			Return([]model.OutboxMessage{}, nil)
+		mockRepo.EXPECT().WatchOutbox(gomock.Any(), token, gomock.Any(), gomock.Any()).Return(nil)
+
+		assert.NoError(t, outboxRelay.relay(ctx))
+	})
+
+	t.Run("rejects invalid rows", func(t *testing.T) {
+		mockRepo, _, outboxRelay := createOutboxRelay(t)
+		invalid := model.OutboxMessage{ID: primitive.NewObjectID(), PhoneNumber: "123", Content: "Hello"}
+
+		mockRepo.EXPECT().GetResumeToken(gomock.Any(), StreamName).Return(nil, nil)
+		mockRepo.EXPECT().GetPendingOutboxMessages(gomock.Any(), OutboxBatchSize).
+			Return([]model.OutboxMessage{invalid}, nil)
+		mockRepo.EXPECT().RejectOutboxMessage(gomock.Any(), invalid.ID, "", gomock.Any()).Return(nil)
+		mockRepo.EXPECT().WatchOutbox(gomock.Any(), nil, gomock.Any(), gomock.Any()).Return(nil)
+
+		assert.NoError(t, outboxRelay.relay(ctx))
+	})
+
+	t.Run("normalizes the phone number", func(t *testing.T) {
+		mockRepo, mockQuota, outboxRelay := createOutboxRelay(t)
+		national := model.OutboxMessage{ID: primitive.NewObjectID(), PhoneNumber: "0555 111 22 33", Content: "Hello"}
+
+		mockQuota.EXPECT().Reserve(gomock.Any(), model.DefaultTenantID, gomock.Any()).Return(nil)
+		mockRepo.EXPECT().GetResumeToken(gomock.Any(), StreamName).Return(nil, nil)
+		mockRepo.EXPECT().GetPendingOutboxMessages(gomock.Any(), OutboxBatchSize).
+			Return([]model.OutboxMessage{national}, nil)
+		mockRepo.EXPECT().RelayOutboxMessage(gomock.Any(), national.ID, gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ primitive.ObjectID, message *model.Message) error {
+				assert.Equal(t, "+905551112233", message.PhoneNumber)
+				assert.Equal(t, "0555 111 22 33", message.RawPhoneNumber)
+				assert.Equal(t, "TR", message.Country)
+				return nil
+			})
+		mockRepo.EXPECT().WatchOutbox(gomock.Any(), nil, gomock.Any(), gomock.Any()).Return(nil)
+
+		assert.NoError(t, outboxRelay.relay(ctx))
+	})
+
+	t.Run("rejects rows exceeding the maximum segments with their normalized number", func(t *testing.T) {
+		mockRepo, _, outboxRelay := createOutboxRelay(t)
+		long := model.OutboxMessage{
+			ID:          primitive.NewObjectID(),
+			PhoneNumber: "0555 111 22 33",
+			Content:     strings.Repeat("a", sms.GSM7MultiCapacity*dto.DefaultMaxSegments+1),
+		}
+
+		mockRepo.EXPECT().GetResumeToken(gomock.Any(), StreamName).Return(nil, nil)
+		mockRepo.EXPECT().GetPendingOutboxMessages(gomock.Any(), OutboxBatchSize).
+			Return([]model.OutboxMessage{long}, nil)
+		mockRepo.EXPECT().RejectOutboxMessage(gomock.Any(), long.ID, "+905551112233", gomock.Any()).Return(nil)
+		mockRepo.EXPECT().WatchOutbox(gomock.Any(), nil, gomock.Any(), gomock.Any()).Return(nil)
+
+		assert.NoError(t, outboxRelay.relay(ctx))
+	})
+
+	t.Run("rejects rows over the daily quota of their tenant", func(t *testing.T) {
+		mockRepo, mockQuota, outboxRelay := createOutboxRelay(t)
+		tenantOutbox := validOutbox
+		tenantOutbox.TenantID = "retail"
+
+		mockRepo.EXPECT().GetResumeToken(gomock.Any(), StreamName).Return(nil, nil)
+		mockRepo.EXPECT().GetPendingOutboxMessages(gomock.Any(), OutboxBatchSize).
+			Return([]model.OutboxMessage{tenantOutbox}, nil)
+		mockQuota.EXPECT().Reserve(gomock.Any(), "retail", gomock.Any()).Return(model.ErrQuotaExceeded)
+		mockRepo.EXPECT().RejectOutboxMessage(gomock.Any(), tenantOutbox.ID, "+905551112233",
+			model.ErrQuotaExceeded.Error()).Return(nil)
+		mockRepo.EXPECT().WatchOutbox(gomock.Any(), nil, gomock.Any(), gomock.Any()).Return(nil)
+
+		assert.NoError(t, outboxRelay.relay(ctx))
+	})
+
+	t.Run("does not save the token when relaying fails", func(t *testing.T) {
+		mockRepo, mockQuota, outboxRelay := createOutboxRelay(t)
+
+		mockRepo.EXPECT().GetResumeToken(gomock.Any(), StreamName).Return(nil, nil)
+		mockRepo.EXPECT().GetPendingOutboxMessages(gomock.Any(), OutboxBatchSize).
+			Return([]model.OutboxMessage{}, nil)
+		mockRepo.EXPECT().WatchOutbox(gomock.Any(), nil, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ bson.Raw, _ time.Time,
+				handle func(context.Context, *model.OutboxMessage, bson.Raw) error) error {
+				return handle(ctx, &validOutbox, token)
+			})
+		mockQuota.EXPECT().Reserve(gomock.Any(), model.DefaultTenantID, gomock.Any()).Return(nil)
+		mockRepo.EXPECT().RelayOutboxMessage(gomock.Any(), validOutbox.ID, gomock.Any()).Return(assert.AnError)
+		mockQuota.EXPECT().Release(gomock.Any(), model.DefaultTenantID, gomock.Any()).Return(nil)
+
+		assert.ErrorIs(t, outboxRelay.relay(ctx), assert.AnError)
+	})
+
+	t.Run("releases the quota of rows relayed before", func(t *testing.T) {
+		mockRepo, mockQuota, outboxRelay := createOutboxRelay(t)
+
+		mockRepo.EXPECT().GetResumeToken(gomock.Any(), StreamName).Return(nil, nil)
+		mockRepo.EXPECT().GetPendingOutboxMessages(gomock.Any(), OutboxBatchSize).
+			Return([]model.OutboxMessage{}, nil)
+		mockRepo.EXPECT().WatchOutbox(gomock.Any(), nil, gomock.Any(), gomock.Any()).
+			DoAndReturn(func(ctx context.Context, _ bson.Raw, _ time.Time,
+				handle func(context.Context, *model.OutboxMessage, bson.Raw) error) error {
+				return handle(ctx, &validOutbox, token)
+			})
+		mockQuota.EXPECT().Reserve(gomock.Any(), model.DefaultTenantID, gomock.Any()).Return(nil)
+		mockRepo.EXPECT().RelayOutboxMessage(gomock.Any(), validOutbox.ID, gomock.Any()).
+			Return(model.ErrAlreadyExists)
+		mockQuota.EXPECT().Release(gomock.Any(), model.DefaultTenantID, gomock.Any()).Return(nil)
+		mockRepo.EXPECT().SaveResumeToken(gomock.Any(), StreamName, token).Return(nil)
+
+		assert.NoError(t, outboxRelay.relay(ctx))
+	})
+}
+
+func TestOutboxRelay_Run(t *testing.T) {
+	mockRepo, _, outboxRelay := createOutboxRelay(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	mockRepo.EXPECT().GetResumeToken(gomock.Any(), StreamName).
+		DoAndReturn(func(context.Context, string) (bson.Raw, error) {
+			cancel()
+			return nil, context.Canceled
+		})
+
+	done := make(chan struct{})
+	go func() {
+		outboxRelay.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("relay did not stop after context cancellation")
+	}
+}
+
+func createOutboxRelay(t *testing.T) (*mocks.MockIOutboxRepository, *mocks.MockIOutboxQuota, *OutboxRelay) {
+	t.Helper()
+	mockController := gomock.NewController(t)
+
+	mockRepo := mocks.NewMockIOutboxRepository(mockController)
+	mockQuota := mocks.NewMockIOutboxQuota(mockController)
+	phones, _ := phone.NewParser("TR", nil)
+	outboxRelay := NewOutboxRelay(mockRepo, phones, mockQuota, &config.Outbox{RetryInterval: time.Millisecond},
+		&config.Message{}, slog.Default())
+
+	return mockRepo, mockQuota, outboxRelay
+}
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *Repository) CreateAPIKey(ctx context.Context, key *model.APIKey) error {
+	_, err := r.apiKeyCollection.InsertOne(ctx, key)
+	if mongo.IsDuplicateKeyError(err) {
+		return model.ErrAlreadyExists
+	}
+	return err
+}
+
+// GetAPIKeyByHash returns the key with the given hash unless it was revoked, keys of every tenant are
+// searched since the key determines the tenant of the caller.
+func (r *Repository) GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error) {
+	filter := bson.M{
+		"hash":      hash,
+		"revokedAt": bson.M{"$exists": false},
+	}
+
+	key := &model.APIKey{}
+	err := r.apiKeyCollection.FindOne(ctx, filter).Decode(key)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+func (r *Repository) GetAPIKeys(ctx context.Context, tenantID string) ([]model.APIKey, error) {
+	keys := []model.APIKey{}
+
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	result, err := r.apiKeyCollection.Find(ctx, bson.M{"tenantId": tenantID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *Repository) RevokeAPIKey(ctx context.Context, tenantID string, keyID primitive.ObjectID,
+	revokedAt time.Time) error {
+	filter := bson.M{
+		"_id":       keyID,
+		"tenantId":  tenantID,
+		"revokedAt": bson.M{"$exists": false},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"revokedAt": revokedAt,
+		},
+	}
+
+	result, err := r.apiKeyCollection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
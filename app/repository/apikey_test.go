@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRepository_APIKeys(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	key := &model.APIKey{
+		ID:        primitive.NewObjectID(),
+		TenantID:  "retail",
+		Name:      "billing",
+		Prefix:    "msk_abcdefgh",
+		Hash:      "hash-1",
+		Scopes:    []string{model.ScopeMessagesRead},
+		CreatedAt: time.Now().UTC(),
+	}
+
+	t.Run("create and get key by hash", func(t *testing.T) {
+		assert.NoError(t, repo.CreateAPIKey(ctx, key))
+		assert.ErrorIs(t, repo.CreateAPIKey(ctx, &model.APIKey{ID: primitive.NewObjectID(), Hash: key.Hash}),
+			model.ErrAlreadyExists)
+
+		result, err := repo.GetAPIKeyByHash(ctx, key.Hash)
+		assert.NoError(t, err)
+		assert.Equal(t, key.Name, result.Name)
+		assert.Equal(t, key.TenantID, result.TenantID)
+	})
+
+	t.Run("revoked key is not returned", func(t *testing.T) {
+		assert.ErrorIs(t, repo.RevokeAPIKey(ctx, model.DefaultTenantID, key.ID, time.Now().UTC()), model.ErrNotFound)
+		assert.NoError(t, repo.RevokeAPIKey(ctx, key.TenantID, key.ID, time.Now().UTC()))
+		assert.ErrorIs(t, repo.RevokeAPIKey(ctx, key.TenantID, key.ID, time.Now().UTC()), model.ErrNotFound)
+
+		_, err := repo.GetAPIKeyByHash(ctx, key.Hash)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+
+		keys, err := repo.GetAPIKeys(ctx, key.TenantID)
+		assert.NoError(t, err)
+		assert.Len(t, keys, 1)
+		assert.NotNil(t, keys[0].RevokedAt)
+
+		keys, err = repo.GetAPIKeys(ctx, model.DefaultTenantID)
+		assert.NoError(t, err)
+		assert.Empty(t, keys)
+	})
+}
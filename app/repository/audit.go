@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CreateAuditEntry appends an entry to the audit log, the repository offers no way to change or delete it.
+func (r *Repository) CreateAuditEntry(ctx context.Context, entry *model.AuditEntry) error {
+	_, err := r.auditCollection.InsertOne(ctx, entry)
+	return err
+}
+
+// GetAuditEntries returns the newest audit entries matching the query first.
+func (r *Repository) GetAuditEntries(ctx context.Context, tenantID string,
+	query *dto.AuditQuery) ([]model.AuditEntry, error) {
+	entries := []model.AuditEntry{}
+
+	filter := bson.M{"tenantId": tenantID}
+	if len(query.Actions) > 0 {
+		filter["action"] = bson.M{"$in": query.Actions}
+	}
+	if query.ActorID != "" {
+		filter["actorId"] = query.ActorID
+	}
+	createdAt := bson.M{}
+	if !query.From.IsZero() {
+		createdAt["$gte"] = query.From
+	}
+	if !query.To.IsZero() {
+		createdAt["$lt"] = query.To
+	}
+	if len(createdAt) > 0 {
+		filter["createdAt"] = createdAt
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(query.Limit))
+
+	result, err := r.auditCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
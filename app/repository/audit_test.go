@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRepository_AuditEntries(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	now := time.Now().UTC()
+	for i, action := range []string{
+		model.AuditActionProcessorStart,
+		model.AuditActionProcessorStop,
+		model.AuditActionTemplateDelete,
+	} {
+		actorID := "key-id"
+		if action == model.AuditActionTemplateDelete {
+			actorID = "user-id"
+		}
+		assert.NoError(t, repo.CreateAuditEntry(ctx, &model.AuditEntry{
+			ID:        primitive.NewObjectID(),
+			TenantID:  model.DefaultTenantID,
+			Action:    action,
+			ActorID:   actorID,
+			Before:    map[string]interface{}{"running": i%2 == 1},
+			CreatedAt: now.Add(time.Duration(i) * time.Minute),
+		}))
+	}
+
+	entries, err := repo.GetAuditEntries(ctx, model.DefaultTenantID, &dto.AuditQuery{Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 3)
+	assert.Equal(t, model.AuditActionTemplateDelete, entries[0].Action)
+	assert.Equal(t, true, entries[1].Before["running"])
+
+	entries, err = repo.GetAuditEntries(ctx, model.DefaultTenantID, &dto.AuditQuery{
+		Actions: []string{model.AuditActionProcessorStart, model.AuditActionProcessorStop},
+		Limit:   10,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	entries, err = repo.GetAuditEntries(ctx, model.DefaultTenantID, &dto.AuditQuery{ActorID: "user-id", Limit: 10})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	entries, err = repo.GetAuditEntries(ctx, model.DefaultTenantID, &dto.AuditQuery{
+		From:  now.Add(30 * time.Second),
+		To:    now.Add(90 * time.Second),
+		Limit: 10,
+	})
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+	assert.Equal(t, model.AuditActionProcessorStop, entries[0].Action)
+
+	entries, err = repo.GetAuditEntries(ctx, "retail", &dto.AuditQuery{Limit: 10})
+	assert.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -0,0 +1,244 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// campaignSummary leaves out the recipient lists of campaigns, they are only returned for a single campaign.
+var campaignSummary = bson.M{"audience": 0, "rejected": 0}
+
+func (r *Repository) CreateCampaign(ctx context.Context, campaign *model.Campaign) error {
+	_, err := r.campaignCollection.InsertOne(ctx, campaign)
+	return err
+}
+
+func (r *Repository) GetCampaign(ctx context.Context, tenantID string,
+	campaignID primitive.ObjectID) (*model.Campaign, error) {
+	filter := bson.M{
+		"_id":      campaignID,
+		"tenantId": tenantID,
+	}
+
+	campaign := &model.Campaign{}
+	err := r.campaignCollection.FindOne(ctx, filter).Decode(campaign)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+// GetCampaigns returns the newest campaigns of a tenant first, without their recipients.
+func (r *Repository) GetCampaigns(ctx context.Context, tenantID string, limit int) ([]model.Campaign, error) {
+	campaigns := []model.Campaign{}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetProjection(campaignSummary)
+
+	result, err := r.campaignCollection.Find(ctx, bson.M{"tenantId": tenantID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &campaigns); err != nil {
+		return nil, err
+	}
+
+	return campaigns, nil
+}
+
+func (r *Repository) UpdateCampaignRejected(ctx context.Context, campaign *model.Campaign) error {
+	filter := bson.M{
+		"_id":      campaign.ID,
+		"tenantId": campaign.TenantID,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"rejected":  campaign.Rejected,
+			"updatedAt": campaign.UpdatedAt,
+		},
+	}
+
+	_, err := r.campaignCollection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// UpdateCampaignStatus moves a campaign in one of the from statuses to status and returns it. Campaigns in
+// another status are reported as model.ErrInvalidState.
+func (r *Repository) UpdateCampaignStatus(ctx context.Context, tenantID string, campaignID primitive.ObjectID,
+	from []string, status string, now time.Time) (*model.Campaign, error) {
+	filter := bson.M{
+		"_id":      campaignID,
+		"tenantId": tenantID,
+		"status":   bson.M{"$in": from},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status":    status,
+			"updatedAt": now,
+		},
+	}
+	opts := options.FindOneAndUpdate().
+		SetReturnDocument(options.After).
+		SetProjection(campaignSummary)
+
+	campaign := &model.Campaign{}
+	err := r.campaignCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(campaign)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		if _, err := r.GetCampaign(ctx, tenantID, campaignID); err != nil {
+			return nil, err
+		}
+		return nil, model.ErrInvalidState
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return campaign, nil
+}
+
+// CancelCampaignMessages moves the unsent messages of a campaign to the cancelled status.
+func (r *Repository) CancelCampaignMessages(ctx context.Context, tenantID string,
+	campaignID primitive.ObjectID) (int64, error) {
+	filter := bson.M{
+		"tenantId":   tenantID,
+		"campaignId": campaignID,
+		"status":     model.StatusUnsent,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"status": model.StatusCancelled,
+		},
+	}
+
+	result, err := r.messageCollection.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// GetCampaignCounts counts the messages of each campaign per status.
+func (r *Repository) GetCampaignCounts(ctx context.Context, tenantID string,
+	campaignIDs []primitive.ObjectID) (map[primitive.ObjectID]*model.CampaignCounts, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"tenantId":   tenantID,
+			"campaignId": bson.M{"$in": campaignIDs},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"campaignId": "$campaignId",
+				"status":     "$status",
+				"retrying":   bson.M{"$ne": bson.A{bson.M{"$ifNull": bson.A{"$lastError", ""}}, ""}},
+				"delivered":  bson.M{"$ne": bson.A{bson.M{"$ifNull": bson.A{"$deliveredAt", nil}}, nil}},
+			},
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+
+	result, err := r.messageCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []struct {
+		ID struct {
+			CampaignID primitive.ObjectID `bson:"campaignId"`
+			Status     string             `bson:"status"`
+			Retrying   bool               `bson:"retrying"`
+			Delivered  bool               `bson:"delivered"`
+		} `bson:"_id"`
+		Count int `bson:"count"`
+	}
+	if err := result.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[primitive.ObjectID]*model.CampaignCounts, len(campaignIDs))
+	for _, campaignID := range campaignIDs {
+		counts[campaignID] = &model.CampaignCounts{}
+	}
+	for _, group := range groups {
+		if campaignCounts, ok := counts[group.ID.CampaignID]; ok {
+			campaignCounts.Add(group.ID.Status, group.ID.Retrying, group.ID.Delivered, group.Count)
+		}
+	}
+
+	return counts, nil
+}
+
+// GetGatedCampaigns returns the campaigns of all tenants whose messages may have to be held back at now:
+// paused campaigns, campaigns scheduled for later and throttled campaigns.
+func (r *Repository) GetGatedCampaigns(ctx context.Context, now time.Time) ([]model.Campaign, error) {
+	campaigns := []model.Campaign{}
+
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": model.CampaignStatusPaused},
+			bson.M{"status": model.CampaignStatusRunning, "scheduledAt": bson.M{"$gt": now}},
+			bson.M{"status": model.CampaignStatusRunning, "throttle": bson.M{"$gt": 0}},
+		},
+	}
+
+	result, err := r.campaignCollection.Find(ctx, filter, options.Find().SetProjection(campaignSummary))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &campaigns); err != nil {
+		return nil, err
+	}
+
+	return campaigns, nil
+}
+
+// CountCampaignSends counts the messages of each campaign sent since the given time.
+func (r *Repository) CountCampaignSends(ctx context.Context, campaignIDs []primitive.ObjectID,
+	since time.Time) (map[primitive.ObjectID]int, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"campaignId": bson.M{"$in": campaignIDs},
+			// messages reported undelivered afterwards were sent all the same
+			"status": bson.M{"$in": bson.A{model.StatusSent, model.StatusFailed}},
+			"sentAt": bson.M{"$gte": since},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":   "$campaignId",
+			"count": bson.M{"$sum": 1},
+		}}},
+	}
+
+	result, err := r.messageCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []struct {
+		CampaignID primitive.ObjectID `bson:"_id"`
+		Count      int                `bson:"count"`
+	}
+	if err := result.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	sends := make(map[primitive.ObjectID]int, len(groups))
+	for _, group := range groups {
+		sends[group.CampaignID] = group.Count
+	}
+
+	return sends, nil
+}
@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRepository_Campaigns(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	now := time.Now().UTC()
+	later := now.Add(time.Hour)
+	campaign := &model.Campaign{
+		ID:         primitive.NewObjectID(),
+		TenantID:   "retail",
+		Name:       "spring sale",
+		TemplateID: primitive.NewObjectID().Hex(),
+		Audience:   []string{"+905551112233", "+905551112244"},
+		Status:     model.CampaignStatusRunning,
+		Throttle:   2,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	t.Run("create and get campaign", func(t *testing.T) {
+		assert.NoError(t, repo.CreateCampaign(ctx, campaign))
+
+		result, err := repo.GetCampaign(ctx, "retail", campaign.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, campaign.Audience, result.Audience)
+
+		_, err = repo.GetCampaign(ctx, model.DefaultTenantID, campaign.ID)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+
+		campaigns, err := repo.GetCampaigns(ctx, "retail", 10)
+		assert.NoError(t, err)
+		assert.Len(t, campaigns, 1)
+		assert.Empty(t, campaigns[0].Audience)
+	})
+
+	t.Run("update campaign status", func(t *testing.T) {
+		paused, err := repo.UpdateCampaignStatus(ctx, "retail", campaign.ID,
+			[]string{model.CampaignStatusRunning}, model.CampaignStatusPaused, now)
+		assert.NoError(t, err)
+		assert.Equal(t, model.CampaignStatusPaused, paused.Status)
+
+		_, err = repo.UpdateCampaignStatus(ctx, "retail", campaign.ID,
+			[]string{model.CampaignStatusRunning}, model.CampaignStatusPaused, now)
+		assert.ErrorIs(t, err, model.ErrInvalidState)
+
+		_, err = repo.UpdateCampaignStatus(ctx, model.DefaultTenantID, campaign.ID,
+			[]string{model.CampaignStatusPaused}, model.CampaignStatusRunning, now)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("gated campaigns", func(t *testing.T) {
+		scheduled := &model.Campaign{ID: primitive.NewObjectID(), TenantID: "retail",
+			Status: model.CampaignStatusRunning, ScheduledAt: &later}
+		unlimited := &model.Campaign{ID: primitive.NewObjectID(), TenantID: "retail",
+			Status: model.CampaignStatusRunning}
+		assert.NoError(t, repo.CreateCampaign(ctx, scheduled))
+		assert.NoError(t, repo.CreateCampaign(ctx, unlimited))
+
+		campaigns, err := repo.GetGatedCampaigns(ctx, now)
+		assert.NoError(t, err)
+		assert.Len(t, campaigns, 2)
+	})
+
+	t.Run("counts, sends and cancelled messages of a campaign", func(t *testing.T) {
+		campaignID := campaign.ID
+		_, err := repo.messageCollection.InsertMany(ctx, []interface{}{
+			bson.M{"_id": primitive.NewObjectID(), "tenantId": "retail", "campaignId": campaignID,
+				"status": "sent", "sentAt": now},
+			bson.M{"_id": primitive.NewObjectID(), "tenantId": "retail", "campaignId": campaignID,
+				"status": "sent", "sentAt": now.Add(-time.Hour), "deliveredAt": now},
+			bson.M{"_id": primitive.NewObjectID(), "tenantId": "retail", "campaignId": campaignID,
+				"status": "unsent", "lastError": "provider unavailable"},
+			bson.M{"_id": primitive.NewObjectID(), "tenantId": "retail", "campaignId": campaignID,
+				"status": "unsent"},
+			bson.M{"_id": primitive.NewObjectID(), "tenantId": "retail", "status": "unsent"},
+		})
+		assert.NoError(t, err)
+
+		counts, err := repo.GetCampaignCounts(ctx, "retail", []primitive.ObjectID{campaignID})
+		assert.NoError(t, err)
+		assert.Equal(t, &model.CampaignCounts{Total: 4, Unsent: 1, Retrying: 1, Sent: 2, Delivered: 1}, counts[campaignID])
+
+		sends, err := repo.CountCampaignSends(ctx, []primitive.ObjectID{campaignID}, now.Add(-time.Minute))
+		assert.NoError(t, err)
+		assert.Equal(t, 1, sends[campaignID])
+
+		messages, err := repo.GetMessagesByPriority(ctx, "unsent", []int{0}, []primitive.ObjectID{campaignID}, 10)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+		assert.Nil(t, messages[0].CampaignID)
+
+		cancelled, err := repo.CancelCampaignMessages(ctx, "retail", campaignID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), cancelled)
+
+		counts, err = repo.GetCampaignCounts(ctx, "retail", []primitive.ObjectID{campaignID})
+		assert.NoError(t, err)
+		assert.Equal(t, 2, counts[campaignID].Cancelled)
+	})
+}
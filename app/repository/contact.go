@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *Repository) CreateContactList(ctx context.Context, list *model.ContactList) error {
+	_, err := r.contactListCollection.InsertOne(ctx, list)
+	return err
+}
+
+func (r *Repository) GetContactList(ctx context.Context, tenantID string,
+	listID primitive.ObjectID) (*model.ContactList, error) {
+	filter := bson.M{
+		"_id":      listID,
+		"tenantId": tenantID,
+	}
+
+	list := &model.ContactList{}
+	err := r.contactListCollection.FindOne(ctx, filter).Decode(list)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// GetContactLists returns the newest contact lists of a tenant first.
+func (r *Repository) GetContactLists(ctx context.Context, tenantID string, limit int) ([]model.ContactList, error) {
+	lists := []model.ContactList{}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit))
+
+	result, err := r.contactListCollection.Find(ctx, bson.M{"tenantId": tenantID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &lists); err != nil {
+		return nil, err
+	}
+
+	return lists, nil
+}
+
+// AddContacts inserts the contacts whose phone number is not a member of their list yet and returns how
+// many were inserted.
+func (r *Repository) AddContacts(ctx context.Context, contacts []model.Contact) (int64, error) {
+	if len(contacts) == 0 {
+		return 0, nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(contacts))
+	for _, contact := range contacts {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{
+				"listId":      contact.ListID,
+				"phoneNumber": contact.PhoneNumber,
+			}).
+			SetUpdate(bson.M{"$setOnInsert": contact}).
+			SetUpsert(true))
+	}
+
+	result, err := r.contactCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, err
+	}
+	return result.UpsertedCount, nil
+}
+
+func (r *Repository) CountContacts(ctx context.Context, tenantID string, listID primitive.ObjectID) (int64, error) {
+	return r.contactCollection.CountDocuments(ctx, bson.M{
+		"tenantId": tenantID,
+		"listId":   listID,
+	})
+}
+
+// ForEachContact calls handle for every contact of a list ordered by phone number without loading the
+// list into memory, it stops at the first error.
+func (r *Repository) ForEachContact(ctx context.Context, tenantID string, listID primitive.ObjectID,
+	handle func(ctx context.Context, contact *model.Contact) error) error {
+	filter := bson.M{
+		"tenantId": tenantID,
+		"listId":   listID,
+	}
+
+	cursor, err := r.contactCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "phoneNumber", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		contact := &model.Contact{}
+		if err := cursor.Decode(contact); err != nil {
+			return err
+		}
+
+		if err := handle(ctx, contact); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
@@ -0,0 +1,82 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRepository_Contacts(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	now := time.Now().UTC()
+	list := &model.ContactList{
+		ID:        primitive.NewObjectID(),
+		TenantID:  "retail",
+		Name:      "newsletter",
+		CreatedAt: now,
+	}
+	newContact := func(phoneNumber string) model.Contact {
+		return model.Contact{
+			ID:          primitive.NewObjectID(),
+			TenantID:    "retail",
+			ListID:      list.ID,
+			PhoneNumber: phoneNumber,
+			CreatedAt:   now,
+		}
+	}
+
+	t.Run("create and get list", func(t *testing.T) {
+		assert.NoError(t, repo.CreateContactList(ctx, list))
+
+		result, err := repo.GetContactList(ctx, "retail", list.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "newsletter", result.Name)
+
+		_, err = repo.GetContactList(ctx, model.DefaultTenantID, list.ID)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+
+		lists, err := repo.GetContactLists(ctx, "retail", 10)
+		assert.NoError(t, err)
+		assert.Len(t, lists, 1)
+	})
+
+	t.Run("add contacts skips members of the list", func(t *testing.T) {
+		added, err := repo.AddContacts(ctx, []model.Contact{newContact("+905551112244"), newContact("+905551112233")})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), added)
+
+		added, err = repo.AddContacts(ctx, []model.Contact{newContact("+905551112233"), newContact("+905551112255")})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), added)
+
+		count, err := repo.CountContacts(ctx, "retail", list.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+
+		count, err = repo.CountContacts(ctx, model.DefaultTenantID, list.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+	})
+
+	t.Run("for each contact", func(t *testing.T) {
+		phoneNumbers := []string{}
+		err := repo.ForEachContact(ctx, "retail", list.ID, func(_ context.Context, contact *model.Contact) error {
+			phoneNumbers = append(phoneNumbers, contact.PhoneNumber)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"+905551112233", "+905551112244", "+905551112255"}, phoneNumbers)
+
+		err = repo.ForEachContact(ctx, "retail", list.ID, func(context.Context, *model.Contact) error {
+			return assert.AnError
+		})
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
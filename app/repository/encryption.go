@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"messaging-system/app/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// encryptMessage returns a copy of a message to store with its phone numbers and content encrypted and the
+// blind index of its phone number set, the message itself is left as it is.
+func (r *Repository) encryptMessage(message *model.Message) (*model.Message, error) {
+	if r.cipher == nil {
+		return message, nil
+	}
+
+	stored := *message
+	stored.PhoneNumberIndex = r.cipher.BlindIndex(message.PhoneNumber)
+	if err := r.encryptFields(&stored.PhoneNumber, &stored.RawPhoneNumber, &stored.Content); err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+func (r *Repository) decryptMessage(message *model.Message) error {
+	return r.decryptFields(&message.PhoneNumber, &message.RawPhoneNumber, &message.Content)
+}
+
+func (r *Repository) decryptMessages(messages []model.Message) error {
+	for i := range messages {
+		if err := r.decryptMessage(&messages[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encryptInboundMessage returns a copy of an inbound message to store with its phone numbers and text
+// encrypted and the blind index of its phone number set.
+func (r *Repository) encryptInboundMessage(message *model.InboundMessage) (*model.InboundMessage, error) {
+	if r.cipher == nil {
+		return message, nil
+	}
+
+	stored := *message
+	stored.PhoneNumberIndex = r.cipher.BlindIndex(message.PhoneNumber)
+	if err := r.encryptFields(&stored.PhoneNumber, &stored.RawPhoneNumber, &stored.Text); err != nil {
+		return nil, err
+	}
+	return &stored, nil
+}
+
+func (r *Repository) decryptInboundMessages(messages []model.InboundMessage) error {
+	for i := range messages {
+		message := &messages[i]
+		if err := r.decryptFields(&message.PhoneNumber, &message.RawPhoneNumber, &message.Text); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Repository) encryptFields(fields ...*string) error {
+	for _, field := range fields {
+		encrypted, err := r.cipher.Encrypt(*field)
+		if err != nil {
+			return err
+		}
+		*field = encrypted
+	}
+	return nil
+}
+
+func (r *Repository) decryptFields(fields ...*string) error {
+	for _, field := range fields {
+		decrypted, err := r.cipher.Decrypt(*field)
+		if err != nil {
+			return err
+		}
+		*field = decrypted
+	}
+	return nil
+}
+
+// matchPhoneNumber adds a phone number to a filter. With encryption enabled the number is matched by its
+// blind index, and in plaintext for the documents stored before encryption was enabled.
+func (r *Repository) matchPhoneNumber(filter bson.M, phoneNumber string) bson.M {
+	if r.cipher == nil {
+		filter["phoneNumber"] = phoneNumber
+		return filter
+	}
+
+	filter["$or"] = bson.A{
+		bson.M{"phoneNumberIndex": r.cipher.BlindIndex(phoneNumber)},
+		bson.M{"phoneNumber": phoneNumber},
+	}
+	return filter
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/model"
+	"messaging-system/app/pii"
+	"messaging-system/config"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRepository_Encryption(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	cipher, err := pii.NewCipher(&config.PII{
+		Encrypt:   true,
+		ActiveKey: "1",
+		Keys:      map[string]string{"1": "s16O6thSz/IYuiY6Q1hk9QtLkf/23CKa05t3nwP7zoI="},
+		IndexKey:  "FvZhw/147kca6t4ArkYnlqhVn6YxGFpJI/mWeI0ew1o=",
+	})
+	assert.NoError(t, err)
+
+	tenantID := "retail"
+	phoneNumber := "+905551112233"
+	now := time.Now().UTC()
+
+	// stored before encryption was enabled
+	plaintext := &model.Message{ID: primitive.NewObjectID(), TenantID: tenantID, PhoneNumber: phoneNumber,
+		Content: "Your order has shipped", Status: model.StatusSent, CreatedAt: now.Add(-time.Hour), SentAt: now}
+	assert.NoError(t, repo.CreateMessage(ctx, plaintext))
+
+	repo.cipher = cipher
+	encrypted := &model.Message{ID: primitive.NewObjectID(), TenantID: tenantID, PhoneNumber: phoneNumber,
+		RawPhoneNumber: "0555 111 22 33", Content: "Your code is 123456", Status: model.StatusUnsent, CreatedAt: now}
+	assert.NoError(t, repo.CreateMessage(ctx, encrypted))
+	assert.Equal(t, phoneNumber, encrypted.PhoneNumber)
+
+	t.Run("phone number and content are stored encrypted", func(t *testing.T) {
+		stored := bson.M{}
+		assert.NoError(t, repo.messageCollection.FindOne(ctx, bson.M{"_id": encrypted.ID}).Decode(&stored))
+		assert.NotContains(t, stored["phoneNumber"], "5551112233")
+		assert.NotContains(t, stored["rawPhoneNumber"], "111 22 33")
+		assert.NotContains(t, stored["content"], "123456")
+		assert.Equal(t, cipher.BlindIndex(phoneNumber), stored["phoneNumberIndex"])
+	})
+
+	t.Run("messages are decrypted when read", func(t *testing.T) {
+		messages, err := repo.GetMessages(ctx, tenantID, model.StatusUnsent, 10)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, phoneNumber, messages[0].PhoneNumber)
+		assert.Equal(t, "0555 111 22 33", messages[0].RawPhoneNumber)
+		assert.Equal(t, "Your code is 123456", messages[0].Content)
+	})
+
+	t.Run("encrypted and plaintext messages are found by phone number", func(t *testing.T) {
+		messages, err := repo.GetMessagesByPhoneNumber(ctx, tenantID, phoneNumber, 10)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 2)
+		assert.Equal(t, encrypted.ID, messages[0].ID)
+		assert.Equal(t, "Your order has shipped", messages[1].Content)
+
+		message, err := repo.GetLastSentMessage(ctx, tenantID, phoneNumber)
+		assert.NoError(t, err)
+		assert.Equal(t, plaintext.ID, message.ID)
+	})
+
+	t.Run("messages are archived encrypted", func(t *testing.T) {
+		messages, err := repo.GetMessagesCreatedBefore(ctx, model.StatusUnsent, now.Add(time.Minute), 10)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+		assert.NotContains(t, messages[0].Content, "123456")
+		assert.Equal(t, cipher.BlindIndex(phoneNumber), messages[0].PhoneNumberIndex)
+
+		assert.NoError(t, repo.ArchiveMessages(ctx, messages))
+		stored := bson.M{}
+		assert.NoError(t, repo.archiveCollection.FindOne(ctx, bson.M{"_id": encrypted.ID}).Decode(&stored))
+		assert.Equal(t, messages[0].Content, stored["content"])
+		assert.Equal(t, cipher.BlindIndex(phoneNumber), stored["phoneNumberIndex"])
+	})
+
+	t.Run("inbound messages are stored encrypted", func(t *testing.T) {
+		inbound := &model.InboundMessage{ID: primitive.NewObjectID(), TenantID: tenantID, PhoneNumber: phoneNumber,
+			Text: "STOP", ReceivedAt: now}
+		assert.NoError(t, repo.CreateInboundMessage(ctx, inbound))
+
+		stored := bson.M{}
+		assert.NoError(t, repo.inboundCollection.FindOne(ctx, bson.M{"_id": inbound.ID}).Decode(&stored))
+		assert.NotEqual(t, "STOP", stored["text"])
+
+		messages, err := repo.GetInboundMessages(ctx, tenantID, phoneNumber, 10)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, "STOP", messages[0].Text)
+	})
+}
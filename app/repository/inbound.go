@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *Repository) CreateInboundMessage(ctx context.Context, message *model.InboundMessage) error {
+	stored, err := r.encryptInboundMessage(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.inboundCollection.InsertOne(ctx, stored)
+	if mongo.IsDuplicateKeyError(err) {
+		return model.ErrAlreadyExists
+	}
+	return err
+}
+
+// GetInboundMessageByProviderID returns the inbound message the provider delivered with the given id.
+func (r *Repository) GetInboundMessageByProviderID(ctx context.Context, tenantID,
+	providerMessageID string) (*model.InboundMessage, error) {
+	filter := bson.M{"tenantId": tenantID, "providerMessageId": providerMessageID}
+
+	message := model.InboundMessage{}
+	err := r.inboundCollection.FindOne(ctx, filter).Decode(&message)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []model.InboundMessage{message}
+	if err := r.decryptInboundMessages(messages); err != nil {
+		return nil, err
+	}
+	return &messages[0], nil
+}
+
+// GetInboundMessages returns the newest inbound messages of a phone number first.
+func (r *Repository) GetInboundMessages(ctx context.Context, tenantID, phoneNumber string,
+	limit int) ([]model.InboundMessage, error) {
+	messages := []model.InboundMessage{}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "receivedAt", Value: -1}}).
+		SetLimit(int64(limit))
+
+	filter := r.matchPhoneNumber(bson.M{"tenantId": tenantID}, phoneNumber)
+	result, err := r.inboundCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+
+	if err := r.decryptInboundMessages(messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// GetMessagesByPhoneNumber returns the newest outbound messages of a phone number first.
+func (r *Repository) GetMessagesByPhoneNumber(ctx context.Context, tenantID, phoneNumber string,
+	limit int) ([]model.Message, error) {
+	messages := []model.Message{}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit))
+
+	filter := r.matchPhoneNumber(bson.M{"tenantId": tenantID}, phoneNumber)
+	result, err := r.messageCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+
+	if err := r.decryptMessages(messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// GetLastSentMessage returns the message most recently sent to a phone number.
+func (r *Repository) GetLastSentMessage(ctx context.Context, tenantID, phoneNumber string) (*model.Message, error) {
+	filter := r.matchPhoneNumber(bson.M{
+		"tenantId": tenantID,
+		"status":   model.StatusSent,
+	}, phoneNumber)
+	opts := options.FindOne().SetSort(bson.D{{Key: "sentAt", Value: -1}})
+
+	message := &model.Message{}
+	err := r.messageCollection.FindOne(ctx, filter, opts).Decode(message)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.decryptMessage(message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
@@ -0,0 +1,75 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRepository_InboundMessages(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	tenantID := "retail"
+	phoneNumber := "+905551112233"
+	now := time.Now().UTC()
+	older := &model.Message{ID: primitive.NewObjectID(), TenantID: tenantID, PhoneNumber: phoneNumber, Status: model.StatusSent,
+		CreatedAt: now.Add(-2 * time.Hour), SentAt: now.Add(-2 * time.Hour)}
+	newer := &model.Message{ID: primitive.NewObjectID(), TenantID: tenantID, PhoneNumber: phoneNumber, Status: model.StatusSent,
+		CreatedAt: now.Add(-time.Hour), SentAt: now.Add(-time.Hour)}
+	unsent := &model.Message{ID: primitive.NewObjectID(), TenantID: tenantID, PhoneNumber: phoneNumber, Status: model.StatusUnsent,
+		CreatedAt: now}
+	for _, message := range []*model.Message{older, newer, unsent} {
+		assert.NoError(t, repo.CreateMessage(ctx, message))
+	}
+
+	t.Run("get last sent message", func(t *testing.T) {
+		message, err := repo.GetLastSentMessage(ctx, tenantID, phoneNumber)
+		assert.NoError(t, err)
+		assert.Equal(t, newer.ID, message.ID)
+
+		_, err = repo.GetLastSentMessage(ctx, tenantID, "+905550000000")
+		assert.ErrorIs(t, err, model.ErrNotFound)
+
+		_, err = repo.GetLastSentMessage(ctx, model.DefaultTenantID, phoneNumber)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("get messages by phone number", func(t *testing.T) {
+		messages, err := repo.GetMessagesByPhoneNumber(ctx, tenantID, phoneNumber, 2)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 2)
+		assert.Equal(t, unsent.ID, messages[0].ID)
+	})
+
+	t.Run("create inbound message once per provider id", func(t *testing.T) {
+		inbound := &model.InboundMessage{ID: primitive.NewObjectID(), TenantID: tenantID, ProviderMessageID: "mo-1",
+			PhoneNumber: phoneNumber, Text: "STOP", ReplyTo: &newer.ID, ReceivedAt: now}
+		assert.NoError(t, repo.CreateInboundMessage(ctx, inbound))
+
+		retry := *inbound
+		retry.ID = primitive.NewObjectID()
+		assert.ErrorIs(t, repo.CreateInboundMessage(ctx, &retry), model.ErrAlreadyExists)
+
+		received, err := repo.GetInboundMessageByProviderID(ctx, tenantID, "mo-1")
+		assert.NoError(t, err)
+		assert.Equal(t, inbound.ID, received.ID)
+		_, err = repo.GetInboundMessageByProviderID(ctx, model.DefaultTenantID, "mo-1")
+		assert.ErrorIs(t, err, model.ErrNotFound)
+
+		otherTenant := *inbound
+		otherTenant.ID = primitive.NewObjectID()
+		otherTenant.TenantID = model.DefaultTenantID
+		assert.NoError(t, repo.CreateInboundMessage(ctx, &otherTenant))
+
+		messages, err := repo.GetInboundMessages(ctx, tenantID, phoneNumber, 10)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, newer.ID, *messages[0].ReplyTo)
+	})
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *Repository) CreateNotification(ctx context.Context, notification *model.Notification) error {
+	_, err := r.notificationCollection.InsertOne(ctx, notification)
+	return err
+}
+
+// ClaimNotification picks the oldest due pending notification and pushes its next attempt back by
+// lease, so other instances do not deliver it at the same time.
+func (r *Repository) ClaimNotification(ctx context.Context, now time.Time,
+	lease time.Duration) (*model.Notification, error) {
+	filter := bson.M{
+		"status":        model.NotificationStatusPending,
+		"nextAttemptAt": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"nextAttemptAt": now.Add(lease),
+		},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "nextAttemptAt", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	notification := &model.Notification{}
+	err := r.notificationCollection.FindOneAndUpdate(ctx, filter, update, opts).Decode(notification)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return notification, nil
+}
+
+func (r *Repository) UpdateNotification(ctx context.Context, notification *model.Notification) error {
+	_, err := r.notificationCollection.ReplaceOne(ctx, bson.M{"_id": notification.ID}, notification)
+	return err
+}
+
+func (r *Repository) GetNotifications(ctx context.Context, tenantID string,
+	messageID primitive.ObjectID) ([]model.Notification, error) {
+	notifications := []model.Notification{}
+
+	filter := bson.M{
+		"tenantId":  tenantID,
+		"messageId": messageID,
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}})
+	result, err := r.notificationCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRepository_Notifications(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	messageID := primitive.NewObjectID()
+	due := &model.Notification{ID: primitive.NewObjectID(), TenantID: model.DefaultTenantID, MessageID: messageID,
+		Status: model.NotificationStatusPending, NextAttemptAt: now.Add(-time.Minute), CreatedAt: now}
+	later := &model.Notification{ID: primitive.NewObjectID(), TenantID: model.DefaultTenantID, MessageID: messageID,
+		Status: model.NotificationStatusPending, NextAttemptAt: now.Add(time.Hour), CreatedAt: now.Add(time.Second)}
+	assert.NoError(t, repo.CreateNotification(ctx, due))
+	assert.NoError(t, repo.CreateNotification(ctx, later))
+
+	t.Run("claim due notification", func(t *testing.T) {
+		claimed, err := repo.ClaimNotification(ctx, now, time.Minute)
+		assert.NoError(t, err)
+		assert.Equal(t, due.ID, claimed.ID)
+		assert.True(t, now.Add(time.Minute).Equal(claimed.NextAttemptAt))
+
+		_, err = repo.ClaimNotification(ctx, now, time.Minute)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("update and list notifications", func(t *testing.T) {
+		due.Status = model.NotificationStatusDelivered
+		due.Attempts = 1
+		assert.NoError(t, repo.UpdateNotification(ctx, due))
+
+		notifications, err := repo.GetNotifications(ctx, model.DefaultTenantID, messageID)
+		assert.NoError(t, err)
+		assert.Len(t, notifications, 2)
+		assert.Equal(t, model.NotificationStatusDelivered, notifications[0].Status)
+
+		notifications, err = repo.GetNotifications(ctx, "retail", messageID)
+		assert.NoError(t, err)
+		assert.Empty(t, notifications)
+	})
+}
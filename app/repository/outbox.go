@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// changeStreamHistoryLost is returned when a resume token is no longer in the oplog.
+const changeStreamHistoryLost = 286
+
+type resumeToken struct {
+	Name      string    `bson:"_id"`
+	Token     bson.Raw  `bson:"token"`
+	UpdatedAt time.Time `bson:"updatedAt"`
+}
+
+func (r *Repository) GetPendingOutboxMessages(ctx context.Context, limit int) ([]model.OutboxMessage, error) {
+	var outboxMessages []model.OutboxMessage
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetLimit(int64(limit))
+
+	result, err := r.outboxCollection.Find(ctx, bson.M{"status": model.OutboxStatusPending}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &outboxMessages); err != nil {
+		return nil, err
+	}
+
+	return outboxMessages, nil
+}
+
+// WatchOutbox tails inserts of pending outbox rows and calls handle for each of them together
+// with the resume token of the event. The stream resumes after resumeToken when it is set and
+// starts at startAt otherwise, or when the token has already fallen out of the oplog.
+func (r *Repository) WatchOutbox(ctx context.Context, resumeToken bson.Raw, startAt time.Time,
+	handle func(ctx context.Context, outbox *model.OutboxMessage, token bson.Raw) error) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"operationType":       "insert",
+			"fullDocument.status": model.OutboxStatusPending,
+		}}},
+	}
+	startAtTime := &primitive.Timestamp{T: uint32(startAt.Unix())}
+
+	opts := options.ChangeStream()
+	if resumeToken != nil {
+		opts.SetResumeAfter(resumeToken)
+	} else {
+		opts.SetStartAtOperationTime(startAtTime)
+	}
+
+	stream, err := r.outboxCollection.Watch(ctx, pipeline, opts)
+	var cmdErr mongo.CommandError
+	if resumeToken != nil && errors.As(err, &cmdErr) && cmdErr.Code == changeStreamHistoryLost {
+		stream, err = r.outboxCollection.Watch(ctx, pipeline, options.ChangeStream().SetStartAtOperationTime(startAtTime))
+	}
+	if err != nil {
+		return err
+	}
+	defer stream.Close(ctx)
+
+	for stream.Next(ctx) {
+		var event struct {
+			FullDocument model.OutboxMessage `bson:"fullDocument"`
+		}
+		if err := stream.Decode(&event); err != nil {
+			return err
+		}
+
+		if err := handle(ctx, &event.FullDocument, stream.ResumeToken()); err != nil {
+			return err
+		}
+	}
+
+	return stream.Err()
+}
+
+// RelayOutboxMessage inserts the message created from an outbox row and marks the row as relayed with the
+// normalized phone number of the message. A message that already exists is treated as relayed, so a crash
+// between both writes never creates a duplicate message. The row is marked either way,
+// model.ErrAlreadyExists then tells the caller that no message was created.
+func (r *Repository) RelayOutboxMessage(ctx context.Context, outboxID primitive.ObjectID, message *model.Message) error {
+	stored, err := r.encryptMessage(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.messageCollection.InsertOne(ctx, stored)
+	duplicate := mongo.IsDuplicateKeyError(err)
+	if err != nil && !duplicate {
+		return err
+	}
+
+	filter := bson.M{"_id": outboxID}
+	update := bson.M{
+		"$set": bson.M{
+			"status":                model.OutboxStatusRelayed,
+			"normalizedPhoneNumber": message.PhoneNumber,
+			"relayedAt":             time.Now().UTC(),
+		},
+	}
+	if _, err := r.outboxCollection.UpdateOne(ctx, filter, update); err != nil {
+		return err
+	}
+
+	if duplicate {
+		return model.ErrAlreadyExists
+	}
+	return nil
+}
+
+// RejectOutboxMessage marks an outbox row as rejected for reason. normalizedPhoneNumber is empty for rows
+// whose phone number does not parse.
+func (r *Repository) RejectOutboxMessage(ctx context.Context, outboxID primitive.ObjectID, normalizedPhoneNumber,
+	reason string) error {
+	set := bson.M{
+		"status": model.OutboxStatusRejected,
+		"error":  reason,
+	}
+	if normalizedPhoneNumber != "" {
+		set["normalizedPhoneNumber"] = normalizedPhoneNumber
+	}
+	_, err := r.outboxCollection.UpdateOne(ctx, bson.M{"_id": outboxID}, bson.M{"$set": set})
+	return err
+}
+
+// GetUnnormalizedOutboxMessages returns the outbox rows of a tenant without a normalized phone number, the
+// pending rows the relay has not picked up yet and the rows relayed before it wrote one.
+func (r *Repository) GetUnnormalizedOutboxMessages(ctx context.Context,
+	tenantID string) ([]model.OutboxMessage, error) {
+	outboxMessages := []model.OutboxMessage{}
+
+	filter := outboxTenantFilter(tenantID)
+	filter["normalizedPhoneNumber"] = bson.M{"$exists": false}
+	result, err := r.outboxCollection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &outboxMessages); err != nil {
+		return nil, err
+	}
+
+	return outboxMessages, nil
+}
+
+// SetOutboxNormalizedPhoneNumbers writes the normalized phone numbers of outbox rows keyed by their IDs.
+func (r *Repository) SetOutboxNormalizedPhoneNumbers(ctx context.Context,
+	phoneNumbers map[primitive.ObjectID]string) error {
+	if len(phoneNumbers) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(phoneNumbers))
+	for id, phoneNumber := range phoneNumbers {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id}).
+			SetUpdate(bson.M{"$set": bson.M{"normalizedPhoneNumber": phoneNumber}}))
+	}
+	_, err := r.outboxCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (r *Repository) GetResumeToken(ctx context.Context, streamName string) (bson.Raw, error) {
+	token := &resumeToken{}
+	err := r.resumeTokenCollection.FindOne(ctx, bson.M{"_id": streamName}).Decode(token)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return token.Token, nil
+}
+
+func (r *Repository) SaveResumeToken(ctx context.Context, streamName string, token bson.Raw) error {
+	filter := bson.M{"_id": streamName}
+	update := bson.M{
+		"$set": bson.M{
+			"token":     token,
+			"updatedAt": time.Now().UTC(),
+		},
+	}
+	_, err := r.resumeTokenCollection.UpdateOne(ctx, filter, update, options.Update().SetUpsert(true))
+	return err
+}
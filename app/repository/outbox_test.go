@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRepository_RelayOutboxMessage(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	t.Run("creates the message once and marks the row relayed", func(t *testing.T) {
+		outbox := &model.OutboxMessage{
+			ID:          primitive.NewObjectID(),
+			PhoneNumber: "+905551112233",
+			Content:     "Hello",
+			Status:      model.OutboxStatusPending,
+			CreatedAt:   time.Now().UTC(),
+		}
+		_, err := repo.outboxCollection.InsertOne(ctx, outbox)
+		assert.NoError(t, err)
+
+		pending, err := repo.GetPendingOutboxMessages(ctx, 10)
+		assert.NoError(t, err)
+		assert.Len(t, pending, 1)
+
+		assert.NoError(t, repo.RelayOutboxMessage(ctx, outbox.ID, outbox.ToMessage()))
+		assert.ErrorIs(t, repo.RelayOutboxMessage(ctx, outbox.ID, outbox.ToMessage()), model.ErrAlreadyExists)
+
+		count, err := repo.messageCollection.CountDocuments(ctx, bson.M{"_id": outbox.ID})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), count)
+
+		pending, err = repo.GetPendingOutboxMessages(ctx, 10)
+		assert.NoError(t, err)
+		assert.Len(t, pending, 0)
+
+		relayed, err := repo.GetSubjectOutboxMessages(ctx, model.DefaultTenantID, "+905551112233")
+		assert.NoError(t, err)
+		assert.Len(t, relayed, 1)
+	})
+
+	t.Run("normalizes rows the relay has not written a number for", func(t *testing.T) {
+		national := model.OutboxMessage{ID: primitive.NewObjectID(), TenantID: "retail",
+			PhoneNumber: "0555 111 22 33", Status: model.OutboxStatusPending}
+		rejected := model.OutboxMessage{ID: primitive.NewObjectID(), TenantID: "retail", PhoneNumber: "0555 111 22 44",
+			Status: model.OutboxStatusPending}
+		_, err := repo.outboxCollection.InsertMany(ctx, []interface{}{national, rejected})
+		assert.NoError(t, err)
+		assert.NoError(t, repo.RejectOutboxMessage(ctx, rejected.ID, "+905551112244", "too long"))
+
+		unnormalized, err := repo.GetUnnormalizedOutboxMessages(ctx, "retail")
+		assert.NoError(t, err)
+		assert.Len(t, unnormalized, 1)
+		assert.Equal(t, national.ID, unnormalized[0].ID)
+
+		assert.NoError(t, repo.SetOutboxNormalizedPhoneNumbers(ctx,
+			map[primitive.ObjectID]string{national.ID: "+905551112233"}))
+		found, err := repo.GetSubjectOutboxMessages(ctx, "retail", "+905551112233")
+		assert.NoError(t, err)
+		assert.Len(t, found, 1)
+		assert.Equal(t, "0555 111 22 33", found[0].PhoneNumber)
+	})
+}
+
+func TestRepository_ResumeToken(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	t.Run("returns nil when no token is stored", func(t *testing.T) {
+		token, err := repo.GetResumeToken(ctx, "stream")
+		assert.NoError(t, err)
+		assert.Nil(t, token)
+	})
+
+	t.Run("returns the last saved token", func(t *testing.T) {
+		first, _ := bson.Marshal(bson.M{"_data": "1"})
+		second, _ := bson.Marshal(bson.M{"_data": "2"})
+
+		assert.NoError(t, repo.SaveResumeToken(ctx, "stream", first))
+		assert.NoError(t, repo.SaveResumeToken(ctx, "stream", second))
+
+		token, err := repo.GetResumeToken(ctx, "stream")
+		assert.NoError(t, err)
+		assert.Equal(t, bson.Raw(second), token)
+	})
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ApplyReceipt records a delivery receipt of a sent message, identified by the id the provider accepted it
+// with. Delivered messages keep the time of the receipt in deliveredAt, undelivered ones move to the failed
+// status. Messages that already received a receipt are reported as model.ErrAlreadyExists.
+func (r *Repository) ApplyReceipt(ctx context.Context, tenantID string, receipt *dto.ReceiptRequest,
+	now time.Time) (*model.Message, error) {
+	filter := bson.M{
+		"tenantId":         tenantID,
+		"webhookMessageId": receipt.MessageID,
+		"status":           model.StatusSent,
+		"deliveredAt":      bson.M{"$exists": false},
+	}
+
+	set := bson.M{"deliveredAt": now}
+	if receipt.Status == dto.ReceiptFailed {
+		set = bson.M{"status": model.StatusFailed, "lastError": receipt.Error}
+	}
+
+	message := &model.Message{}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := r.messageCollection.FindOneAndUpdate(ctx, filter, bson.M{"$set": set}, opts).Decode(message)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, r.receiptMiss(ctx, tenantID, receipt.MessageID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.decryptMessage(message); err != nil {
+		return nil, err
+	}
+	return message, nil
+}
+
+// receiptMiss tells a receipt of an unknown message from a repeated one.
+func (r *Repository) receiptMiss(ctx context.Context, tenantID, webhookMessageID string) error {
+	count, err := r.messageCollection.CountDocuments(ctx, bson.M{
+		"tenantId":         tenantID,
+		"webhookMessageId": webhookMessageID,
+	})
+	if err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return model.ErrNotFound
+	}
+	return model.ErrAlreadyExists
+}
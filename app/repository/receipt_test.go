@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRepository_ApplyReceipt(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	delivered := &model.Message{ID: primitive.NewObjectID(), TenantID: "retail", WebhookMessageID: "webhook-1",
+		PhoneNumber: "+905551112233", Status: model.StatusSent, SentAt: now}
+	undelivered := &model.Message{ID: primitive.NewObjectID(), TenantID: "retail", WebhookMessageID: "webhook-2",
+		PhoneNumber: "+905551112233", Status: model.StatusSent, SentAt: now}
+	for _, message := range []*model.Message{delivered, undelivered} {
+		assert.NoError(t, repo.CreateMessage(ctx, message))
+	}
+
+	t.Run("records the delivery", func(t *testing.T) {
+		message, err := repo.ApplyReceipt(ctx, "retail",
+			&dto.ReceiptRequest{MessageID: "webhook-1", Status: dto.ReceiptDelivered}, now)
+		assert.NoError(t, err)
+		assert.Equal(t, delivered.ID, message.ID)
+		assert.Equal(t, model.StatusSent, message.Status)
+		assert.Equal(t, now, *message.DeliveredAt)
+	})
+
+	t.Run("fails undelivered messages", func(t *testing.T) {
+		message, err := repo.ApplyReceipt(ctx, "retail",
+			&dto.ReceiptRequest{MessageID: "webhook-2", Status: dto.ReceiptFailed, Error: "absent subscriber"}, now)
+		assert.NoError(t, err)
+		assert.Equal(t, model.StatusFailed, message.Status)
+		assert.Equal(t, "absent subscriber", message.LastError)
+		assert.Nil(t, message.DeliveredAt)
+	})
+
+	t.Run("repeated receipts", func(t *testing.T) {
+		_, err := repo.ApplyReceipt(ctx, "retail",
+			&dto.ReceiptRequest{MessageID: "webhook-1", Status: dto.ReceiptFailed}, now)
+		assert.ErrorIs(t, err, model.ErrAlreadyExists)
+	})
+
+	t.Run("unknown messages", func(t *testing.T) {
+		_, err := repo.ApplyReceipt(ctx, model.DefaultTenantID,
+			&dto.ReceiptRequest{MessageID: "webhook-1", Status: dto.ReceiptDelivered}, now)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+}
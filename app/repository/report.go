@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// reportStatus tells retried messages, unsent ones with a failed last attempt, apart from the other unsent ones.
+var reportStatus = bson.M{"$cond": bson.A{
+	bson.M{"$and": bson.A{
+		bson.M{"$eq": bson.A{"$status", model.StatusUnsent}},
+		bson.M{"$ne": bson.A{bson.M{"$ifNull": bson.A{"$lastError", ""}}, ""}},
+	}},
+	model.ReportStatusRetrying,
+	"$status",
+}}
+
+// GetMessageReport counts the messages of a tenant created within the range of the request and the segments
+// they were split into, grouped by the interval and dimensions of the request. Rows are ordered by time, then
+// by count.
+func (r *Repository) GetMessageReport(ctx context.Context, tenantID string,
+	request *dto.ReportRequest) ([]model.ReportRow, error) {
+	group := bson.M{}
+	if request.Interval != "" {
+		group["time"] = bson.M{"$dateTrunc": bson.M{"date": "$createdAt", "unit": request.Interval}}
+	}
+	for _, dimension := range request.GroupBy {
+		switch dimension {
+		case dto.ReportGroupStatus:
+			group["status"] = reportStatus
+		case dto.ReportGroupProvider:
+			group["provider"] = "$provider"
+		case dto.ReportGroupCampaign:
+			group["campaignId"] = "$campaignId"
+		}
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"tenantId":  tenantID,
+			"createdAt": bson.M{"$gte": request.From, "$lt": request.To},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":      group,
+			"count":    bson.M{"$sum": 1},
+			"segments": bson.M{"$sum": "$segments"},
+		}}},
+		{{Key: "$sort", Value: bson.D{
+			{Key: "_id.time", Value: 1},
+			{Key: "count", Value: -1},
+			{Key: "_id", Value: 1},
+		}}},
+	}
+
+	result, err := r.messageCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []struct {
+		ID struct {
+			Time       *time.Time          `bson:"time"`
+			Status     string              `bson:"status"`
+			Provider   string              `bson:"provider"`
+			CampaignID *primitive.ObjectID `bson:"campaignId"`
+		} `bson:"_id"`
+		Count    int `bson:"count"`
+		Segments int `bson:"segments"`
+	}
+	if err := result.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	rows := make([]model.ReportRow, 0, len(groups))
+	for _, group := range groups {
+		row := model.ReportRow{
+			Status:   group.ID.Status,
+			Provider: group.ID.Provider,
+			Count:    group.Count,
+			Segments: group.Segments,
+		}
+		if group.ID.Time != nil {
+			bucket := group.ID.Time.UTC()
+			row.Time = &bucket
+		}
+		if group.ID.CampaignID != nil {
+			row.CampaignID = group.ID.CampaignID.Hex()
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRepository_GetMessageReport(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	campaignID := primitive.NewObjectID()
+	messages := []interface{}{
+		model.Message{ID: primitive.NewObjectID(), TenantID: "retail", Status: model.StatusSent, Segments: 2,
+			Provider: "sms.example.com", CampaignID: &campaignID, CreatedAt: from.Add(time.Hour)},
+		model.Message{ID: primitive.NewObjectID(), TenantID: "retail", Status: model.StatusSent, Segments: 1,
+			Provider: "sms.example.com", CreatedAt: from.Add(2 * time.Hour)},
+		model.Message{ID: primitive.NewObjectID(), TenantID: "retail", Status: model.StatusUnsent, Segments: 1,
+			LastError: "provider unavailable", CreatedAt: from.Add(26 * time.Hour)},
+		model.Message{ID: primitive.NewObjectID(), TenantID: "retail", Status: model.StatusUnsent, Segments: 1,
+			CreatedAt: from.Add(27 * time.Hour)},
+		// outside of the range and of another tenant
+		model.Message{ID: primitive.NewObjectID(), TenantID: "retail", Status: model.StatusSent, Segments: 1,
+			CreatedAt: from.Add(-time.Hour)},
+		model.Message{ID: primitive.NewObjectID(), TenantID: "logistics", Status: model.StatusSent, Segments: 1,
+			CreatedAt: from.Add(time.Hour)},
+	}
+	_, err := repo.messageCollection.InsertMany(ctx, messages)
+	assert.NoError(t, err)
+
+	t.Run("summary by status", func(t *testing.T) {
+		rows, err := repo.GetMessageReport(ctx, "retail", &dto.ReportRequest{
+			From:    from,
+			To:      from.AddDate(0, 0, 7),
+			GroupBy: []string{dto.ReportGroupStatus},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []model.ReportRow{
+			{Status: model.StatusSent, Count: 2, Segments: 3},
+			{Status: model.ReportStatusRetrying, Count: 1, Segments: 1},
+			{Status: model.StatusUnsent, Count: 1, Segments: 1},
+		}, rows)
+	})
+
+	t.Run("summary by provider and campaign", func(t *testing.T) {
+		rows, err := repo.GetMessageReport(ctx, "retail", &dto.ReportRequest{
+			From:    from,
+			To:      from.AddDate(0, 0, 7),
+			GroupBy: []string{dto.ReportGroupProvider, dto.ReportGroupCampaign},
+		})
+		assert.NoError(t, err)
+		assert.Len(t, rows, 3)
+		assert.Contains(t, rows, model.ReportRow{Provider: "sms.example.com", CampaignID: campaignID.Hex(),
+			Count: 1, Segments: 2})
+		assert.Contains(t, rows, model.ReportRow{Count: 2, Segments: 2})
+	})
+
+	t.Run("daily time series", func(t *testing.T) {
+		rows, err := repo.GetMessageReport(ctx, "retail", &dto.ReportRequest{
+			From:     from,
+			To:       from.AddDate(0, 0, 7),
+			Interval: dto.ReportIntervalDay,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, rows, 2)
+		assert.Equal(t, from, *rows[0].Time)
+		assert.Equal(t, 2, rows[0].Count)
+		assert.Equal(t, from.AddDate(0, 0, 1), *rows[1].Time)
+		assert.Equal(t, 2, rows[1].Count)
+	})
+}
@@ -2,8 +2,12 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"messaging-system/app/dto"
 	"messaging-system/app/model"
+	"messaging-system/app/pii"
 	"messaging-system/config"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -11,33 +15,368 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+const (
+	codeNamespaceNotFound = 26
+	codeIndexNotFound     = 27
+)
+
 type Repository struct {
-	client            *mongo.Client
-	database          *mongo.Database
-	messageCollection *mongo.Collection
+	client                 *mongo.Client
+	database               *mongo.Database
+	messageCollection      *mongo.Collection
+	outboxCollection       *mongo.Collection
+	resumeTokenCollection  *mongo.Collection
+	templateCollection     *mongo.Collection
+	suppressionCollection  *mongo.Collection
+	inboundCollection      *mongo.Collection
+	notificationCollection *mongo.Collection
+	apiKeyCollection       *mongo.Collection
+	auditCollection        *mongo.Collection
+	campaignCollection     *mongo.Collection
+	contactListCollection  *mongo.Collection
+	contactCollection      *mongo.Collection
+	archiveCollection      *mongo.Collection
+	cipher                 *pii.Cipher
 }
 
-func New(ctx context.Context, conf *config.Mongo) (*Repository, error) {
+// New connects to Mongo, cipher encrypts the phone numbers and content of messages and may be nil.
+func New(ctx context.Context, conf *config.Mongo, cipher *pii.Cipher) (*Repository, error) {
 	client, err := mongo.Connect(ctx, options.Client().ApplyURI(conf.URI))
 	if err != nil {
 		return nil, err
 	}
 
-	return &Repository{
-		client:            client,
-		database:          client.Database(conf.Database),
-		messageCollection: client.Database(conf.Database).Collection(conf.MessageCollection),
-	}, nil
+	database := client.Database(conf.Database)
+	repo := &Repository{
+		client:                 client,
+		database:               database,
+		messageCollection:      database.Collection(conf.MessageCollection),
+		outboxCollection:       database.Collection(conf.OutboxCollection),
+		resumeTokenCollection:  database.Collection(conf.ResumeTokenCollection),
+		templateCollection:     database.Collection(conf.TemplateCollection),
+		suppressionCollection:  database.Collection(conf.SuppressionCollection),
+		inboundCollection:      database.Collection(conf.InboundCollection),
+		notificationCollection: database.Collection(conf.NotificationCollection),
+		apiKeyCollection:       database.Collection(conf.APIKeyCollection),
+		auditCollection:        database.Collection(conf.AuditCollection),
+		campaignCollection:     database.Collection(conf.CampaignCollection),
+		contactListCollection:  database.Collection(conf.ContactListCollection),
+		contactCollection:      database.Collection(conf.ContactCollection),
+		archiveCollection:      database.Collection(conf.ArchiveCollection),
+		cipher:                 cipher,
+	}
+
+	if err := repo.migrateTenants(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := repo.migratePriorities(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := repo.createIndexes(ctx); err != nil {
+		return nil, err
+	}
+
+	return repo, nil
+}
+
+// migrateTenants assigns documents stored before tenants existed to the default tenant.
+func (r *Repository) migrateTenants(ctx context.Context) error {
+	collections := []*mongo.Collection{
+		r.messageCollection,
+		r.templateCollection,
+		r.inboundCollection,
+		r.notificationCollection,
+		r.apiKeyCollection,
+		r.auditCollection,
+	}
+	filter := bson.M{"tenantId": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"tenantId": model.DefaultTenantID}}
+	for _, collection := range collections {
+		if _, err := collection.UpdateMany(ctx, filter, update); err != nil {
+			return err
+		}
+	}
+
+	// suppressions of the default tenant keep the phone number as their id
+	_, err := r.suppressionCollection.UpdateMany(ctx, filter, mongo.Pipeline{
+		{{Key: "$set", Value: bson.M{
+			"tenantId":    model.DefaultTenantID,
+			"phoneNumber": "$_id",
+		}}},
+	})
+	return err
+}
+
+// migratePriorities gives messages stored before priorities existed the normal priority, so they are
+// picked with the normal lane in order of their age.
+func (r *Repository) migratePriorities(ctx context.Context) error {
+	filter := bson.M{"priority": bson.M{"$exists": false}}
+	update := bson.M{"$set": bson.M{"priority": dto.PriorityNormal}}
+	_, err := r.messageCollection.UpdateMany(ctx, filter, update)
+	return err
+}
+
+func (r *Repository) createIndexes(ctx context.Context) error {
+	// unique indexes that became unique per tenant
+	for collection, name := range map[*mongo.Collection]string{
+		r.templateCollection: "name_1",
+		r.inboundCollection:  "providerMessageId_1",
+	} {
+		if err := dropIndex(ctx, collection, name); err != nil {
+			return err
+		}
+	}
+
+	// supports picking unsent messages of all tenants by priority and age
+	_, err := r.messageCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "status", Value: 1},
+			{Key: "priority", Value: -1},
+			{Key: "createdAt", Value: 1},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.messageCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// supports listing the messages of a tenant by status
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "priority", Value: -1},
+				{Key: "createdAt", Value: 1},
+			},
+		},
+		{
+			// supports campaign progress counts, cancelling and throttling campaigns
+			Keys: bson.D{
+				{Key: "campaignId", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "sentAt", Value: 1},
+			},
+			Options: options.Index().
+				SetPartialFilterExpression(bson.M{"campaignId": bson.M{"$exists": true}}),
+		},
+		{
+			// supports applying delivery receipts by the id the provider accepted a message with
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "webhookMessageId", Value: 1},
+			},
+			Options: options.Index().
+				SetPartialFilterExpression(bson.M{"webhookMessageId": bson.M{"$exists": true}}),
+		},
+		{
+			// supports exporting the messages a tenant sent within a time range
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "status", Value: 1},
+				{Key: "sentAt", Value: 1},
+			},
+		},
+		{
+			// supports archiving messages past the retention of their status
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "createdAt", Value: 1},
+			},
+		},
+		{
+			// supports reports over the messages a tenant created within a time range
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "createdAt", Value: 1},
+			},
+		},
+		{
+			// supports conversation lookups by phone number
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "phoneNumber", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+		},
+		{
+			// supports conversation lookups by the blind index of encrypted phone numbers
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "phoneNumberIndex", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+			Options: options.Index().
+				SetPartialFilterExpression(bson.M{"phoneNumberIndex": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.templateCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "tenantId", Value: 1},
+			{Key: "name", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.suppressionCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "tenantId", Value: 1},
+			{Key: "createdAt", Value: -1},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.inboundCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "phoneNumber", Value: 1},
+				{Key: "receivedAt", Value: -1},
+			},
+		},
+		{
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "phoneNumberIndex", Value: 1},
+				{Key: "receivedAt", Value: -1},
+			},
+			Options: options.Index().
+				SetPartialFilterExpression(bson.M{"phoneNumberIndex": bson.M{"$exists": true}}),
+		},
+		{
+			// providers retry webhooks, the same inbound message is stored once
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "providerMessageId", Value: 1},
+			},
+			Options: options.Index().
+				SetUnique(true).
+				SetPartialFilterExpression(bson.M{"providerMessageId": bson.M{"$exists": true}}),
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.notificationCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// supports claiming due notifications
+			Keys: bson.D{
+				{Key: "status", Value: 1},
+				{Key: "nextAttemptAt", Value: 1},
+			},
+		},
+		{
+			Keys: bson.D{{Key: "messageId", Value: 1}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.apiKeyCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys:    bson.D{{Key: "hash", Value: 1}},
+		Options: options.Index().SetUnique(true),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.auditCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+		},
+		{
+			// supports filtering the audit log by action
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "action", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.campaignCollection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			Keys: bson.D{
+				{Key: "tenantId", Value: 1},
+				{Key: "createdAt", Value: -1},
+			},
+		},
+		{
+			// supports finding the campaigns the processor has to hold back
+			Keys: bson.D{{Key: "status", Value: 1}},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = r.contactListCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "tenantId", Value: 1},
+			{Key: "createdAt", Value: -1},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// a phone number is a member of a list once, imports rely on it to skip duplicates
+	_, err = r.contactCollection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{
+			{Key: "listId", Value: 1},
+			{Key: "phoneNumber", Value: 1},
+		},
+		Options: options.Index().SetUnique(true),
+	})
+	return err
+}
+
+// dropIndex removes an index that is no longer used, indexes that do not exist are ignored.
+func dropIndex(ctx context.Context, collection *mongo.Collection, name string) error {
+	_, err := collection.Indexes().DropOne(ctx, name)
+	var commandErr mongo.CommandError
+	if errors.As(err, &commandErr) && (commandErr.Code == codeNamespaceNotFound || commandErr.Code == codeIndexNotFound) {
+		return nil
+	}
+	return err
 }
 
-func (r *Repository) GetMessages(ctx context.Context, status string, limit int) ([]model.Message, error) {
+// messageSort orders messages by priority, then oldest first.
+func messageSort() bson.D {
+	return bson.D{
+		{Key: "priority", Value: -1},
+		{Key: "createdAt", Value: 1},
+	}
+}
+
+func (r *Repository) GetMessages(ctx context.Context, tenantID, status string, limit int) ([]model.Message, error) {
 	var messages []model.Message
 
 	filter := bson.M{
-		"status": status,
+		"tenantId": tenantID,
+		"status":   status,
 	}
 
 	opts := options.Find()
+	opts.SetSort(messageSort())
 	opts.SetLimit(int64(limit))
 
 	result, err := r.messageCollection.Find(ctx, filter, opts)
@@ -49,16 +388,224 @@ func (r *Repository) GetMessages(ctx context.Context, status string, limit int)
 		return nil, err
 	}
 
+	if err := r.decryptMessages(messages); err != nil {
+		return nil, err
+	}
 	return messages, nil
 }
 
-func (r *Repository) MarkMessageAsSent(ctx context.Context, messageID primitive.ObjectID, webhookMessageID string) error {
+// ForEachSentMessage calls handle for every message of a tenant sent within [from, to) in the order they were
+// sent, without loading them into memory. A zero from or to leaves the range open, it stops at the first error.
+func (r *Repository) ForEachSentMessage(ctx context.Context, tenantID string, from, to time.Time,
+	handle func(ctx context.Context, message *model.Message) error) error {
+	filter := bson.M{
+		"tenantId": tenantID,
+		"status":   model.StatusSent,
+	}
+	sentAt := bson.M{}
+	if !from.IsZero() {
+		sentAt["$gte"] = from
+	}
+	if !to.IsZero() {
+		sentAt["$lt"] = to
+	}
+	if len(sentAt) > 0 {
+		filter["sentAt"] = sentAt
+	}
+
+	cursor, err := r.messageCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "sentAt", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		message := &model.Message{}
+		if err := cursor.Decode(message); err != nil {
+			return err
+		}
+		if err := r.decryptMessage(message); err != nil {
+			return err
+		}
+
+		if err := handle(ctx, message); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// GetMessagesByPriority returns messages of all tenants, it feeds the processor which sends for every tenant.
+// Messages of the excluded campaigns are skipped.
+func (r *Repository) GetMessagesByPriority(ctx context.Context, status string, priorities []int,
+	excludedCampaigns []primitive.ObjectID, limit int) ([]model.Message, error) {
+	var messages []model.Message
+
+	filter := bson.M{
+		"status":   status,
+		"priority": bson.M{"$in": priorities},
+	}
+	if len(excludedCampaigns) > 0 {
+		filter["campaignId"] = bson.M{"$nin": excludedCampaigns}
+	}
+
+	opts := options.Find()
+	opts.SetSort(messageSort())
+	opts.SetLimit(int64(limit))
+
+	result, err := r.messageCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+
+	if err := r.decryptMessages(messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *Repository) CreateMessage(ctx context.Context, message *model.Message) error {
+	stored, err := r.encryptMessage(message)
+	if err != nil {
+		return err
+	}
+
+	_, err = r.messageCollection.InsertOne(ctx, stored)
+	return err
+}
+
+// CreateMessages stores messages with a single insert, it is used to fan out a campaign.
+func (r *Repository) CreateMessages(ctx context.Context, messages []*model.Message) error {
+	documents := make([]interface{}, 0, len(messages))
+	for _, message := range messages {
+		stored, err := r.encryptMessage(message)
+		if err != nil {
+			return err
+		}
+		documents = append(documents, stored)
+	}
+
+	_, err := r.messageCollection.InsertMany(ctx, documents)
+	return err
+}
+
+// ExpireMessages moves unsent messages whose expiry has passed to the expired status and returns them.
+// Each message is moved on its own while it is still unsent, so a message sent in the meantime is
+// neither expired nor returned.
+func (r *Repository) ExpireMessages(ctx context.Context, now time.Time) ([]model.Message, error) {
+	overdue := []model.Message{}
+	result, err := r.messageCollection.Find(ctx, bson.M{
+		"status":    model.StatusUnsent,
+		"expiresAt": bson.M{"$lte": now},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := result.All(ctx, &overdue); err != nil {
+		return nil, err
+	}
+
+	expired := make([]model.Message, 0, len(overdue))
+	for _, message := range overdue {
+		updated, err := r.messageCollection.UpdateOne(ctx,
+			bson.M{"_id": message.ID, "status": model.StatusUnsent},
+			bson.M{"$set": bson.M{"status": model.StatusExpired}})
+		if err != nil {
+			return nil, err
+		}
+		if updated.ModifiedCount == 0 {
+			continue
+		}
+		message.Status = model.StatusExpired
+		expired = append(expired, message)
+	}
+
+	if err := r.decryptMessages(expired); err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+// WatchUnsentMessages tails inserts of unsent messages and calls handle for each of them
+// until ctx is cancelled or the stream fails.
+func (r *Repository) WatchUnsentMessages(ctx context.Context,
+	handle func(ctx context.Context, message *model.Message) error) error {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"operationType":       "insert",
+			"fullDocument.status": model.StatusUnsent,
+		}}},
+	}
+
+	stream, err := r.messageCollection.Watch(ctx, pipeline)
+	if err != nil {
+		return err
+	}
+	defer stream.Close(ctx)
+
+	for stream.Next(ctx) {
+		var event struct {
+			FullDocument model.Message `bson:"fullDocument"`
+		}
+		if err := stream.Decode(&event); err != nil {
+			return err
+		}
+		if err := r.decryptMessage(&event.FullDocument); err != nil {
+			return err
+		}
+
+		if err := handle(ctx, &event.FullDocument); err != nil {
+			return err
+		}
+	}
+
+	return stream.Err()
+}
+
+func (r *Repository) UpdateMessageStatus(ctx context.Context, messageID primitive.ObjectID, status string) error {
+	filter := bson.M{"_id": messageID}
+	update := bson.M{
+		"$set": bson.M{
+			"status": status,
+		},
+	}
+	_, err := r.messageCollection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+func (r *Repository) MarkMessageAsSent(ctx context.Context, messageID primitive.ObjectID, webhookMessageID,
+	provider string) error {
 	filter := bson.M{"_id": messageID}
 	update := bson.M{
 		"$set": bson.M{
 			"status":           "sent",
 			"webhookMessageId": webhookMessageID,
+			"provider":         provider,
+			"sentAt":           time.Now().UTC(),
 		},
+		"$unset": bson.M{"lastError": ""},
+	}
+	_, err := r.messageCollection.UpdateOne(ctx, filter, update)
+	return err
+}
+
+// RecordSendFailure keeps the reason the last send attempt of a message failed and counts the attempt,
+// the message moves to the failed status after its final attempt.
+func (r *Repository) RecordSendFailure(ctx context.Context, messageID primitive.ObjectID, reason string,
+	final bool) error {
+	filter := bson.M{"_id": messageID}
+	set := bson.M{"lastError": reason}
+	if final {
+		set["status"] = model.StatusFailed
+	}
+	update := bson.M{
+		"$set": set,
+		"$inc": bson.M{"attempts": 1},
 	}
 	_, err := r.messageCollection.UpdateOne(ctx, filter, update)
 	return err
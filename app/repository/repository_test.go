@@ -2,8 +2,10 @@ package repository
 
 import (
 	"context"
+	"messaging-system/app/model"
 	"messaging-system/config"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/testcontainers/testcontainers-go/modules/mongodb"
@@ -12,9 +14,21 @@ import (
 )
 
 const (
-	mongoImage     = "mongo:7.0.4"
-	mockDB         = "message"
-	mockCollection = "messages"
+	mongoImage                 = "mongo:7.0.4"
+	mockDB                     = "message"
+	mockCollection             = "messages"
+	mockOutboxCollection       = "outbox"
+	mockResumeTokenCollection  = "resumeTokens"
+	mockTemplateCollection     = "templates"
+	mockSuppressionCollection  = "suppressions"
+	mockInboundCollection      = "inboundMessages"
+	mockNotificationCollection = "notifications"
+	mockAPIKeyCollection       = "apiKeys"
+	mockAuditCollection        = "auditLog"
+	mockCampaignCollection     = "campaigns"
+	mockContactListCollection  = "contactLists"
+	mockContactCollection      = "contacts"
+	mockArchiveCollection      = "messagesArchive"
 )
 
 func createTestContainer(ctx context.Context) (repo *Repository, clean func()) {
@@ -35,10 +49,22 @@ func createTestContainer(ctx context.Context) (repo *Repository, clean func()) {
 	}
 
 	repo, err = New(ctx, &config.Mongo{
-		URI:               uri,
-		Database:          mockDB,
-		MessageCollection: mockCollection,
-	})
+		URI:                    uri,
+		Database:               mockDB,
+		MessageCollection:      mockCollection,
+		OutboxCollection:       mockOutboxCollection,
+		ResumeTokenCollection:  mockResumeTokenCollection,
+		TemplateCollection:     mockTemplateCollection,
+		SuppressionCollection:  mockSuppressionCollection,
+		InboundCollection:      mockInboundCollection,
+		NotificationCollection: mockNotificationCollection,
+		APIKeyCollection:       mockAPIKeyCollection,
+		AuditCollection:        mockAuditCollection,
+		CampaignCollection:     mockCampaignCollection,
+		ContactListCollection:  mockContactListCollection,
+		ContactCollection:      mockContactCollection,
+		ArchiveCollection:      mockArchiveCollection,
+	}, nil)
 	if err != nil {
 		panic(err)
 	}
@@ -52,27 +78,195 @@ func TestRepository_GetMessages(t *testing.T) {
 	defer clean()
 
 	t.Run("", func(t *testing.T) {
-		messages, err := repo.GetMessages(ctx, "unsent", 10)
+		messages, err := repo.GetMessages(ctx, model.DefaultTenantID, "unsent", 10)
 		assert.NoError(t, err)
 		assert.Len(t, messages, 0)
 	})
 
 	t.Run("returns only unsent messages with limit", func(t *testing.T) {
 		testData := []interface{}{
-			bson.M{"_id": primitive.NewObjectID(), "status": "unsent", "to": "+9053", "content": "A"},
-			bson.M{"_id": primitive.NewObjectID(), "status": "unsent", "to": "+9053", "content": "B"},
-			bson.M{"_id": primitive.NewObjectID(), "status": "sent", "to": "+9053", "content": "C"},
+			bson.M{"_id": primitive.NewObjectID(), "tenantId": "default", "status": "unsent", "to": "+9053", "content": "A"},
+			bson.M{"_id": primitive.NewObjectID(), "tenantId": "default", "status": "unsent", "to": "+9053", "content": "B"},
+			bson.M{"_id": primitive.NewObjectID(), "tenantId": "default", "status": "sent", "to": "+9053", "content": "C"},
 		}
 
 		_, err := repo.messageCollection.InsertMany(ctx, testData)
 		assert.NoError(t, err)
 
-		messages, err := repo.GetMessages(ctx, "unsent", 1)
+		messages, err := repo.GetMessages(ctx, model.DefaultTenantID, "unsent", 1)
 		assert.NoError(t, err)
 
 		assert.Len(t, messages, 1)
 		assert.Equal(t, "unsent", messages[0].Status)
 	})
+
+	t.Run("returns only messages of the tenant", func(t *testing.T) {
+		_, err := repo.messageCollection.InsertOne(ctx,
+			bson.M{"_id": primitive.NewObjectID(), "tenantId": "retail", "status": "unsent", "content": "D"})
+		assert.NoError(t, err)
+
+		messages, err := repo.GetMessages(ctx, "retail", "unsent", 10)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, "D", messages[0].Content)
+	})
+}
+
+func TestRepository_GetMessagesByPriority(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	t.Run("returns messages of the lane by priority then creation time", func(t *testing.T) {
+		now := time.Now().UTC()
+		testData := []interface{}{
+			bson.M{"_id": primitive.NewObjectID(), "tenantId": "default", "status": "unsent", "priority": 0, "content": "normal", "createdAt": now},
+			bson.M{"_id": primitive.NewObjectID(), "tenantId": "default", "status": "unsent", "priority": -1, "content": "low", "createdAt": now.Add(-time.Hour)},
+			bson.M{"_id": primitive.NewObjectID(), "tenantId": "default", "status": "unsent", "priority": 1, "content": "high-new", "createdAt": now},
+			bson.M{"_id": primitive.NewObjectID(), "tenantId": "default", "status": "unsent", "priority": 1, "content": "high-old", "createdAt": now.Add(-time.Minute)},
+			bson.M{"_id": primitive.NewObjectID(), "tenantId": "default", "status": "unsent", "content": "legacy", "createdAt": now.Add(time.Minute)},
+		}
+
+		_, err := repo.messageCollection.InsertMany(ctx, testData)
+		assert.NoError(t, err)
+		assert.NoError(t, repo.migratePriorities(ctx))
+
+		messages, err := repo.GetMessagesByPriority(ctx, "unsent", []int{0, -1}, nil, 10)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 3)
+		assert.Equal(t, "normal", messages[0].Content)
+		assert.Equal(t, "legacy", messages[1].Content)
+		assert.Equal(t, "low", messages[2].Content)
+
+		messages, err = repo.GetMessages(ctx, model.DefaultTenantID, "unsent", 2)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 2)
+		assert.Equal(t, "high-old", messages[0].Content)
+		assert.Equal(t, "high-new", messages[1].Content)
+	})
+}
+
+func TestRepository_ExpireMessages(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	t.Run("expires only overdue unsent messages", func(t *testing.T) {
+		now := time.Now().UTC()
+		overdue := primitive.NewObjectID()
+		testData := []interface{}{
+			bson.M{"_id": overdue, "status": "unsent", "expiresAt": now.Add(-time.Minute)},
+			bson.M{"_id": primitive.NewObjectID(), "status": "unsent", "expiresAt": now.Add(time.Minute)},
+			bson.M{"_id": primitive.NewObjectID(), "status": "unsent"},
+			bson.M{"_id": primitive.NewObjectID(), "status": "sent", "expiresAt": now.Add(-time.Minute)},
+		}
+
+		_, err := repo.messageCollection.InsertMany(ctx, testData)
+		assert.NoError(t, err)
+
+		expired, err := repo.ExpireMessages(ctx, now)
+		assert.NoError(t, err)
+		assert.Len(t, expired, 1)
+		assert.Equal(t, overdue, expired[0].ID)
+		assert.Equal(t, model.StatusExpired, expired[0].Status)
+
+		expired, err = repo.ExpireMessages(ctx, now)
+		assert.NoError(t, err)
+		assert.Empty(t, expired)
+
+		var result bson.M
+		err = repo.messageCollection.FindOne(ctx, bson.M{"_id": overdue}).Decode(&result)
+		assert.NoError(t, err)
+		assert.Equal(t, "expired", result["status"])
+	})
+}
+
+func TestRepository_ForEachSentMessage(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	first, second := primitive.NewObjectID(), primitive.NewObjectID()
+	testData := []interface{}{
+		bson.M{"_id": second, "tenantId": "retail", "status": "sent", "sentAt": from.Add(2 * time.Hour)},
+		bson.M{"_id": first, "tenantId": "retail", "status": "sent", "sentAt": from.Add(time.Hour)},
+		bson.M{"_id": primitive.NewObjectID(), "tenantId": "retail", "status": "sent", "sentAt": from.Add(-time.Hour)},
+		bson.M{"_id": primitive.NewObjectID(), "tenantId": "retail", "status": "unsent"},
+		bson.M{"_id": primitive.NewObjectID(), "tenantId": "logistics", "status": "sent", "sentAt": from.Add(time.Hour)},
+	}
+	_, err := repo.messageCollection.InsertMany(ctx, testData)
+	assert.NoError(t, err)
+
+	t.Run("iterates the sent messages of the range in the order they were sent", func(t *testing.T) {
+		exported := []primitive.ObjectID{}
+		err := repo.ForEachSentMessage(ctx, "retail", from, from.AddDate(0, 0, 1),
+			func(_ context.Context, message *model.Message) error {
+				exported = append(exported, message.ID)
+				return nil
+			})
+		assert.NoError(t, err)
+		assert.Equal(t, []primitive.ObjectID{first, second}, exported)
+	})
+
+	t.Run("open range", func(t *testing.T) {
+		count := 0
+		err := repo.ForEachSentMessage(ctx, "retail", time.Time{}, time.Time{},
+			func(context.Context, *model.Message) error {
+				count++
+				return nil
+			})
+		assert.NoError(t, err)
+		assert.Equal(t, 3, count)
+	})
+
+	t.Run("stops at the first error", func(t *testing.T) {
+		err := repo.ForEachSentMessage(ctx, "retail", time.Time{}, time.Time{},
+			func(context.Context, *model.Message) error {
+				return assert.AnError
+			})
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestRepository_CreateMessage(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	t.Run("successfully inserts unsent message", func(t *testing.T) {
+		message := &model.Message{
+			ID:          primitive.NewObjectID(),
+			PhoneNumber: "+905551112233",
+			Content:     "Hello",
+			Status:      "unsent",
+			CreatedAt:   time.Now().UTC(),
+		}
+
+		err := repo.CreateMessage(ctx, message)
+		assert.NoError(t, err)
+
+		var result bson.M
+		err = repo.messageCollection.FindOne(ctx, bson.M{"_id": message.ID}).Decode(&result)
+		assert.NoError(t, err)
+
+		assert.Equal(t, "unsent", result["status"])
+		assert.Equal(t, "+905551112233", result["phoneNumber"])
+	})
+
+	t.Run("inserts the messages of a campaign at once", func(t *testing.T) {
+		campaignID := primitive.NewObjectID()
+		messages := []*model.Message{
+			{ID: primitive.NewObjectID(), PhoneNumber: "+905551112244", Status: "unsent", CampaignID: &campaignID},
+			{ID: primitive.NewObjectID(), PhoneNumber: "+905551112255", Status: "unsent", CampaignID: &campaignID},
+		}
+
+		assert.NoError(t, repo.CreateMessages(ctx, messages))
+
+		count, err := repo.messageCollection.CountDocuments(ctx, bson.M{"campaignId": campaignID})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+	})
 }
 
 func TestRepository_MarkMessageAsSent(t *testing.T) {
@@ -91,7 +285,7 @@ func TestRepository_MarkMessageAsSent(t *testing.T) {
 		assert.NoError(t, err)
 
 		webhookID := "webhook-123"
-		err = repo.MarkMessageAsSent(ctx, id, webhookID)
+		err = repo.MarkMessageAsSent(ctx, id, webhookID, "sms.example.com")
 		assert.NoError(t, err)
 
 		var result bson.M
@@ -100,5 +294,61 @@ func TestRepository_MarkMessageAsSent(t *testing.T) {
 
 		assert.Equal(t, "sent", result["status"])
 		assert.Equal(t, webhookID, result["webhookMessageId"])
+		assert.Equal(t, "sms.example.com", result["provider"])
+		assert.NotNil(t, result["sentAt"])
+	})
+
+	t.Run("clears the last send failure", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		_, err := repo.messageCollection.InsertOne(ctx, bson.M{"_id": id, "status": "unsent", "content": "Hello"})
+		assert.NoError(t, err)
+
+		assert.NoError(t, repo.RecordSendFailure(ctx, id, "provider unavailable", false))
+		var failed model.Message
+		assert.NoError(t, repo.messageCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&failed))
+		assert.Equal(t, "provider unavailable", failed.LastError)
+		assert.Equal(t, 1, failed.Attempts)
+		assert.Equal(t, model.StatusUnsent, failed.Status)
+
+		assert.NoError(t, repo.MarkMessageAsSent(ctx, id, "webhook-456", "sms.example.com"))
+		var sent model.Message
+		assert.NoError(t, repo.messageCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&sent))
+		assert.Empty(t, sent.LastError)
+	})
+
+	t.Run("fails the message after its final attempt", func(t *testing.T) {
+		id := primitive.NewObjectID()
+		_, err := repo.messageCollection.InsertOne(ctx, bson.M{"_id": id, "status": "unsent", "attempts": 2})
+		assert.NoError(t, err)
+
+		assert.NoError(t, repo.RecordSendFailure(ctx, id, "provider unavailable", true))
+		var failed model.Message
+		assert.NoError(t, repo.messageCollection.FindOne(ctx, bson.M{"_id": id}).Decode(&failed))
+		assert.Equal(t, model.StatusFailed, failed.Status)
+		assert.Equal(t, 3, failed.Attempts)
+	})
+}
+
+func TestRepository_MigrateTenants(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	t.Run("assigns legacy documents to the default tenant", func(t *testing.T) {
+		messageID := primitive.NewObjectID()
+		_, err := repo.messageCollection.InsertOne(ctx, bson.M{"_id": messageID, "status": "unsent"})
+		assert.NoError(t, err)
+		_, err = repo.suppressionCollection.InsertOne(ctx, bson.M{"_id": "+905551112233", "source": "manual"})
+		assert.NoError(t, err)
+
+		assert.NoError(t, repo.migrateTenants(ctx))
+
+		var message bson.M
+		assert.NoError(t, repo.messageCollection.FindOne(ctx, bson.M{"_id": messageID}).Decode(&message))
+		assert.Equal(t, model.DefaultTenantID, message["tenantId"])
+
+		suppression, err := repo.GetSuppression(ctx, model.DefaultTenantID, "+905551112233")
+		assert.NoError(t, err)
+		assert.Equal(t, "+905551112233", suppression.PhoneNumber)
 	})
 }
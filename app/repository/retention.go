@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetMessagesCreatedBefore returns the oldest messages of all tenants with the status created before the time
+// as they are stored: encrypted fields stay encrypted and the blind index is kept, so archives never hold
+// plaintext of encrypted messages.
+func (r *Repository) GetMessagesCreatedBefore(ctx context.Context, status string, before time.Time,
+	limit int) ([]model.Message, error) {
+	messages := []model.Message{}
+
+	filter := bson.M{
+		"status":    status,
+		"createdAt": bson.M{"$lt": before},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetLimit(int64(limit))
+
+	result, err := r.messageCollection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// CountMessagesCreatedBefore counts the messages of a tenant with the status created before the time and
+// returns the creation time of the oldest one, nil without messages.
+func (r *Repository) CountMessagesCreatedBefore(ctx context.Context, tenantID, status string,
+	before time.Time) (int64, *time.Time, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.M{
+			"tenantId":  tenantID,
+			"status":    status,
+			"createdAt": bson.M{"$lt": before},
+		}}},
+		{{Key: "$group", Value: bson.M{
+			"_id":    nil,
+			"count":  bson.M{"$sum": 1},
+			"oldest": bson.M{"$min": "$createdAt"},
+		}}},
+	}
+
+	result, err := r.messageCollection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var groups []struct {
+		Count  int64     `bson:"count"`
+		Oldest time.Time `bson:"oldest"`
+	}
+	if err := result.All(ctx, &groups); err != nil {
+		return 0, nil, err
+	}
+	if len(groups) == 0 {
+		return 0, nil, nil
+	}
+
+	oldest := groups[0].Oldest.UTC()
+	return groups[0].Count, &oldest, nil
+}
+
+// ArchiveMessages copies messages as GetMessagesCreatedBefore returned them into the archive collection.
+// Messages archived before are skipped, so a batch that was archived but not deleted can be archived again.
+func (r *Repository) ArchiveMessages(ctx context.Context, messages []model.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	documents := make([]interface{}, 0, len(messages))
+	for i := range messages {
+		documents = append(documents, &messages[i])
+	}
+
+	_, err := r.archiveCollection.InsertMany(ctx, documents, options.InsertMany().SetOrdered(false))
+	var writeErr mongo.BulkWriteException
+	if errors.As(err, &writeErr) && onlyDuplicateKeys(writeErr) {
+		return nil
+	}
+	return err
+}
+
+func (r *Repository) DeleteMessages(ctx context.Context, messageIDs []primitive.ObjectID) (int64, error) {
+	result, err := r.messageCollection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": messageIDs}})
+	if err != nil {
+		return 0, err
+	}
+	return result.DeletedCount, nil
+}
+
+func onlyDuplicateKeys(err mongo.BulkWriteException) bool {
+	if err.WriteConcernError != nil {
+		return false
+	}
+	for _, writeErr := range err.WriteErrors {
+		if !mongo.IsDuplicateKeyError(writeErr) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRepository_Retention(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	cutoff := time.Date(2026, 7, 1, 0, 0, 0, 0, time.UTC)
+	oldest, older := primitive.NewObjectID(), primitive.NewObjectID()
+	testData := []interface{}{
+		model.Message{ID: older, TenantID: "retail", Status: model.StatusSent, CreatedAt: cutoff.AddDate(0, 0, -1)},
+		model.Message{ID: oldest, TenantID: "retail", Status: model.StatusSent, CreatedAt: cutoff.AddDate(0, 0, -2)},
+		model.Message{ID: primitive.NewObjectID(), TenantID: "logistics", Status: model.StatusSent,
+			CreatedAt: cutoff.AddDate(0, 0, -3)},
+		model.Message{ID: primitive.NewObjectID(), TenantID: "retail", Status: model.StatusSent, CreatedAt: cutoff},
+		model.Message{ID: primitive.NewObjectID(), TenantID: "retail", Status: model.StatusExpired,
+			CreatedAt: cutoff.AddDate(0, 0, -1)},
+	}
+	_, err := repo.messageCollection.InsertMany(ctx, testData)
+	assert.NoError(t, err)
+
+	t.Run("count messages created before", func(t *testing.T) {
+		count, first, err := repo.CountMessagesCreatedBefore(ctx, "retail", model.StatusSent, cutoff)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+		assert.Equal(t, cutoff.AddDate(0, 0, -2), *first)
+
+		count, first, err = repo.CountMessagesCreatedBefore(ctx, "retail", model.StatusCancelled, cutoff)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), count)
+		assert.Nil(t, first)
+	})
+
+	t.Run("archive and delete messages created before", func(t *testing.T) {
+		messages, err := repo.GetMessagesCreatedBefore(ctx, model.StatusSent, cutoff, 2)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 2)
+		assert.Equal(t, "logistics", messages[0].TenantID)
+		assert.Equal(t, oldest, messages[1].ID)
+
+		assert.NoError(t, repo.ArchiveMessages(ctx, messages))
+		// a batch that was archived but not deleted is archived again
+		assert.NoError(t, repo.ArchiveMessages(ctx, messages))
+		archived, err := repo.archiveCollection.CountDocuments(ctx, bson.M{})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), archived)
+
+		deleted, err := repo.DeleteMessages(ctx, []primitive.ObjectID{messages[0].ID, messages[1].ID})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), deleted)
+
+		messages, err = repo.GetMessagesCreatedBefore(ctx, model.StatusSent, cutoff, 2)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, older, messages[0].ID)
+	})
+}
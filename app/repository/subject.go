@@ -0,0 +1,268 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GetSubjectMessages returns every message of a tenant to a phone number oldest first, from the archive
+// collection when archived is set.
+func (r *Repository) GetSubjectMessages(ctx context.Context, tenantID, phoneNumber string,
+	archived bool) ([]model.Message, error) {
+	messages := []model.Message{}
+
+	collection := r.messageCollection
+	if archived {
+		collection = r.archiveCollection
+	}
+
+	filter := r.matchPhoneNumber(bson.M{"tenantId": tenantID}, phoneNumber)
+	result, err := collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "createdAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+
+	if err := r.decryptMessages(messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// GetSubjectInboundMessages returns every inbound message of a tenant from a phone number oldest first.
+func (r *Repository) GetSubjectInboundMessages(ctx context.Context, tenantID,
+	phoneNumber string) ([]model.InboundMessage, error) {
+	messages := []model.InboundMessage{}
+
+	filter := r.matchPhoneNumber(bson.M{"tenantId": tenantID}, phoneNumber)
+	result, err := r.inboundCollection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "receivedAt", Value: 1}}))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &messages); err != nil {
+		return nil, err
+	}
+
+	if err := r.decryptInboundMessages(messages); err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// GetSubjectContacts returns the memberships of a phone number in the contact lists of a tenant.
+func (r *Repository) GetSubjectContacts(ctx context.Context, tenantID, phoneNumber string) ([]model.Contact, error) {
+	contacts := []model.Contact{}
+
+	result, err := r.contactCollection.Find(ctx, bson.M{
+		"tenantId":    tenantID,
+		"phoneNumber": phoneNumber,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &contacts); err != nil {
+		return nil, err
+	}
+
+	return contacts, nil
+}
+
+// GetSubjectOutboxMessages returns the outbox rows of a tenant to a phone number, whether relayed or not.
+func (r *Repository) GetSubjectOutboxMessages(ctx context.Context, tenantID,
+	phoneNumber string) ([]model.OutboxMessage, error) {
+	outboxMessages := []model.OutboxMessage{}
+
+	result, err := r.outboxCollection.Find(ctx, outboxSubjectFilter(tenantID, phoneNumber))
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &outboxMessages); err != nil {
+		return nil, err
+	}
+
+	return outboxMessages, nil
+}
+
+// GetSubjectCampaigns returns the campaigns of a tenant whose audience holds a phone number oldest first,
+// with the reason no message was created for it when it was rejected. The rest of the audience is left out.
+func (r *Repository) GetSubjectCampaigns(ctx context.Context, tenantID,
+	phoneNumber string) ([]model.SubjectCampaign, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: 1}}).
+		SetProjection(bson.M{
+			"name":      1,
+			"status":    1,
+			"createdAt": 1,
+			"rejected":  bson.M{"$elemMatch": bson.M{"phonenumber": phoneNumber}},
+		})
+	result, err := r.campaignCollection.Find(ctx, campaignSubjectFilter(tenantID, phoneNumber), opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var found []struct {
+		model.SubjectCampaign `bson:",inline"`
+		Rejected              []dto.InvalidPhoneNumber `bson:"rejected"`
+	}
+	if err := result.All(ctx, &found); err != nil {
+		return nil, err
+	}
+
+	campaigns := make([]model.SubjectCampaign, 0, len(found))
+	for _, campaign := range found {
+		if len(campaign.Rejected) > 0 {
+			campaign.SubjectCampaign.Rejected = true
+			campaign.Error = campaign.Rejected[0].Error
+		}
+		campaigns = append(campaigns, campaign.SubjectCampaign)
+	}
+	return campaigns, nil
+}
+
+// EraseSubject deletes the messages, archived messages, inbound messages, contacts and outbox rows of a
+// tenant holding a phone number and removes it from the audience of campaigns. The suppression of the number
+// is kept so it is never messaged again.
+func (r *Repository) EraseSubject(ctx context.Context, tenantID, phoneNumber string) (*model.SubjectErasure, error) {
+	erasure := &model.SubjectErasure{PhoneNumber: phoneNumber, Mode: model.ErasureModeErase}
+	subject := r.matchPhoneNumber(bson.M{"tenantId": tenantID}, phoneNumber)
+
+	for _, erase := range []struct {
+		collection *mongo.Collection
+		filter     bson.M
+		count      *int64
+	}{
+		{r.messageCollection, subject, &erasure.Messages},
+		{r.archiveCollection, subject, &erasure.ArchivedMessages},
+		{r.inboundCollection, subject, &erasure.InboundMessages},
+		{r.contactCollection, bson.M{"tenantId": tenantID, "phoneNumber": phoneNumber}, &erasure.Contacts},
+		{r.outboxCollection, outboxSubjectFilter(tenantID, phoneNumber), &erasure.OutboxMessages},
+	} {
+		result, err := erase.collection.DeleteMany(ctx, erase.filter)
+		if err != nil {
+			return nil, err
+		}
+		*erase.count = result.DeletedCount
+	}
+
+	campaigns, err := r.eraseSubjectCampaigns(ctx, tenantID, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	erasure.Campaigns = campaigns
+
+	return erasure, nil
+}
+
+// AnonymizeSubject removes a phone number and the content sent to or received from it from the messages,
+// archived messages and inbound messages of a tenant, which are kept for reports. Unsent messages are
+// cancelled, contacts and outbox rows holding the number are deleted and it is removed from the audience of
+// campaigns.
+func (r *Repository) AnonymizeSubject(ctx context.Context, tenantID,
+	phoneNumber string) (*model.SubjectErasure, error) {
+	erasure := &model.SubjectErasure{PhoneNumber: phoneNumber, Mode: model.ErasureModeAnonymize}
+	subject := r.matchPhoneNumber(bson.M{"tenantId": tenantID}, phoneNumber)
+
+	cancel := r.matchPhoneNumber(bson.M{"tenantId": tenantID, "status": model.StatusUnsent}, phoneNumber)
+	if _, err := r.messageCollection.UpdateMany(ctx, cancel,
+		bson.M{"$set": bson.M{"status": model.StatusCancelled}}); err != nil {
+		return nil, err
+	}
+
+	anonymize := func(contentField string) bson.M {
+		return bson.M{
+			"$set":   bson.M{"phoneNumber": model.AnonymizedPhoneNumber, contentField: ""},
+			"$unset": bson.M{"rawPhoneNumber": "", "phoneNumberIndex": ""},
+		}
+	}
+	for _, update := range []struct {
+		collection *mongo.Collection
+		update     bson.M
+		count      *int64
+	}{
+		{r.messageCollection, anonymize("content"), &erasure.Messages},
+		{r.archiveCollection, anonymize("content"), &erasure.ArchivedMessages},
+		{r.inboundCollection, anonymize("text"), &erasure.InboundMessages},
+	} {
+		result, err := update.collection.UpdateMany(ctx, subject, update.update)
+		if err != nil {
+			return nil, err
+		}
+		*update.count = result.ModifiedCount
+	}
+
+	result, err := r.contactCollection.DeleteMany(ctx, bson.M{"tenantId": tenantID, "phoneNumber": phoneNumber})
+	if err != nil {
+		return nil, err
+	}
+	erasure.Contacts = result.DeletedCount
+
+	result, err = r.outboxCollection.DeleteMany(ctx, outboxSubjectFilter(tenantID, phoneNumber))
+	if err != nil {
+		return nil, err
+	}
+	erasure.OutboxMessages = result.DeletedCount
+
+	campaigns, err := r.eraseSubjectCampaigns(ctx, tenantID, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	erasure.Campaigns = campaigns
+
+	return erasure, nil
+}
+
+// outboxTenantFilter matches the outbox rows of a tenant, rows without a tenant belong to the default
+// tenant.
+func outboxTenantFilter(tenantID string) bson.M {
+	if tenantID == model.DefaultTenantID {
+		return bson.M{"tenantId": bson.M{"$in": bson.A{tenantID, nil}}}
+	}
+	return bson.M{"tenantId": tenantID}
+}
+
+// outboxSubjectFilter matches the outbox rows of a tenant to a phone number by their normalized phone
+// number, other services write the number in whatever format they received it.
+func outboxSubjectFilter(tenantID, phoneNumber string) bson.M {
+	filter := outboxTenantFilter(tenantID)
+	filter["normalizedPhoneNumber"] = phoneNumber
+	return filter
+}
+
+// campaignSubjectFilter matches the campaigns of a tenant whose audience holds a phone number, whether a
+// message was created for it or it was rejected. Rejected recipients are stored with the lowercased field
+// names the driver gives the untagged dto.InvalidPhoneNumber.
+func campaignSubjectFilter(tenantID, phoneNumber string) bson.M {
+	return bson.M{
+		"tenantId": tenantID,
+		"$or": bson.A{
+			bson.M{"audience": phoneNumber},
+			bson.M{"rejected.phonenumber": phoneNumber},
+		},
+	}
+}
+
+// eraseSubjectCampaigns removes a phone number from the audience and the rejected recipients of the
+// campaigns of a tenant and returns how many campaigns held it.
+func (r *Repository) eraseSubjectCampaigns(ctx context.Context, tenantID, phoneNumber string) (int64, error) {
+	result, err := r.campaignCollection.UpdateMany(ctx, campaignSubjectFilter(tenantID, phoneNumber), bson.M{
+		"$pull": bson.M{
+			"audience": phoneNumber,
+			"rejected": bson.M{"phonenumber": phoneNumber},
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
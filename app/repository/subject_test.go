@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRepository_Subjects(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	phoneNumber := "+905551112233"
+	now := time.Now().UTC()
+	seed := func(tenantID string) {
+		messages := []*model.Message{
+			{ID: primitive.NewObjectID(), TenantID: tenantID, PhoneNumber: phoneNumber, Content: "shipped",
+				Status: model.StatusSent, CreatedAt: now},
+			{ID: primitive.NewObjectID(), TenantID: tenantID, PhoneNumber: phoneNumber, Content: "reminder",
+				Status: model.StatusUnsent, CreatedAt: now},
+			{ID: primitive.NewObjectID(), TenantID: tenantID, PhoneNumber: "+905550000000", Content: "other",
+				Status: model.StatusSent, CreatedAt: now},
+		}
+		for _, message := range messages {
+			assert.NoError(t, repo.CreateMessage(ctx, message))
+		}
+		assert.NoError(t, repo.ArchiveMessages(ctx, []model.Message{{ID: primitive.NewObjectID(), TenantID: tenantID,
+			PhoneNumber: phoneNumber, Content: "archived", Status: model.StatusSent, CreatedAt: now.AddDate(-1, 0, 0)}}))
+		assert.NoError(t, repo.CreateInboundMessage(ctx, &model.InboundMessage{ID: primitive.NewObjectID(),
+			TenantID: tenantID, PhoneNumber: phoneNumber, Text: "STOP", ReceivedAt: now}))
+		_, err := repo.AddContacts(ctx, []model.Contact{{ID: primitive.NewObjectID(), TenantID: tenantID,
+			ListID: primitive.NewObjectID(), PhoneNumber: phoneNumber, CreatedAt: now}})
+		assert.NoError(t, err)
+		// outbox rows are matched by the number the relay normalized, not the one other services wrote
+		_, err = repo.outboxCollection.InsertOne(ctx, model.OutboxMessage{ID: primitive.NewObjectID(),
+			TenantID: tenantID, PhoneNumber: "0555 111 22 33", NormalizedPhoneNumber: phoneNumber, Content: "relayed",
+			Status: model.OutboxStatusRelayed})
+		assert.NoError(t, err)
+		assert.NoError(t, repo.CreateCampaign(ctx, &model.Campaign{ID: primitive.NewObjectID(), TenantID: tenantID,
+			Name: "Launch", Audience: []string{phoneNumber, "+905550000000"}, Status: model.CampaignStatusRunning,
+			CreatedAt: now}))
+		assert.NoError(t, repo.CreateCampaign(ctx, &model.Campaign{ID: primitive.NewObjectID(), TenantID: tenantID,
+			Name: "Reminder", Audience: []string{"+905550000000"}, Rejected: []dto.InvalidPhoneNumber{
+				{PhoneNumber: phoneNumber, Error: "suppressed"}, {PhoneNumber: "+905559999999", Error: "suppressed"}},
+			Status: model.CampaignStatusRunning, CreatedAt: now.Add(time.Minute)}))
+	}
+	seed("retail")
+	seed("logistics")
+
+	t.Run("get subject data", func(t *testing.T) {
+		messages, err := repo.GetSubjectMessages(ctx, "retail", phoneNumber, false)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 2)
+
+		archived, err := repo.GetSubjectMessages(ctx, "retail", phoneNumber, true)
+		assert.NoError(t, err)
+		assert.Len(t, archived, 1)
+		assert.Equal(t, "archived", archived[0].Content)
+
+		inbound, err := repo.GetSubjectInboundMessages(ctx, "retail", phoneNumber)
+		assert.NoError(t, err)
+		assert.Len(t, inbound, 1)
+
+		contacts, err := repo.GetSubjectContacts(ctx, "retail", phoneNumber)
+		assert.NoError(t, err)
+		assert.Len(t, contacts, 1)
+
+		outbox, err := repo.GetSubjectOutboxMessages(ctx, "retail", phoneNumber)
+		assert.NoError(t, err)
+		assert.Len(t, outbox, 1)
+
+		campaigns, err := repo.GetSubjectCampaigns(ctx, "retail", phoneNumber)
+		assert.NoError(t, err)
+		assert.Len(t, campaigns, 2)
+		assert.Equal(t, "Launch", campaigns[0].Name)
+		assert.False(t, campaigns[0].Rejected)
+		assert.True(t, campaigns[1].Rejected)
+		assert.Equal(t, "suppressed", campaigns[1].Error)
+	})
+
+	t.Run("anonymize subject", func(t *testing.T) {
+		erasure, err := repo.AnonymizeSubject(ctx, "logistics", phoneNumber)
+		assert.NoError(t, err)
+		assert.Equal(t, &model.SubjectErasure{PhoneNumber: phoneNumber, Mode: model.ErasureModeAnonymize,
+			Messages: 2, ArchivedMessages: 1, InboundMessages: 1, Contacts: 1, OutboxMessages: 1, Campaigns: 2}, erasure)
+
+		messages, err := repo.GetMessages(ctx, "logistics", model.StatusCancelled, 10)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+		assert.Equal(t, model.AnonymizedPhoneNumber, messages[0].PhoneNumber)
+		assert.Empty(t, messages[0].Content)
+
+		count, err := repo.messageCollection.CountDocuments(ctx, bson.M{"tenantId": "logistics"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), count)
+	})
+
+	t.Run("erase subject", func(t *testing.T) {
+		erasure, err := repo.EraseSubject(ctx, "retail", phoneNumber)
+		assert.NoError(t, err)
+		assert.Equal(t, &model.SubjectErasure{PhoneNumber: phoneNumber, Mode: model.ErasureModeErase,
+			Messages: 2, ArchivedMessages: 1, InboundMessages: 1, Contacts: 1, OutboxMessages: 1, Campaigns: 2}, erasure)
+
+		messages, err := repo.GetMessagesByPhoneNumber(ctx, "retail", "+905550000000", 10)
+		assert.NoError(t, err)
+		assert.Len(t, messages, 1)
+
+		messages, err = repo.GetSubjectMessages(ctx, "retail", phoneNumber, false)
+		assert.NoError(t, err)
+		assert.Empty(t, messages)
+	})
+}
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// suppressionID keys a suppression by tenant and phone number. Suppressions of the default tenant
+// keep the phone number as their id, the layout used before tenants existed.
+func suppressionID(tenantID, phoneNumber string) string {
+	if tenantID == model.DefaultTenantID {
+		return phoneNumber
+	}
+	return tenantID + ":" + phoneNumber
+}
+
+// AddSuppressions upserts the given suppressions, existing entries keep their creation time.
+func (r *Repository) AddSuppressions(ctx context.Context, suppressions []model.Suppression) error {
+	if len(suppressions) == 0 {
+		return nil
+	}
+
+	writes := make([]mongo.WriteModel, 0, len(suppressions))
+	for _, suppression := range suppressions {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": suppressionID(suppression.TenantID, suppression.PhoneNumber)}).
+			SetUpdate(bson.M{
+				"$set": bson.M{
+					"tenantId":    suppression.TenantID,
+					"phoneNumber": suppression.PhoneNumber,
+					"reason":      suppression.Reason,
+					"source":      suppression.Source,
+				},
+				"$setOnInsert": bson.M{
+					"createdAt": suppression.CreatedAt,
+				},
+			}).
+			SetUpsert(true))
+	}
+
+	_, err := r.suppressionCollection.BulkWrite(ctx, writes, options.BulkWrite().SetOrdered(false))
+	return err
+}
+
+func (r *Repository) GetSuppression(ctx context.Context, tenantID, phoneNumber string) (*model.Suppression, error) {
+	filter := bson.M{"_id": suppressionID(tenantID, phoneNumber)}
+
+	suppression := &model.Suppression{}
+	err := r.suppressionCollection.FindOne(ctx, filter).Decode(suppression)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return suppression, nil
+}
+
+func (r *Repository) GetSuppressions(ctx context.Context, tenantID string, limit int) ([]model.Suppression, error) {
+	suppressions := []model.Suppression{}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "createdAt", Value: -1}}).
+		SetLimit(int64(limit))
+
+	result, err := r.suppressionCollection.Find(ctx, bson.M{"tenantId": tenantID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &suppressions); err != nil {
+		return nil, err
+	}
+
+	return suppressions, nil
+}
+
+func (r *Repository) DeleteSuppression(ctx context.Context, tenantID, phoneNumber string) error {
+	result, err := r.suppressionCollection.DeleteOne(ctx, bson.M{"_id": suppressionID(tenantID, phoneNumber)})
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepository_Suppressions(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	createdAt := time.Now().UTC().Truncate(time.Millisecond)
+	suppression := model.Suppression{
+		TenantID:    model.DefaultTenantID,
+		PhoneNumber: "+905551112233",
+		Reason:      "replied STOP",
+		Source:      model.SuppressionSourceInbound,
+		CreatedAt:   createdAt,
+	}
+
+	t.Run("add and get suppression", func(t *testing.T) {
+		assert.NoError(t, repo.AddSuppressions(ctx, []model.Suppression{suppression}))
+
+		result, err := repo.GetSuppression(ctx, suppression.TenantID, suppression.PhoneNumber)
+		assert.NoError(t, err)
+		assert.Equal(t, model.SuppressionSourceInbound, result.Source)
+		assert.Equal(t, suppression.PhoneNumber, result.PhoneNumber)
+	})
+
+	t.Run("suppressions are kept per tenant", func(t *testing.T) {
+		_, err := repo.GetSuppression(ctx, "retail", suppression.PhoneNumber)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+
+		other := suppression
+		other.TenantID = "retail"
+		assert.NoError(t, repo.AddSuppressions(ctx, []model.Suppression{other}))
+
+		suppressions, err := repo.GetSuppressions(ctx, "retail", 10)
+		assert.NoError(t, err)
+		assert.Len(t, suppressions, 1)
+		assert.Equal(t, "retail", suppressions[0].TenantID)
+	})
+
+	t.Run("re-adding keeps creation time", func(t *testing.T) {
+		again := suppression
+		again.Source = model.SuppressionSourceImport
+		again.CreatedAt = createdAt.Add(time.Hour)
+		assert.NoError(t, repo.AddSuppressions(ctx, []model.Suppression{again}))
+
+		suppressions, err := repo.GetSuppressions(ctx, suppression.TenantID, 10)
+		assert.NoError(t, err)
+		assert.Len(t, suppressions, 1)
+		assert.Equal(t, model.SuppressionSourceImport, suppressions[0].Source)
+		assert.True(t, createdAt.Equal(suppressions[0].CreatedAt))
+	})
+
+	t.Run("delete suppression", func(t *testing.T) {
+		assert.NoError(t, repo.DeleteSuppression(ctx, suppression.TenantID, suppression.PhoneNumber))
+		assert.ErrorIs(t, repo.DeleteSuppression(ctx, suppression.TenantID, suppression.PhoneNumber),
+			model.ErrNotFound)
+
+		_, err := repo.GetSuppression(ctx, suppression.TenantID, suppression.PhoneNumber)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+
+		_, err = repo.GetSuppression(ctx, "retail", suppression.PhoneNumber)
+		assert.NoError(t, err)
+	})
+}
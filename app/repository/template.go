@@ -0,0 +1,100 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"messaging-system/app/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func (r *Repository) CreateTemplate(ctx context.Context, template *model.Template) error {
+	_, err := r.templateCollection.InsertOne(ctx, template)
+	if mongo.IsDuplicateKeyError(err) {
+		return model.ErrAlreadyExists
+	}
+	return err
+}
+
+func (r *Repository) GetTemplate(ctx context.Context, tenantID string,
+	templateID primitive.ObjectID) (*model.Template, error) {
+	filter := bson.M{
+		"_id":      templateID,
+		"tenantId": tenantID,
+	}
+
+	template := &model.Template{}
+	err := r.templateCollection.FindOne(ctx, filter).Decode(template)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, model.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return template, nil
+}
+
+func (r *Repository) GetTemplates(ctx context.Context, tenantID string) ([]model.Template, error) {
+	templates := []model.Template{}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	result, err := r.templateCollection.Find(ctx, bson.M{"tenantId": tenantID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := result.All(ctx, &templates); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
+
+func (r *Repository) UpdateTemplate(ctx context.Context, template *model.Template) error {
+	filter := bson.M{
+		"_id":      template.ID,
+		"tenantId": template.TenantID,
+	}
+	update := bson.M{
+		"$set": bson.M{
+			"name":          template.Name,
+			"defaultLocale": template.DefaultLocale,
+			"locales":       template.Locales,
+			"updatedAt":     template.UpdatedAt,
+		},
+	}
+
+	result, err := r.templateCollection.UpdateOne(ctx, filter, update)
+	if mongo.IsDuplicateKeyError(err) {
+		return model.ErrAlreadyExists
+	}
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
+
+func (r *Repository) DeleteTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID) error {
+	filter := bson.M{
+		"_id":      templateID,
+		"tenantId": tenantID,
+	}
+
+	result, err := r.templateCollection.DeleteOne(ctx, filter)
+	if err != nil {
+		return err
+	}
+	if result.DeletedCount == 0 {
+		return model.ErrNotFound
+	}
+
+	return nil
+}
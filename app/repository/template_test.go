@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"context"
+	"messaging-system/app/model"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRepository_Templates(t *testing.T) {
+	ctx := context.Background()
+	repo, clean := createTestContainer(ctx)
+	defer clean()
+
+	template := &model.Template{
+		ID:            primitive.NewObjectID(),
+		TenantID:      "retail",
+		Name:          "otp",
+		DefaultLocale: "en",
+		Locales:       map[string]string{"en": "Your code is {{code}}"},
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	t.Run("create and get template", func(t *testing.T) {
+		assert.NoError(t, repo.CreateTemplate(ctx, template))
+
+		result, err := repo.GetTemplate(ctx, template.TenantID, template.ID)
+		assert.NoError(t, err)
+		assert.Equal(t, "otp", result.Name)
+		assert.Equal(t, template.Locales, result.Locales)
+
+		_, err = repo.GetTemplate(ctx, model.DefaultTenantID, template.ID)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+
+	t.Run("duplicate name", func(t *testing.T) {
+		duplicate := *template
+		duplicate.ID = primitive.NewObjectID()
+
+		assert.ErrorIs(t, repo.CreateTemplate(ctx, &duplicate), model.ErrAlreadyExists)
+	})
+
+	t.Run("same name in another tenant", func(t *testing.T) {
+		other := *template
+		other.ID = primitive.NewObjectID()
+		other.TenantID = model.DefaultTenantID
+
+		assert.NoError(t, repo.CreateTemplate(ctx, &other))
+	})
+
+	t.Run("update template", func(t *testing.T) {
+		template.Locales["tr"] = "Kodunuz {{code}}"
+		assert.NoError(t, repo.UpdateTemplate(ctx, template))
+
+		templates, err := repo.GetTemplates(ctx, template.TenantID)
+		assert.NoError(t, err)
+		assert.Len(t, templates, 1)
+		assert.Equal(t, "Kodunuz {{code}}", templates[0].Locales["tr"])
+	})
+
+	t.Run("delete template", func(t *testing.T) {
+		assert.NoError(t, repo.DeleteTemplate(ctx, template.TenantID, template.ID))
+		assert.ErrorIs(t, repo.DeleteTemplate(ctx, template.TenantID, template.ID), model.ErrNotFound)
+
+		_, err := repo.GetTemplate(ctx, template.TenantID, template.ID)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+	})
+}
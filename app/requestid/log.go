@@ -0,0 +1,36 @@
+package requestid
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogHandler adds the request ID carried by the context of a record to it, so every line logged with
+// the Context variants of slog.Logger while serving a request can be correlated.
+type LogHandler struct {
+	next slog.Handler
+}
+
+func NewLogHandler(next slog.Handler) *LogHandler {
+	return &LogHandler{next: next}
+}
+
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *LogHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := FromContext(ctx); id != "" {
+		record = record.Clone()
+		record.AddAttrs(slog.String(LogKey, id))
+	}
+	return h.next.Handle(ctx, record)
+}
+
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &LogHandler{next: h.next.WithAttrs(attrs)}
+}
+
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	return &LogHandler{next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,53 @@
+package requestid
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// Header carries the request ID from callers and to providers.
+	Header = "X-Request-ID"
+	// LogKey is the attribute of log records holding the request ID.
+	LogKey = "requestId"
+
+	maxLength = 128
+)
+
+// ContextKey is the key of the request ID in contexts. Fiber handlers pass the fasthttp context on,
+// which answers Value from the request locals, so the middleware stores the ID there under this key too.
+type ContextKey struct{}
+
+// New generates a request ID.
+func New() string {
+	return uuid.NewString()
+}
+
+// Valid reports whether an ID received from a caller can be used, IDs are printable ASCII without spaces
+// so they cannot break log lines.
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] <= ' ' || id[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
+
+// NewContext returns a copy of ctx carrying the request ID.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ContextKey{}, id)
+}
+
+// FromContext returns the request ID carried by ctx or an empty string.
+func FromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(ContextKey{}).(string)
+	return id
+}
@@ -0,0 +1,48 @@
+package requestid
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValid(t *testing.T) {
+	assert.True(t, Valid(New()))
+	assert.True(t, Valid("Root=1-67891233-abcdef012345678912345678"))
+	assert.False(t, Valid(""))
+	assert.False(t, Valid("two words"))
+	assert.False(t, Valid("line\nbreak"))
+	assert.False(t, Valid("ünicode"))
+	assert.False(t, Valid(strings.Repeat("a", maxLength+1)))
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, FromContext(ctx))
+	assert.Equal(t, "request-id", FromContext(NewContext(ctx, "request-id")))
+}
+
+func TestLogHandler(t *testing.T) {
+	buffer := &bytes.Buffer{}
+	logger := slog.New(NewLogHandler(slog.NewJSONHandler(buffer, nil))).With("component", "test")
+
+	decode := func() map[string]interface{} {
+		line := map[string]interface{}{}
+		assert.Nil(t, json.Unmarshal(buffer.Bytes(), &line))
+		buffer.Reset()
+		return line
+	}
+
+	logger.InfoContext(NewContext(context.Background(), "request-id"), "message sent")
+	line := decode()
+	assert.Equal(t, "request-id", line[LogKey])
+	assert.Equal(t, "test", line["component"])
+
+	logger.Info("message sent")
+	assert.NotContains(t, decode(), LogKey)
+}
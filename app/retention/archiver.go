@@ -0,0 +1,202 @@
+package retention
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"messaging-system/app/model"
+	"messaging-system/app/pii"
+	"messaging-system/config"
+	"slices"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	ArchiveCollection = "collection"
+	ArchiveFile       = "file"
+
+	DefaultBatchSize = 1000
+	DefaultInterval  = time.Hour
+)
+
+// finalStatuses are the statuses a message no longer leaves, unsent messages are never archived.
+var finalStatuses = []string{model.StatusSent, model.StatusExpired, model.StatusSuppressed, model.StatusCancelled,
+	model.StatusFailed}
+
+type IRetentionRepository interface {
+	GetMessagesCreatedBefore(ctx context.Context, status string, before time.Time,
+		limit int) ([]model.Message, error)
+	CountMessagesCreatedBefore(ctx context.Context, tenantID, status string,
+		before time.Time) (int64, *time.Time, error)
+	DeleteMessages(ctx context.Context, messageIDs []primitive.ObjectID) (int64, error)
+}
+
+type IMessageArchive interface {
+	ArchiveMessages(ctx context.Context, messages []model.Message) error
+}
+
+// Archiver moves messages past the retention of their status out of the messages collection. Every batch
+// is archived before it is deleted, a batch that fails in between is archived again on the next run.
+type Archiver struct {
+	repo    IRetentionRepository
+	archive IMessageArchive
+	conf    *config.Retention
+	logger  *slog.Logger
+}
+
+// NewArchiver checks that only final statuses have a retention, archive receives the archived messages.
+func NewArchiver(repo IRetentionRepository, archive IMessageArchive, conf *config.Retention,
+	logger *slog.Logger) (*Archiver, error) {
+	if conf == nil {
+		conf = &config.Retention{}
+	}
+	for status, retention := range conf.Statuses {
+		if !slices.Contains(finalStatuses, status) {
+			return nil, fmt.Errorf("retention of %q messages is not supported, only of final statuses", status)
+		}
+		if retention <= 0 {
+			return nil, fmt.Errorf("retention of %q messages must be positive", status)
+		}
+	}
+
+	return &Archiver{
+		repo:    repo,
+		archive: archive,
+		conf:    conf,
+		logger:  logger,
+	}, nil
+}
+
+// NewArchive returns the archive the configuration moves messages to, collection is the archive collection.
+// The cipher decrypts the messages of archive files for subject exports, it is nil when PII encryption is off.
+func NewArchive(conf *config.Retention, collection IMessageArchive, cipher *pii.Cipher) (IMessageArchive, error) {
+	if conf == nil {
+		return collection, nil
+	}
+
+	switch conf.Archive {
+	case "", ArchiveCollection:
+		return collection, nil
+	case ArchiveFile:
+		return NewFileArchive(conf.Directory, cipher)
+	default:
+		return nil, fmt.Errorf("unknown retention archive %q, expected collection or file", conf.Archive)
+	}
+}
+
+// Run archives messages past their retention every interval until ctx is cancelled.
+func (a *Archiver) Run(ctx context.Context) {
+	a.logger.Info("Starting retention archiver")
+	interval := a.conf.Interval
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := a.Archive(ctx, time.Now().UTC()); err != nil && ctx.Err() == nil {
+			a.logger.Error("failed to archive messages", "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			a.logger.Warn("Stopping retention archiver...")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Archive moves the messages of all tenants that are past their retention at now in batches and returns
+// how many were moved.
+func (a *Archiver) Archive(ctx context.Context, now time.Time) (int64, error) {
+	var archived int64
+	for _, status := range a.statuses() {
+		before := now.Add(-a.conf.Statuses[status])
+		for ctx.Err() == nil {
+			messages, err := a.repo.GetMessagesCreatedBefore(ctx, status, before, a.batchSize())
+			if err != nil {
+				return archived, err
+			}
+			if len(messages) == 0 {
+				break
+			}
+
+			if err := a.archive.ArchiveMessages(ctx, messages); err != nil {
+				return archived, err
+			}
+
+			ids := make([]primitive.ObjectID, 0, len(messages))
+			for _, message := range messages {
+				ids = append(ids, message.ID)
+			}
+			deleted, err := a.repo.DeleteMessages(ctx, ids)
+			if err != nil {
+				return archived, err
+			}
+			archived += deleted
+
+			if len(messages) < a.batchSize() {
+				break
+			}
+		}
+	}
+
+	if archived > 0 {
+		a.logger.Info("archived messages", "count", archived)
+	}
+	return archived, ctx.Err()
+}
+
+// DryRun reports the messages of the tenant the archiver would move at now, nothing is changed.
+func (a *Archiver) DryRun(ctx context.Context, tenantID string, now time.Time) (*model.RetentionReport, error) {
+	report := &model.RetentionReport{
+		GeneratedAt: now,
+		Archive:     a.conf.Archive,
+		Statuses:    []model.RetentionStatus{},
+	}
+	if report.Archive == "" {
+		report.Archive = ArchiveCollection
+	}
+
+	for _, status := range a.statuses() {
+		retention := a.conf.Statuses[status]
+		before := now.Add(-retention)
+		count, oldest, err := a.repo.CountMessagesCreatedBefore(ctx, tenantID, status, before)
+		if err != nil {
+			return nil, err
+		}
+
+		report.Total += count
+		report.Statuses = append(report.Statuses, model.RetentionStatus{
+			Status:        status,
+			RetainFor:     retention.String(),
+			CreatedBefore: before,
+			Messages:      count,
+			Oldest:        oldest,
+		})
+	}
+
+	return report, nil
+}
+
+// statuses returns the statuses with a retention in a stable order.
+func (a *Archiver) statuses() []string {
+	statuses := make([]string, 0, len(a.conf.Statuses))
+	for status := range a.conf.Statuses {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	return statuses
+}
+
+func (a *Archiver) batchSize() int {
+	if a.conf.BatchSize > 0 {
+		return a.conf.BatchSize
+	}
+	return DefaultBatchSize
+}
@@ -0,0 +1,158 @@
+package retention
+
+import (
+	"context"
+	"log/slog"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"messaging-system/config"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNewArchiver(t *testing.T) {
+	t.Run("unsent messages are never archived", func(t *testing.T) {
+		archiver, err := NewArchiver(nil, nil, &config.Retention{
+			Statuses: map[string]time.Duration{model.StatusUnsent: time.Hour},
+		}, slog.Default())
+		assert.Error(t, err)
+		assert.Nil(t, archiver)
+	})
+
+	t.Run("retention must be positive", func(t *testing.T) {
+		archiver, err := NewArchiver(nil, nil, &config.Retention{
+			Statuses: map[string]time.Duration{model.StatusSent: 0},
+		}, slog.Default())
+		assert.Error(t, err)
+		assert.Nil(t, archiver)
+	})
+}
+
+func TestNewArchive(t *testing.T) {
+	collection := mocks.NewMockIMessageArchive(gomock.NewController(t))
+
+	archive, err := NewArchive(&config.Retention{}, collection, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, collection, archive)
+
+	archive, err = NewArchive(&config.Retention{Archive: ArchiveFile, Directory: t.TempDir()}, collection, nil)
+	assert.NoError(t, err)
+	assert.IsType(t, &FileArchive{}, archive)
+
+	_, err = NewArchive(&config.Retention{Archive: ArchiveFile}, collection, nil)
+	assert.Error(t, err)
+
+	_, err = NewArchive(&config.Retention{Archive: "s3"}, collection, nil)
+	assert.Error(t, err)
+}
+
+func TestArchiver_Archive(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	conf := &config.Retention{
+		BatchSize: 2,
+		Statuses: map[string]time.Duration{
+			model.StatusSent:    90 * 24 * time.Hour,
+			model.StatusExpired: 30 * 24 * time.Hour,
+		},
+	}
+	newMessages := func(count int) []model.Message {
+		messages := make([]model.Message, 0, count)
+		for i := 0; i < count; i++ {
+			messages = append(messages, model.Message{ID: primitive.NewObjectID()})
+		}
+		return messages
+	}
+
+	t.Run("archives and deletes every status in batches", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		mockRepo := mocks.NewMockIRetentionRepository(mockController)
+		mockArchive := mocks.NewMockIMessageArchive(mockController)
+		archiver, err := NewArchiver(mockRepo, mockArchive, conf, slog.Default())
+		assert.NoError(t, err)
+
+		full, rest, expired := newMessages(2), newMessages(1), newMessages(1)
+		gomock.InOrder(
+			mockRepo.EXPECT().GetMessagesCreatedBefore(gomock.Any(), model.StatusExpired,
+				now.AddDate(0, 0, -30), 2).Return(expired, nil),
+			mockArchive.EXPECT().ArchiveMessages(gomock.Any(), expired).Return(nil),
+			mockRepo.EXPECT().DeleteMessages(gomock.Any(), []primitive.ObjectID{expired[0].ID}).Return(int64(1), nil),
+			mockRepo.EXPECT().GetMessagesCreatedBefore(gomock.Any(), model.StatusSent,
+				now.AddDate(0, 0, -90), 2).Return(full, nil),
+			mockArchive.EXPECT().ArchiveMessages(gomock.Any(), full).Return(nil),
+			mockRepo.EXPECT().DeleteMessages(gomock.Any(), []primitive.ObjectID{full[0].ID, full[1].ID}).
+				Return(int64(2), nil),
+			mockRepo.EXPECT().GetMessagesCreatedBefore(gomock.Any(), model.StatusSent,
+				now.AddDate(0, 0, -90), 2).Return(rest, nil),
+			mockArchive.EXPECT().ArchiveMessages(gomock.Any(), rest).Return(nil),
+			mockRepo.EXPECT().DeleteMessages(gomock.Any(), []primitive.ObjectID{rest[0].ID}).Return(int64(1), nil),
+		)
+
+		archived, err := archiver.Archive(ctx, now)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(4), archived)
+	})
+
+	t.Run("messages are kept when archiving fails", func(t *testing.T) {
+		mockController := gomock.NewController(t)
+		mockRepo := mocks.NewMockIRetentionRepository(mockController)
+		mockArchive := mocks.NewMockIMessageArchive(mockController)
+		archiver, err := NewArchiver(mockRepo, mockArchive, conf, slog.Default())
+		assert.NoError(t, err)
+
+		mockRepo.EXPECT().GetMessagesCreatedBefore(gomock.Any(), model.StatusExpired, gomock.Any(), 2).
+			Return(newMessages(1), nil)
+		mockArchive.EXPECT().ArchiveMessages(gomock.Any(), gomock.Any()).Return(assert.AnError)
+
+		archived, err := archiver.Archive(ctx, now)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, int64(0), archived)
+	})
+}
+
+func TestArchiver_DryRun(t *testing.T) {
+	mockController := gomock.NewController(t)
+	mockRepo := mocks.NewMockIRetentionRepository(mockController)
+	archiver, err := NewArchiver(mockRepo, nil, &config.Retention{
+		Archive: ArchiveFile,
+		Statuses: map[string]time.Duration{
+			model.StatusSent:       90 * 24 * time.Hour,
+			model.StatusSuppressed: 30 * 24 * time.Hour,
+		},
+	}, slog.Default())
+	assert.NoError(t, err)
+
+	ctx := context.Background()
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	oldest := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("counts the messages past their retention", func(t *testing.T) {
+		mockRepo.EXPECT().CountMessagesCreatedBefore(gomock.Any(), "retail", model.StatusSent, now.AddDate(0, 0, -90)).
+			Return(int64(120), &oldest, nil)
+		mockRepo.EXPECT().CountMessagesCreatedBefore(gomock.Any(), "retail", model.StatusSuppressed, gomock.Any()).
+			Return(int64(0), nil, nil)
+
+		report, err := archiver.DryRun(ctx, "retail", now)
+		assert.NoError(t, err)
+		assert.Equal(t, ArchiveFile, report.Archive)
+		assert.Equal(t, int64(120), report.Total)
+		assert.Equal(t, []model.RetentionStatus{
+			{Status: model.StatusSent, RetainFor: "2160h0m0s", CreatedBefore: now.AddDate(0, 0, -90), Messages: 120,
+				Oldest: &oldest},
+			{Status: model.StatusSuppressed, RetainFor: "720h0m0s", CreatedBefore: now.AddDate(0, 0, -30)},
+		}, report.Statuses)
+	})
+
+	t.Run("error counting messages", func(t *testing.T) {
+		mockRepo.EXPECT().CountMessagesCreatedBefore(gomock.Any(), "retail", model.StatusSent, gomock.Any()).
+			Return(int64(0), nil, assert.AnError)
+
+		report, err := archiver.DryRun(ctx, "retail", now)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, report)
+	})
+}
@@ -0,0 +1,274 @@
+package retention
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"messaging-system/app/model"
+	"messaging-system/app/pii"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileArchive appends archived messages to a gzipped NDJSON file per UTC day in a directory. Every batch
+// is a gzip member of its own and synced to disk before it is deleted, gzip tools read the members as one
+// stream. The cipher decrypts the messages of data subjects, it is nil when PII encryption is off.
+type FileArchive struct {
+	directory string
+	cipher    *pii.Cipher
+	now       func() time.Time
+	// mu keeps files from being appended to while a subject erasure rewrites them
+	mu sync.Mutex
+}
+
+// archivedMessage is a message as written to an archive file. Messages come as they are stored, so
+// encrypted messages keep their ciphertext and the blind index the API leaves out is written with them.
+type archivedMessage struct {
+	*model.Message
+	PhoneNumberIndex string `json:"phoneNumberIndex,omitempty"`
+}
+
+func NewFileArchive(directory string, cipher *pii.Cipher) (*FileArchive, error) {
+	if directory == "" {
+		return nil, fmt.Errorf("retention directory is required to archive to files")
+	}
+	if err := os.MkdirAll(directory, 0o750); err != nil {
+		return nil, err
+	}
+
+	return &FileArchive{
+		directory: directory,
+		cipher:    cipher,
+		now:       func() time.Time { return time.Now().UTC() },
+	}, nil
+}
+
+// Path returns the file the messages archived on the day of at are appended to.
+func (f *FileArchive) Path(at time.Time) string {
+	return filepath.Join(f.directory, "messages-"+at.UTC().Format(time.DateOnly)+".ndjson.gz")
+}
+
+func (f *FileArchive) ArchiveMessages(_ context.Context, messages []model.Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	file, err := os.OpenFile(f.Path(f.now()), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	zipped := gzip.NewWriter(file)
+	encoder := json.NewEncoder(zipped)
+	for i := range messages {
+		message := &messages[i]
+		if err := encoder.Encode(&archivedMessage{Message: message, PhoneNumberIndex: message.PhoneNumberIndex}); err != nil {
+			return err
+		}
+	}
+	if err := zipped.Close(); err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+// GetSubjectMessages returns the archived messages of a tenant to a phone number from every archive file
+// oldest first, decrypted. Encrypted messages are matched by their blind index.
+func (f *FileArchive) GetSubjectMessages(_ context.Context, tenantID, phoneNumber string) ([]model.Message, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	paths, err := f.paths()
+	if err != nil {
+		return nil, err
+	}
+
+	messages := []model.Message{}
+	for _, path := range paths {
+		archived, err := readArchive(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range archived {
+			if !f.matches(line, tenantID, phoneNumber) {
+				continue
+			}
+			message := *line.Message
+			if err := f.decrypt(&message); err != nil {
+				return nil, fmt.Errorf("%s: %w", path, err)
+			}
+			messages = append(messages, message)
+		}
+	}
+	return messages, nil
+}
+
+// EraseSubject removes the archived messages of a tenant to a phone number from the archive files, or
+// removes their phone numbers and content when anonymize is set, and returns how many were changed. Every
+// affected file is rewritten to a temporary file that replaces it once synced, a failed erasure leaves the
+// file as it was.
+func (f *FileArchive) EraseSubject(_ context.Context, tenantID, phoneNumber string, anonymize bool) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	paths, err := f.paths()
+	if err != nil {
+		return 0, err
+	}
+
+	var count int64
+	for _, path := range paths {
+		archived, err := readArchive(path)
+		if err != nil {
+			return count, err
+		}
+
+		var matched int64
+		kept := make([]archivedMessage, 0, len(archived))
+		for _, line := range archived {
+			if !f.matches(line, tenantID, phoneNumber) {
+				kept = append(kept, line)
+				continue
+			}
+			matched++
+			if anonymize {
+				line.PhoneNumber = model.AnonymizedPhoneNumber
+				line.RawPhoneNumber = ""
+				line.Content = ""
+				line.PhoneNumberIndex = ""
+				kept = append(kept, line)
+			}
+		}
+		if matched == 0 {
+			continue
+		}
+
+		if err := f.rewrite(path, kept); err != nil {
+			return count, err
+		}
+		count += matched
+	}
+	return count, nil
+}
+
+// Erasable checks that archive files can be rewritten, subject erasures are refused before anything is
+// erased when they cannot.
+func (f *FileArchive) Erasable() error {
+	file, err := os.CreateTemp(f.directory, ".erasable-*")
+	if err != nil {
+		return err
+	}
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+// paths returns the archive files of the directory oldest first.
+func (f *FileArchive) paths() ([]string, error) {
+	entries, err := os.ReadDir(f.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.Type().IsRegular() && strings.HasPrefix(name, "messages-") && strings.HasSuffix(name, ".ndjson.gz") {
+			paths = append(paths, filepath.Join(f.directory, name))
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+func (f *FileArchive) matches(line archivedMessage, tenantID, phoneNumber string) bool {
+	if line.TenantID != tenantID {
+		return false
+	}
+	if line.PhoneNumber == phoneNumber {
+		return true
+	}
+	return f.cipher != nil && line.PhoneNumberIndex != "" && line.PhoneNumberIndex == f.cipher.BlindIndex(phoneNumber)
+}
+
+func (f *FileArchive) decrypt(message *model.Message) error {
+	for _, field := range []*string{&message.PhoneNumber, &message.RawPhoneNumber, &message.Content} {
+		value, err := f.cipher.Decrypt(*field)
+		if err != nil {
+			return err
+		}
+		*field = value
+	}
+	return nil
+}
+
+// rewrite replaces an archive file with the messages kept, a file without messages left is removed.
+func (f *FileArchive) rewrite(path string, messages []archivedMessage) error {
+	if len(messages) == 0 {
+		return os.Remove(path)
+	}
+
+	file, err := os.CreateTemp(f.directory, ".rewrite-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	zipped := gzip.NewWriter(file)
+	encoder := json.NewEncoder(zipped)
+	for i := range messages {
+		if err := encoder.Encode(&messages[i]); err != nil {
+			return err
+		}
+	}
+	if err := zipped.Close(); err != nil {
+		return err
+	}
+	if err := file.Chmod(0o640); err != nil {
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+// readArchive reads every message of an archive file, the gzip members of its batches read as one stream.
+func readArchive(path string) ([]archivedMessage, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	reader, err := gzip.NewReader(file)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	defer reader.Close()
+
+	messages := []archivedMessage{}
+	decoder := json.NewDecoder(reader)
+	for decoder.More() {
+		line := archivedMessage{Message: &model.Message{}}
+		if err := decoder.Decode(&line); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		messages = append(messages, line)
+	}
+	return messages, nil
+}
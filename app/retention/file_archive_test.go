@@ -0,0 +1,149 @@
+package retention
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"messaging-system/app/model"
+	"messaging-system/app/pii"
+	"messaging-system/config"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestFileArchive_ArchiveMessages(t *testing.T) {
+	ctx := context.Background()
+	archive, err := NewFileArchive(t.TempDir(), nil)
+	assert.NoError(t, err)
+	now := time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC)
+	archive.now = func() time.Time { return now }
+
+	// messages come as they are stored, encrypted ones keep their ciphertext and blind index
+	first := []model.Message{{ID: primitive.NewObjectID(), PhoneNumber: "enc:1:cGhvbmU", Content: "enc:1:aGVsbG8",
+		PhoneNumberIndex: "blind-index"}}
+	second := []model.Message{{ID: primitive.NewObjectID(), Content: "World"}, {ID: primitive.NewObjectID()}}
+	assert.NoError(t, archive.ArchiveMessages(ctx, first))
+	assert.NoError(t, archive.ArchiveMessages(ctx, second))
+	assert.NoError(t, archive.ArchiveMessages(ctx, nil))
+
+	file, err := os.Open(archive.Path(now))
+	assert.NoError(t, err)
+	defer file.Close()
+	assert.Contains(t, file.Name(), "messages-2026-10-19.ndjson.gz")
+
+	// the batches are gzip members of their own, they read back as a single stream
+	reader, err := gzip.NewReader(file)
+	assert.NoError(t, err)
+	decoder := json.NewDecoder(reader)
+	archived := []primitive.ObjectID{}
+	lines := []map[string]interface{}{}
+	for decoder.More() {
+		line := json.RawMessage{}
+		assert.NoError(t, decoder.Decode(&line))
+		message, fields := model.Message{}, map[string]interface{}{}
+		assert.NoError(t, json.Unmarshal(line, &message))
+		assert.NoError(t, json.Unmarshal(line, &fields))
+		archived = append(archived, message.ID)
+		lines = append(lines, fields)
+	}
+	assert.Equal(t, []primitive.ObjectID{first[0].ID, second[0].ID, second[1].ID}, archived)
+	assert.Equal(t, "enc:1:cGhvbmU", lines[0]["phoneNumber"])
+	assert.Equal(t, "enc:1:aGVsbG8", lines[0]["content"])
+	assert.Equal(t, "blind-index", lines[0]["phoneNumberIndex"])
+	assert.NotContains(t, lines[1], "phoneNumberIndex")
+}
+
+func TestFileArchive_Subjects(t *testing.T) {
+	ctx := context.Background()
+	cipher, err := pii.NewCipher(&config.PII{
+		Encrypt:   true,
+		ActiveKey: "1",
+		Keys:      map[string]string{"1": "s16O6thSz/IYuiY6Q1hk9QtLkf/23CKa05t3nwP7zoI="},
+		IndexKey:  "FvZhw/147kca6t4ArkYnlqhVn6YxGFpJI/mWeI0ew1o=",
+	})
+	assert.NoError(t, err)
+
+	phoneNumber := "+905551112233"
+	encrypt := func(value string) string {
+		encrypted, err := cipher.Encrypt(value)
+		assert.NoError(t, err)
+		return encrypted
+	}
+	seed := func(t *testing.T) (*FileArchive, []model.Message) {
+		archive, err := NewFileArchive(t.TempDir(), cipher)
+		assert.NoError(t, err)
+
+		// archived before encryption was enabled, encrypted, of another tenant and of another number
+		messages := []model.Message{
+			{ID: primitive.NewObjectID(), TenantID: "retail", PhoneNumber: phoneNumber, Content: "plaintext"},
+			{ID: primitive.NewObjectID(), TenantID: "retail", PhoneNumber: encrypt(phoneNumber),
+				Content: encrypt("encrypted"), PhoneNumberIndex: cipher.BlindIndex(phoneNumber)},
+			{ID: primitive.NewObjectID(), TenantID: "logistics", PhoneNumber: phoneNumber, Content: "other tenant"},
+			{ID: primitive.NewObjectID(), TenantID: "retail", PhoneNumber: "+905550000000", Content: "other number"},
+		}
+		archive.now = func() time.Time { return time.Date(2026, 10, 18, 8, 0, 0, 0, time.UTC) }
+		assert.NoError(t, archive.ArchiveMessages(ctx, messages[:2]))
+		archive.now = func() time.Time { return time.Date(2026, 10, 19, 8, 0, 0, 0, time.UTC) }
+		assert.NoError(t, archive.ArchiveMessages(ctx, messages[2:]))
+		return archive, messages
+	}
+
+	t.Run("get subject messages decrypted", func(t *testing.T) {
+		archive, messages := seed(t)
+
+		found, err := archive.GetSubjectMessages(ctx, "retail", phoneNumber)
+		assert.NoError(t, err)
+		assert.Len(t, found, 2)
+		assert.Equal(t, messages[0].ID, found[0].ID)
+		assert.Equal(t, messages[1].ID, found[1].ID)
+		assert.Equal(t, phoneNumber, found[1].PhoneNumber)
+		assert.Equal(t, "encrypted", found[1].Content)
+	})
+
+	t.Run("erase subject", func(t *testing.T) {
+		archive, messages := seed(t)
+
+		count, err := archive.EraseSubject(ctx, "retail", phoneNumber, false)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		// the file left without messages is removed, the other one keeps the messages of others
+		_, err = os.Stat(archive.Path(time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)))
+		assert.True(t, os.IsNotExist(err))
+		found, err := archive.GetSubjectMessages(ctx, "retail", phoneNumber)
+		assert.NoError(t, err)
+		assert.Empty(t, found)
+		found, err = archive.GetSubjectMessages(ctx, "logistics", phoneNumber)
+		assert.NoError(t, err)
+		assert.Len(t, found, 1)
+		assert.Equal(t, messages[2].ID, found[0].ID)
+	})
+
+	t.Run("anonymize subject", func(t *testing.T) {
+		archive, messages := seed(t)
+
+		count, err := archive.EraseSubject(ctx, "retail", phoneNumber, true)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), count)
+
+		anonymized, err := archive.GetSubjectMessages(ctx, "retail", model.AnonymizedPhoneNumber)
+		assert.NoError(t, err)
+		assert.Len(t, anonymized, 2)
+		assert.Equal(t, messages[1].ID, anonymized[1].ID)
+		assert.Empty(t, anonymized[1].Content)
+		assert.Empty(t, anonymized[1].PhoneNumberIndex)
+	})
+
+	t.Run("erasable only when the directory is writable", func(t *testing.T) {
+		archive, _ := seed(t)
+		assert.NoError(t, archive.Erasable())
+
+		archive.directory = filepath.Join(t.TempDir(), "missing")
+		assert.Error(t, archive.Erasable())
+	})
+}
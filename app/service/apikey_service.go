@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"messaging-system/config"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	APIKeyPrefix = "msk_"
+	// apiKeyBytes of randomness make the keys unguessable, a plain SHA-256 is enough to store them.
+	apiKeyBytes = 32
+	// apiKeyShownPrefix is the number of leading characters kept to identify a key.
+	apiKeyShownPrefix = 12
+
+	BootstrapPrincipalID = "bootstrap"
+)
+
+type IAPIKeyRepository interface {
+	CreateAPIKey(ctx context.Context, key *model.APIKey) error
+	GetAPIKeyByHash(ctx context.Context, hash string) (*model.APIKey, error)
+	GetAPIKeys(ctx context.Context, tenantID string) ([]model.APIKey, error)
+	RevokeAPIKey(ctx context.Context, tenantID string, keyID primitive.ObjectID, revokedAt time.Time) error
+}
+
+type APIKeyService struct {
+	repo IAPIKeyRepository
+	conf *config.Auth
+}
+
+func NewAPIKeyService(repo IAPIKeyRepository, conf *config.Auth) *APIKeyService {
+	if conf == nil {
+		conf = &config.Auth{}
+	}
+
+	return &APIKeyService{
+		repo: repo,
+		conf: conf,
+	}
+}
+
+// CreateAPIKey generates a new key in the tenant of the caller, the bootstrap key may create keys of
+// any tenant to onboard it. The plaintext key is only part of the returned response.
+func (s *APIKeyService) CreateAPIKey(ctx context.Context, principal *model.Principal,
+	request *dto.APIKeyRequest) (*dto.APIKeyResponse, error) {
+	if err := request.Validate(model.Scopes); err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+
+	tenantID := principal.TenantID
+	if request.TenantID != "" && request.TenantID != tenantID {
+		if principal.ID != BootstrapPrincipalID {
+			return nil, fmt.Errorf("%w: keys can only be created for your own tenant", model.ErrInvalidRequest)
+		}
+		tenantID = request.TenantID
+	}
+
+	secret := make([]byte, apiKeyBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	plaintext := APIKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	key := &model.APIKey{
+		ID:        primitive.NewObjectID(),
+		TenantID:  tenantID,
+		Name:      request.Name,
+		Prefix:    plaintext[:apiKeyShownPrefix],
+		Hash:      hashAPIKey(plaintext),
+		Scopes:    request.Scopes,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.repo.CreateAPIKey(ctx, key); err != nil {
+		return nil, err
+	}
+
+	return &dto.APIKeyResponse{
+		ID:       key.ID.Hex(),
+		Name:     key.Name,
+		TenantID: key.TenantID,
+		Key:      plaintext,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+func (s *APIKeyService) GetAPIKeys(ctx context.Context, tenantID string) ([]model.APIKey, error) {
+	return s.repo.GetAPIKeys(ctx, tenantID)
+}
+
+func (s *APIKeyService) RevokeAPIKey(ctx context.Context, tenantID string, keyID primitive.ObjectID) error {
+	return s.repo.RevokeAPIKey(ctx, tenantID, keyID, time.Now().UTC())
+}
+
+// Authenticate resolves the caller of an API key. Unknown and revoked keys are reported as model.ErrUnauthorized.
+func (s *APIKeyService) Authenticate(ctx context.Context, plaintext string) (*model.Principal, error) {
+	if s.conf.BootstrapKey != "" &&
+		subtle.ConstantTimeCompare([]byte(plaintext), []byte(s.conf.BootstrapKey)) == 1 {
+		return &model.Principal{
+			ID:       BootstrapPrincipalID,
+			Name:     BootstrapPrincipalID,
+			TenantID: model.DefaultTenantID,
+			Scopes:   model.Scopes,
+		}, nil
+	}
+
+	key, err := s.repo.GetAPIKeyByHash(ctx, hashAPIKey(plaintext))
+	if errors.Is(err, model.ErrNotFound) {
+		return nil, model.ErrUnauthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.Principal{
+		ID:       key.ID.Hex(),
+		Name:     key.Name,
+		TenantID: key.TenantID,
+		Scopes:   key.Scopes,
+	}, nil
+}
+
+func hashAPIKey(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"messaging-system/app/dto"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"messaging-system/config"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+const bootstrapKey = "bootstrap-secret"
+
+func TestAPIKeyService_CreateAPIKey(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIAPIKeyRepository(mockController)
+	apiKeyService := NewAPIKeyService(mockRepo, &config.Auth{BootstrapKey: bootstrapKey})
+	bootstrap := &model.Principal{ID: BootstrapPrincipalID, TenantID: model.DefaultTenantID}
+	tenantAdmin := &model.Principal{ID: "key-id", TenantID: "retail"}
+
+	t.Run("stores only the hash of the generated key", func(t *testing.T) {
+		var stored *model.APIKey
+		mockRepo.
+			EXPECT().
+			CreateAPIKey(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, key *model.APIKey) error {
+				stored = key
+				return nil
+			})
+
+		response, err := apiKeyService.CreateAPIKey(ctx, tenantAdmin, &dto.APIKeyRequest{
+			Name:   "billing",
+			Scopes: []string{model.ScopeMessagesRead},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "retail", stored.TenantID)
+		assert.True(t, strings.HasPrefix(response.Key, APIKeyPrefix))
+		assert.Equal(t, hashAPIKey(response.Key), stored.Hash)
+		assert.NotContains(t, stored.Hash, response.Key)
+		assert.True(t, strings.HasPrefix(response.Key, stored.Prefix))
+	})
+
+	t.Run("bootstrap key creates keys of other tenants", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			CreateAPIKey(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, key *model.APIKey) error {
+				assert.Equal(t, "logistics", key.TenantID)
+				return nil
+			})
+
+		response, err := apiKeyService.CreateAPIKey(ctx, bootstrap, &dto.APIKeyRequest{
+			Name:     "logistics admin",
+			TenantID: "logistics",
+			Scopes:   []string{model.ScopeProcessorAdmin},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "logistics", response.TenantID)
+	})
+
+	t.Run("other tenant", func(t *testing.T) {
+		response, err := apiKeyService.CreateAPIKey(ctx, tenantAdmin, &dto.APIKeyRequest{
+			Name:     "billing",
+			TenantID: "logistics",
+			Scopes:   []string{model.ScopeMessagesRead},
+		})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, response)
+	})
+
+	t.Run("unknown scope", func(t *testing.T) {
+		response, err := apiKeyService.CreateAPIKey(ctx, tenantAdmin, &dto.APIKeyRequest{
+			Name:   "billing",
+			Scopes: []string{"messages:delete"},
+		})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, response)
+	})
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIAPIKeyRepository(mockController)
+	apiKeyService := NewAPIKeyService(mockRepo, &config.Auth{BootstrapKey: bootstrapKey})
+
+	t.Run("bootstrap key is granted every scope", func(t *testing.T) {
+		principal, err := apiKeyService.Authenticate(ctx, bootstrapKey)
+		assert.Nil(t, err)
+		assert.Equal(t, BootstrapPrincipalID, principal.ID)
+		assert.Equal(t, model.DefaultTenantID, principal.TenantID)
+		assert.True(t, principal.HasScope(model.ScopeProcessorAdmin))
+	})
+
+	t.Run("stored key", func(t *testing.T) {
+		key := &model.APIKey{Name: "billing", TenantID: "retail", Scopes: []string{model.ScopeMessagesRead}}
+		mockRepo.
+			EXPECT().
+			GetAPIKeyByHash(gomock.Any(), hashAPIKey("msk_valid")).
+			Return(key, nil)
+
+		principal, err := apiKeyService.Authenticate(ctx, "msk_valid")
+		assert.Nil(t, err)
+		assert.Equal(t, "billing", principal.Name)
+		assert.Equal(t, "retail", principal.TenantID)
+		assert.False(t, principal.HasScope(model.ScopeProcessorAdmin))
+	})
+
+	t.Run("unknown or revoked key", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			GetAPIKeyByHash(gomock.Any(), hashAPIKey("msk_unknown")).
+			Return(nil, model.ErrNotFound)
+
+		principal, err := apiKeyService.Authenticate(ctx, "msk_unknown")
+		assert.ErrorIs(t, err, model.ErrUnauthorized)
+		assert.Nil(t, principal)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			GetAPIKeyByHash(gomock.Any(), gomock.Any()).
+			Return(nil, fmt.Errorf("mongo down"))
+
+		_, err := apiKeyService.Authenticate(ctx, "msk_other")
+		assert.NotErrorIs(t, err, model.ErrUnauthorized)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type IAuditRepository interface {
+	CreateAuditEntry(ctx context.Context, entry *model.AuditEntry) error
+	GetAuditEntries(ctx context.Context, tenantID string, query *dto.AuditQuery) ([]model.AuditEntry, error)
+}
+
+type AuditService struct {
+	repo IAuditRepository
+}
+
+func NewAuditService(repo IAuditRepository) *AuditService {
+	return &AuditService{repo: repo}
+}
+
+// Record appends an administrative action to the audit log of the tenant of its principal, anonymous
+// callers are recorded as such.
+func (s *AuditService) Record(ctx context.Context, event *model.AuditEvent) error {
+	entry := &model.AuditEntry{
+		ID:        primitive.NewObjectID(),
+		TenantID:  model.DefaultTenantID,
+		Action:    event.Action,
+		ActorID:   model.AnonymousPrincipalID,
+		ActorName: model.AnonymousPrincipalID,
+		SourceIP:  event.SourceIP,
+		RequestID: event.RequestID,
+		Target:    event.Target,
+		CreatedAt: time.Now().UTC(),
+	}
+	if event.Principal != nil {
+		entry.ActorID = event.Principal.ID
+		entry.ActorName = event.Principal.Name
+		entry.TenantID = event.Principal.TenantID
+	}
+
+	var err error
+	if entry.Before, err = auditState(event.Before); err != nil {
+		return err
+	}
+	if entry.After, err = auditState(event.After); err != nil {
+		return err
+	}
+
+	return s.repo.CreateAuditEntry(ctx, entry)
+}
+
+func (s *AuditService) GetAuditEntries(ctx context.Context, tenantID string,
+	query *dto.AuditQuery) ([]model.AuditEntry, error) {
+	return s.repo.GetAuditEntries(ctx, tenantID, query)
+}
+
+// auditState converts the state of a target to its JSON representation, so fields the API hides are
+// never written to the audit log.
+func auditState(state interface{}) (map[string]interface{}, error) {
+	if state == nil {
+		return nil, nil
+	}
+
+	encoded, err := json.Marshal(state)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"messaging-system/app/dto"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditService_Record(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIAuditRepository(mockController)
+	auditService := NewAuditService(mockRepo)
+
+	t.Run("records the actor and the state of the target", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			CreateAuditEntry(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, entry *model.AuditEntry) error {
+				assert.Equal(t, "retail", entry.TenantID)
+				assert.Equal(t, "key-id", entry.ActorID)
+				assert.Equal(t, "ops", entry.ActorName)
+				assert.Equal(t, "10.0.0.1", entry.SourceIP)
+				assert.Equal(t, "request-id", entry.RequestID)
+				assert.Equal(t, "processor", entry.Target)
+				assert.Equal(t, map[string]interface{}{"running": false}, entry.Before)
+				assert.Equal(t, map[string]interface{}{"running": true}, entry.After)
+				assert.False(t, entry.CreatedAt.IsZero())
+				return nil
+			})
+
+		err := auditService.Record(ctx, &model.AuditEvent{
+			Action:    model.AuditActionProcessorStart,
+			Principal: &model.Principal{ID: "key-id", Name: "ops", TenantID: "retail"},
+			SourceIP:  "10.0.0.1",
+			RequestID: "request-id",
+			Target:    "processor",
+			Before:    &dto.ProcessorState{Running: false},
+			After:     &dto.ProcessorState{Running: true},
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("anonymous caller without state", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			CreateAuditEntry(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, entry *model.AuditEntry) error {
+				assert.Equal(t, model.DefaultTenantID, entry.TenantID)
+				assert.Equal(t, model.AnonymousPrincipalID, entry.ActorID)
+				assert.Nil(t, entry.Before)
+				assert.Nil(t, entry.After)
+				return nil
+			})
+
+		err := auditService.Record(ctx, &model.AuditEvent{Action: model.AuditActionAPIKeyRevoke, Target: "key-id"})
+		assert.Nil(t, err)
+	})
+
+	t.Run("fields hidden from the API are not recorded", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			CreateAuditEntry(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, entry *model.AuditEntry) error {
+				assert.NotContains(t, entry.Before, "phoneNumberIndex")
+				assert.Equal(t, "+90********33", entry.Before["phoneNumber"])
+				return nil
+			})
+
+		err := auditService.Record(ctx, &model.AuditEvent{
+			Action: model.AuditActionSuppressionDelete,
+			Before: &model.Message{PhoneNumber: "+90********33", PhoneNumberIndex: "index"},
+		})
+		assert.Nil(t, err)
+	})
+
+	t.Run("repository fails", func(t *testing.T) {
+		mockRepo.EXPECT().CreateAuditEntry(gomock.Any(), gomock.Any()).Return(assert.AnError)
+
+		err := auditService.Record(ctx, &model.AuditEvent{Action: model.AuditActionProcessorStop})
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
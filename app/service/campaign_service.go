@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"messaging-system/app/phone"
+	"messaging-system/app/template"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ThrottleWindow is the period the throttle of a campaign applies to.
+const ThrottleWindow = time.Minute
+
+type ICampaignRepository interface {
+	CreateCampaign(ctx context.Context, campaign *model.Campaign) error
+	GetCampaign(ctx context.Context, tenantID string, campaignID primitive.ObjectID) (*model.Campaign, error)
+	GetCampaigns(ctx context.Context, tenantID string, limit int) ([]model.Campaign, error)
+	UpdateCampaignRejected(ctx context.Context, campaign *model.Campaign) error
+	UpdateCampaignStatus(ctx context.Context, tenantID string, campaignID primitive.ObjectID, from []string,
+		status string, now time.Time) (*model.Campaign, error)
+	CancelCampaignMessages(ctx context.Context, tenantID string, campaignID primitive.ObjectID) (int64, error)
+	GetCampaignCounts(ctx context.Context, tenantID string,
+		campaignIDs []primitive.ObjectID) (map[primitive.ObjectID]*model.CampaignCounts, error)
+	GetGatedCampaigns(ctx context.Context, now time.Time) ([]model.Campaign, error)
+	CountCampaignSends(ctx context.Context, campaignIDs []primitive.ObjectID,
+		since time.Time) (map[primitive.ObjectID]int, error)
+	GetTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID) (*model.Template, error)
+}
+
+type ICampaignMessageCreator interface {
+	CreateCampaignMessages(ctx context.Context, tenantID string, campaignID primitive.ObjectID,
+		request *dto.MessageRequest, phoneNumbers []string) ([]dto.InvalidPhoneNumber, error)
+}
+
+// CampaignService sends a template to an audience as one message per recipient and controls when the
+// processor may send them.
+type CampaignService struct {
+	repo     ICampaignRepository
+	messages ICampaignMessageCreator
+	phones   *phone.Parser
+}
+
+func NewCampaignService(repo ICampaignRepository, messages ICampaignMessageCreator,
+	phones *phone.Parser) *CampaignService {
+	return &CampaignService{
+		repo:     repo,
+		messages: messages,
+		phones:   phones,
+	}
+}
+
+// CreateCampaign renders the template once, stores the campaign and creates a low priority message for every
+// distinct phone number of the audience. Recipients without a message, invalid numbers and numbers over the
+// daily quota of the tenant, are kept in Rejected. Invalid requests are reported as model.ErrInvalidRequest.
+func (s *CampaignService) CreateCampaign(ctx context.Context, tenantID string,
+	request *dto.CampaignRequest) (*model.Campaign, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+
+	templateID, err := primitive.ObjectIDFromHex(request.TemplateID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid template id", model.ErrInvalidRequest)
+	}
+	messageTemplate, err := s.repo.GetTemplate(ctx, tenantID, templateID)
+	if errors.Is(err, model.ErrNotFound) {
+		return nil, fmt.Errorf("%w: template not found", model.ErrInvalidRequest)
+	}
+	if err != nil {
+		return nil, err
+	}
+	content, err := template.Render(messageTemplate.Body(request.Locale), request.Params)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+
+	now := time.Now().UTC()
+	campaign := &model.Campaign{
+		ID:          primitive.NewObjectID(),
+		TenantID:    tenantID,
+		Name:        request.Name,
+		TemplateID:  request.TemplateID,
+		Params:      request.Params,
+		Locale:      request.Locale,
+		Audience:    make([]string, 0, len(request.Audience)),
+		Rejected:    []dto.InvalidPhoneNumber{},
+		ScheduledAt: request.ScheduledAt,
+		Throttle:    request.Throttle,
+		Status:      model.CampaignStatusRunning,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+
+	seen := make(map[string]bool, len(request.Audience))
+	for _, raw := range request.Audience {
+		number, err := s.phones.Parse(raw)
+		if err != nil {
+			campaign.Rejected = append(campaign.Rejected, dto.InvalidPhoneNumber{PhoneNumber: raw, Error: err.Error()})
+			continue
+		}
+		if seen[number.E164] {
+			continue
+		}
+
+		seen[number.E164] = true
+		campaign.Audience = append(campaign.Audience, number.E164)
+	}
+	if len(campaign.Audience) == 0 {
+		return nil, fmt.Errorf("%w: the audience has no valid phone number", model.ErrInvalidRequest)
+	}
+
+	// the campaign is stored first so its messages are held back from the start
+	if err := s.repo.CreateCampaign(ctx, campaign); err != nil {
+		return nil, err
+	}
+
+	rejected, err := s.messages.CreateCampaignMessages(ctx, tenantID, campaign.ID, &dto.MessageRequest{
+		Content:    content,
+		Priority:   dto.PriorityLow,
+		TemplateID: campaign.TemplateID,
+		Params:     campaign.Params,
+		Locale:     campaign.Locale,
+	}, campaign.Audience)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rejected) > 0 {
+		campaign.Rejected = append(campaign.Rejected, rejected...)
+		if err := s.repo.UpdateCampaignRejected(ctx, campaign); err != nil {
+			return nil, err
+		}
+	}
+	return campaign, nil
+}
+
+// GetCampaign returns a campaign with the counts of its messages.
+func (s *CampaignService) GetCampaign(ctx context.Context, tenantID string,
+	campaignID primitive.ObjectID) (*model.Campaign, error) {
+	campaign, err := s.repo.GetCampaign(ctx, tenantID, campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.addCounts(ctx, tenantID, []*model.Campaign{campaign}); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// GetCampaigns returns the newest campaigns with the counts of their messages.
+func (s *CampaignService) GetCampaigns(ctx context.Context, tenantID string, limit int) ([]model.Campaign, error) {
+	campaigns, err := s.repo.GetCampaigns(ctx, tenantID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	refs := make([]*model.Campaign, 0, len(campaigns))
+	for i := range campaigns {
+		refs = append(refs, &campaigns[i])
+	}
+	if err := s.addCounts(ctx, tenantID, refs); err != nil {
+		return nil, err
+	}
+	return campaigns, nil
+}
+
+// PauseCampaign holds back the unsent messages of a running campaign until it is resumed.
+func (s *CampaignService) PauseCampaign(ctx context.Context, tenantID string,
+	campaignID primitive.ObjectID) (*model.Campaign, error) {
+	return s.updateStatus(ctx, tenantID, campaignID,
+		[]string{model.CampaignStatusRunning}, model.CampaignStatusPaused)
+}
+
+func (s *CampaignService) ResumeCampaign(ctx context.Context, tenantID string,
+	campaignID primitive.ObjectID) (*model.Campaign, error) {
+	return s.updateStatus(ctx, tenantID, campaignID,
+		[]string{model.CampaignStatusPaused}, model.CampaignStatusRunning)
+}
+
+// CancelCampaign stops a campaign for good and moves its unsent messages to the cancelled status. Messages
+// the processor picked before are still sent. Cancelling a cancelled campaign cancels messages left behind
+// by an earlier attempt that failed halfway.
+func (s *CampaignService) CancelCampaign(ctx context.Context, tenantID string,
+	campaignID primitive.ObjectID) (*model.Campaign, error) {
+	from := []string{model.CampaignStatusRunning, model.CampaignStatusPaused, model.CampaignStatusCancelled}
+	campaign, err := s.repo.UpdateCampaignStatus(ctx, tenantID, campaignID, from, model.CampaignStatusCancelled,
+		time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.CancelCampaignMessages(ctx, tenantID, campaignID); err != nil {
+		return nil, err
+	}
+
+	if err := s.addCounts(ctx, tenantID, []*model.Campaign{campaign}); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// HeldCampaigns returns the campaigns of all tenants whose messages must not be sent at now: paused
+// campaigns, campaigns scheduled for later and campaigns that sent their throttle within the last minute.
+func (s *CampaignService) HeldCampaigns(ctx context.Context, now time.Time) ([]primitive.ObjectID, error) {
+	campaigns, err := s.repo.GetGatedCampaigns(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+
+	held := make([]primitive.ObjectID, 0, len(campaigns))
+	throttled := make([]primitive.ObjectID, 0, len(campaigns))
+	throttles := make(map[primitive.ObjectID]int, len(campaigns))
+	for _, campaign := range campaigns {
+		if campaign.Held(now) {
+			held = append(held, campaign.ID)
+			continue
+		}
+		if campaign.Throttle > 0 {
+			throttled = append(throttled, campaign.ID)
+			throttles[campaign.ID] = campaign.Throttle
+		}
+	}
+	if len(throttled) == 0 {
+		return held, nil
+	}
+
+	sends, err := s.repo.CountCampaignSends(ctx, throttled, now.Add(-ThrottleWindow))
+	if err != nil {
+		return nil, err
+	}
+	for _, campaignID := range throttled {
+		if sends[campaignID] >= throttles[campaignID] {
+			held = append(held, campaignID)
+		}
+	}
+
+	return held, nil
+}
+
+func (s *CampaignService) updateStatus(ctx context.Context, tenantID string, campaignID primitive.ObjectID,
+	from []string, status string) (*model.Campaign, error) {
+	campaign, err := s.repo.UpdateCampaignStatus(ctx, tenantID, campaignID, from, status, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.addCounts(ctx, tenantID, []*model.Campaign{campaign}); err != nil {
+		return nil, err
+	}
+	return campaign, nil
+}
+
+func (s *CampaignService) addCounts(ctx context.Context, tenantID string, campaigns []*model.Campaign) error {
+	if len(campaigns) == 0 {
+		return nil
+	}
+
+	campaignIDs := make([]primitive.ObjectID, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		campaignIDs = append(campaignIDs, campaign.ID)
+	}
+
+	counts, err := s.repo.GetCampaignCounts(ctx, tenantID, campaignIDs)
+	if err != nil {
+		return err
+	}
+	for _, campaign := range campaigns {
+		campaign.Counts = counts[campaign.ID]
+	}
+
+	return nil
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"messaging-system/app/dto"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCampaignService_CreateCampaign(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockICampaignRepository(mockController)
+	mockMessages := mocks.NewMockICampaignMessageCreator(mockController)
+	campaignService := NewCampaignService(mockRepo, mockMessages, newPhoneParser(t))
+	templateID := primitive.NewObjectID()
+	messageTemplate := &model.Template{DefaultLocale: "en", Locales: map[string]string{"en": "Save {{discount}} today"}}
+	newRequest := func(audience ...string) *dto.CampaignRequest {
+		return &dto.CampaignRequest{
+			Name:       "spring sale",
+			Audience:   audience,
+			TemplateID: templateID.Hex(),
+			Params:     map[string]string{"discount": "20%"},
+			Throttle:   60,
+		}
+	}
+
+	t.Run("invalid request", func(t *testing.T) {
+		campaign, err := campaignService.CreateCampaign(ctx, "retail", &dto.CampaignRequest{Name: "spring sale"})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, campaign)
+	})
+
+	t.Run("template not found", func(t *testing.T) {
+		mockRepo.EXPECT().GetTemplate(gomock.Any(), "retail", templateID).Return(nil, model.ErrNotFound)
+
+		campaign, err := campaignService.CreateCampaign(ctx, "retail", newRequest("+905551112233"))
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, campaign)
+	})
+
+	t.Run("template params missing", func(t *testing.T) {
+		mockRepo.EXPECT().GetTemplate(gomock.Any(), "retail", templateID).Return(messageTemplate, nil)
+		request := newRequest("+905551112233")
+		request.Params = nil
+
+		campaign, err := campaignService.CreateCampaign(ctx, "retail", request)
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, campaign)
+	})
+
+	t.Run("audience without valid phone numbers", func(t *testing.T) {
+		mockRepo.EXPECT().GetTemplate(gomock.Any(), "retail", templateID).Return(messageTemplate, nil)
+
+		campaign, err := campaignService.CreateCampaign(ctx, "retail", newRequest("123"))
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, campaign)
+	})
+
+	t.Run("creates the low priority messages of distinct recipients at once", func(t *testing.T) {
+		var campaignID primitive.ObjectID
+		mockRepo.EXPECT().GetTemplate(gomock.Any(), "retail", templateID).Return(messageTemplate, nil)
+		mockRepo.
+			EXPECT().
+			CreateCampaign(gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, campaign *model.Campaign) {
+				campaignID = campaign.ID
+				assert.Equal(t, model.CampaignStatusRunning, campaign.Status)
+			}).
+			Return(nil)
+		mockMessages.
+			EXPECT().
+			CreateCampaignMessages(gomock.Any(), "retail", gomock.Any(), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, id primitive.ObjectID, request *dto.MessageRequest,
+				phoneNumbers []string) ([]dto.InvalidPhoneNumber, error) {
+				assert.Equal(t, campaignID, id)
+				assert.Equal(t, dto.PriorityLow, request.Priority)
+				assert.Equal(t, "Save 20% today", request.Content)
+				assert.Equal(t, templateID.Hex(), request.TemplateID)
+				assert.Equal(t, []string{"+905551112233", "+905551112244"}, phoneNumbers)
+				return []dto.InvalidPhoneNumber{}, nil
+			})
+
+		campaign, err := campaignService.CreateCampaign(ctx, "retail",
+			newRequest("+905551112233", "05551112233", "+905551112244", "123"))
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"+905551112233", "+905551112244"}, campaign.Audience)
+		assert.Len(t, campaign.Rejected, 1)
+		assert.Equal(t, "123", campaign.Rejected[0].PhoneNumber)
+	})
+
+	t.Run("recipients without a message are rejected", func(t *testing.T) {
+		mockRepo.EXPECT().GetTemplate(gomock.Any(), "retail", templateID).Return(messageTemplate, nil)
+		mockRepo.EXPECT().CreateCampaign(gomock.Any(), gomock.Any()).Return(nil)
+		mockMessages.
+			EXPECT().
+			CreateCampaignMessages(gomock.Any(), "retail", gomock.Any(), gomock.Any(), gomock.Any()).
+			Return([]dto.InvalidPhoneNumber{
+				{PhoneNumber: "+905551112244", Error: model.ErrQuotaExceeded.Error()},
+				{PhoneNumber: "+905551112255", Error: model.ErrQuotaExceeded.Error()},
+			}, nil)
+		mockRepo.
+			EXPECT().
+			UpdateCampaignRejected(gomock.Any(), gomock.Any()).
+			Do(func(_ context.Context, campaign *model.Campaign) {
+				assert.Len(t, campaign.Rejected, 2)
+				assert.Equal(t, model.ErrQuotaExceeded.Error(), campaign.Rejected[0].Error)
+			}).
+			Return(nil)
+
+		campaign, err := campaignService.CreateCampaign(ctx, "retail",
+			newRequest("+905551112233", "+905551112244", "+905551112255"))
+		assert.Nil(t, err)
+		assert.Equal(t, "+905551112244", campaign.Rejected[0].PhoneNumber)
+	})
+}
+
+func TestCampaignService_UpdateStatus(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockICampaignRepository(mockController)
+	campaignService := NewCampaignService(mockRepo, nil, newPhoneParser(t))
+	campaignID := primitive.NewObjectID()
+	counts := map[primitive.ObjectID]*model.CampaignCounts{campaignID: {Total: 3, Sent: 1, Unsent: 2}}
+
+	t.Run("pause campaign", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			UpdateCampaignStatus(gomock.Any(), "retail", campaignID, []string{model.CampaignStatusRunning},
+				model.CampaignStatusPaused, gomock.Any()).
+			Return(&model.Campaign{ID: campaignID, Status: model.CampaignStatusPaused}, nil)
+		mockRepo.EXPECT().GetCampaignCounts(gomock.Any(), "retail", []primitive.ObjectID{campaignID}).Return(counts, nil)
+
+		campaign, err := campaignService.PauseCampaign(ctx, "retail", campaignID)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, campaign.Counts.Unsent)
+	})
+
+	t.Run("resume campaign that is not paused", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			UpdateCampaignStatus(gomock.Any(), "retail", campaignID, []string{model.CampaignStatusPaused},
+				model.CampaignStatusRunning, gomock.Any()).
+			Return(nil, model.ErrInvalidState)
+
+		campaign, err := campaignService.ResumeCampaign(ctx, "retail", campaignID)
+		assert.ErrorIs(t, err, model.ErrInvalidState)
+		assert.Nil(t, campaign)
+	})
+
+	t.Run("cancel campaign cancels its unsent messages", func(t *testing.T) {
+		gomock.InOrder(
+			mockRepo.EXPECT().
+				UpdateCampaignStatus(gomock.Any(), "retail", campaignID, gomock.Any(),
+					model.CampaignStatusCancelled, gomock.Any()).
+				Return(&model.Campaign{ID: campaignID, Status: model.CampaignStatusCancelled}, nil),
+			mockRepo.EXPECT().CancelCampaignMessages(gomock.Any(), "retail", campaignID).Return(int64(2), nil),
+			mockRepo.EXPECT().GetCampaignCounts(gomock.Any(), "retail", gomock.Any()).Return(counts, nil),
+		)
+
+		campaign, err := campaignService.CancelCampaign(ctx, "retail", campaignID)
+		assert.Nil(t, err)
+		assert.Equal(t, model.CampaignStatusCancelled, campaign.Status)
+	})
+
+	t.Run("cancel error", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			UpdateCampaignStatus(gomock.Any(), "retail", campaignID, gomock.Any(), gomock.Any(), gomock.Any()).
+			Return(&model.Campaign{ID: campaignID}, nil)
+		mockRepo.EXPECT().CancelCampaignMessages(gomock.Any(), "retail", campaignID).Return(int64(0), assert.AnError)
+
+		campaign, err := campaignService.CancelCampaign(ctx, "retail", campaignID)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, campaign)
+	})
+}
+
+func TestCampaignService_HeldCampaigns(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockICampaignRepository(mockController)
+	campaignService := NewCampaignService(mockRepo, nil, newPhoneParser(t))
+	now := time.Now().UTC()
+	later := now.Add(time.Hour)
+	earlier := now.Add(-time.Hour)
+
+	paused := model.Campaign{ID: primitive.NewObjectID(), Status: model.CampaignStatusPaused}
+	scheduled := model.Campaign{ID: primitive.NewObjectID(), Status: model.CampaignStatusRunning, ScheduledAt: &later}
+	throttled := model.Campaign{ID: primitive.NewObjectID(), Status: model.CampaignStatusRunning, Throttle: 10}
+	belowThrottle := model.Campaign{ID: primitive.NewObjectID(), Status: model.CampaignStatusRunning, Throttle: 10,
+		ScheduledAt: &earlier}
+
+	t.Run("paused, scheduled and throttled campaigns are held", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			GetGatedCampaigns(gomock.Any(), now).
+			Return([]model.Campaign{paused, scheduled, throttled, belowThrottle}, nil)
+		mockRepo.
+			EXPECT().
+			CountCampaignSends(gomock.Any(), []primitive.ObjectID{throttled.ID, belowThrottle.ID},
+				now.Add(-ThrottleWindow)).
+			Return(map[primitive.ObjectID]int{throttled.ID: 10, belowThrottle.ID: 9}, nil)
+
+		held, err := campaignService.HeldCampaigns(ctx, now)
+		assert.Nil(t, err)
+		assert.Equal(t, []primitive.ObjectID{paused.ID, scheduled.ID, throttled.ID}, held)
+	})
+
+	t.Run("no throttled campaign", func(t *testing.T) {
+		mockRepo.EXPECT().GetGatedCampaigns(gomock.Any(), now).Return([]model.Campaign{paused}, nil)
+
+		held, err := campaignService.HeldCampaigns(ctx, now)
+		assert.Nil(t, err)
+		assert.Equal(t, []primitive.ObjectID{paused.ID}, held)
+	})
+
+	t.Run("error counting sends", func(t *testing.T) {
+		mockRepo.EXPECT().GetGatedCampaigns(gomock.Any(), now).Return([]model.Campaign{throttled}, nil)
+		mockRepo.EXPECT().CountCampaignSends(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		held, err := campaignService.HeldCampaigns(ctx, now)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, held)
+	})
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"strings"
+)
+
+const (
+	// maxNDJSONLine bounds a single line of an NDJSON upload.
+	maxNDJSONLine = 64 * 1024
+	// utf8BOM is written at the start of CSV files by spreadsheet applications.
+	utf8BOM = "\ufeff"
+)
+
+// phoneColumns are the names of the CSV column holding the phone number, compared in lower case.
+var phoneColumns = []string{"phone", "phonenumber", "phone_number", "msisdn"}
+
+// contactRow is a row of an upload, number counts the rows after the CSV header from 1.
+type contactRow struct {
+	number      int
+	phoneNumber string
+	err         error
+}
+
+type contactReader interface {
+	// next returns the next row or io.EOF, rows that cannot be read carry their error.
+	next() (*contactRow, error)
+}
+
+func newContactReader(format string, body io.Reader) (contactReader, error) {
+	switch format {
+	case dto.ContactFormatCSV:
+		return newCSVContactReader(body)
+	case dto.ContactFormatNDJSON:
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 4096), maxNDJSONLine)
+		return &ndjsonContactReader{scanner: scanner}, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q, expected csv or ndjson", model.ErrInvalidRequest, format)
+	}
+}
+
+type csvContactReader struct {
+	reader *csv.Reader
+	column int
+	rows   int
+}
+
+func newCSVContactReader(body io.Reader) (*csvContactReader, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("%w: the file is empty", model.ErrInvalidRequest)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid csv header: %s", model.ErrInvalidRequest, err)
+	}
+
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, utf8BOM)))
+		for _, column := range phoneColumns {
+			if name == column {
+				return &csvContactReader{reader: reader, column: i}, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("%w: the csv header has no phone column", model.ErrInvalidRequest)
+}
+
+func (r *csvContactReader) next() (*contactRow, error) {
+	record, err := r.reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, io.EOF
+	}
+	r.rows++
+
+	var parseErr *csv.ParseError
+	if errors.As(err, &parseErr) {
+		return &contactRow{number: r.rows, err: parseErr.Err}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if r.column >= len(record) {
+		return &contactRow{number: r.rows, err: errors.New("phone column is missing")}, nil
+	}
+	return &contactRow{number: r.rows, phoneNumber: record[r.column]}, nil
+}
+
+type ndjsonContactReader struct {
+	scanner *bufio.Scanner
+	rows    int
+}
+
+func (r *ndjsonContactReader) next() (*contactRow, error) {
+	for r.scanner.Scan() {
+		r.rows++
+		line := bytes.TrimSpace(r.scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var fields struct {
+			Phone       string `json:"phone"`
+			PhoneNumber string `json:"phoneNumber"`
+		}
+		if err := json.Unmarshal(line, &fields); err != nil {
+			return &contactRow{number: r.rows, err: errors.New("invalid JSON object")}, nil
+		}
+
+		phoneNumber := fields.PhoneNumber
+		if phoneNumber == "" {
+			phoneNumber = fields.Phone
+		}
+		return &contactRow{number: r.rows, phoneNumber: phoneNumber}, nil
+	}
+
+	if err := r.scanner.Err(); err != nil {
+		if errors.Is(err, bufio.ErrTooLong) {
+			return nil, fmt.Errorf("%w: line %d is longer than %d bytes", model.ErrInvalidRequest, r.rows+1,
+				maxNDJSONLine)
+		}
+		return nil, err
+	}
+	return nil, io.EOF
+}
@@ -0,0 +1,238 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"messaging-system/app/phone"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ContactImportBatchSize is the number of contacts written to Mongo at once while an upload is read.
+const ContactImportBatchSize = 500
+
+type IContactRepository interface {
+	CreateContactList(ctx context.Context, list *model.ContactList) error
+	GetContactList(ctx context.Context, tenantID string, listID primitive.ObjectID) (*model.ContactList, error)
+	GetContactLists(ctx context.Context, tenantID string, limit int) ([]model.ContactList, error)
+	AddContacts(ctx context.Context, contacts []model.Contact) (int64, error)
+	CountContacts(ctx context.Context, tenantID string, listID primitive.ObjectID) (int64, error)
+	ForEachContact(ctx context.Context, tenantID string, listID primitive.ObjectID,
+		handle func(ctx context.Context, contact *model.Contact) error) error
+}
+
+type IListMessageCreator interface {
+	CreateMessage(ctx context.Context, tenantID string, request *dto.MessageRequest) (*model.Message, error)
+}
+
+// ContactService manages the contact lists of each tenant and sends messages to their members.
+type ContactService struct {
+	repo     IContactRepository
+	messages IListMessageCreator
+	phones   *phone.Parser
+}
+
+func NewContactService(repo IContactRepository, messages IListMessageCreator, phones *phone.Parser) *ContactService {
+	return &ContactService{
+		repo:     repo,
+		messages: messages,
+		phones:   phones,
+	}
+}
+
+func (s *ContactService) CreateList(ctx context.Context, tenantID string,
+	request *dto.ContactListRequest) (*model.ContactList, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+
+	list := &model.ContactList{
+		ID:        primitive.NewObjectID(),
+		TenantID:  tenantID,
+		Name:      request.Name,
+		CreatedAt: time.Now().UTC(),
+	}
+	if err := s.repo.CreateContactList(ctx, list); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *ContactService) GetList(ctx context.Context, tenantID string,
+	listID primitive.ObjectID) (*model.ContactList, error) {
+	list, err := s.repo.GetContactList(ctx, tenantID, listID)
+	if err != nil {
+		return nil, err
+	}
+
+	if list.Size, err = s.repo.CountContacts(ctx, tenantID, listID); err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (s *ContactService) GetLists(ctx context.Context, tenantID string, limit int) ([]model.ContactList, error) {
+	lists, err := s.repo.GetContactLists(ctx, tenantID, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range lists {
+		if lists[i].Size, err = s.repo.CountContacts(ctx, tenantID, lists[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return lists, nil
+}
+
+// ImportContacts streams a CSV or NDJSON upload into a list in batches. Phone numbers are normalized
+// and validated like the recipient of a message, numbers already on the list are counted as duplicates
+// and invalid rows are reported back. CSV uploads need a header with a phone column, NDJSON lines are
+// objects with a phone or phoneNumber field.
+func (s *ContactService) ImportContacts(ctx context.Context, tenantID string, listID primitive.ObjectID,
+	format string, body io.Reader) (*dto.ContactImportResponse, error) {
+	if _, err := s.repo.GetContactList(ctx, tenantID, listID); err != nil {
+		return nil, err
+	}
+
+	rows, err := newContactReader(format, body)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &dto.ContactImportResponse{Invalid: []dto.InvalidRow{}}
+	now := time.Now().UTC()
+	batch := make([]model.Contact, 0, ContactImportBatchSize)
+	// duplicates within a batch are skipped here, the unique index of the list skips all others
+	seen := make(map[string]bool, ContactImportBatchSize)
+	flush := func() error {
+		imported, err := s.repo.AddContacts(ctx, batch)
+		if err != nil {
+			return err
+		}
+		response.Imported += int(imported)
+		batch = batch[:0]
+		clear(seen)
+		return nil
+	}
+
+	for {
+		row, err := rows.next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		response.Rows++
+
+		phoneNumber, err := s.normalize(row)
+		if err != nil {
+			response.InvalidCount++
+			if len(response.Invalid) < dto.MaxReportedRows {
+				response.Invalid = append(response.Invalid,
+					dto.InvalidRow{Row: row.number, PhoneNumber: row.phoneNumber, Error: err.Error()})
+			}
+			continue
+		}
+		if seen[phoneNumber.E164] {
+			continue
+		}
+
+		seen[phoneNumber.E164] = true
+		batch = append(batch, model.Contact{
+			ID:             primitive.NewObjectID(),
+			TenantID:       tenantID,
+			ListID:         listID,
+			PhoneNumber:    phoneNumber.E164,
+			RawPhoneNumber: row.phoneNumber,
+			Country:        phoneNumber.Country,
+			CreatedAt:      now,
+		})
+		if len(batch) == ContactImportBatchSize {
+			if err := flush(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if err := flush(); err != nil {
+		return nil, err
+	}
+
+	response.Duplicates = response.Rows - response.InvalidCount - response.Imported
+	return response, nil
+}
+
+// SendToList creates an unsent message with the same content for every member of a list. Members that
+// are no valid recipient anymore are rejected, once the daily quota is used up the remaining members
+// are skipped.
+func (s *ContactService) SendToList(ctx context.Context, tenantID string, listID primitive.ObjectID,
+	request *dto.ListSendRequest) (*dto.ListSendResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+
+	if _, err := s.repo.GetContactList(ctx, tenantID, listID); err != nil {
+		return nil, err
+	}
+
+	response := &dto.ListSendResponse{Rejected: []dto.InvalidPhoneNumber{}}
+	quotaExceeded := false
+	err := s.repo.ForEachContact(ctx, tenantID, listID, func(ctx context.Context, contact *model.Contact) error {
+		if quotaExceeded {
+			response.Skipped++
+			return nil
+		}
+
+		_, err := s.messages.CreateMessage(ctx, tenantID, &dto.MessageRequest{
+			To:          contact.PhoneNumber,
+			Content:     request.Content,
+			Priority:    request.Priority,
+			Category:    request.Category,
+			CallbackURL: request.CallbackURL,
+		})
+		switch {
+		case errors.Is(err, model.ErrQuotaExceeded):
+			quotaExceeded = true
+			response.Skipped++
+		case errors.Is(err, model.ErrInvalidRequest):
+			response.RejectedCount++
+			if len(response.Rejected) < dto.MaxReportedRows {
+				response.Rejected = append(response.Rejected,
+					dto.InvalidPhoneNumber{PhoneNumber: contact.PhoneNumber, Error: err.Error()})
+			}
+		case err != nil:
+			return err
+		default:
+			response.Queued++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// normalize reads the phone number of a row with the same rules as the recipient of a message.
+func (s *ContactService) normalize(row *contactRow) (*phone.Number, error) {
+	if row.err != nil {
+		return nil, row.err
+	}
+
+	number, err := s.phones.Parse(row.phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	if err := dto.ValidatePhoneNumber(number.E164); err != nil {
+		return nil, err
+	}
+	return number, nil
+}
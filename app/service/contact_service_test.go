@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"messaging-system/app/dto"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestContactService_ImportContacts(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIContactRepository(mockController)
+	contactService := NewContactService(mockRepo, nil, newPhoneParser(t))
+	listID := primitive.NewObjectID()
+	phoneNumbers := func(contacts []model.Contact) []string {
+		numbers := make([]string, 0, len(contacts))
+		for _, contact := range contacts {
+			numbers = append(numbers, contact.PhoneNumber)
+		}
+		return numbers
+	}
+
+	t.Run("list not found", func(t *testing.T) {
+		mockRepo.EXPECT().GetContactList(gomock.Any(), "retail", listID).Return(nil, model.ErrNotFound)
+
+		response, err := contactService.ImportContacts(ctx, "retail", listID, dto.ContactFormatCSV,
+			strings.NewReader("phone\n+905551112233\n"))
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		assert.Nil(t, response)
+	})
+
+	mockRepo.EXPECT().GetContactList(gomock.Any(), "retail", listID).Return(&model.ContactList{}, nil).AnyTimes()
+
+	t.Run("csv with invalid and duplicate rows", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			AddContacts(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, contacts []model.Contact) (int64, error) {
+				assert.Equal(t, []string{"+905551112233", "+4915112345678"}, phoneNumbers(contacts))
+				assert.Equal(t, listID, contacts[0].ListID)
+				assert.Equal(t, "0555 111 22 33", contacts[0].RawPhoneNumber)
+				// the second number is on the list already
+				return 1, nil
+			})
+
+		body := "\ufeffname,Phone\nAda,0555 111 22 33\nBob,123\nAda again,+905551112233\nCem,+4915112345678\nDeniz\n"
+		response, err := contactService.ImportContacts(ctx, "retail", listID, dto.ContactFormatCSV,
+			strings.NewReader(body))
+		assert.Nil(t, err)
+		assert.Equal(t, 5, response.Rows)
+		assert.Equal(t, 1, response.Imported)
+		assert.Equal(t, 2, response.Duplicates)
+		assert.Equal(t, 2, response.InvalidCount)
+		assert.Equal(t, 2, response.Invalid[0].Row)
+		assert.Equal(t, "123", response.Invalid[0].PhoneNumber)
+		assert.Equal(t, 5, response.Invalid[1].Row)
+	})
+
+	t.Run("csv without phone column", func(t *testing.T) {
+		response, err := contactService.ImportContacts(ctx, "retail", listID, dto.ContactFormatCSV,
+			strings.NewReader("name,email\nAda,ada@example.com\n"))
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, response)
+	})
+
+	t.Run("ndjson", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			AddContacts(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, contacts []model.Contact) (int64, error) {
+				assert.Equal(t, []string{"+905551112233", "+905551112244"}, phoneNumbers(contacts))
+				return int64(len(contacts)), nil
+			})
+
+		body := `{"phoneNumber":"+905551112233"}` + "\n\n" + `{"phone":"05551112244","name":"Bob"}` + "\n{oops\n"
+		response, err := contactService.ImportContacts(ctx, "retail", listID, dto.ContactFormatNDJSON,
+			strings.NewReader(body))
+		assert.Nil(t, err)
+		assert.Equal(t, 3, response.Rows)
+		assert.Equal(t, 2, response.Imported)
+		assert.Equal(t, 1, response.InvalidCount)
+		assert.Equal(t, 4, response.Invalid[0].Row)
+	})
+
+	t.Run("large uploads are written in batches", func(t *testing.T) {
+		var body strings.Builder
+		body.WriteString("phone\n")
+		for i := 0; i < ContactImportBatchSize+1; i++ {
+			body.WriteString(fmt.Sprintf("+90555%07d\n", i))
+		}
+
+		gomock.InOrder(
+			mockRepo.EXPECT().AddContacts(gomock.Any(), gomock.Len(ContactImportBatchSize)).
+				Return(int64(ContactImportBatchSize), nil),
+			mockRepo.EXPECT().AddContacts(gomock.Any(), gomock.Len(1)).Return(int64(1), nil),
+		)
+
+		response, err := contactService.ImportContacts(ctx, "retail", listID, dto.ContactFormatCSV,
+			strings.NewReader(body.String()))
+		assert.Nil(t, err)
+		assert.Equal(t, ContactImportBatchSize+1, response.Imported)
+		assert.Equal(t, 0, response.Duplicates)
+	})
+
+	t.Run("unsupported format", func(t *testing.T) {
+		response, err := contactService.ImportContacts(ctx, "retail", listID, "xlsx", strings.NewReader(""))
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, response)
+	})
+
+	t.Run("error adding contacts", func(t *testing.T) {
+		mockRepo.EXPECT().AddContacts(gomock.Any(), gomock.Any()).Return(int64(0), assert.AnError)
+
+		response, err := contactService.ImportContacts(ctx, "retail", listID, dto.ContactFormatCSV,
+			strings.NewReader("phone\n+905551112233\n"))
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, response)
+	})
+}
+
+func TestContactService_SendToList(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIContactRepository(mockController)
+	mockMessages := mocks.NewMockIListMessageCreator(mockController)
+	contactService := NewContactService(mockRepo, mockMessages, newPhoneParser(t))
+	listID := primitive.NewObjectID()
+	members := func(phoneNumbers ...string) interface{} {
+		return func(ctx context.Context, _ string, _ primitive.ObjectID,
+			handle func(context.Context, *model.Contact) error) error {
+			for _, phoneNumber := range phoneNumbers {
+				if err := handle(ctx, &model.Contact{PhoneNumber: phoneNumber}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	t.Run("invalid request", func(t *testing.T) {
+		response, err := contactService.SendToList(ctx, "retail", listID, &dto.ListSendRequest{})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, response)
+	})
+
+	t.Run("fans the content out to every member", func(t *testing.T) {
+		mockRepo.EXPECT().GetContactList(gomock.Any(), "retail", listID).Return(&model.ContactList{}, nil)
+		mockRepo.
+			EXPECT().
+			ForEachContact(gomock.Any(), "retail", listID, gomock.Any()).
+			DoAndReturn(members("+905551112233", "+905551112244", "+905551112255", "+905551112266"))
+		gomock.InOrder(
+			mockMessages.EXPECT().
+				CreateMessage(gomock.Any(), "retail", &dto.MessageRequest{To: "+905551112233", Content: "Sale!"}).
+				Return(&model.Message{}, nil),
+			mockMessages.EXPECT().CreateMessage(gomock.Any(), "retail", gomock.Any()).
+				Return(nil, fmt.Errorf("%w: country is not allowed", model.ErrInvalidRequest)),
+			mockMessages.EXPECT().CreateMessage(gomock.Any(), "retail", gomock.Any()).
+				Return(nil, model.ErrQuotaExceeded),
+		)
+
+		response, err := contactService.SendToList(ctx, "retail", listID, &dto.ListSendRequest{Content: "Sale!"})
+		assert.Nil(t, err)
+		assert.Equal(t, 1, response.Queued)
+		assert.Equal(t, 1, response.RejectedCount)
+		assert.Equal(t, "+905551112244", response.Rejected[0].PhoneNumber)
+		assert.Equal(t, 2, response.Skipped)
+	})
+
+	t.Run("error creating message", func(t *testing.T) {
+		mockRepo.EXPECT().GetContactList(gomock.Any(), "retail", listID).Return(&model.ContactList{}, nil)
+		mockRepo.
+			EXPECT().
+			ForEachContact(gomock.Any(), "retail", listID, gomock.Any()).
+			DoAndReturn(members("+905551112233"))
+		mockMessages.EXPECT().CreateMessage(gomock.Any(), "retail", gomock.Any()).Return(nil, assert.AnError)
+
+		response, err := contactService.SendToList(ctx, "retail", listID, &dto.ListSendRequest{Content: "Sale!"})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, response)
+	})
+}
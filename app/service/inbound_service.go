@@ -0,0 +1,232 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"messaging-system/app/phone"
+	"messaging-system/config"
+	"sort"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Keywords maps inbound replies to the keyword they trigger.
+var Keywords = map[string]string{
+	"STOP":        model.KeywordStop,
+	"STOPALL":     model.KeywordStop,
+	"UNSUBSCRIBE": model.KeywordStop,
+	"CANCEL":      model.KeywordStop,
+	"END":         model.KeywordStop,
+	"QUIT":        model.KeywordStop,
+	"IPTAL":       model.KeywordStop,
+	"RET":         model.KeywordStop,
+	"START":       model.KeywordStart,
+	"UNSTOP":      model.KeywordStart,
+	"BASLA":       model.KeywordStart,
+	"HELP":        model.KeywordHelp,
+	"INFO":        model.KeywordHelp,
+	"YARDIM":      model.KeywordHelp,
+}
+
+type IInboundRepository interface {
+	CreateInboundMessage(ctx context.Context, message *model.InboundMessage) error
+	GetInboundMessageByProviderID(ctx context.Context, tenantID, providerMessageID string) (*model.InboundMessage, error)
+	GetInboundMessages(ctx context.Context, tenantID, phoneNumber string, limit int) ([]model.InboundMessage, error)
+	GetMessagesByPhoneNumber(ctx context.Context, tenantID, phoneNumber string, limit int) ([]model.Message, error)
+	GetLastSentMessage(ctx context.Context, tenantID, phoneNumber string) (*model.Message, error)
+}
+
+type ISuppressor interface {
+	Suppress(ctx context.Context, tenantID string, request *dto.SuppressionRequest,
+		source string) (*model.Suppression, error)
+	Unsuppress(ctx context.Context, tenantID, phoneNumber string) error
+}
+
+type IReplySender interface {
+	CreateMessage(ctx context.Context, tenantID string, request *dto.MessageRequest) (*model.Message, error)
+}
+
+type InboundService struct {
+	repo         IInboundRepository
+	suppressions ISuppressor
+	replies      IReplySender
+	phones       *phone.Parser
+	conf         *config.Message
+	logger       *slog.Logger
+}
+
+func NewInboundService(repo IInboundRepository, suppressions ISuppressor, replies IReplySender,
+	phones *phone.Parser, conf *config.Message, logger *slog.Logger) *InboundService {
+	return &InboundService{
+		repo:         repo,
+		suppressions: suppressions,
+		replies:      replies,
+		phones:       phones,
+		conf:         conf,
+		logger:       logger,
+	}
+}
+
+// DetectKeyword returns the keyword the text triggers or an empty string.
+func DetectKeyword(text string) string {
+	return Keywords[strings.ToUpper(strings.TrimSpace(text))]
+}
+
+// ReceiveMessage acts on the keyword of an inbound message and stores it linked to the last
+// message sent to the sender. The keyword is handled before storing so a provider retry after a
+// failure never loses an opt-out, messages the provider already delivered are neither acted on nor
+// stored twice.
+func (s *InboundService) ReceiveMessage(ctx context.Context, tenantID string,
+	request *dto.InboundRequest) (*model.InboundMessage, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+
+	if request.MessageID != "" {
+		received, err := s.repo.GetInboundMessageByProviderID(ctx, tenantID, request.MessageID)
+		if err == nil {
+			s.logger.WarnContext(ctx, "inbound message already received", "providerMessageId", request.MessageID)
+			return received, nil
+		}
+		if !errors.Is(err, model.ErrNotFound) {
+			return nil, err
+		}
+	}
+
+	// senders are read without the country allowlist, a number whose country was removed from it can
+	// still opt out
+	number, err := s.phones.ParseAnyCountry(request.From)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+
+	message := &model.InboundMessage{
+		ID:                primitive.NewObjectID(),
+		TenantID:          tenantID,
+		ProviderMessageID: request.MessageID,
+		PhoneNumber:       number.E164,
+		RawPhoneNumber:    request.From,
+		Text:              request.Text,
+		Keyword:           DetectKeyword(request.Text),
+		ReceivedAt:        time.Now().UTC(),
+	}
+
+	lastSent, err := s.repo.GetLastSentMessage(ctx, tenantID, message.PhoneNumber)
+	switch {
+	case err == nil:
+		message.ReplyTo = &lastSent.ID
+	case !errors.Is(err, model.ErrNotFound):
+		return nil, err
+	}
+
+	if err := s.handleKeyword(ctx, message); err != nil {
+		return nil, err
+	}
+
+	err = s.repo.CreateInboundMessage(ctx, message)
+	if errors.Is(err, model.ErrAlreadyExists) {
+		s.logger.WarnContext(ctx, "inbound message already received", "providerMessageId", message.ProviderMessageID)
+		return message, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// GetConversation returns the newest inbound and outbound messages of a phone number in time order.
+func (s *InboundService) GetConversation(ctx context.Context, tenantID, rawPhoneNumber string,
+	limit int) ([]dto.ConversationEntry, error) {
+	number, err := s.phones.Parse(rawPhoneNumber)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+
+	outbound, err := s.repo.GetMessagesByPhoneNumber(ctx, tenantID, number.E164, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	inbound, err := s.repo.GetInboundMessages(ctx, tenantID, number.E164, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]dto.ConversationEntry, 0, len(outbound)+len(inbound))
+	for _, message := range outbound {
+		timestamp := message.CreatedAt
+		if !message.SentAt.IsZero() {
+			timestamp = message.SentAt
+		}
+
+		entries = append(entries, dto.ConversationEntry{
+			ID:        message.ID.Hex(),
+			Direction: dto.DirectionOutbound,
+			Content:   message.Content,
+			Status:    message.Status,
+			Timestamp: timestamp,
+		})
+	}
+	for _, message := range inbound {
+		entry := dto.ConversationEntry{
+			ID:        message.ID.Hex(),
+			Direction: dto.DirectionInbound,
+			Content:   message.Text,
+			Keyword:   message.Keyword,
+			Timestamp: message.ReceivedAt,
+		}
+		if message.ReplyTo != nil {
+			entry.ReplyTo = message.ReplyTo.Hex()
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Timestamp.Before(entries[j].Timestamp)
+	})
+	if len(entries) > limit {
+		entries = entries[len(entries)-limit:]
+	}
+
+	return entries, nil
+}
+
+func (s *InboundService) handleKeyword(ctx context.Context, message *model.InboundMessage) error {
+	switch message.Keyword {
+	case model.KeywordStop:
+		request := &dto.SuppressionRequest{PhoneNumber: message.PhoneNumber, Reason: "replied " + message.Text}
+		_, err := s.suppressions.Suppress(ctx, message.TenantID, request, model.SuppressionSourceInbound)
+		return err
+	case model.KeywordStart:
+		err := s.suppressions.Unsuppress(ctx, message.TenantID, message.PhoneNumber)
+		if errors.Is(err, model.ErrNotFound) {
+			return nil
+		}
+		return err
+	case model.KeywordHelp:
+		if s.conf == nil || s.conf.HelpReply == "" {
+			return nil
+		}
+
+		_, err := s.replies.CreateMessage(ctx, message.TenantID, &dto.MessageRequest{
+			To:       message.PhoneNumber,
+			Content:  s.conf.HelpReply,
+			Priority: dto.PriorityHigh,
+		})
+		// a provider retry would not free up the quota, the inbound message is stored without a reply
+		if errors.Is(err, model.ErrQuotaExceeded) {
+			s.logger.WarnContext(ctx, "help reply skipped, daily quota exceeded", "tenantId", message.TenantID)
+			return nil
+		}
+		return err
+	default:
+		return nil
+	}
+}
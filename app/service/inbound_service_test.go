@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"messaging-system/app/dto"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"messaging-system/config"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDetectKeyword(t *testing.T) {
+	assert.Equal(t, model.KeywordStop, DetectKeyword(" stop "))
+	assert.Equal(t, model.KeywordStop, DetectKeyword("IPTAL"))
+	assert.Equal(t, model.KeywordStart, DetectKeyword("Start"))
+	assert.Equal(t, model.KeywordHelp, DetectKeyword("help"))
+	assert.Empty(t, DetectKeyword("please stop sending"))
+}
+
+func TestInboundService_ReceiveMessage(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	tenantID := "retail"
+	mockRepo := mocks.NewMockIInboundRepository(mockController)
+	mockSuppressor := mocks.NewMockISuppressor(mockController)
+	mockReplies := mocks.NewMockIReplySender(mockController)
+	inboundService := NewInboundService(mockRepo, mockSuppressor, mockReplies, newPhoneParser(t),
+		&config.Message{HelpReply: "Reply STOP to unsubscribe"}, slog.Default())
+
+	t.Run("links reply to last sent message", func(t *testing.T) {
+		lastSent := &model.Message{ID: primitive.NewObjectID()}
+		mockRepo.EXPECT().GetLastSentMessage(gomock.Any(), tenantID, "+905551112233").Return(lastSent, nil)
+		mockRepo.
+			EXPECT().
+			CreateInboundMessage(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, message *model.InboundMessage) error {
+				assert.Equal(t, lastSent.ID, *message.ReplyTo)
+				assert.Equal(t, "0555 111 22 33", message.RawPhoneNumber)
+				assert.Equal(t, tenantID, message.TenantID)
+				return nil
+			})
+
+		message, err := inboundService.ReceiveMessage(ctx, tenantID,
+			&dto.InboundRequest{From: "0555 111 22 33", Text: "Thanks"})
+		assert.Nil(t, err)
+		assert.Empty(t, message.Keyword)
+	})
+
+	t.Run("stop keyword suppresses sender", func(t *testing.T) {
+		mockRepo.EXPECT().GetLastSentMessage(gomock.Any(), tenantID, gomock.Any()).Return(nil, model.ErrNotFound)
+		mockSuppressor.
+			EXPECT().
+			Suppress(gomock.Any(), tenantID, gomock.Any(), model.SuppressionSourceInbound).
+			Return(&model.Suppression{}, nil)
+		mockRepo.EXPECT().CreateInboundMessage(gomock.Any(), gomock.Any()).Return(nil)
+
+		message, err := inboundService.ReceiveMessage(ctx, tenantID, &dto.InboundRequest{From: "+905551112233", Text: "stop"})
+		assert.Nil(t, err)
+		assert.Equal(t, model.KeywordStop, message.Keyword)
+		assert.Nil(t, message.ReplyTo)
+	})
+
+	t.Run("stop keyword of a country outside the allowlist", func(t *testing.T) {
+		mockRepo.EXPECT().GetLastSentMessage(gomock.Any(), tenantID, "+31612345678").Return(nil, model.ErrNotFound)
+		mockSuppressor.
+			EXPECT().
+			Suppress(gomock.Any(), tenantID, &dto.SuppressionRequest{PhoneNumber: "+31612345678", Reason: "replied STOP"},
+				model.SuppressionSourceInbound).
+			Return(&model.Suppression{}, nil)
+		mockRepo.EXPECT().CreateInboundMessage(gomock.Any(), gomock.Any()).Return(nil)
+
+		message, err := inboundService.ReceiveMessage(ctx, tenantID, &dto.InboundRequest{From: "+31 6 12345678", Text: "STOP"})
+		assert.Nil(t, err)
+		assert.Equal(t, "+31612345678", message.PhoneNumber)
+	})
+
+	t.Run("start keyword of a number that is not suppressed", func(t *testing.T) {
+		mockRepo.EXPECT().GetLastSentMessage(gomock.Any(), tenantID, gomock.Any()).Return(nil, model.ErrNotFound)
+		mockSuppressor.EXPECT().Unsuppress(gomock.Any(), tenantID, "+905551112233").Return(model.ErrNotFound)
+		mockRepo.EXPECT().CreateInboundMessage(gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err := inboundService.ReceiveMessage(ctx, tenantID, &dto.InboundRequest{From: "+905551112233", Text: "START"})
+		assert.Nil(t, err)
+	})
+
+	t.Run("help keyword sends help reply", func(t *testing.T) {
+		mockRepo.EXPECT().GetLastSentMessage(gomock.Any(), tenantID, gomock.Any()).Return(nil, model.ErrNotFound)
+		mockReplies.
+			EXPECT().
+			CreateMessage(gomock.Any(), tenantID, &dto.MessageRequest{
+				To:       "+905551112233",
+				Content:  "Reply STOP to unsubscribe",
+				Priority: dto.PriorityHigh,
+			}).
+			Return(&model.Message{}, nil)
+		mockRepo.EXPECT().CreateInboundMessage(gomock.Any(), gomock.Any()).Return(nil)
+
+		_, err := inboundService.ReceiveMessage(ctx, tenantID, &dto.InboundRequest{From: "+905551112233", Text: "HELP"})
+		assert.Nil(t, err)
+	})
+
+	t.Run("help reply over the daily quota is skipped", func(t *testing.T) {
+		mockRepo.EXPECT().GetLastSentMessage(gomock.Any(), tenantID, gomock.Any()).Return(nil, model.ErrNotFound)
+		mockReplies.EXPECT().CreateMessage(gomock.Any(), tenantID, gomock.Any()).Return(nil, model.ErrQuotaExceeded)
+		mockRepo.EXPECT().CreateInboundMessage(gomock.Any(), gomock.Any()).Return(nil)
+
+		message, err := inboundService.ReceiveMessage(ctx, tenantID, &dto.InboundRequest{From: "+905551112233", Text: "HELP"})
+		assert.Nil(t, err)
+		assert.Equal(t, model.KeywordHelp, message.Keyword)
+	})
+
+	t.Run("keyword error is not stored", func(t *testing.T) {
+		mockRepo.EXPECT().GetLastSentMessage(gomock.Any(), tenantID, gomock.Any()).Return(nil, model.ErrNotFound)
+		mockSuppressor.EXPECT().Suppress(gomock.Any(), tenantID, gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+
+		message, err := inboundService.ReceiveMessage(ctx, tenantID, &dto.InboundRequest{From: "+905551112233", Text: "STOP"})
+		assert.Error(t, err)
+		assert.Nil(t, message)
+	})
+
+	t.Run("provider retry is not acted on twice", func(t *testing.T) {
+		received := &model.InboundMessage{ID: primitive.NewObjectID(), ProviderMessageID: "mo-2",
+			Keyword: model.KeywordHelp}
+		mockRepo.EXPECT().GetInboundMessageByProviderID(gomock.Any(), tenantID, "mo-2").Return(received, nil)
+
+		message, err := inboundService.ReceiveMessage(ctx, tenantID,
+			&dto.InboundRequest{MessageID: "mo-2", From: "+905551112233", Text: "HELP"})
+		assert.Nil(t, err)
+		assert.Equal(t, received, message)
+	})
+
+	t.Run("lookup of the provider id fails", func(t *testing.T) {
+		mockRepo.EXPECT().GetInboundMessageByProviderID(gomock.Any(), tenantID, "mo-3").Return(nil, assert.AnError)
+
+		message, err := inboundService.ReceiveMessage(ctx, tenantID,
+			&dto.InboundRequest{MessageID: "mo-3", From: "+905551112233", Text: "HELP"})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, message)
+	})
+
+	t.Run("provider retry is not stored twice", func(t *testing.T) {
+		mockRepo.EXPECT().GetInboundMessageByProviderID(gomock.Any(), tenantID, "mo-1").Return(nil, model.ErrNotFound)
+		mockRepo.EXPECT().GetLastSentMessage(gomock.Any(), tenantID, gomock.Any()).Return(nil, model.ErrNotFound)
+		mockRepo.EXPECT().CreateInboundMessage(gomock.Any(), gomock.Any()).Return(model.ErrAlreadyExists)
+
+		message, err := inboundService.ReceiveMessage(ctx, tenantID,
+			&dto.InboundRequest{MessageID: "mo-1", From: "+905551112233", Text: "Thanks"})
+		assert.Nil(t, err)
+		assert.Equal(t, "mo-1", message.ProviderMessageID)
+	})
+
+	t.Run("invalid sender", func(t *testing.T) {
+		message, err := inboundService.ReceiveMessage(ctx, tenantID, &dto.InboundRequest{From: "123", Text: "STOP"})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, message)
+	})
+}
+
+func TestInboundService_GetConversation(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	tenantID := "retail"
+	mockRepo := mocks.NewMockIInboundRepository(mockController)
+	inboundService := NewInboundService(mockRepo, nil, nil, newPhoneParser(t), &config.Message{}, slog.Default())
+
+	now := time.Now().UTC()
+	outbound := []model.Message{
+		{ID: primitive.NewObjectID(), Content: "second", Status: model.StatusUnsent, CreatedAt: now.Add(-time.Minute)},
+		{ID: primitive.NewObjectID(), Content: "first", Status: model.StatusSent, CreatedAt: now.Add(-time.Hour),
+			SentAt: now.Add(-50 * time.Minute)},
+	}
+	inbound := []model.InboundMessage{
+		{ID: primitive.NewObjectID(), Text: "reply", ReplyTo: &outbound[1].ID, ReceivedAt: now.Add(-30 * time.Minute)},
+	}
+
+	t.Run("merges messages in time order", func(t *testing.T) {
+		mockRepo.EXPECT().GetMessagesByPhoneNumber(gomock.Any(), tenantID, "+905551112233", 10).Return(outbound, nil)
+		mockRepo.EXPECT().GetInboundMessages(gomock.Any(), tenantID, "+905551112233", 10).Return(inbound, nil)
+
+		conversation, err := inboundService.GetConversation(ctx, tenantID, "+905551112233", 10)
+		assert.Nil(t, err)
+		assert.Len(t, conversation, 3)
+		assert.Equal(t, "first", conversation[0].Content)
+		assert.Equal(t, dto.DirectionInbound, conversation[1].Direction)
+		assert.Equal(t, outbound[1].ID.Hex(), conversation[1].ReplyTo)
+		assert.Equal(t, "second", conversation[2].Content)
+	})
+
+	t.Run("keeps the newest messages", func(t *testing.T) {
+		mockRepo.EXPECT().GetMessagesByPhoneNumber(gomock.Any(), tenantID, gomock.Any(), 2).Return(outbound, nil)
+		mockRepo.EXPECT().GetInboundMessages(gomock.Any(), tenantID, gomock.Any(), 2).Return(inbound, nil)
+
+		conversation, err := inboundService.GetConversation(ctx, tenantID, "+905551112233", 2)
+		assert.Nil(t, err)
+		assert.Len(t, conversation, 2)
+		assert.Equal(t, "reply", conversation[0].Content)
+	})
+}
@@ -2,32 +2,286 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"messaging-system/app/dto"
 	"messaging-system/app/model"
+	"messaging-system/app/phone"
+	"messaging-system/app/requestid"
+	"messaging-system/app/template"
+	"messaging-system/config"
+	"net/url"
+	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type IRepository interface {
-	GetMessages(ctx context.Context, status string, limit int) ([]model.Message, error)
-	MarkMessageAsSent(ctx context.Context, messageID primitive.ObjectID, webhookMessageID string) error
+	GetMessages(ctx context.Context, tenantID, status string, limit int) ([]model.Message, error)
+	GetMessagesByPriority(ctx context.Context, status string, priorities []int,
+		excludedCampaigns []primitive.ObjectID, limit int) ([]model.Message, error)
+	CreateMessage(ctx context.Context, message *model.Message) error
+	CreateMessages(ctx context.Context, messages []*model.Message) error
+	GetTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID) (*model.Template, error)
+	ExpireMessages(ctx context.Context, now time.Time) ([]model.Message, error)
+	WatchUnsentMessages(ctx context.Context, handle func(ctx context.Context, message *model.Message) error) error
+	ForEachSentMessage(ctx context.Context, tenantID string, from, to time.Time,
+		handle func(ctx context.Context, message *model.Message) error) error
+	MarkMessageAsSent(ctx context.Context, messageID primitive.ObjectID, webhookMessageID,
+		provider string) error
+	UpdateMessageStatus(ctx context.Context, messageID primitive.ObjectID, status string) error
+	RecordSendFailure(ctx context.Context, messageID primitive.ObjectID, reason string, final bool) error
+}
+
+type IQuota interface {
+	Reserve(ctx context.Context, tenantID string, now time.Time) error
+	Release(ctx context.Context, tenantID string, now time.Time) error
+	ReserveMany(ctx context.Context, tenantID string, count int, now time.Time) (int, error)
+	ReleaseMany(ctx context.Context, tenantID string, count int, now time.Time) error
 }
 
 type MessageService struct {
-	repo IRepository
+	repo    IRepository
+	phones  *phone.Parser
+	quota   IQuota
+	conf    *config.Message
+	tenants config.Tenants
 }
 
-func NewMessageService(repo IRepository) *MessageService {
-	return &MessageService{repo: repo}
+func NewMessageService(repo IRepository, phones *phone.Parser, quota IQuota, conf *config.Message,
+	tenants config.Tenants) *MessageService {
+	return &MessageService{
+		repo:    repo,
+		phones:  phones,
+		quota:   quota,
+		conf:    conf,
+		tenants: tenants,
+	}
 }
 
-func (s *MessageService) GetMessages(ctx context.Context, status string, limit int) ([]model.Message, error) {
-	messages, err := s.repo.GetMessages(ctx, status, limit)
+func (s *MessageService) GetMessages(ctx context.Context, tenantID, status string,
+	limit int) ([]model.Message, error) {
+	messages, err := s.repo.GetMessages(ctx, tenantID, status, limit)
 	if err != nil {
 		return nil, err
 	}
 	return messages, nil
 }
 
-func (s *MessageService) MarkMessageAsSent(ctx context.Context, messageID primitive.ObjectID, webhookMessageID string) error {
-	return s.repo.MarkMessageAsSent(ctx, messageID, webhookMessageID)
+func (s *MessageService) GetMessagesByPriority(ctx context.Context, status string, priorities []int,
+	excludedCampaigns []primitive.ObjectID, limit int) ([]model.Message, error) {
+	messages, err := s.repo.GetMessagesByPriority(ctx, status, priorities, excludedCampaigns, limit)
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// CreateMessage renders the template of the request if it references one, normalizes the phone
+// number to E.164, validates the request and stores it as an unsent message of the tenant. Invalid requests are
+// reported as model.ErrInvalidRequest, requests over the daily quota of the tenant as model.ErrQuotaExceeded.
+func (s *MessageService) CreateMessage(ctx context.Context, tenantID string,
+	request *dto.MessageRequest) (*model.Message, error) {
+	if request.TemplateID != "" {
+		if err := s.renderTemplate(ctx, tenantID, request); err != nil {
+			return nil, err
+		}
+	}
+
+	rawPhoneNumber := request.To
+	number, err := s.phones.Parse(rawPhoneNumber)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+	request.To = number.E164
+
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+	if err := request.ValidateLength(s.maxSegments()); err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+	if err := s.checkCallback(tenantID, request.CallbackURL); err != nil {
+		return nil, err
+	}
+
+	message := model.NewMessage(tenantID, request)
+	message.RawPhoneNumber = rawPhoneNumber
+	message.Country = number.Country
+	message.RequestID = requestid.FromContext(ctx)
+	if message.ExpiresAt == nil {
+		message.ExpiresAt = s.defaultExpiry(message.Category, message.CreatedAt)
+	}
+
+	if err := s.quota.Reserve(ctx, tenantID, message.CreatedAt); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateMessage(ctx, message); err != nil {
+		if releaseErr := s.quota.Release(ctx, tenantID, message.CreatedAt); releaseErr != nil {
+			return nil, errors.Join(err, releaseErr)
+		}
+		return nil, err
+	}
+	return message, nil
+}
+
+// CreateCampaignMessages creates a message of the campaign for every phone number, with the content the
+// campaign rendered from its template. The quota of the tenant is reserved once for all of them and they are
+// stored with a single insert. Phone numbers that are invalid or over the daily quota are returned as rejected,
+// content that is too long rejects all of them. The processor holds the messages back while the campaign is
+// paused, scheduled for later or throttled.
+func (s *MessageService) CreateCampaignMessages(ctx context.Context, tenantID string, campaignID primitive.ObjectID,
+	request *dto.MessageRequest, phoneNumbers []string) ([]dto.InvalidPhoneNumber, error) {
+	rejected := []dto.InvalidPhoneNumber{}
+	if err := request.ValidateLength(s.maxSegments()); err != nil {
+		for _, rawPhoneNumber := range phoneNumbers {
+			rejected = append(rejected, dto.InvalidPhoneNumber{PhoneNumber: rawPhoneNumber, Error: err.Error()})
+		}
+		return rejected, nil
+	}
+
+	messages := make([]*model.Message, 0, len(phoneNumbers))
+	for _, rawPhoneNumber := range phoneNumbers {
+		number, err := s.phones.Parse(rawPhoneNumber)
+		if err != nil {
+			rejected = append(rejected, dto.InvalidPhoneNumber{PhoneNumber: rawPhoneNumber, Error: err.Error()})
+			continue
+		}
+
+		recipient := *request
+		recipient.To = number.E164
+		if err := recipient.Validate(); err != nil {
+			rejected = append(rejected, dto.InvalidPhoneNumber{PhoneNumber: rawPhoneNumber, Error: err.Error()})
+			continue
+		}
+
+		message := model.NewMessage(tenantID, &recipient)
+		message.RawPhoneNumber = rawPhoneNumber
+		message.Country = number.Country
+		message.CampaignID = &campaignID
+		message.RequestID = requestid.FromContext(ctx)
+		if message.ExpiresAt == nil {
+			message.ExpiresAt = s.defaultExpiry(message.Category, message.CreatedAt)
+		}
+		messages = append(messages, message)
+	}
+	if len(messages) == 0 {
+		return rejected, nil
+	}
+
+	now := messages[0].CreatedAt
+	reserved, err := s.quota.ReserveMany(ctx, tenantID, len(messages), now)
+	if err != nil {
+		return nil, err
+	}
+	for _, message := range messages[reserved:] {
+		rejected = append(rejected,
+			dto.InvalidPhoneNumber{PhoneNumber: message.RawPhoneNumber, Error: model.ErrQuotaExceeded.Error()})
+	}
+	if reserved == 0 {
+		return rejected, nil
+	}
+
+	if err := s.repo.CreateMessages(ctx, messages[:reserved]); err != nil {
+		if releaseErr := s.quota.ReleaseMany(ctx, tenantID, reserved, now); releaseErr != nil {
+			return nil, errors.Join(err, releaseErr)
+		}
+		return nil, err
+	}
+	return rejected, nil
+}
+
+func (s *MessageService) ExpireMessages(ctx context.Context, now time.Time) ([]model.Message, error) {
+	return s.repo.ExpireMessages(ctx, now)
+}
+
+func (s *MessageService) WatchUnsentMessages(ctx context.Context,
+	handle func(ctx context.Context, message *model.Message) error) error {
+	return s.repo.WatchUnsentMessages(ctx, handle)
+}
+
+func (s *MessageService) ForEachSentMessage(ctx context.Context, tenantID string, from, to time.Time,
+	handle func(ctx context.Context, message *model.Message) error) error {
+	return s.repo.ForEachSentMessage(ctx, tenantID, from, to, handle)
+}
+
+func (s *MessageService) MarkMessageAsSent(ctx context.Context, messageID primitive.ObjectID, webhookMessageID,
+	provider string) error {
+	return s.repo.MarkMessageAsSent(ctx, messageID, webhookMessageID, provider)
+}
+
+func (s *MessageService) UpdateMessageStatus(ctx context.Context, messageID primitive.ObjectID, status string) error {
+	return s.repo.UpdateMessageStatus(ctx, messageID, status)
+}
+
+func (s *MessageService) RecordSendFailure(ctx context.Context, messageID primitive.ObjectID, reason string,
+	final bool) error {
+	return s.repo.RecordSendFailure(ctx, messageID, reason, final)
+}
+
+func (s *MessageService) renderTemplate(ctx context.Context, tenantID string, request *dto.MessageRequest) error {
+	if request.Content != "" {
+		return fmt.Errorf("%w: content and templateId cannot be used together", model.ErrInvalidRequest)
+	}
+
+	templateID, err := primitive.ObjectIDFromHex(request.TemplateID)
+	if err != nil {
+		return fmt.Errorf("%w: invalid template id", model.ErrInvalidRequest)
+	}
+
+	messageTemplate, err := s.repo.GetTemplate(ctx, tenantID, templateID)
+	if errors.Is(err, model.ErrNotFound) {
+		return fmt.Errorf("%w: template not found", model.ErrInvalidRequest)
+	}
+	if err != nil {
+		return err
+	}
+
+	content, err := template.Render(messageTemplate.Body(request.Locale), request.Params)
+	if err != nil {
+		return fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+
+	request.Content = content
+	return nil
+}
+
+// checkCallback rejects callback URLs outside the callback hosts of the tenant, status notifications are
+// posted from inside the network and must not reach arbitrary hosts.
+func (s *MessageService) checkCallback(tenantID, callbackURL string) error {
+	if callbackURL == "" {
+		return nil
+	}
+
+	callback, err := url.Parse(callbackURL)
+	if err != nil {
+		return fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+	if !s.tenants.Get(tenantID).AllowsCallback(callback.Hostname()) {
+		return fmt.Errorf("%w: callbackUrl host %s is not allowed for the tenant", model.ErrInvalidRequest,
+			callback.Hostname())
+	}
+	return nil
+}
+
+func (s *MessageService) maxSegments() int {
+	if s.conf == nil {
+		return dto.DefaultMaxSegments
+	}
+	return s.conf.MaxSegments
+}
+
+func (s *MessageService) defaultExpiry(category string, createdAt time.Time) *time.Time {
+	if s.conf == nil || category == "" {
+		return nil
+	}
+
+	ttl, ok := s.conf.Expiry[category]
+	if !ok || ttl <= 0 {
+		return nil
+	}
+
+	expiresAt := createdAt.Add(ttl)
+	return &expiresAt
 }
@@ -2,9 +2,16 @@ package service
 
 import (
 	"context"
+	"messaging-system/app/dto"
 	"messaging-system/app/mocks"
 	"messaging-system/app/model"
+	"messaging-system/app/phone"
+	"messaging-system/app/requestid"
+	"messaging-system/app/sms"
+	"messaging-system/config"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -17,14 +24,14 @@ func TestMessageService_GetMessages(t *testing.T) {
 
 	ctx := context.Background()
 	mockRepo := mocks.NewMockIRepository(mockController)
-	messageService := NewMessageService(mockRepo)
+	messageService := NewMessageService(mockRepo, newPhoneParser(t), NewQuotaService(nil, nil), &config.Message{}, nil)
 	t.Run("retrieve messages successfully", func(t *testing.T) {
 		mockRepo.
 			EXPECT().
-			GetMessages(gomock.Any(), "unsent", 10).
+			GetMessages(gomock.Any(), model.DefaultTenantID, "unsent", 10).
 			Return([]model.Message{}, nil)
 
-		messages, err := messageService.GetMessages(ctx, "unsent", 10)
+		messages, err := messageService.GetMessages(ctx, model.DefaultTenantID, "unsent", 10)
 		assert.Nil(t, err)
 		assert.NotNil(t, messages)
 	})
@@ -32,10 +39,10 @@ func TestMessageService_GetMessages(t *testing.T) {
 	t.Run("error retrieving messages", func(t *testing.T) {
 		mockRepo.
 			EXPECT().
-			GetMessages(gomock.Any(), "unsent", 10).
+			GetMessages(gomock.Any(), model.DefaultTenantID, "unsent", 10).
 			Return(nil, assert.AnError)
 
-		messages, err := messageService.GetMessages(ctx, "unsent", 10)
+		messages, err := messageService.GetMessages(ctx, model.DefaultTenantID, "unsent", 10)
 		assert.NotNil(t, err)
 		assert.Nil(t, messages)
 	})
@@ -47,24 +54,449 @@ func TestMessageService_MarkMessageAsSent(t *testing.T) {
 
 	ctx := context.Background()
 	mockRepo := mocks.NewMockIRepository(mockController)
-	messageService := NewMessageService(mockRepo)
+	messageService := NewMessageService(mockRepo, newPhoneParser(t), NewQuotaService(nil, nil), &config.Message{}, nil)
 	t.Run("mark message as sent successfully", func(t *testing.T) {
 		mockRepo.
 			EXPECT().
-			MarkMessageAsSent(gomock.Any(), gomock.Any(), "webhook123").
+			MarkMessageAsSent(gomock.Any(), gomock.Any(), "webhook123", "sms.example.com").
 			Return(nil)
 
-		err := messageService.MarkMessageAsSent(ctx, primitive.NewObjectID(), "webhook123")
+		err := messageService.MarkMessageAsSent(ctx, primitive.NewObjectID(), "webhook123", "sms.example.com")
 		assert.Nil(t, err)
 	})
 
 	t.Run("error marking message as sent", func(t *testing.T) {
 		mockRepo.
 			EXPECT().
-			MarkMessageAsSent(gomock.Any(), gomock.Any(), "webhook123").
+			MarkMessageAsSent(gomock.Any(), gomock.Any(), "webhook123", "sms.example.com").
 			Return(assert.AnError)
 
-		err := messageService.MarkMessageAsSent(ctx, primitive.NewObjectID(), "webhook123")
+		err := messageService.MarkMessageAsSent(ctx, primitive.NewObjectID(), "webhook123", "sms.example.com")
 		assert.NotNil(t, err)
 	})
 }
+
+func TestMessageService_GetMessagesByPriority(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIRepository(mockController)
+	messageService := NewMessageService(mockRepo, newPhoneParser(t), NewQuotaService(nil, nil), &config.Message{}, nil)
+	priorities := []int{dto.PriorityHigh}
+	held := []primitive.ObjectID{primitive.NewObjectID()}
+
+	t.Run("retrieve messages successfully", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			GetMessagesByPriority(gomock.Any(), "unsent", priorities, held, 10).
+			Return([]model.Message{}, nil)
+
+		messages, err := messageService.GetMessagesByPriority(ctx, "unsent", priorities, held, 10)
+		assert.Nil(t, err)
+		assert.NotNil(t, messages)
+	})
+
+	t.Run("error retrieving messages", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			GetMessagesByPriority(gomock.Any(), "unsent", priorities, nil, 10).
+			Return(nil, assert.AnError)
+
+		messages, err := messageService.GetMessagesByPriority(ctx, "unsent", priorities, nil, 10)
+		assert.NotNil(t, err)
+		assert.Nil(t, messages)
+	})
+}
+
+func TestMessageService_CreateMessage(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIRepository(mockController)
+	messageService := NewMessageService(mockRepo, newPhoneParser(t), NewQuotaService(nil, nil), &config.Message{},
+		config.Tenants{model.DefaultTenantID: {CallbackHosts: []string{"hooks.example.com", "*.example.org"}}})
+	request := &dto.MessageRequest{To: "+905551112233", Content: "Hello"}
+
+	t.Run("create message successfully", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			CreateMessage(gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		message, err := messageService.CreateMessage(ctx, model.DefaultTenantID, request)
+		assert.Nil(t, err)
+		assert.Equal(t, request.To, message.PhoneNumber)
+		assert.Equal(t, request.Content, message.Content)
+		assert.Equal(t, model.StatusUnsent, message.Status)
+		assert.Equal(t, model.DefaultTenantID, message.TenantID)
+		assert.Equal(t, sms.EncodingGSM7, message.Encoding)
+		assert.Equal(t, 1, message.Segments)
+		assert.False(t, message.ID.IsZero())
+		assert.Empty(t, message.RequestID)
+	})
+
+	t.Run("keeps the request id", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			CreateMessage(gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		message, err := messageService.CreateMessage(requestid.NewContext(ctx, "request-id"), model.DefaultTenantID,
+			&dto.MessageRequest{To: "+905551112233", Content: "Hello"})
+		assert.Nil(t, err)
+		assert.Equal(t, "request-id", message.RequestID)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		message, err := messageService.CreateMessage(ctx, model.DefaultTenantID,
+			&dto.MessageRequest{To: "123", Content: "Hello"})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, message)
+	})
+
+	t.Run("invalid callback url", func(t *testing.T) {
+		message, err := messageService.CreateMessage(ctx, model.DefaultTenantID,
+			&dto.MessageRequest{To: "+905551112233", Content: "Hello", CallbackURL: "/callback"})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, message)
+	})
+
+	t.Run("callback to an allowed host", func(t *testing.T) {
+		mockRepo.EXPECT().CreateMessage(gomock.Any(), gomock.Any()).Return(nil).Times(2)
+
+		for _, callbackURL := range []string{"https://hooks.example.com/sms", "https://api.example.org:8443/sms"} {
+			message, err := messageService.CreateMessage(ctx, model.DefaultTenantID,
+				&dto.MessageRequest{To: "+905551112233", Content: "Hello", CallbackURL: callbackURL})
+			assert.Nil(t, err)
+			assert.Equal(t, callbackURL, message.CallbackURL)
+		}
+	})
+
+	t.Run("callback to a host outside the allowlist", func(t *testing.T) {
+		for _, callbackURL := range []string{"http://169.254.169.254/latest", "https://example.org/sms",
+			"https://hooks.example.com.evil.io/sms"} {
+			message, err := messageService.CreateMessage(ctx, model.DefaultTenantID,
+				&dto.MessageRequest{To: "+905551112233", Content: "Hello", CallbackURL: callbackURL})
+			assert.ErrorIs(t, err, model.ErrInvalidRequest)
+			assert.Nil(t, message)
+		}
+
+		message, err := messageService.CreateMessage(ctx, "retail",
+			&dto.MessageRequest{To: "+905551112233", Content: "Hello", CallbackURL: "https://hooks.example.com/sms"})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, message)
+	})
+
+	t.Run("error creating message", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			CreateMessage(gomock.Any(), gomock.Any()).
+			Return(assert.AnError)
+
+		message, err := messageService.CreateMessage(ctx, model.DefaultTenantID, request)
+		assert.NotNil(t, err)
+		assert.Nil(t, message)
+	})
+}
+
+func TestMessageService_CreateCampaignMessages(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIRepository(mockController)
+	mockQuota := mocks.NewMockIQuota(mockController)
+	messageService := NewMessageService(mockRepo, newPhoneParser(t), mockQuota, &config.Message{}, nil)
+	campaignID := primitive.NewObjectID()
+	request := &dto.MessageRequest{Content: "Hello", Priority: dto.PriorityLow}
+	phoneNumbers := []string{"+905551112233", "123", "+905551112244", "+905551112255"}
+
+	t.Run("reserves the quota once and inserts the messages at once", func(t *testing.T) {
+		gomock.InOrder(
+			mockQuota.EXPECT().ReserveMany(gomock.Any(), "retail", 3, gomock.Any()).Return(2, nil),
+			mockRepo.
+				EXPECT().
+				CreateMessages(gomock.Any(), gomock.Any()).
+				Do(func(_ context.Context, messages []*model.Message) {
+					assert.Len(t, messages, 2)
+					assert.Equal(t, "+905551112233", messages[0].PhoneNumber)
+					assert.Equal(t, &campaignID, messages[0].CampaignID)
+					assert.Equal(t, dto.PriorityLow, messages[0].Priority)
+				}).
+				Return(nil),
+		)
+
+		rejected, err := messageService.CreateCampaignMessages(ctx, "retail", campaignID, request, phoneNumbers)
+		assert.Nil(t, err)
+		assert.Len(t, rejected, 2)
+		assert.Equal(t, "123", rejected[0].PhoneNumber)
+		assert.Equal(t, dto.InvalidPhoneNumber{PhoneNumber: "+905551112255", Error: model.ErrQuotaExceeded.Error()},
+			rejected[1])
+	})
+
+	t.Run("quota used up", func(t *testing.T) {
+		mockQuota.EXPECT().ReserveMany(gomock.Any(), "retail", 3, gomock.Any()).Return(0, nil)
+
+		rejected, err := messageService.CreateCampaignMessages(ctx, "retail", campaignID, request, phoneNumbers)
+		assert.Nil(t, err)
+		assert.Len(t, rejected, 4)
+	})
+
+	t.Run("content too long rejects every recipient", func(t *testing.T) {
+		long := &dto.MessageRequest{Content: strings.Repeat("a", 2000), Priority: dto.PriorityLow}
+
+		rejected, err := messageService.CreateCampaignMessages(ctx, "retail", campaignID, long, phoneNumbers)
+		assert.Nil(t, err)
+		assert.Len(t, rejected, 4)
+	})
+
+	t.Run("releases the reservation when storing fails", func(t *testing.T) {
+		gomock.InOrder(
+			mockQuota.EXPECT().ReserveMany(gomock.Any(), "retail", 3, gomock.Any()).Return(3, nil),
+			mockRepo.EXPECT().CreateMessages(gomock.Any(), gomock.Any()).Return(assert.AnError),
+			mockQuota.EXPECT().ReleaseMany(gomock.Any(), "retail", 3, gomock.Any()).Return(nil),
+		)
+
+		rejected, err := messageService.CreateCampaignMessages(ctx, "retail", campaignID, request, phoneNumbers)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, rejected)
+	})
+}
+
+func TestMessageService_CreateMessage_Quota(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIRepository(mockController)
+	mockQuota := mocks.NewMockIQuota(mockController)
+	messageService := NewMessageService(mockRepo, newPhoneParser(t), mockQuota, &config.Message{}, nil)
+	request := &dto.MessageRequest{To: "+905551112233", Content: "Hello"}
+
+	t.Run("daily quota exceeded", func(t *testing.T) {
+		mockQuota.EXPECT().Reserve(gomock.Any(), "retail", gomock.Any()).Return(model.ErrQuotaExceeded)
+
+		message, err := messageService.CreateMessage(ctx, "retail", request)
+		assert.ErrorIs(t, err, model.ErrQuotaExceeded)
+		assert.Nil(t, message)
+	})
+
+	t.Run("invalid requests do not use the quota", func(t *testing.T) {
+		message, err := messageService.CreateMessage(ctx, "retail", &dto.MessageRequest{To: "123", Content: "Hello"})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, message)
+	})
+
+	t.Run("releases the reservation when storing fails", func(t *testing.T) {
+		gomock.InOrder(
+			mockQuota.EXPECT().Reserve(gomock.Any(), "retail", gomock.Any()).Return(nil),
+			mockRepo.EXPECT().CreateMessage(gomock.Any(), gomock.Any()).Return(assert.AnError),
+			mockQuota.EXPECT().Release(gomock.Any(), "retail", gomock.Any()).Return(nil),
+		)
+
+		message, err := messageService.CreateMessage(ctx, "retail", request)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, message)
+	})
+}
+
+func TestMessageService_CreateMessage_PhoneNumber(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIRepository(mockController)
+	messageService := NewMessageService(mockRepo, newPhoneParser(t), NewQuotaService(nil, nil), &config.Message{}, nil)
+
+	t.Run("stores raw and normalized number", func(t *testing.T) {
+		mockRepo.EXPECT().CreateMessage(gomock.Any(), gomock.Any()).Return(nil)
+
+		message, err := messageService.CreateMessage(ctx, model.DefaultTenantID,
+			&dto.MessageRequest{To: "0555 111 22 33", Content: "Hello"})
+		assert.Nil(t, err)
+		assert.Equal(t, "+905551112233", message.PhoneNumber)
+		assert.Equal(t, "0555 111 22 33", message.RawPhoneNumber)
+		assert.Equal(t, "TR", message.Country)
+	})
+
+	t.Run("country outside the allowlist", func(t *testing.T) {
+		message, err := messageService.CreateMessage(ctx, model.DefaultTenantID,
+			&dto.MessageRequest{To: "+31612345678", Content: "Hello"})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, message)
+	})
+}
+
+func TestMessageService_CreateMessage_MaxSegments(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIRepository(mockController)
+	messageService := NewMessageService(mockRepo, newPhoneParser(t), NewQuotaService(nil, nil),
+		&config.Message{MaxSegments: 2}, nil)
+
+	t.Run("turkish content within the limit", func(t *testing.T) {
+		mockRepo.EXPECT().CreateMessage(gomock.Any(), gomock.Any()).Return(nil)
+
+		message, err := messageService.CreateMessage(ctx, model.DefaultTenantID, &dto.MessageRequest{
+			To: "+905551112233", Content: strings.Repeat("ş", 100),
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, sms.EncodingGSM7Turkish, message.Encoding)
+		assert.Equal(t, 2, message.Segments)
+	})
+
+	t.Run("content exceeding the configured segments", func(t *testing.T) {
+		message, err := messageService.CreateMessage(ctx, model.DefaultTenantID, &dto.MessageRequest{
+			To: "+905551112233", Content: strings.Repeat("я", 3*sms.UCS2MultiCapacity),
+		})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, message)
+	})
+}
+
+func TestMessageService_CreateMessage_DefaultExpiry(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIRepository(mockController)
+	messageService := NewMessageService(mockRepo, newPhoneParser(t), NewQuotaService(nil, nil), &config.Message{
+		Expiry: map[string]time.Duration{"otp": 5 * time.Minute},
+	}, nil)
+	mockRepo.EXPECT().CreateMessage(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	t.Run("applies the category default", func(t *testing.T) {
+		message, err := messageService.CreateMessage(ctx, model.DefaultTenantID, &dto.MessageRequest{
+			To: "+905551112233", Content: "123456", Category: "otp",
+		})
+		assert.Nil(t, err)
+		assert.NotNil(t, message.ExpiresAt)
+		assert.Equal(t, message.CreatedAt.Add(5*time.Minute), *message.ExpiresAt)
+	})
+
+	t.Run("keeps an explicit expiry", func(t *testing.T) {
+		expiresAt := time.Now().Add(time.Hour)
+		message, err := messageService.CreateMessage(ctx, model.DefaultTenantID, &dto.MessageRequest{
+			To: "+905551112233", Content: "123456", Category: "otp", ExpiresAt: &expiresAt,
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, expiresAt, *message.ExpiresAt)
+	})
+
+	t.Run("unknown category does not expire", func(t *testing.T) {
+		message, err := messageService.CreateMessage(ctx, model.DefaultTenantID, &dto.MessageRequest{
+			To: "+905551112233", Content: "Hello", Category: "marketing",
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, message.ExpiresAt)
+	})
+}
+
+func TestMessageService_ExpireMessages(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIRepository(mockController)
+	messageService := NewMessageService(mockRepo, newPhoneParser(t), NewQuotaService(nil, nil), &config.Message{}, nil)
+	now := time.Now()
+
+	mockRepo.
+		EXPECT().
+		ExpireMessages(gomock.Any(), now).
+		Return([]model.Message{{ID: primitive.NewObjectID()}, {ID: primitive.NewObjectID()}}, nil)
+
+	expired, err := messageService.ExpireMessages(ctx, now)
+	assert.Nil(t, err)
+	assert.Len(t, expired, 2)
+}
+
+func TestMessageService_CreateMessage_Template(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIRepository(mockController)
+	messageService := NewMessageService(mockRepo, newPhoneParser(t), NewQuotaService(nil, nil), &config.Message{}, nil)
+	templateID := primitive.NewObjectID()
+	template := &model.Template{
+		ID:            templateID,
+		DefaultLocale: "en",
+		Locales: map[string]string{
+			"en": "Your code is {{code}}",
+			"tr": "Kodunuz {{code}}",
+		},
+	}
+
+	t.Run("renders the requested locale", func(t *testing.T) {
+		mockRepo.EXPECT().GetTemplate(gomock.Any(), model.DefaultTenantID, templateID).Return(template, nil)
+		mockRepo.EXPECT().CreateMessage(gomock.Any(), gomock.Any()).Return(nil)
+
+		message, err := messageService.CreateMessage(ctx, model.DefaultTenantID, &dto.MessageRequest{
+			To:         "+905551112233",
+			TemplateID: templateID.Hex(),
+			Params:     map[string]string{"code": "123456"},
+			Locale:     "tr-TR",
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, "Kodunuz 123456", message.Content)
+		assert.Equal(t, templateID.Hex(), message.TemplateID)
+	})
+
+	t.Run("missing params", func(t *testing.T) {
+		mockRepo.EXPECT().GetTemplate(gomock.Any(), model.DefaultTenantID, templateID).Return(template, nil)
+
+		message, err := messageService.CreateMessage(ctx, model.DefaultTenantID, &dto.MessageRequest{
+			To:         "+905551112233",
+			TemplateID: templateID.Hex(),
+		})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, message)
+	})
+
+	t.Run("rendered content exceeds maximum length", func(t *testing.T) {
+		mockRepo.EXPECT().GetTemplate(gomock.Any(), model.DefaultTenantID, templateID).Return(template, nil)
+
+		message, err := messageService.CreateMessage(ctx, model.DefaultTenantID, &dto.MessageRequest{
+			To:         "+905551112233",
+			TemplateID: templateID.Hex(),
+			Params:     map[string]string{"code": strings.Repeat("1", sms.GSM7MultiCapacity*dto.DefaultMaxSegments)},
+		})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, message)
+	})
+
+	t.Run("template not found", func(t *testing.T) {
+		mockRepo.EXPECT().GetTemplate(gomock.Any(), model.DefaultTenantID, templateID).Return(nil, model.ErrNotFound)
+
+		message, err := messageService.CreateMessage(ctx, model.DefaultTenantID, &dto.MessageRequest{
+			To:         "+905551112233",
+			TemplateID: templateID.Hex(),
+		})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, message)
+	})
+
+	t.Run("content and template together", func(t *testing.T) {
+		message, err := messageService.CreateMessage(ctx, model.DefaultTenantID, &dto.MessageRequest{
+			To:         "+905551112233",
+			Content:    "Hello",
+			TemplateID: templateID.Hex(),
+		})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, message)
+	})
+}
+
+func newPhoneParser(t *testing.T) *phone.Parser {
+	t.Helper()
+	parser, err := phone.NewParser("TR", []string{"TR", "DE"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parser
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"context"
+	"messaging-system/app/cache"
+	"messaging-system/app/model"
+	"messaging-system/config"
+	"time"
+)
+
+const (
+	quotaKeyPrefix = "quota:"
+	// quotaCounterTTL keeps the counter of a day until the day is over in every time zone.
+	quotaCounterTTL = 48 * time.Hour
+)
+
+type IQuotaCounter interface {
+	Increment(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	Decrement(ctx context.Context, key string) error
+	IncrementBy(ctx context.Context, key string, n int64, ttl time.Duration) (int64, error)
+	DecrementBy(ctx context.Context, key string, n int64) error
+}
+
+// QuotaService enforces the daily message quota of each tenant with a Redis counter per UTC day.
+type QuotaService struct {
+	counter IQuotaCounter
+	tenants config.Tenants
+}
+
+func NewQuotaService(counter IQuotaCounter, tenants config.Tenants) *QuotaService {
+	return &QuotaService{
+		counter: counter,
+		tenants: tenants,
+	}
+}
+
+// Reserve counts one message of the tenant created at now, model.ErrQuotaExceeded is returned
+// once the quota of the day is used up.
+func (s *QuotaService) Reserve(ctx context.Context, tenantID string, now time.Time) error {
+	quota := s.tenants.Get(tenantID).DailyQuota
+	if quota <= 0 {
+		return nil
+	}
+
+	key := quotaKey(tenantID, now)
+	count, err := s.counter.Increment(ctx, key, quotaCounterTTL)
+	if err != nil {
+		return err
+	}
+	if count > int64(quota) {
+		// rejected messages must not use up the quota
+		if err := s.counter.Decrement(ctx, key); err != nil {
+			return err
+		}
+		return model.ErrQuotaExceeded
+	}
+
+	return nil
+}
+
+// Release gives back a reservation of a message that could not be stored.
+func (s *QuotaService) Release(ctx context.Context, tenantID string, now time.Time) error {
+	if s.tenants.Get(tenantID).DailyQuota <= 0 {
+		return nil
+	}
+	return s.counter.Decrement(ctx, quotaKey(tenantID, now))
+}
+
+// ReserveMany counts up to count messages of the tenant created at now in one step and returns how many
+// fit into the quota of the day, the remainder is given back.
+func (s *QuotaService) ReserveMany(ctx context.Context, tenantID string, count int, now time.Time) (int, error) {
+	quota := s.tenants.Get(tenantID).DailyQuota
+	if quota <= 0 || count <= 0 {
+		return count, nil
+	}
+
+	key := quotaKey(tenantID, now)
+	total, err := s.counter.IncrementBy(ctx, key, int64(count), quotaCounterTTL)
+	if err != nil {
+		return 0, err
+	}
+	over := min(int(total)-quota, count)
+	if over <= 0 {
+		return count, nil
+	}
+
+	// rejected messages must not use up the quota
+	if err := s.counter.DecrementBy(ctx, key, int64(over)); err != nil {
+		return 0, err
+	}
+	return count - over, nil
+}
+
+// ReleaseMany gives back the reservations of count messages that could not be stored.
+func (s *QuotaService) ReleaseMany(ctx context.Context, tenantID string, count int, now time.Time) error {
+	if s.tenants.Get(tenantID).DailyQuota <= 0 || count <= 0 {
+		return nil
+	}
+	return s.counter.DecrementBy(ctx, quotaKey(tenantID, now), int64(count))
+}
+
+func quotaKey(tenantID string, now time.Time) string {
+	return cache.TenantKey(tenantID, quotaKeyPrefix+now.UTC().Format(time.DateOnly))
+}
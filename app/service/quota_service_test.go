@@ -0,0 +1,117 @@
+package service
+
+import (
+	"context"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"messaging-system/config"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuotaService_Reserve(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 23, 30, 0, 0, time.UTC)
+	key := "tenant:retail:quota:2024-05-01"
+	mockCounter := mocks.NewMockIQuotaCounter(mockController)
+	quotaService := NewQuotaService(mockCounter, config.Tenants{"retail": {DailyQuota: 2}})
+
+	t.Run("tenants without a quota are unlimited", func(t *testing.T) {
+		assert.Nil(t, quotaService.Reserve(ctx, model.DefaultTenantID, now))
+		assert.Nil(t, quotaService.Release(ctx, model.DefaultTenantID, now))
+	})
+
+	t.Run("within the quota", func(t *testing.T) {
+		mockCounter.EXPECT().Increment(gomock.Any(), key, quotaCounterTTL).Return(int64(2), nil)
+
+		assert.Nil(t, quotaService.Reserve(ctx, "retail", now))
+	})
+
+	t.Run("quota exceeded gives back the reservation", func(t *testing.T) {
+		gomock.InOrder(
+			mockCounter.EXPECT().Increment(gomock.Any(), key, quotaCounterTTL).Return(int64(3), nil),
+			mockCounter.EXPECT().Decrement(gomock.Any(), key).Return(nil),
+		)
+
+		assert.ErrorIs(t, quotaService.Reserve(ctx, "retail", now), model.ErrQuotaExceeded)
+	})
+
+	t.Run("counter error", func(t *testing.T) {
+		mockCounter.EXPECT().Increment(gomock.Any(), key, quotaCounterTTL).Return(int64(0), assert.AnError)
+
+		assert.ErrorIs(t, quotaService.Reserve(ctx, "retail", now), assert.AnError)
+	})
+
+	t.Run("counts per UTC day", func(t *testing.T) {
+		local := time.Date(2024, 5, 2, 1, 30, 0, 0, time.FixedZone("TRT", 3*60*60))
+		mockCounter.EXPECT().Increment(gomock.Any(), key, quotaCounterTTL).Return(int64(1), nil)
+
+		assert.Nil(t, quotaService.Reserve(ctx, "retail", local))
+	})
+
+	t.Run("release", func(t *testing.T) {
+		mockCounter.EXPECT().Decrement(gomock.Any(), key).Return(nil)
+
+		assert.Nil(t, quotaService.Release(ctx, "retail", now))
+	})
+}
+
+func TestQuotaService_ReserveMany(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	now := time.Date(2024, 5, 1, 23, 30, 0, 0, time.UTC)
+	key := "tenant:retail:quota:2024-05-01"
+	mockCounter := mocks.NewMockIQuotaCounter(mockController)
+	quotaService := NewQuotaService(mockCounter, config.Tenants{"retail": {DailyQuota: 10}})
+
+	t.Run("tenants without a quota are unlimited", func(t *testing.T) {
+		reserved, err := quotaService.ReserveMany(ctx, model.DefaultTenantID, 500, now)
+		assert.Nil(t, err)
+		assert.Equal(t, 500, reserved)
+		assert.Nil(t, quotaService.ReleaseMany(ctx, model.DefaultTenantID, 500, now))
+	})
+
+	t.Run("within the quota", func(t *testing.T) {
+		mockCounter.EXPECT().IncrementBy(gomock.Any(), key, int64(4), quotaCounterTTL).Return(int64(10), nil)
+
+		reserved, err := quotaService.ReserveMany(ctx, "retail", 4, now)
+		assert.Nil(t, err)
+		assert.Equal(t, 4, reserved)
+	})
+
+	t.Run("gives back the reservations over the quota", func(t *testing.T) {
+		gomock.InOrder(
+			mockCounter.EXPECT().IncrementBy(gomock.Any(), key, int64(4), quotaCounterTTL).Return(int64(12), nil),
+			mockCounter.EXPECT().DecrementBy(gomock.Any(), key, int64(2)).Return(nil),
+		)
+
+		reserved, err := quotaService.ReserveMany(ctx, "retail", 4, now)
+		assert.Nil(t, err)
+		assert.Equal(t, 2, reserved)
+	})
+
+	t.Run("quota used up before", func(t *testing.T) {
+		gomock.InOrder(
+			mockCounter.EXPECT().IncrementBy(gomock.Any(), key, int64(4), quotaCounterTTL).Return(int64(15), nil),
+			mockCounter.EXPECT().DecrementBy(gomock.Any(), key, int64(4)).Return(nil),
+		)
+
+		reserved, err := quotaService.ReserveMany(ctx, "retail", 4, now)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, reserved)
+	})
+
+	t.Run("release", func(t *testing.T) {
+		mockCounter.EXPECT().DecrementBy(gomock.Any(), key, int64(3)).Return(nil)
+
+		assert.Nil(t, quotaService.ReleaseMany(ctx, "retail", 3, now))
+	})
+}
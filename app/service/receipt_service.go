@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"time"
+)
+
+type IReceiptRepository interface {
+	ApplyReceipt(ctx context.Context, tenantID string, receipt *dto.ReceiptRequest,
+		now time.Time) (*model.Message, error)
+}
+
+type IStatusNotifier interface {
+	Notify(ctx context.Context, message *model.Message, eventType, reason string)
+}
+
+type ReceiptService struct {
+	repo     IReceiptRepository
+	notifier IStatusNotifier
+	logger   *slog.Logger
+}
+
+func NewReceiptService(repo IReceiptRepository, notifier IStatusNotifier, logger *slog.Logger) *ReceiptService {
+	return &ReceiptService{
+		repo:     repo,
+		notifier: notifier,
+		logger:   logger,
+	}
+}
+
+// ReceiveReceipt applies a delivery receipt of the SMS provider to the message it reports on and notifies
+// the callback of the message. Repeated receipts are reported as model.ErrAlreadyExists and notify nothing.
+func (s *ReceiptService) ReceiveReceipt(ctx context.Context, tenantID string,
+	request *dto.ReceiptRequest) (*model.Message, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+
+	message, err := s.repo.ApplyReceipt(ctx, tenantID, request, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Status == dto.ReceiptFailed {
+		s.logger.WarnContext(ctx, "message not delivered", "messageId", message.ID, "error", request.Error)
+		s.notifier.Notify(ctx, message, model.EventMessageFailed, request.Error)
+		return message, nil
+	}
+
+	s.logger.InfoContext(ctx, "message delivered", "messageId", message.ID)
+	s.notifier.Notify(ctx, message, model.EventMessageDelivered, "")
+	return message, nil
+}
@@ -0,0 +1,58 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"messaging-system/app/dto"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReceiptService_ReceiveReceipt(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIReceiptRepository(mockController)
+	mockNotifier := mocks.NewMockIStatusNotifier(mockController)
+	receiptService := NewReceiptService(mockRepo, mockNotifier, slog.Default())
+	message := &model.Message{ID: primitive.NewObjectID(), TenantID: "retail", Status: model.StatusSent}
+
+	t.Run("invalid status", func(t *testing.T) {
+		_, err := receiptService.ReceiveReceipt(ctx, "retail", &dto.ReceiptRequest{MessageID: "webhook-1", Status: "read"})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+	})
+
+	t.Run("delivered", func(t *testing.T) {
+		request := &dto.ReceiptRequest{MessageID: "webhook-1", Status: dto.ReceiptDelivered}
+		mockRepo.EXPECT().ApplyReceipt(gomock.Any(), "retail", request, gomock.Any()).Return(message, nil)
+		mockNotifier.EXPECT().Notify(gomock.Any(), message, model.EventMessageDelivered, "")
+
+		received, err := receiptService.ReceiveReceipt(ctx, "retail", request)
+		assert.NoError(t, err)
+		assert.Equal(t, message, received)
+	})
+
+	t.Run("not delivered", func(t *testing.T) {
+		request := &dto.ReceiptRequest{MessageID: "webhook-1", Status: dto.ReceiptFailed, Error: "absent subscriber"}
+		mockRepo.EXPECT().ApplyReceipt(gomock.Any(), "retail", request, gomock.Any()).Return(message, nil)
+		mockNotifier.EXPECT().Notify(gomock.Any(), message, model.EventMessageFailed, "absent subscriber")
+
+		_, err := receiptService.ReceiveReceipt(ctx, "retail", request)
+		assert.NoError(t, err)
+	})
+
+	t.Run("repeated receipts notify nothing", func(t *testing.T) {
+		mockRepo.EXPECT().ApplyReceipt(gomock.Any(), "retail", gomock.Any(), gomock.Any()).
+			Return(nil, model.ErrAlreadyExists)
+
+		_, err := receiptService.ReceiveReceipt(ctx, "retail",
+			&dto.ReceiptRequest{MessageID: "webhook-1", Status: dto.ReceiptDelivered})
+		assert.ErrorIs(t, err, model.ErrAlreadyExists)
+	})
+}
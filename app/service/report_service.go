@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"messaging-system/app/cache"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"messaging-system/config"
+	"strings"
+	"time"
+)
+
+const (
+	ReportCacheKeyPrefix = "report:"
+
+	// DefaultReportRange is the range covered when a report has no from time.
+	DefaultReportRange = 7 * 24 * time.Hour
+)
+
+type IReportRepository interface {
+	GetMessageReport(ctx context.Context, tenantID string, request *dto.ReportRequest) ([]model.ReportRow, error)
+}
+
+type IReportCache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error
+}
+
+// ReportService counts the messages of a tenant with MongoDB aggregations. Computed reports are kept in
+// Redis for the configured cache TTL, so they can lag behind the messages by that long.
+type ReportService struct {
+	repo   IReportRepository
+	cache  IReportCache
+	conf   *config.Report
+	logger *slog.Logger
+}
+
+func NewReportService(repo IReportRepository, cache IReportCache, conf *config.Report,
+	logger *slog.Logger) *ReportService {
+	if conf == nil {
+		conf = &config.Report{}
+	}
+
+	return &ReportService{
+		repo:   repo,
+		cache:  cache,
+		conf:   conf,
+		logger: logger,
+	}
+}
+
+// Summary counts the messages created within the range of the request, by status unless the request
+// groups them otherwise. Invalid requests are reported as model.ErrInvalidRequest.
+func (s *ReportService) Summary(ctx context.Context, tenantID string,
+	request *dto.ReportRequest) (*model.Report, error) {
+	request.Interval = ""
+	return s.report(ctx, tenantID, request, time.Now().UTC())
+}
+
+// Timeseries counts the messages created within the range of the request per day unless the request
+// asks for hours, by status unless the request groups them otherwise.
+func (s *ReportService) Timeseries(ctx context.Context, tenantID string,
+	request *dto.ReportRequest) (*model.Report, error) {
+	if request.Interval == "" {
+		request.Interval = dto.ReportIntervalDay
+	}
+	return s.report(ctx, tenantID, request, time.Now().UTC())
+}
+
+// report fills in the defaults of the request and serves the report from the cache, or computes and caches it.
+// Without a to time reports end with the current hour, which keeps their cache key stable within the hour.
+func (s *ReportService) report(ctx context.Context, tenantID string, request *dto.ReportRequest,
+	now time.Time) (*model.Report, error) {
+	if request.To.IsZero() {
+		request.To = now.Truncate(time.Hour).Add(time.Hour)
+	}
+	if request.From.IsZero() {
+		request.From = request.To.Add(-DefaultReportRange)
+	}
+	request.From, request.To = request.From.UTC(), request.To.UTC()
+	if request.GroupBy == nil {
+		request.GroupBy = []string{dto.ReportGroupStatus}
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+	if s.conf.MaxRange > 0 && request.To.Sub(request.From) > s.conf.MaxRange {
+		return nil, fmt.Errorf("%w: a report can cover at most %s", model.ErrInvalidRequest, s.conf.MaxRange)
+	}
+
+	key := reportCacheKey(tenantID, request)
+	if report := s.cachedReport(ctx, key); report != nil {
+		return report, nil
+	}
+
+	rows, err := s.repo.GetMessageReport(ctx, tenantID, request)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.Report{
+		From:        request.From,
+		To:          request.To,
+		GroupBy:     request.GroupBy,
+		Interval:    request.Interval,
+		Rows:        rows,
+		GeneratedAt: now,
+	}
+	for _, row := range rows {
+		report.Total += row.Count
+		report.Segments += row.Segments
+	}
+
+	s.cacheReport(ctx, key, report)
+	return report, nil
+}
+
+func (s *ReportService) cachedReport(ctx context.Context, key string) *model.Report {
+	if s.conf.CacheTTL <= 0 {
+		return nil
+	}
+
+	value, err := s.cache.Get(ctx, key)
+	if err != nil {
+		if !errors.Is(err, cache.ErrCacheMiss) {
+			s.logger.ErrorContext(ctx, "failed to read report cache", "error", err)
+		}
+		return nil
+	}
+
+	report := &model.Report{}
+	if err := json.Unmarshal(value, report); err != nil {
+		s.logger.ErrorContext(ctx, "failed to unmarshal cached report", "error", err)
+		return nil
+	}
+	return report
+}
+
+func (s *ReportService) cacheReport(ctx context.Context, key string, report *model.Report) {
+	if s.conf.CacheTTL <= 0 {
+		return
+	}
+
+	value, err := json.Marshal(report)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "failed to marshal report", "error", err)
+		return
+	}
+	if err := s.cache.SetWithTTL(ctx, key, value, s.conf.CacheTTL); err != nil {
+		s.logger.ErrorContext(ctx, "failed to cache report", "error", err)
+	}
+}
+
+func reportCacheKey(tenantID string, request *dto.ReportRequest) string {
+	return cache.TenantKey(tenantID, ReportCacheKeyPrefix+strings.Join([]string{
+		request.From.Format(time.RFC3339),
+		request.To.Format(time.RFC3339),
+		strings.Join(request.GroupBy, ","),
+		request.Interval,
+	}, ":"))
+}
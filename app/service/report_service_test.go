@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"messaging-system/app/cache"
+	"messaging-system/app/dto"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"messaging-system/config"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportService_Summary(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIReportRepository(mockController)
+	mockCache := mocks.NewMockIReportCache(mockController)
+	conf := &config.Report{CacheTTL: 5 * time.Minute, MaxRange: 31 * 24 * time.Hour}
+	reportService := NewReportService(mockRepo, mockCache, conf, slog.Default())
+
+	from := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(7 * 24 * time.Hour)
+	key := cache.TenantKey("retail", "report:2026-10-01T00:00:00Z:2026-10-08T00:00:00Z:status,provider:")
+
+	t.Run("invalid dimension", func(t *testing.T) {
+		report, err := reportService.Summary(ctx, "retail",
+			&dto.ReportRequest{From: from, To: to, GroupBy: []string{"country"}})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, report)
+	})
+
+	t.Run("range over the maximum", func(t *testing.T) {
+		report, err := reportService.Summary(ctx, "retail", &dto.ReportRequest{From: from, To: from.AddDate(0, 2, 0)})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, report)
+	})
+
+	t.Run("computes and caches the report", func(t *testing.T) {
+		mockCache.EXPECT().Get(gomock.Any(), key).Return(nil, cache.ErrCacheMiss)
+		mockRepo.
+			EXPECT().
+			GetMessageReport(gomock.Any(), "retail", &dto.ReportRequest{
+				From:    from,
+				To:      to,
+				GroupBy: []string{dto.ReportGroupStatus, dto.ReportGroupProvider},
+			}).
+			Return([]model.ReportRow{
+				{Status: model.StatusSent, Provider: "sms.example.com", Count: 40, Segments: 52},
+				{Status: model.ReportStatusRetrying, Count: 2, Segments: 2},
+			}, nil)
+		mockCache.EXPECT().SetWithTTL(gomock.Any(), key, gomock.Any(), 5*time.Minute).Return(nil)
+
+		report, err := reportService.Summary(ctx, "retail", &dto.ReportRequest{
+			From:    from,
+			To:      to,
+			GroupBy: []string{dto.ReportGroupStatus, dto.ReportGroupProvider},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 42, report.Total)
+		assert.Equal(t, 54, report.Segments)
+		assert.Len(t, report.Rows, 2)
+	})
+
+	t.Run("serves the report from the cache", func(t *testing.T) {
+		cached, err := json.Marshal(&model.Report{From: from, To: to, Total: 7})
+		assert.Nil(t, err)
+		mockCache.EXPECT().Get(gomock.Any(), key).Return(cached, nil)
+
+		report, err := reportService.Summary(ctx, "retail", &dto.ReportRequest{
+			From:    from,
+			To:      to,
+			GroupBy: []string{dto.ReportGroupStatus, dto.ReportGroupProvider},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 7, report.Total)
+	})
+
+	t.Run("cache errors do not fail the report", func(t *testing.T) {
+		mockCache.EXPECT().Get(gomock.Any(), gomock.Any()).Return(nil, assert.AnError)
+		mockRepo.EXPECT().GetMessageReport(gomock.Any(), "retail", gomock.Any()).Return([]model.ReportRow{}, nil)
+		mockCache.EXPECT().SetWithTTL(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(assert.AnError)
+
+		report, err := reportService.Summary(ctx, "retail", &dto.ReportRequest{From: from, To: to})
+		assert.Nil(t, err)
+		assert.Equal(t, []string{dto.ReportGroupStatus}, report.GroupBy)
+		assert.Equal(t, 0, report.Total)
+	})
+}
+
+func TestReportService_Timeseries(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockIReportRepository(mockController)
+	reportService := NewReportService(mockRepo, nil, &config.Report{}, slog.Default())
+
+	t.Run("defaults to daily counts by status over the last week", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			GetMessageReport(gomock.Any(), "retail", gomock.Any()).
+			DoAndReturn(func(_ context.Context, _ string, request *dto.ReportRequest) ([]model.ReportRow, error) {
+				assert.Equal(t, dto.ReportIntervalDay, request.Interval)
+				assert.Equal(t, []string{dto.ReportGroupStatus}, request.GroupBy)
+				assert.Equal(t, DefaultReportRange, request.To.Sub(request.From))
+				assert.True(t, request.To.After(time.Now()))
+				return []model.ReportRow{}, nil
+			})
+
+		report, err := reportService.Timeseries(ctx, "retail", &dto.ReportRequest{})
+		assert.Nil(t, err)
+		assert.Equal(t, dto.ReportIntervalDay, report.Interval)
+	})
+
+	t.Run("invalid interval", func(t *testing.T) {
+		report, err := reportService.Timeseries(ctx, "retail", &dto.ReportRequest{Interval: "week"})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, report)
+	})
+
+	t.Run("error getting report", func(t *testing.T) {
+		mockRepo.EXPECT().GetMessageReport(gomock.Any(), "retail", gomock.Any()).Return(nil, assert.AnError)
+
+		report, err := reportService.Timeseries(ctx, "retail", &dto.ReportRequest{Interval: dto.ReportIntervalHour})
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Nil(t, report)
+	})
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"messaging-system/app/cache"
+	"messaging-system/app/model"
+	"messaging-system/app/phone"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ISubjectRepository interface {
+	GetSubjectMessages(ctx context.Context, tenantID, phoneNumber string, archived bool) ([]model.Message, error)
+	GetSubjectInboundMessages(ctx context.Context, tenantID, phoneNumber string) ([]model.InboundMessage, error)
+	GetSubjectContacts(ctx context.Context, tenantID, phoneNumber string) ([]model.Contact, error)
+	GetSubjectOutboxMessages(ctx context.Context, tenantID, phoneNumber string) ([]model.OutboxMessage, error)
+	GetUnnormalizedOutboxMessages(ctx context.Context, tenantID string) ([]model.OutboxMessage, error)
+	SetOutboxNormalizedPhoneNumbers(ctx context.Context, phoneNumbers map[primitive.ObjectID]string) error
+	GetSubjectCampaigns(ctx context.Context, tenantID, phoneNumber string) ([]model.SubjectCampaign, error)
+	GetSuppression(ctx context.Context, tenantID, phoneNumber string) (*model.Suppression, error)
+	EraseSubject(ctx context.Context, tenantID, phoneNumber string) (*model.SubjectErasure, error)
+	AnonymizeSubject(ctx context.Context, tenantID, phoneNumber string) (*model.SubjectErasure, error)
+}
+
+// ISubjectArchive is an archive of messages kept outside of MongoDB, such as archive files.
+type ISubjectArchive interface {
+	GetSubjectMessages(ctx context.Context, tenantID, phoneNumber string) ([]model.Message, error)
+	EraseSubject(ctx context.Context, tenantID, phoneNumber string, anonymize bool) (int64, error)
+	Erasable() error
+}
+
+type ISubjectCache interface {
+	Delete(ctx context.Context, key string) error
+}
+
+// SubjectService answers the requests of data subjects, the people behind the phone numbers of a tenant.
+// The archive holds the messages archived outside of MongoDB, it is nil when messages are archived to a
+// collection.
+type SubjectService struct {
+	repo    ISubjectRepository
+	archive ISubjectArchive
+	cache   ISubjectCache
+	phones  *phone.Parser
+}
+
+func NewSubjectService(repo ISubjectRepository, archive ISubjectArchive, cache ISubjectCache,
+	phones *phone.Parser) *SubjectService {
+	return &SubjectService{
+		repo:    repo,
+		archive: archive,
+		cache:   cache,
+		phones:  phones,
+	}
+}
+
+// Export collects everything stored about a phone number within the tenant.
+func (s *SubjectService) Export(ctx context.Context, tenantID, rawPhoneNumber string) (*model.SubjectExport, error) {
+	phoneNumber, err := s.normalize(rawPhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &model.SubjectExport{
+		PhoneNumber: phoneNumber,
+		GeneratedAt: time.Now().UTC(),
+	}
+	if export.Messages, err = s.repo.GetSubjectMessages(ctx, tenantID, phoneNumber, false); err != nil {
+		return nil, err
+	}
+	if export.ArchivedMessages, err = s.getArchivedMessages(ctx, tenantID, phoneNumber); err != nil {
+		return nil, err
+	}
+	if export.InboundMessages, err = s.repo.GetSubjectInboundMessages(ctx, tenantID, phoneNumber); err != nil {
+		return nil, err
+	}
+	if export.Contacts, err = s.repo.GetSubjectContacts(ctx, tenantID, phoneNumber); err != nil {
+		return nil, err
+	}
+	if err := s.normalizeOutbox(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	if export.OutboxMessages, err = s.repo.GetSubjectOutboxMessages(ctx, tenantID, phoneNumber); err != nil {
+		return nil, err
+	}
+	if export.Campaigns, err = s.repo.GetSubjectCampaigns(ctx, tenantID, phoneNumber); err != nil {
+		return nil, err
+	}
+
+	export.Suppression, err = s.repo.GetSuppression(ctx, tenantID, phoneNumber)
+	if err != nil && !errors.Is(err, model.ErrNotFound) {
+		return nil, err
+	}
+	return export, nil
+}
+
+// Erase erases or anonymizes the messages of a phone number within the tenant, depending on mode, and
+// clears the cache entries of the number and its messages first. Clearing the cache before Mongo lets a
+// failed erasure be repeated, since the messages that name the cache keys are still there. A request is
+// refused with model.ErrNotErasable before anything is erased when archive files cannot be rewritten.
+func (s *SubjectService) Erase(ctx context.Context, tenantID, rawPhoneNumber,
+	mode string) (*model.SubjectErasure, error) {
+	if mode == "" {
+		mode = model.ErasureModeErase
+	}
+	if mode != model.ErasureModeErase && mode != model.ErasureModeAnonymize {
+		return nil, fmt.Errorf("%w: mode must be %s or %s", model.ErrInvalidRequest, model.ErasureModeErase,
+			model.ErasureModeAnonymize)
+	}
+
+	phoneNumber, err := s.normalize(rawPhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.archive != nil {
+		if err := s.archive.Erasable(); err != nil {
+			return nil, fmt.Errorf("%w: archive files cannot be rewritten: %s", model.ErrNotErasable, err)
+		}
+	}
+	if err := s.normalizeOutbox(ctx, tenantID); err != nil {
+		return nil, err
+	}
+
+	messages, err := s.repo.GetSubjectMessages(ctx, tenantID, phoneNumber, false)
+	if err != nil {
+		return nil, err
+	}
+	archived, err := s.getArchivedMessages(ctx, tenantID, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := []string{suppressionCacheKey(tenantID, phoneNumber)}
+	// the processor caches every message it sent by its id
+	for _, message := range append(messages, archived...) {
+		keys = append(keys, cache.TenantKey(tenantID, message.ID.Hex()))
+	}
+	for _, key := range keys {
+		if err := s.cache.Delete(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+
+	var erasure *model.SubjectErasure
+	if mode == model.ErasureModeAnonymize {
+		erasure, err = s.repo.AnonymizeSubject(ctx, tenantID, phoneNumber)
+	} else {
+		erasure, err = s.repo.EraseSubject(ctx, tenantID, phoneNumber)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.archive != nil {
+		erasure.ArchiveFileMessages, err = s.archive.EraseSubject(ctx, tenantID, phoneNumber,
+			mode == model.ErasureModeAnonymize)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	erasure.CacheKeys = int64(len(keys))
+	return erasure, nil
+}
+
+// getArchivedMessages returns the archived messages of a phone number from the archive collection and the
+// archive files.
+func (s *SubjectService) getArchivedMessages(ctx context.Context, tenantID,
+	phoneNumber string) ([]model.Message, error) {
+	messages, err := s.repo.GetSubjectMessages(ctx, tenantID, phoneNumber, true)
+	if err != nil {
+		return nil, err
+	}
+	if s.archive == nil {
+		return messages, nil
+	}
+
+	files, err := s.archive.GetSubjectMessages(ctx, tenantID, phoneNumber)
+	if err != nil {
+		return nil, err
+	}
+	return append(messages, files...), nil
+}
+
+// normalizeOutbox writes the normalized phone number of the outbox rows of a tenant the relay has not
+// written one for, subject requests match rows by it. Rows whose phone number does not parse are left as
+// they are, they hold no number a subject request could name. Numbers are normalized without the country
+// allowlist, rows of countries that left it still belong to the subject.
+func (s *SubjectService) normalizeOutbox(ctx context.Context, tenantID string) error {
+	outboxMessages, err := s.repo.GetUnnormalizedOutboxMessages(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	phoneNumbers := map[primitive.ObjectID]string{}
+	for _, outbox := range outboxMessages {
+		if number, err := s.phones.ParseAnyCountry(outbox.PhoneNumber); err == nil {
+			phoneNumbers[outbox.ID] = number.E164
+		}
+	}
+	return s.repo.SetOutboxNormalizedPhoneNumbers(ctx, phoneNumbers)
+}
+
+// normalize skips the country allowlist, the data of numbers whose country left it or that only reached the
+// service as inbound senders and suppressions must still be exportable and erasable.
+func (s *SubjectService) normalize(rawPhoneNumber string) (string, error) {
+	number, err := s.phones.ParseAnyCountry(rawPhoneNumber)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+
+	return number.E164, nil
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"messaging-system/app/cache"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSubjectService_Export(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	tenantID := "retail"
+	phoneNumber := "+905551112233"
+	mockRepo := mocks.NewMockISubjectRepository(mockController)
+	mockArchive := mocks.NewMockISubjectArchive(mockController)
+	subjectService := NewSubjectService(mockRepo, nil, mocks.NewMockISubjectCache(mockController), newPhoneParser(t))
+
+	t.Run("collects the data of the normalized number", func(t *testing.T) {
+		message := model.Message{ID: primitive.NewObjectID(), PhoneNumber: phoneNumber}
+		campaign := model.SubjectCampaign{ID: primitive.NewObjectID(), Name: "Black Friday"}
+		mockRepo.EXPECT().GetSubjectMessages(gomock.Any(), tenantID, phoneNumber, false).Return([]model.Message{message}, nil)
+		mockRepo.EXPECT().GetSubjectMessages(gomock.Any(), tenantID, phoneNumber, true).Return([]model.Message{}, nil)
+		mockRepo.EXPECT().GetSubjectInboundMessages(gomock.Any(), tenantID, phoneNumber).Return([]model.InboundMessage{}, nil)
+		mockRepo.EXPECT().GetSubjectContacts(gomock.Any(), tenantID, phoneNumber).Return([]model.Contact{}, nil)
+		mockRepo.EXPECT().GetUnnormalizedOutboxMessages(gomock.Any(), tenantID).Return([]model.OutboxMessage{}, nil)
+		mockRepo.EXPECT().SetOutboxNormalizedPhoneNumbers(gomock.Any(), map[primitive.ObjectID]string{}).Return(nil)
+		mockRepo.EXPECT().GetSubjectOutboxMessages(gomock.Any(), tenantID, phoneNumber).Return([]model.OutboxMessage{}, nil)
+		mockRepo.EXPECT().GetSubjectCampaigns(gomock.Any(), tenantID, phoneNumber).
+			Return([]model.SubjectCampaign{campaign}, nil)
+		mockRepo.EXPECT().GetSuppression(gomock.Any(), tenantID, phoneNumber).Return(nil, model.ErrNotFound)
+
+		export, err := subjectService.Export(ctx, tenantID, "0555 111 22 33")
+		assert.Nil(t, err)
+		assert.Equal(t, phoneNumber, export.PhoneNumber)
+		assert.Equal(t, []model.Message{message}, export.Messages)
+		assert.Equal(t, []model.SubjectCampaign{campaign}, export.Campaigns)
+		assert.Nil(t, export.Suppression)
+	})
+
+	t.Run("includes archive files and normalizes outbox rows first", func(t *testing.T) {
+		archiveService := NewSubjectService(mockRepo, mockArchive, mocks.NewMockISubjectCache(mockController),
+			newPhoneParser(t))
+		archived := model.Message{ID: primitive.NewObjectID(), PhoneNumber: phoneNumber}
+		filed := model.Message{ID: primitive.NewObjectID(), PhoneNumber: phoneNumber}
+		national := model.OutboxMessage{ID: primitive.NewObjectID(), PhoneNumber: "0555 111 22 33"}
+		invalid := model.OutboxMessage{ID: primitive.NewObjectID(), PhoneNumber: "123"}
+
+		mockRepo.EXPECT().GetSubjectMessages(gomock.Any(), tenantID, phoneNumber, false).Return([]model.Message{}, nil)
+		mockRepo.EXPECT().GetSubjectMessages(gomock.Any(), tenantID, phoneNumber, true).
+			Return([]model.Message{archived}, nil)
+		mockArchive.EXPECT().GetSubjectMessages(gomock.Any(), tenantID, phoneNumber).Return([]model.Message{filed}, nil)
+		mockRepo.EXPECT().GetSubjectInboundMessages(gomock.Any(), tenantID, phoneNumber).Return([]model.InboundMessage{}, nil)
+		mockRepo.EXPECT().GetSubjectContacts(gomock.Any(), tenantID, phoneNumber).Return([]model.Contact{}, nil)
+		gomock.InOrder(
+			mockRepo.EXPECT().GetUnnormalizedOutboxMessages(gomock.Any(), tenantID).
+				Return([]model.OutboxMessage{national, invalid}, nil),
+			mockRepo.EXPECT().SetOutboxNormalizedPhoneNumbers(gomock.Any(),
+				map[primitive.ObjectID]string{national.ID: phoneNumber}).Return(nil),
+			mockRepo.EXPECT().GetSubjectOutboxMessages(gomock.Any(), tenantID, phoneNumber).
+				Return([]model.OutboxMessage{national}, nil),
+		)
+		mockRepo.EXPECT().GetSubjectCampaigns(gomock.Any(), tenantID, phoneNumber).Return([]model.SubjectCampaign{}, nil)
+		mockRepo.EXPECT().GetSuppression(gomock.Any(), tenantID, phoneNumber).Return(nil, model.ErrNotFound)
+
+		export, err := archiveService.Export(ctx, tenantID, phoneNumber)
+		assert.Nil(t, err)
+		assert.Equal(t, []model.Message{archived, filed}, export.ArchivedMessages)
+		assert.Equal(t, []model.OutboxMessage{national}, export.OutboxMessages)
+	})
+
+	t.Run("number of a country outside the allowlist", func(t *testing.T) {
+		foreign := "+31612345678"
+		mockRepo.EXPECT().GetSubjectMessages(gomock.Any(), tenantID, foreign, false).Return([]model.Message{}, nil)
+		mockRepo.EXPECT().GetSubjectMessages(gomock.Any(), tenantID, foreign, true).Return([]model.Message{}, nil)
+		mockRepo.EXPECT().GetSubjectInboundMessages(gomock.Any(), tenantID, foreign).
+			Return([]model.InboundMessage{{PhoneNumber: foreign}}, nil)
+		mockRepo.EXPECT().GetSubjectContacts(gomock.Any(), tenantID, foreign).Return([]model.Contact{}, nil)
+		mockRepo.EXPECT().GetUnnormalizedOutboxMessages(gomock.Any(), tenantID).Return([]model.OutboxMessage{}, nil)
+		mockRepo.EXPECT().SetOutboxNormalizedPhoneNumbers(gomock.Any(), map[primitive.ObjectID]string{}).Return(nil)
+		mockRepo.EXPECT().GetSubjectOutboxMessages(gomock.Any(), tenantID, foreign).Return([]model.OutboxMessage{}, nil)
+		mockRepo.EXPECT().GetSubjectCampaigns(gomock.Any(), tenantID, foreign).Return([]model.SubjectCampaign{}, nil)
+		mockRepo.EXPECT().GetSuppression(gomock.Any(), tenantID, foreign).Return(&model.Suppression{}, nil)
+
+		export, err := subjectService.Export(ctx, tenantID, foreign)
+		assert.Nil(t, err)
+		assert.Equal(t, foreign, export.PhoneNumber)
+		assert.Len(t, export.InboundMessages, 1)
+		assert.NotNil(t, export.Suppression)
+	})
+
+	t.Run("invalid phone number", func(t *testing.T) {
+		_, err := subjectService.Export(ctx, tenantID, "123")
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+	})
+
+	t.Run("error reading messages", func(t *testing.T) {
+		mockRepo.EXPECT().GetSubjectMessages(gomock.Any(), tenantID, phoneNumber, false).Return(nil, assert.AnError)
+
+		_, err := subjectService.Export(ctx, tenantID, phoneNumber)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func TestSubjectService_Erase(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	tenantID := "retail"
+	phoneNumber := "+905551112233"
+	mockRepo := mocks.NewMockISubjectRepository(mockController)
+	mockCache := mocks.NewMockISubjectCache(mockController)
+	mockArchive := mocks.NewMockISubjectArchive(mockController)
+	subjectService := NewSubjectService(mockRepo, nil, mockCache, newPhoneParser(t))
+	archiveService := NewSubjectService(mockRepo, mockArchive, mockCache, newPhoneParser(t))
+
+	sent := model.Message{ID: primitive.NewObjectID()}
+	archived := model.Message{ID: primitive.NewObjectID()}
+	expectOutbox := func() {
+		mockRepo.EXPECT().GetUnnormalizedOutboxMessages(gomock.Any(), tenantID).Return([]model.OutboxMessage{}, nil)
+		mockRepo.EXPECT().SetOutboxNormalizedPhoneNumbers(gomock.Any(), gomock.Any()).Return(nil)
+	}
+
+	t.Run("clears the cache before erasing", func(t *testing.T) {
+		expectOutbox()
+		mockRepo.EXPECT().GetSubjectMessages(gomock.Any(), tenantID, phoneNumber, false).Return([]model.Message{sent}, nil)
+		mockRepo.EXPECT().GetSubjectMessages(gomock.Any(), tenantID, phoneNumber, true).Return([]model.Message{archived}, nil)
+		gomock.InOrder(
+			mockCache.EXPECT().Delete(gomock.Any(), suppressionCacheKey(tenantID, phoneNumber)).Return(nil),
+			mockCache.EXPECT().Delete(gomock.Any(), cache.TenantKey(tenantID, sent.ID.Hex())).Return(nil),
+			mockCache.EXPECT().Delete(gomock.Any(), cache.TenantKey(tenantID, archived.ID.Hex())).Return(nil),
+			mockRepo.EXPECT().EraseSubject(gomock.Any(), tenantID, phoneNumber).
+				Return(&model.SubjectErasure{PhoneNumber: phoneNumber, Mode: model.ErasureModeErase, Messages: 1}, nil),
+		)
+
+		erasure, err := subjectService.Erase(ctx, tenantID, phoneNumber, "")
+		assert.Nil(t, err)
+		assert.Equal(t, model.ErasureModeErase, erasure.Mode)
+		assert.Equal(t, int64(3), erasure.CacheKeys)
+	})
+
+	t.Run("anonymize", func(t *testing.T) {
+		expectOutbox()
+		mockRepo.EXPECT().GetSubjectMessages(gomock.Any(), tenantID, phoneNumber, gomock.Any()).
+			Return([]model.Message{}, nil).Times(2)
+		mockCache.EXPECT().Delete(gomock.Any(), suppressionCacheKey(tenantID, phoneNumber)).Return(nil)
+		mockRepo.EXPECT().AnonymizeSubject(gomock.Any(), tenantID, phoneNumber).
+			Return(&model.SubjectErasure{PhoneNumber: phoneNumber, Mode: model.ErasureModeAnonymize}, nil)
+
+		erasure, err := subjectService.Erase(ctx, tenantID, phoneNumber, model.ErasureModeAnonymize)
+		assert.Nil(t, err)
+		assert.Equal(t, model.ErasureModeAnonymize, erasure.Mode)
+	})
+
+	t.Run("erases archive files after mongo", func(t *testing.T) {
+		filed := model.Message{ID: primitive.NewObjectID()}
+		mockArchive.EXPECT().Erasable().Return(nil)
+		expectOutbox()
+		mockRepo.EXPECT().GetSubjectMessages(gomock.Any(), tenantID, phoneNumber, gomock.Any()).
+			Return([]model.Message{}, nil).Times(2)
+		mockArchive.EXPECT().GetSubjectMessages(gomock.Any(), tenantID, phoneNumber).Return([]model.Message{filed}, nil)
+		gomock.InOrder(
+			mockCache.EXPECT().Delete(gomock.Any(), suppressionCacheKey(tenantID, phoneNumber)).Return(nil),
+			mockCache.EXPECT().Delete(gomock.Any(), cache.TenantKey(tenantID, filed.ID.Hex())).Return(nil),
+			mockRepo.EXPECT().AnonymizeSubject(gomock.Any(), tenantID, phoneNumber).
+				Return(&model.SubjectErasure{PhoneNumber: phoneNumber, Mode: model.ErasureModeAnonymize}, nil),
+			mockArchive.EXPECT().EraseSubject(gomock.Any(), tenantID, phoneNumber, true).Return(int64(1), nil),
+		)
+
+		erasure, err := archiveService.Erase(ctx, tenantID, phoneNumber, model.ErasureModeAnonymize)
+		assert.Nil(t, err)
+		assert.Equal(t, int64(1), erasure.ArchiveFileMessages)
+		assert.Equal(t, int64(2), erasure.CacheKeys)
+	})
+
+	t.Run("refuses when archive files cannot be erased", func(t *testing.T) {
+		mockArchive.EXPECT().Erasable().Return(assert.AnError)
+
+		_, err := archiveService.Erase(ctx, tenantID, phoneNumber, "")
+		assert.ErrorIs(t, err, model.ErrNotErasable)
+	})
+
+	t.Run("cache failure leaves mongo untouched", func(t *testing.T) {
+		expectOutbox()
+		mockRepo.EXPECT().GetSubjectMessages(gomock.Any(), tenantID, phoneNumber, gomock.Any()).
+			Return([]model.Message{}, nil).Times(2)
+		mockCache.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(assert.AnError)
+
+		_, err := subjectService.Erase(ctx, tenantID, phoneNumber, "")
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+
+	t.Run("unknown mode", func(t *testing.T) {
+		_, err := subjectService.Erase(ctx, tenantID, phoneNumber, "shred")
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+	})
+}
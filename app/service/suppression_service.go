@@ -0,0 +1,196 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"messaging-system/app/cache"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"messaging-system/app/phone"
+	"time"
+)
+
+const (
+	SuppressionCacheKeyPrefix = "suppression:"
+
+	suppressedValue   = "1"
+	unsuppressedValue = "0"
+)
+
+type ISuppressionRepository interface {
+	AddSuppressions(ctx context.Context, suppressions []model.Suppression) error
+	GetSuppression(ctx context.Context, tenantID, phoneNumber string) (*model.Suppression, error)
+	GetSuppressions(ctx context.Context, tenantID string, limit int) ([]model.Suppression, error)
+	DeleteSuppression(ctx context.Context, tenantID, phoneNumber string) error
+}
+
+type ISuppressionCache interface {
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value interface{}) error
+	Delete(ctx context.Context, key string) error
+}
+
+// SuppressionService manages the opted out phone numbers of each tenant. Mongo is the source of truth,
+// lookups are served from Redis which caches both suppressed and unsuppressed numbers.
+type SuppressionService struct {
+	repo   ISuppressionRepository
+	cache  ISuppressionCache
+	phones *phone.Parser
+	logger *slog.Logger
+}
+
+func NewSuppressionService(repo ISuppressionRepository, cache ISuppressionCache, phones *phone.Parser,
+	logger *slog.Logger) *SuppressionService {
+	return &SuppressionService{
+		repo:   repo,
+		cache:  cache,
+		phones: phones,
+		logger: logger,
+	}
+}
+
+// Suppress adds a single phone number to the suppression list. Invalid numbers are reported as model.ErrInvalidRequest.
+func (s *SuppressionService) Suppress(ctx context.Context, tenantID string, request *dto.SuppressionRequest,
+	source string) (*model.Suppression, error) {
+	phoneNumber, err := s.normalize(request.PhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	suppression := model.Suppression{
+		TenantID:    tenantID,
+		PhoneNumber: phoneNumber,
+		Reason:      request.Reason,
+		Source:      source,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := s.repo.AddSuppressions(ctx, []model.Suppression{suppression}); err != nil {
+		return nil, err
+	}
+
+	s.cacheState(ctx, tenantID, phoneNumber, suppressedValue)
+	return &suppression, nil
+}
+
+// Import adds all valid phone numbers of the request, invalid ones are skipped and reported back.
+func (s *SuppressionService) Import(ctx context.Context, tenantID string,
+	request *dto.SuppressionImportRequest) (*dto.SuppressionImportResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+
+	now := time.Now().UTC()
+	seen := make(map[string]bool, len(request.PhoneNumbers))
+	suppressions := make([]model.Suppression, 0, len(request.PhoneNumbers))
+	response := &dto.SuppressionImportResponse{Invalid: []dto.InvalidPhoneNumber{}}
+
+	for _, raw := range request.PhoneNumbers {
+		number, err := s.phones.ParseAnyCountry(raw)
+		if err != nil {
+			response.Invalid = append(response.Invalid, dto.InvalidPhoneNumber{PhoneNumber: raw, Error: err.Error()})
+			continue
+		}
+		if seen[number.E164] {
+			continue
+		}
+
+		seen[number.E164] = true
+		suppressions = append(suppressions, model.Suppression{
+			TenantID:    tenantID,
+			PhoneNumber: number.E164,
+			Reason:      request.Reason,
+			Source:      model.SuppressionSourceImport,
+			CreatedAt:   now,
+		})
+	}
+
+	if err := s.repo.AddSuppressions(ctx, suppressions); err != nil {
+		return nil, err
+	}
+
+	for _, suppression := range suppressions {
+		s.cacheState(ctx, tenantID, suppression.PhoneNumber, suppressedValue)
+	}
+
+	response.Imported = len(suppressions)
+	return response, nil
+}
+
+func (s *SuppressionService) GetSuppression(ctx context.Context, tenantID,
+	rawPhoneNumber string) (*model.Suppression, error) {
+	phoneNumber, err := s.normalize(rawPhoneNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.GetSuppression(ctx, tenantID, phoneNumber)
+}
+
+func (s *SuppressionService) GetSuppressions(ctx context.Context, tenantID string,
+	limit int) ([]model.Suppression, error) {
+	return s.repo.GetSuppressions(ctx, tenantID, limit)
+}
+
+// Unsuppress removes a phone number from the suppression list so it can receive messages again.
+func (s *SuppressionService) Unsuppress(ctx context.Context, tenantID, rawPhoneNumber string) error {
+	phoneNumber, err := s.normalize(rawPhoneNumber)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteSuppression(ctx, tenantID, phoneNumber); err != nil {
+		return err
+	}
+
+	s.cacheState(ctx, tenantID, phoneNumber, unsuppressedValue)
+	return nil
+}
+
+// IsSuppressed reports whether the E.164 phone number is on the suppression list of the tenant. Cache
+// failures fall back to Mongo so an unavailable Redis never lets a suppressed number through.
+func (s *SuppressionService) IsSuppressed(ctx context.Context, tenantID, phoneNumber string) (bool, error) {
+	key := suppressionCacheKey(tenantID, phoneNumber)
+
+	value, err := s.cache.Get(ctx, key)
+	if err == nil {
+		return string(value) == suppressedValue, nil
+	}
+	if !errors.Is(err, cache.ErrCacheMiss) {
+		s.logger.ErrorContext(ctx, "failed to read suppression cache", "error", err)
+	}
+
+	_, err = s.repo.GetSuppression(ctx, tenantID, phoneNumber)
+	switch {
+	case err == nil:
+		s.cacheState(ctx, tenantID, phoneNumber, suppressedValue)
+		return true, nil
+	case errors.Is(err, model.ErrNotFound):
+		s.cacheState(ctx, tenantID, phoneNumber, unsuppressedValue)
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// normalize reads phone numbers without the country allowlist, numbers of countries removed from it can
+// still be suppressed and looked up.
+func (s *SuppressionService) normalize(rawPhoneNumber string) (string, error) {
+	number, err := s.phones.ParseAnyCountry(rawPhoneNumber)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", model.ErrInvalidRequest, err)
+	}
+
+	return number.E164, nil
+}
+
+func (s *SuppressionService) cacheState(ctx context.Context, tenantID, phoneNumber, value string) {
+	if err := s.cache.Set(ctx, suppressionCacheKey(tenantID, phoneNumber), value); err != nil {
+		s.logger.ErrorContext(ctx, "failed to cache suppression", "error", err)
+	}
+}
+
+func suppressionCacheKey(tenantID, phoneNumber string) string {
+	return cache.TenantKey(tenantID, SuppressionCacheKeyPrefix+phoneNumber)
+}
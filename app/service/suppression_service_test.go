@@ -0,0 +1,173 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"messaging-system/app/cache"
+	"messaging-system/app/dto"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSuppressionService_Suppress(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	tenantID := "retail"
+	mockRepo := mocks.NewMockISuppressionRepository(mockController)
+	mockCache := mocks.NewMockISuppressionCache(mockController)
+	suppressionService := NewSuppressionService(mockRepo, mockCache, newPhoneParser(t), slog.Default())
+
+	t.Run("stores normalized number and mirrors it in cache", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			AddSuppressions(gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_ context.Context, suppressions []model.Suppression) error {
+				assert.Len(t, suppressions, 1)
+				assert.Equal(t, "+905551112233", suppressions[0].PhoneNumber)
+				assert.Equal(t, tenantID, suppressions[0].TenantID)
+				return nil
+			})
+		mockCache.
+			EXPECT().
+			Set(gomock.Any(), suppressionCacheKey(tenantID, "+905551112233"), suppressedValue).
+			Return(nil)
+
+		suppression, err := suppressionService.Suppress(ctx, tenantID, &dto.SuppressionRequest{PhoneNumber: "0555 111 22 33"},
+			model.SuppressionSourceManual)
+		assert.Nil(t, err)
+		assert.Equal(t, model.SuppressionSourceManual, suppression.Source)
+	})
+
+	t.Run("number of a country outside the allowlist", func(t *testing.T) {
+		mockRepo.EXPECT().AddSuppressions(gomock.Any(), gomock.Any()).Return(nil)
+		mockCache.EXPECT().Set(gomock.Any(), suppressionCacheKey(tenantID, "+31612345678"), suppressedValue).Return(nil)
+
+		suppression, err := suppressionService.Suppress(ctx, tenantID, &dto.SuppressionRequest{PhoneNumber: "+31612345678"},
+			model.SuppressionSourceInbound)
+		assert.Nil(t, err)
+		assert.Equal(t, "+31612345678", suppression.PhoneNumber)
+	})
+
+	t.Run("invalid phone number", func(t *testing.T) {
+		suppression, err := suppressionService.Suppress(ctx, tenantID, &dto.SuppressionRequest{PhoneNumber: "123"},
+			model.SuppressionSourceManual)
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, suppression)
+	})
+}
+
+func TestSuppressionService_Import(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	tenantID := "retail"
+	mockRepo := mocks.NewMockISuppressionRepository(mockController)
+	mockCache := mocks.NewMockISuppressionCache(mockController)
+	suppressionService := NewSuppressionService(mockRepo, mockCache, newPhoneParser(t), slog.Default())
+
+	t.Run("skips invalid and duplicate numbers", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			AddSuppressions(gomock.Any(), gomock.Len(2)).
+			Return(nil)
+		mockCache.
+			EXPECT().
+			Set(gomock.Any(), gomock.Any(), suppressedValue).
+			Return(nil).
+			Times(2)
+
+		response, err := suppressionService.Import(ctx, tenantID, &dto.SuppressionImportRequest{
+			PhoneNumbers: []string{"+905551112233", "05551112233", "+4915112345678", "abc"},
+		})
+		assert.Nil(t, err)
+		assert.Equal(t, 2, response.Imported)
+		assert.Len(t, response.Invalid, 1)
+		assert.Equal(t, "abc", response.Invalid[0].PhoneNumber)
+	})
+
+	t.Run("empty import", func(t *testing.T) {
+		response, err := suppressionService.Import(ctx, tenantID, &dto.SuppressionImportRequest{})
+		assert.ErrorIs(t, err, model.ErrInvalidRequest)
+		assert.Nil(t, response)
+	})
+}
+
+func TestSuppressionService_IsSuppressed(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	tenantID := "retail"
+	phoneNumber := "+905551112233"
+	key := "tenant:retail:suppression:" + phoneNumber
+	mockRepo := mocks.NewMockISuppressionRepository(mockController)
+	mockCache := mocks.NewMockISuppressionCache(mockController)
+	suppressionService := NewSuppressionService(mockRepo, mockCache, newPhoneParser(t), slog.Default())
+
+	t.Run("served from cache", func(t *testing.T) {
+		mockCache.EXPECT().Get(gomock.Any(), key).Return([]byte(suppressedValue), nil)
+
+		suppressed, err := suppressionService.IsSuppressed(ctx, tenantID, phoneNumber)
+		assert.Nil(t, err)
+		assert.True(t, suppressed)
+	})
+
+	t.Run("cache miss falls back to repository", func(t *testing.T) {
+		mockCache.EXPECT().Get(gomock.Any(), key).Return(nil, cache.ErrCacheMiss)
+		mockRepo.EXPECT().GetSuppression(gomock.Any(), tenantID, phoneNumber).Return(nil, model.ErrNotFound)
+		mockCache.EXPECT().Set(gomock.Any(), key, unsuppressedValue).Return(nil)
+
+		suppressed, err := suppressionService.IsSuppressed(ctx, tenantID, phoneNumber)
+		assert.Nil(t, err)
+		assert.False(t, suppressed)
+	})
+
+	t.Run("cache error falls back to repository", func(t *testing.T) {
+		mockCache.EXPECT().Get(gomock.Any(), key).Return(nil, assert.AnError)
+		mockRepo.EXPECT().GetSuppression(gomock.Any(), tenantID, phoneNumber).Return(&model.Suppression{}, nil)
+		mockCache.EXPECT().Set(gomock.Any(), key, suppressedValue).Return(assert.AnError)
+
+		suppressed, err := suppressionService.IsSuppressed(ctx, tenantID, phoneNumber)
+		assert.Nil(t, err)
+		assert.True(t, suppressed)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockCache.EXPECT().Get(gomock.Any(), key).Return(nil, cache.ErrCacheMiss)
+		mockRepo.EXPECT().GetSuppression(gomock.Any(), tenantID, phoneNumber).Return(nil, assert.AnError)
+
+		_, err := suppressionService.IsSuppressed(ctx, tenantID, phoneNumber)
+		assert.Error(t, err)
+	})
+}
+
+func TestSuppressionService_Unsuppress(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	tenantID := "retail"
+	mockRepo := mocks.NewMockISuppressionRepository(mockController)
+	mockCache := mocks.NewMockISuppressionCache(mockController)
+	suppressionService := NewSuppressionService(mockRepo, mockCache, newPhoneParser(t), slog.Default())
+
+	t.Run("removes number and updates cache", func(t *testing.T) {
+		mockRepo.EXPECT().DeleteSuppression(gomock.Any(), tenantID, "+905551112233").Return(nil)
+		mockCache.EXPECT().Set(gomock.Any(), suppressionCacheKey(tenantID, "+905551112233"), unsuppressedValue).Return(nil)
+
+		assert.Nil(t, suppressionService.Unsuppress(ctx, tenantID, "+905551112233"))
+	})
+
+	t.Run("number is not suppressed", func(t *testing.T) {
+		mockRepo.EXPECT().DeleteSuppression(gomock.Any(), tenantID, "+905551112233").Return(model.ErrNotFound)
+
+		assert.ErrorIs(t, suppressionService.Unsuppress(ctx, tenantID, "+905551112233"), model.ErrNotFound)
+	})
+}
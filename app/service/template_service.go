@@ -0,0 +1,76 @@
+package service
+
+import (
+	"context"
+	"messaging-system/app/dto"
+	"messaging-system/app/model"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ITemplateRepository interface {
+	CreateTemplate(ctx context.Context, template *model.Template) error
+	GetTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID) (*model.Template, error)
+	GetTemplates(ctx context.Context, tenantID string) ([]model.Template, error)
+	UpdateTemplate(ctx context.Context, template *model.Template) error
+	DeleteTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID) error
+}
+
+type TemplateService struct {
+	repo ITemplateRepository
+}
+
+func NewTemplateService(repo ITemplateRepository) *TemplateService {
+	return &TemplateService{repo: repo}
+}
+
+func (s *TemplateService) CreateTemplate(ctx context.Context, tenantID string,
+	request *dto.TemplateRequest) (*model.Template, error) {
+	now := time.Now().UTC()
+	template := &model.Template{
+		ID:            primitive.NewObjectID(),
+		TenantID:      tenantID,
+		Name:          request.Name,
+		DefaultLocale: request.DefaultLocale,
+		Locales:       request.Locales,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := s.repo.CreateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *TemplateService) GetTemplate(ctx context.Context, tenantID string,
+	templateID primitive.ObjectID) (*model.Template, error) {
+	return s.repo.GetTemplate(ctx, tenantID, templateID)
+}
+
+func (s *TemplateService) GetTemplates(ctx context.Context, tenantID string) ([]model.Template, error) {
+	return s.repo.GetTemplates(ctx, tenantID)
+}
+
+func (s *TemplateService) UpdateTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID,
+	request *dto.TemplateRequest) (*model.Template, error) {
+	template, err := s.repo.GetTemplate(ctx, tenantID, templateID)
+	if err != nil {
+		return nil, err
+	}
+
+	template.Name = request.Name
+	template.DefaultLocale = request.DefaultLocale
+	template.Locales = request.Locales
+	template.UpdatedAt = time.Now().UTC()
+
+	if err := s.repo.UpdateTemplate(ctx, template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *TemplateService) DeleteTemplate(ctx context.Context, tenantID string, templateID primitive.ObjectID) error {
+	return s.repo.DeleteTemplate(ctx, tenantID, templateID)
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"messaging-system/app/dto"
+	"messaging-system/app/mocks"
+	"messaging-system/app/model"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTemplateService_CreateTemplate(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockITemplateRepository(mockController)
+	templateService := NewTemplateService(mockRepo)
+	request := &dto.TemplateRequest{Name: "otp", DefaultLocale: "en", Locales: map[string]string{"en": "{{code}}"}}
+
+	t.Run("create template successfully", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			CreateTemplate(gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		template, err := templateService.CreateTemplate(ctx, "retail", request)
+		assert.Nil(t, err)
+		assert.Equal(t, "otp", template.Name)
+		assert.Equal(t, "retail", template.TenantID)
+		assert.False(t, template.ID.IsZero())
+	})
+
+	t.Run("error creating template", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			CreateTemplate(gomock.Any(), gomock.Any()).
+			Return(model.ErrAlreadyExists)
+
+		template, err := templateService.CreateTemplate(ctx, "retail", request)
+		assert.ErrorIs(t, err, model.ErrAlreadyExists)
+		assert.Nil(t, template)
+	})
+}
+
+func TestTemplateService_UpdateTemplate(t *testing.T) {
+	mockController := gomock.NewController(t)
+	defer mockController.Finish()
+
+	ctx := context.Background()
+	mockRepo := mocks.NewMockITemplateRepository(mockController)
+	templateService := NewTemplateService(mockRepo)
+	templateID := primitive.NewObjectID()
+	request := &dto.TemplateRequest{Name: "otp-v2", DefaultLocale: "tr", Locales: map[string]string{"tr": "{{code}}"}}
+
+	t.Run("update template successfully", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			GetTemplate(gomock.Any(), "retail", templateID).
+			Return(&model.Template{ID: templateID, TenantID: "retail", Name: "otp"}, nil)
+		mockRepo.
+			EXPECT().
+			UpdateTemplate(gomock.Any(), gomock.Any()).
+			Return(nil)
+
+		template, err := templateService.UpdateTemplate(ctx, "retail", templateID, request)
+		assert.Nil(t, err)
+		assert.Equal(t, "otp-v2", template.Name)
+		assert.Equal(t, "tr", template.DefaultLocale)
+	})
+
+	t.Run("template not found", func(t *testing.T) {
+		mockRepo.
+			EXPECT().
+			GetTemplate(gomock.Any(), "retail", templateID).
+			Return(nil, model.ErrNotFound)
+
+		template, err := templateService.UpdateTemplate(ctx, "retail", templateID, request)
+		assert.ErrorIs(t, err, model.ErrNotFound)
+		assert.Nil(t, template)
+	})
+}
@@ -0,0 +1,117 @@
+package sms
+
+import "unicode/utf16"
+
+type Encoding string
+
+const (
+	EncodingGSM7 Encoding = "GSM-7"
+	// EncodingGSM7Turkish is GSM-7 with the Turkish national language single shift table,
+	// which adds ğ, Ğ, ı, İ, ş, Ş and ç as two-septet characters.
+	EncodingGSM7Turkish Encoding = "GSM-7-TR"
+	EncodingUCS2        Encoding = "UCS-2"
+)
+
+// Segment capacities in septets (GSM-7) or UTF-16 code units (UCS-2). Multipart messages lose
+// room to the concatenation header, the Turkish shift table also needs its own header element.
+const (
+	GSM7SingleCapacity        = 160
+	GSM7MultiCapacity         = 153
+	GSM7TurkishSingleCapacity = 155
+	GSM7TurkishMultiCapacity  = 149
+	UCS2SingleCapacity        = 70
+	UCS2MultiCapacity         = 67
+)
+
+const (
+	gsm7Basic     = "@£$¥èéùìòÇ\nØø\rÅåΔ_ΦΓΛΩΠΨΣΘΞÆæßÉ !\"#¤%&'()*+,-./0123456789:;<=>?¡ABCDEFGHIJKLMNOPQRSTUVWXYZÄÖÑÜ§¿abcdefghijklmnopqrstuvwxyzäöñüà"
+	gsm7Extension = "\f^{}\\[~]|€"
+	turkishShift  = "\f^{}\\[~]|€ĞİŞçğış"
+)
+
+var (
+	basicSet     = runeSet(gsm7Basic)
+	extensionSet = runeSet(gsm7Extension)
+	turkishSet   = runeSet(turkishShift)
+)
+
+type Info struct {
+	Encoding Encoding
+	// Units is the length in septets for GSM-7 and in UTF-16 code units for UCS-2.
+	Units    int
+	Segments int
+}
+
+// Analyze detects the cheapest encoding able to represent content and counts the SMS
+// segments needed to send it.
+func Analyze(content string) Info {
+	if costs, ok := septetCosts(content, extensionSet); ok {
+		return newInfo(EncodingGSM7, costs, GSM7SingleCapacity, GSM7MultiCapacity)
+	}
+
+	if costs, ok := septetCosts(content, turkishSet); ok {
+		return newInfo(EncodingGSM7Turkish, costs, GSM7TurkishSingleCapacity, GSM7TurkishMultiCapacity)
+	}
+
+	costs := make([]int, 0, len(content))
+	for _, r := range content {
+		costs = append(costs, utf16.RuneLen(r))
+	}
+	return newInfo(EncodingUCS2, costs, UCS2SingleCapacity, UCS2MultiCapacity)
+}
+
+// septetCosts returns the septets of every character, characters of the shift table are
+// sent as an escape plus the character.
+func septetCosts(content string, shift map[rune]bool) ([]int, bool) {
+	costs := make([]int, 0, len(content))
+	for _, r := range content {
+		switch {
+		case basicSet[r]:
+			costs = append(costs, 1)
+		case shift[r]:
+			costs = append(costs, 2)
+		default:
+			return nil, false
+		}
+	}
+	return costs, true
+}
+
+func newInfo(encoding Encoding, costs []int, single, multi int) Info {
+	units := 0
+	for _, cost := range costs {
+		units += cost
+	}
+
+	return Info{
+		Encoding: encoding,
+		Units:    units,
+		Segments: countSegments(costs, units, single, multi),
+	}
+}
+
+// countSegments fills segments greedily, so an escaped character or a surrogate pair is never
+// split between two segments.
+func countSegments(costs []int, units, single, multi int) int {
+	if units <= single {
+		return 1
+	}
+
+	segments, used := 1, 0
+	for _, cost := range costs {
+		if used+cost > multi {
+			segments++
+			used = 0
+		}
+		used += cost
+	}
+	return segments
+}
+
+func runeSet(chars string) map[rune]bool {
+	set := make(map[rune]bool, len(chars))
+	for _, r := range chars {
+		set[r] = true
+	}
+	return set
+}
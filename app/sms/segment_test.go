@@ -0,0 +1,43 @@
+package sms
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		encoding Encoding
+		units    int
+		segments int
+	}{
+		{name: "plain ascii", content: "Hello", encoding: EncodingGSM7, units: 5, segments: 1},
+		{name: "extension characters count twice", content: "{€}", encoding: EncodingGSM7, units: 6, segments: 1},
+		{name: "full single segment", content: strings.Repeat("a", 160), encoding: EncodingGSM7, units: 160, segments: 1},
+		{name: "two segments", content: strings.Repeat("a", 161), encoding: EncodingGSM7, units: 161, segments: 2},
+		{name: "three segments", content: strings.Repeat("a", 307), encoding: EncodingGSM7, units: 307, segments: 3},
+		{
+			name:     "escape is not split between segments",
+			content:  strings.Repeat("a", 152) + "€" + strings.Repeat("a", 10),
+			encoding: EncodingGSM7, units: 164, segments: 2,
+		},
+		{name: "turkish characters use the shift table", content: "Şifreniz: ğüşıöç", encoding: EncodingGSM7Turkish, units: 21, segments: 1},
+		{name: "turkish single segment limit", content: strings.Repeat("ş", 78), encoding: EncodingGSM7Turkish, units: 156, segments: 2},
+		{name: "ucs-2", content: "Привет", encoding: EncodingUCS2, units: 6, segments: 1},
+		{name: "ucs-2 two segments", content: strings.Repeat("я", 71), encoding: EncodingUCS2, units: 71, segments: 2},
+		{name: "surrogate pairs count twice", content: "Hi 😀", encoding: EncodingUCS2, units: 5, segments: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := Analyze(tt.content)
+			assert.Equal(t, tt.encoding, info.Encoding)
+			assert.Equal(t, tt.units, info.Units)
+			assert.Equal(t, tt.segments, info.Segments)
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package template
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var placeholderRegex = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// Placeholders returns the distinct placeholder names of a template body in order of appearance.
+func Placeholders(body string) ([]string, error) {
+	rest := placeholderRegex.ReplaceAllString(body, "")
+	if strings.Contains(rest, "{{") || strings.Contains(rest, "}}") {
+		return nil, errors.New("template contains a malformed placeholder")
+	}
+
+	var names []string
+	seen := map[string]bool{}
+	for _, match := range placeholderRegex.FindAllStringSubmatch(body, -1) {
+		if !seen[match[1]] {
+			seen[match[1]] = true
+			names = append(names, match[1])
+		}
+	}
+
+	return names, nil
+}
+
+// Render replaces every {{placeholder}} of body with its value from params.
+func Render(body string, params map[string]string) (string, error) {
+	var missing []string
+	rendered := placeholderRegex.ReplaceAllStringFunc(body, func(match string) string {
+		name := placeholderRegex.FindStringSubmatch(match)[1]
+		value, ok := params[name]
+		if !ok {
+			missing = append(missing, name)
+			return match
+		}
+		return value
+	})
+
+	if len(missing) > 0 {
+		return "", fmt.Errorf("missing template params: %s", strings.Join(missing, ", "))
+	}
+
+	return rendered, nil
+}
@@ -0,0 +1,36 @@
+package template
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlaceholders(t *testing.T) {
+	t.Run("returns distinct names", func(t *testing.T) {
+		names, err := Placeholders("Hi {{name}}, your code is {{ code }}. Bye {{name}}")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"name", "code"}, names)
+	})
+
+	t.Run("malformed placeholder", func(t *testing.T) {
+		_, err := Placeholders("Hi {{name}, your code is {{code}}")
+		assert.Error(t, err)
+	})
+}
+
+func TestRender(t *testing.T) {
+	t.Run("replaces placeholders", func(t *testing.T) {
+		content, err := Render("Hi {{name}}, your code is {{ code }}", map[string]string{
+			"name": "Ayşe",
+			"code": "123456",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "Hi Ayşe, your code is 123456", content)
+	})
+
+	t.Run("missing params", func(t *testing.T) {
+		_, err := Render("Hi {{name}}, your code is {{code}}", map[string]string{})
+		assert.EqualError(t, err, "missing template params: name, code")
+	})
+}
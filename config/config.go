@@ -1,6 +1,7 @@
 package config
 
 import (
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -14,6 +15,17 @@ type Config struct {
 	Mongo     *Mongo
 	Redis     *Redis
 	Processor *Processor
+	Outbox    *Outbox
+	Message   *Message
+	Notifier  *Notifier
+	Auth      *Auth
+	RateLimit *RateLimit
+	Report    *Report
+	Retention *Retention
+	PII       *PII
+	// Tenants holds the settings of each tenant by lower case tenant id, tenants without an entry use
+	// the shared provider client without a quota.
+	Tenants Tenants
 }
 
 type Server struct {
@@ -26,9 +38,21 @@ type Client struct {
 }
 
 type Mongo struct {
-	URI               string
-	Database          string
-	MessageCollection string
+	URI                    string
+	Database               string
+	MessageCollection      string
+	OutboxCollection       string
+	ResumeTokenCollection  string
+	TemplateCollection     string
+	SuppressionCollection  string
+	InboundCollection      string
+	NotificationCollection string
+	APIKeyCollection       string
+	AuditCollection        string
+	CampaignCollection     string
+	ContactListCollection  string
+	ContactCollection      string
+	ArchiveCollection      string
 }
 
 type Redis struct {
@@ -40,6 +64,165 @@ type Redis struct {
 
 type Processor struct {
 	BatchSize int
+	// ChangeStream wakes the processor up as soon as an unsent message is inserted,
+	// the interval ticker keeps running as a safety-net sweep.
+	ChangeStream bool
+	// HighPriorityShare is the share of each batch reserved for high priority messages,
+	// unused slots of either lane are filled from the other one.
+	HighPriorityShare float64
+	// MaxAttempts is the number of send attempts after which a message moves to the failed status.
+	MaxAttempts int
+}
+
+type Message struct {
+	// DefaultCountry is used to read phone numbers given in national format.
+	DefaultCountry string
+	// Countries is the allowlist of recipient countries, all supported countries are allowed when empty.
+	Countries []string
+	// MaxSegments is the maximum number of SMS segments a message may be split into.
+	MaxSegments int
+	// Expiry is the default time to live of a message per category, applied at creation
+	// when the request has no explicit expiresAt.
+	Expiry map[string]time.Duration
+	// HelpReply is sent back to senders replying HELP, no reply is sent when empty.
+	HelpReply string
+}
+
+type Outbox struct {
+	Enabled       bool
+	RetryInterval time.Duration
+}
+
+type Notifier struct {
+	Enabled bool
+	// Secret signs the events of tenants without a callback secret with HMAC-SHA256, receivers verify the
+	// X-Signature header with it. It is required while notifications are enabled.
+	Secret string
+	// Timeout bounds a single delivery attempt.
+	Timeout time.Duration
+	// PollInterval is how often due notifications are delivered.
+	PollInterval time.Duration
+	// MaxAttempts is the number of deliveries tried before a notification is marked as failed.
+	MaxAttempts int
+	// Backoff is the delay after the first failed attempt, it doubles with every further attempt.
+	Backoff time.Duration
+}
+
+type Tenant struct {
+	// Client replaces the provider credentials of the tenant, unset fields fall back to the shared client.
+	Client *Client
+	// DailyQuota is the number of messages the tenant may create per UTC day, unlimited when zero.
+	DailyQuota int
+	// CallbackHosts lists the hosts status notifications of the tenant may be posted to, "*.example.com"
+	// matches every subdomain. Messages of tenants without hosts cannot have a callback URL.
+	CallbackHosts []string
+	// CallbackSecret signs the status notifications of the tenant instead of notifier.secret, so receivers
+	// of one tenant cannot forge events of another.
+	CallbackSecret string
+}
+
+type Tenants map[string]*Tenant
+
+// Get returns the settings of a tenant, an empty configuration for tenants that have none.
+func (t Tenants) Get(tenantID string) *Tenant {
+	if tenant, ok := t[strings.ToLower(tenantID)]; ok && tenant != nil {
+		return tenant
+	}
+	return &Tenant{}
+}
+
+// AllowsCallback reports whether status notifications of the tenant may be posted to host.
+func (t *Tenant) AllowsCallback(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range t.CallbackHosts {
+		allowed = strings.ToLower(allowed)
+		if host == allowed || (strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:])) {
+			return true
+		}
+	}
+	return false
+}
+
+type Auth struct {
+	// Enabled requires every API request to carry a valid key, all requests are allowed otherwise.
+	Enabled bool
+	// BootstrapKey is accepted with every scope, it is meant to create the first API keys.
+	BootstrapKey string
+	// JWT additionally accepts bearer tokens of an identity provider.
+	JWT *JWT
+}
+
+type JWT struct {
+	Enabled bool
+	// JWKSURL serves the signing keys of the identity provider, JWKSFile is read instead when no URL is set.
+	JWKSURL  string
+	JWKSFile string
+	Issuer   string
+	Audience string
+	// TenantClaim names the claim holding the tenant of the caller, tokens without it belong to the default tenant.
+	TenantClaim string
+	// ScopeClaim names the claim listing what the caller was granted, as a space separated string or a list.
+	ScopeClaim string
+	// Scopes maps claim values to API scopes, claim values that are API scopes already are granted as is.
+	Scopes map[string][]string
+	// RefreshInterval is the minimum time between two downloads of the JWKS URL.
+	RefreshInterval time.Duration
+	// Leeway tolerates clock skew when checking the expiry and not-before times of a token.
+	Leeway time.Duration
+}
+
+type RateLimit struct {
+	Enabled bool
+	// Routes limits the requests each API key, or client IP when authentication is disabled, may send
+	// to a route. Requests to routes without an entry are not limited.
+	Routes []RouteLimit
+}
+
+type RouteLimit struct {
+	// Method matches any method when empty.
+	Method string
+	// Path matches the request path exactly, a trailing * matches every path with the prefix.
+	Path string
+	// Limit is the number of requests allowed within any Window.
+	Limit  int
+	Window time.Duration
+}
+
+type Report struct {
+	// CacheTTL is how long a computed report is served from Redis, reports are not cached when zero.
+	CacheTTL time.Duration
+	// MaxRange is the longest time range a report may cover.
+	MaxRange time.Duration
+}
+
+type Retention struct {
+	Enabled bool
+	// Interval is how often messages past their retention are archived.
+	Interval time.Duration
+	// Statuses keeps the messages of each final status for the given time after they were created,
+	// messages of statuses without an entry are kept forever.
+	Statuses map[string]time.Duration
+	// BatchSize is the number of messages archived and deleted at once.
+	BatchSize int
+	// Archive is where messages are moved to: "collection" for the archive collection, "file" for
+	// gzipped NDJSON files in Directory.
+	Archive   string
+	Directory string
+}
+
+type PII struct {
+	// MaskLogs masks phone numbers and message content in every log record.
+	MaskLogs bool
+	// Encrypt stores the phone numbers and content of messages encrypted with AES-GCM.
+	Encrypt bool
+	// ActiveKey names the key new values are encrypted with, the other keys only decrypt values
+	// written before the key was rotated. Key ids are lower case since the configuration keys are.
+	ActiveKey string
+	// Keys holds base64 encoded AES keys of 16, 24 or 32 bytes by id.
+	Keys map[string]string
+	// IndexKey is the base64 encoded HMAC-SHA256 key of the blind index phone numbers are looked up by,
+	// changing it hides the messages stored before.
+	IndexKey string
 }
 
 func NewConfig(configPath, configName string) (Config, error) {
@@ -5,11 +5,19 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
+	"messaging-system/app/auth"
 	"messaging-system/app/cache"
 	"messaging-system/app/client"
 	"messaging-system/app/handler"
+	"messaging-system/app/middleware"
+	"messaging-system/app/notifier"
+	"messaging-system/app/phone"
+	"messaging-system/app/pii"
 	"messaging-system/app/processor"
+	"messaging-system/app/relay"
 	"messaging-system/app/repository"
+	"messaging-system/app/requestid"
+	"messaging-system/app/retention"
 	"messaging-system/app/service"
 	"messaging-system/config"
 	_ "messaging-system/docs"
@@ -35,6 +43,15 @@ import (
 // @BasePath /
 // @schemes http https
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT of the identity provider as "Bearer <token>"
+
 func main() {
 	appConfig, err := config.NewConfig(".config", os.Getenv("APP_ENV"))
 	if err != nil {
@@ -42,26 +59,126 @@ func main() {
 	}
 	appConfig.Print()
 	ctx := context.Background()
-	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+	// the request ID is added below the masking handler, IDs made of digits must not be mistaken for numbers
+	logger := slog.New(requestid.NewLogHandler(slog.NewTextHandler(os.Stdout, nil)))
+	if appConfig.PII != nil && appConfig.PII.MaskLogs {
+		logger = slog.New(pii.NewMaskingHandler(logger.Handler()))
+	}
+
+	fieldCipher, err := pii.NewCipher(appConfig.PII)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	mongoRepo, err := repository.New(ctx, appConfig.Mongo, fieldCipher)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	mongoRepo, err := repository.New(ctx, appConfig.Mongo)
+	phoneParser, err := phone.NewParser(appConfig.Message.DefaultCountry, appConfig.Message.Countries)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	webhookClient := client.NewClient(appConfig.Client, logger)
+	webhookClients := client.NewTenantClients(appConfig.Client, appConfig.Tenants, logger)
 	redis := cache.NewRedis(appConfig.Redis)
-	messageService := service.NewMessageService(mongoRepo)
-	messageProcessor := processor.NewMessageProcessor(messageService, webhookClient, redis, logger)
-	messageHandler := handler.NewMessageHandler(messageProcessor)
+	quotaService := service.NewQuotaService(redis, appConfig.Tenants)
+	messageService := service.NewMessageService(mongoRepo, phoneParser, quotaService, appConfig.Message,
+		appConfig.Tenants)
+	suppressionService := service.NewSuppressionService(mongoRepo, redis, phoneParser, logger)
+	statusNotifier, err := notifier.NewNotifier(mongoRepo, appConfig.Notifier, appConfig.Tenants, logger)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if appConfig.Notifier != nil && appConfig.Notifier.Enabled {
+		go statusNotifier.Run(ctx)
+	}
 
-	server := fiber.New()
+	campaignService := service.NewCampaignService(mongoRepo, messageService, phoneParser)
+	messageProcessor, err := processor.NewMessageProcessor(messageService, webhookClients, redis, suppressionService,
+		statusNotifier, campaignService, appConfig.Processor, logger)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if appConfig.Outbox != nil && appConfig.Outbox.Enabled {
+		outboxRelay := relay.NewOutboxRelay(mongoRepo, phoneParser, quotaService, appConfig.Outbox, appConfig.Message,
+			logger)
+		go outboxRelay.Run(ctx)
+	}
+
+	archive, err := retention.NewArchive(appConfig.Retention, mongoRepo, fieldCipher)
+	if err != nil {
+		log.Fatal(err)
+	}
+	archiver, err := retention.NewArchiver(mongoRepo, archive, appConfig.Retention, logger)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if appConfig.Retention != nil && appConfig.Retention.Enabled {
+		go archiver.Run(ctx)
+	}
+
+	apiKeyService := service.NewAPIKeyService(mongoRepo, appConfig.Auth)
+	auditService := service.NewAuditService(mongoRepo)
+	messageHandler := handler.NewMessageHandler(ctx, messageProcessor, messageService, auditService)
+	templateHandler := handler.NewTemplateHandler(service.NewTemplateService(mongoRepo), auditService)
+	suppressionHandler := handler.NewSuppressionHandler(suppressionService, auditService)
+	inboundService := service.NewInboundService(mongoRepo, suppressionService, messageService, phoneParser,
+		appConfig.Message, logger)
+	inboundHandler := handler.NewInboundHandler(inboundService)
+	receiptHandler := handler.NewReceiptHandler(service.NewReceiptService(mongoRepo, statusNotifier, logger))
+	notificationHandler := handler.NewNotificationHandler(statusNotifier)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService, auditService)
+	campaignHandler := handler.NewCampaignHandler(campaignService)
+	contactHandler := handler.NewContactHandler(service.NewContactService(mongoRepo, messageService, phoneParser))
+	retentionHandler := handler.NewRetentionHandler(archiver)
+	// archive files are searched and rewritten by subject requests, the archive collection by the repository
+	subjectArchive, _ := archive.(service.ISubjectArchive)
+	subjectHandler := handler.NewSubjectHandler(service.NewSubjectService(mongoRepo, subjectArchive, redis, phoneParser),
+		auditService)
+	auditHandler := handler.NewAuditHandler(auditService)
+	reportHandler := handler.NewReportHandler(service.NewReportService(mongoRepo, redis, appConfig.Report, logger))
+
+	// contact uploads are read while they arrive instead of being buffered up to the body limit
+	server := fiber.New(fiber.Config{StreamRequestBody: true})
 	server.Use(
+		middleware.RequestID(),
+		middleware.AccessLog(logger),
 		cors.New(cors.ConfigDefault),
 	)
 	server.Get("/swagger/*", fiberSwagger.WrapHandler)
+	if appConfig.Auth != nil && appConfig.Auth.Enabled {
+		var tokens middleware.ITokenAuthenticator
+		if appConfig.Auth.JWT != nil && appConfig.Auth.JWT.Enabled {
+			jwtAuthenticator, err := auth.NewJWTAuthenticator(ctx, appConfig.Auth.JWT, logger)
+			if err != nil {
+				log.Fatal(err)
+			}
+			tokens = jwtAuthenticator
+		}
+		server.Use(middleware.Authenticate(apiKeyService, tokens))
+	} else {
+		logger.Warn("API authentication is disabled, every request is allowed")
+		server.Use(middleware.AllowAll())
+	}
+	if appConfig.RateLimit != nil && appConfig.RateLimit.Enabled {
+		server.Use(middleware.RateLimit(redis, appConfig.RateLimit, logger))
+	}
+	server.Use("/messages", middleware.Idempotency(redis))
 
 	messageHandler.RegisterRoutes(server)
+	templateHandler.RegisterRoutes(server)
+	suppressionHandler.RegisterRoutes(server)
+	inboundHandler.RegisterRoutes(server)
+	receiptHandler.RegisterRoutes(server)
+	notificationHandler.RegisterRoutes(server)
+	apiKeyHandler.RegisterRoutes(server)
+	campaignHandler.RegisterRoutes(server)
+	contactHandler.RegisterRoutes(server)
+	reportHandler.RegisterRoutes(server)
+	retentionHandler.RegisterRoutes(server)
+	subjectHandler.RegisterRoutes(server)
+	auditHandler.RegisterRoutes(server)
 	go log.Fatal(
 		server.Listen(fmt.Sprintf(":%d", appConfig.Server.Port)),
 	)